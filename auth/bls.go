@@ -42,8 +42,8 @@ func (*BLS) ComputeUnits(chain.Rules) uint64 {
 	return BLSComputeUnits
 }
 
-func (*BLS) ValidRange(chain.Rules) (int64, int64) {
-	return -1, -1
+func (b *BLS) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.AuthValidRange(r, b.GetTypeID())
 }
 
 func (b *BLS) Verify(_ context.Context, msg []byte) error {
@@ -42,8 +42,8 @@ func (*ED25519) ComputeUnits(chain.Rules) uint64 {
 	return ED25519ComputeUnits
 }
 
-func (*ED25519) ValidRange(chain.Rules) (int64, int64) {
-	return -1, -1
+func (d *ED25519) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.AuthValidRange(r, d.GetTypeID())
 }
 
 func (d *ED25519) Verify(_ context.Context, msg []byte) error {
@@ -42,8 +42,8 @@ func (*SECP256R1) ComputeUnits(chain.Rules) uint64 {
 	return SECP256R1ComputeUnits
 }
 
-func (*SECP256R1) ValidRange(chain.Rules) (int64, int64) {
-	return -1, -1
+func (d *SECP256R1) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.AuthValidRange(r, d.GetTypeID())
 }
 
 func (d *SECP256R1) Verify(_ context.Context, msg []byte) error {
@@ -6,13 +6,16 @@ package chain
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/maybe"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/x/merkledb"
 	"go.opentelemetry.io/otel/attribute"
@@ -23,9 +26,12 @@ import (
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/fees"
 	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/tstate"
 	"github.com/ava-labs/hypersdk/utils"
 	"github.com/ava-labs/hypersdk/window"
 	"github.com/ava-labs/hypersdk/workers"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
 )
 
 var (
@@ -100,6 +106,7 @@ type StatelessBlock struct {
 
 	results    []*Result
 	feeManager *fees.Manager
+	changes    map[string]maybe.Maybe[[]byte]
 
 	vm   VM
 	view merkledb.View
@@ -227,6 +234,7 @@ func (b *StatelessBlock) initializeBuilt(
 	view merkledb.View,
 	results []*Result,
 	feeManager *fees.Manager,
+	changes map[string]maybe.Maybe[[]byte],
 ) error {
 	_, span := b.vm.Tracer().Start(ctx, "StatelessBlock.initializeBuilt")
 	defer span.End()
@@ -241,6 +249,7 @@ func (b *StatelessBlock) initializeBuilt(
 	b.t = time.UnixMilli(b.StatefulBlock.Tmstmp)
 	b.results = results
 	b.feeManager = feeManager
+	b.changes = changes
 	b.txsSet = set.NewSet[ids.ID](len(b.Txs))
 	for _, tx := range b.Txs {
 		b.txsSet.Add(tx.ID())
@@ -428,29 +437,12 @@ func (b *StatelessBlock) innerVerify(ctx context.Context, vctx VerifyContext) er
 	b.feeManager = feeManager
 
 	// Update chain metadata
-	heightKeyStr := string(heightKey)
-	timestampKeyStr := string(timestampKey)
-	feeKeyStr := string(feeKey)
-
-	keys := make(state.Keys)
-	keys.Add(heightKeyStr, state.Write)
-	keys.Add(timestampKeyStr, state.Write)
-	keys.Add(feeKeyStr, state.Write)
-	tsv := ts.NewView(keys, map[string][]byte{
-		heightKeyStr:    parentHeightRaw,
-		timestampKeyStr: parentTimestampRaw,
-		feeKeyStr:       parentFeeManager.Bytes(),
-	})
-	if err := tsv.Insert(ctx, heightKey, binary.BigEndian.AppendUint64(nil, b.Hght)); err != nil {
-		return err
-	}
-	if err := tsv.Insert(ctx, timestampKey, binary.BigEndian.AppendUint64(nil, uint64(b.Tmstmp))); err != nil {
+	if err := b.commitFees(ctx, ts, parentView, results); err != nil {
 		return err
 	}
-	if err := tsv.Insert(ctx, feeKey, feeManager.Bytes()); err != nil {
+	if err := b.commitMetadata(ctx, ts, parentHeightRaw, parentTimestampRaw, parentFeeManager, feeManager); err != nil {
 		return err
 	}
-	tsv.Commit()
 
 	// Compare state root
 	//
@@ -491,6 +483,7 @@ func (b *StatelessBlock) innerVerify(ctx context.Context, vctx VerifyContext) er
 		return err
 	}
 	b.view = view
+	b.changes = ts.ChangedKeys()
 
 	// Kickoff root generation
 	go func() {
@@ -510,6 +503,85 @@ func (b *StatelessBlock) innerVerify(ctx context.Context, vctx VerifyContext) er
 	return nil
 }
 
+// commitFees passes the fees paid by [results] to the [StateManager] of the VM
+// if it is a [BlockFeeHandler] and records its changes in [ts].
+func (b *StatelessBlock) commitFees(ctx context.Context, ts *tstate.TState, parentView state.Immutable, results []*Result) error {
+	handler, ok := b.vm.StateManager().(BlockFeeHandler)
+	if !ok {
+		return nil
+	}
+	var (
+		blockFees uint64
+		err       error
+	)
+	for _, result := range results {
+		blockFees, err = smath.Add64(blockFees, result.Fee)
+		if err != nil {
+			return err
+		}
+	}
+	keys := handler.BlockStateKeys()
+	storage := make(map[string][]byte, len(keys))
+	for k := range keys {
+		v, err := parentView.GetValue(ctx, []byte(k))
+		if errors.Is(err, database.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		storage[k] = v
+	}
+	tsv := ts.NewView(keys, storage)
+	if err := handler.BlockFees(ctx, tsv, blockFees); err != nil {
+		return err
+	}
+	tsv.Commit()
+	return nil
+}
+
+// commitMetadata records the height, timestamp, and fees of [b] in [ts].
+func (b *StatelessBlock) commitMetadata(
+	ctx context.Context,
+	ts *tstate.TState,
+	parentHeightRaw []byte,
+	parentTimestampRaw []byte,
+	parentFeeManager *fees.Manager,
+	feeManager *fees.Manager,
+) error {
+	var (
+		sm           = b.vm.StateManager()
+		heightKey    = HeightKey(sm.HeightKey())
+		timestampKey = TimestampKey(sm.TimestampKey())
+		feeKey       = FeeKey(sm.FeeKey())
+
+		heightKeyStr    = string(heightKey)
+		timestampKeyStr = string(timestampKey)
+		feeKeyStr       = string(feeKey)
+	)
+
+	keys := make(state.Keys)
+	keys.Add(heightKeyStr, state.Write)
+	keys.Add(timestampKeyStr, state.Write)
+	keys.Add(feeKeyStr, state.Write)
+	tsv := ts.NewView(keys, map[string][]byte{
+		heightKeyStr:    parentHeightRaw,
+		timestampKeyStr: parentTimestampRaw,
+		feeKeyStr:       parentFeeManager.Bytes(),
+	})
+	if err := tsv.Insert(ctx, heightKey, binary.BigEndian.AppendUint64(nil, b.Hght)); err != nil {
+		return err
+	}
+	if err := tsv.Insert(ctx, timestampKey, binary.BigEndian.AppendUint64(nil, uint64(b.Tmstmp))); err != nil {
+		return err
+	}
+	if err := tsv.Insert(ctx, feeKey, feeManager.Bytes()); err != nil {
+		return err
+	}
+	tsv.Commit()
+	return nil
+}
+
 // implements "snowman.Block.choices.Decidable"
 func (b *StatelessBlock) Accept(ctx context.Context) error {
 	start := time.Now()
@@ -578,6 +650,7 @@ func (b *StatelessBlock) MarkAccepted(ctx context.Context) {
 	//
 	// Note: We will not call [b.vm.Verified] before accepting during state sync
 	b.vm.Accepted(ctx, b)
+	b.changes = nil // only used by [b.vm.Accepted]
 }
 
 // implements "snowman.Block.choices.Decidable"
@@ -798,6 +871,15 @@ func (b *StatelessBlock) FeeManager() *fees.Manager {
 	return b.feeManager
 }
 
+// StateChanges returns all keys modified by the block and their new
+// values (Nothing if the key was removed).
+//
+// This is only populated for processed blocks that have not yet been
+// accepted.
+func (b *StatelessBlock) StateChanges() map[string]maybe.Maybe[[]byte] {
+	return b.changes
+}
+
 func (b *StatefulBlock) Marshal() ([]byte, error) {
 	size := ids.IDLen + consts.Uint64Len + consts.Uint64Len +
 		consts.Uint64Len + window.WindowSliceSize +
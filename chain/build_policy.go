@@ -0,0 +1,111 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk/fees"
+)
+
+// BuildPolicy controls which transactions [BuildBlock] attempts to include in
+// a block (and in what order) and when it stops building.
+type BuildPolicy interface {
+	// Start is called each time [BuildBlock] starts building a block on top of
+	// [parent] at [timestamp] and returns the [BuildRound] used for that
+	// block.
+	Start(ctx context.Context, r Rules, parent *StatelessBlock, timestamp int64) BuildRound
+}
+
+// BuildRound makes the decisions of a [BuildPolicy] while building a single
+// block. Its methods are never called concurrently.
+type BuildRound interface {
+	// Order is called with each batch of transactions streamed from the
+	// mempool (in mempool order, without repeats) and returns the
+	// transactions of [batch] to attempt, in the order they should be
+	// included. Any transactions that are not returned are restored to the
+	// mempool. Returned transactions that are not in [batch] (or are returned
+	// more than once) are ignored.
+	//
+	// Conflicting transactions are always executed in the returned order,
+	// however, non-conflicting transactions may be executed concurrently.
+	Order(ctx context.Context, batch []*Transaction) []*Transaction
+
+	// Full is called when [tx] is skipped because including it would exceed
+	// the maximum units of [dimension] ([consumed] are the units consumed by
+	// the block so far). If it returns true, building stops.
+	Full(tx *Transaction, dimension fees.Dimension, consumed fees.Dimensions) bool
+}
+
+var _ BuildPolicy = (*DefaultBuildPolicy)(nil)
+
+// DefaultBuildPolicy attempts transactions in mempool order and stops building
+// once a transaction doesn't fit and the block is at or above the window
+// target units of the corresponding dimension (which prevents a full mempool
+// iteration looking for the "perfect fit").
+//
+// If [StopThreshold] is non-zero, building also stops once a transaction
+// doesn't fit and the block has less than [StopThreshold] units of the
+// corresponding dimension left.
+type DefaultBuildPolicy struct {
+	StopThreshold uint64
+}
+
+func (p *DefaultBuildPolicy) Start(_ context.Context, r Rules, _ *StatelessBlock, _ int64) BuildRound {
+	return &defaultBuildRound{
+		stopThreshold: p.StopThreshold,
+		maxUnits:      r.GetMaxBlockUnits(),
+		targetUnits:   r.GetWindowTargetUnits(),
+	}
+}
+
+type defaultBuildRound struct {
+	stopThreshold uint64
+	maxUnits      fees.Dimensions
+	targetUnits   fees.Dimensions
+}
+
+func (*defaultBuildRound) Order(_ context.Context, batch []*Transaction) []*Transaction {
+	return batch
+}
+
+func (d *defaultBuildRound) Full(_ *Transaction, dimension fees.Dimension, consumed fees.Dimensions) bool {
+	if consumed[dimension] >= d.targetUnits[dimension] {
+		return true
+	}
+	return d.stopThreshold > 0 && d.maxUnits[dimension]-consumed[dimension] < d.stopThreshold
+}
+
+// orderBatch calls [round.Order] with [batch] and returns the transactions to
+// attempt (ignoring any that are not in [batch] or are repeated) and the
+// transactions of [batch] that were not returned.
+func orderBatch(ctx context.Context, round BuildRound, batch []*Transaction) ([]*Transaction, []*Transaction, int) {
+	remaining := set.NewSet[ids.ID](len(batch))
+	for _, tx := range batch {
+		remaining.Add(tx.ID())
+	}
+	var (
+		ordered = round.Order(ctx, batch)
+		attempt = make([]*Transaction, 0, len(ordered))
+		ignored int
+	)
+	for _, tx := range ordered {
+		if !remaining.Contains(tx.ID()) {
+			ignored++
+			continue
+		}
+		remaining.Remove(tx.ID())
+		attempt = append(attempt, tx)
+	}
+	skipped := make([]*Transaction, 0, remaining.Len())
+	for _, tx := range batch {
+		if remaining.Contains(tx.ID()) {
+			skipped = append(skipped, tx)
+		}
+	}
+	return attempt, skipped, ignored
+}
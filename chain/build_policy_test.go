@@ -0,0 +1,104 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ava-labs/hypersdk/fees"
+)
+
+func TestDefaultBuildPolicy(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	r := NewMockRules(ctrl)
+	r.EXPECT().GetMaxBlockUnits().Return(fees.Dimensions{10_000, 10_000, 10_000, 10_000, 10_000}).Times(2)
+	r.EXPECT().GetWindowTargetUnits().Return(fees.Dimensions{5_000, 5_000, 5_000, 5_000, 5_000}).Times(2)
+
+	p := &DefaultBuildPolicy{StopThreshold: 100}
+	round := p.Start(context.Background(), r, nil, 0)
+
+	// Transactions are attempted in mempool order
+	batch := []*Transaction{{}, {}}
+	require.Equal(batch, round.Order(context.Background(), batch))
+
+	// Keep building below the target
+	require.False(round.Full(nil, fees.Compute, fees.Dimensions{0, 4_999, 0, 0, 0}))
+
+	// Stop building at the target
+	require.True(round.Full(nil, fees.Compute, fees.Dimensions{0, 5_000, 0, 0, 0}))
+
+	// Stop building when too few units are left (only possible if the target
+	// is close to the max)
+	p.StopThreshold = 5_002
+	round = p.Start(context.Background(), r, nil, 0)
+	require.True(round.Full(nil, fees.Bandwidth, fees.Dimensions{4_999, 0, 0, 0, 0}))
+}
+
+func TestDefaultBuildPolicyBaseline(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	// The default config disables [StopThreshold], which would otherwise stop
+	// building immediately if the max units are below it
+	var (
+		maxUnits    = fees.Dimensions{2_000, 2_000, 2_000, 2_000, 2_000}
+		targetUnits = fees.Dimensions{1_000, 1_500, 500, 2_000, 0}
+	)
+	r := NewMockRules(ctrl)
+	r.EXPECT().GetMaxBlockUnits().Return(maxUnits)
+	r.EXPECT().GetWindowTargetUnits().Return(targetUnits)
+	round := (&DefaultBuildPolicy{}).Start(context.Background(), r, nil, 0)
+
+	// The default policy stops building exactly when the block is at or above
+	// the target units of the dimension that doesn't fit
+	rng := rand.New(rand.NewSource(0)) //nolint:gosec
+	for i := 0; i < 1_000; i++ {
+		var consumed fees.Dimensions
+		for d := range consumed {
+			consumed[d] = rng.Uint64() % (maxUnits[d] + 1)
+		}
+		d := fees.Dimension(rng.Intn(fees.FeeDimensions))
+		require.Equal(consumed[d] >= targetUnits[d], round.Full(nil, d, consumed))
+	}
+}
+
+type testBuildRound struct {
+	order func([]*Transaction) []*Transaction
+}
+
+func (r *testBuildRound) Order(_ context.Context, batch []*Transaction) []*Transaction {
+	return r.order(batch)
+}
+
+func (*testBuildRound) Full(*Transaction, fees.Dimension, fees.Dimensions) bool {
+	return false
+}
+
+func TestOrderBatch(t *testing.T) {
+	require := require.New(t)
+
+	batch := make([]*Transaction, 4)
+	for i := range batch {
+		batch[i] = &Transaction{id: ids.GenerateTestID()}
+	}
+	unknown := &Transaction{id: ids.GenerateTestID()}
+
+	// Transactions are attempted in the returned order, skipping unknown and
+	// repeated ones, and the rest are restored
+	round := &testBuildRound{order: func(b []*Transaction) []*Transaction {
+		return []*Transaction{b[2], unknown, b[0], b[2]}
+	}}
+	attempt, skipped, ignored := orderBatch(context.Background(), round, batch)
+	require.Equal([]*Transaction{batch[2], batch[0]}, attempt)
+	require.Equal([]*Transaction{batch[1], batch[3]}, skipped)
+	require.Equal(2, ignored)
+}
@@ -25,15 +25,6 @@ import (
 	"github.com/ava-labs/hypersdk/tstate"
 )
 
-const (
-	maxViewPreallocation = 10_000
-
-	// TODO: make these tunable
-	streamBatch             = 256
-	streamPrefetchThreshold = streamBatch / 2
-	stopBuildingThreshold   = 2_048 // units
-)
-
 var errBlockFull = errors.New("block full")
 
 func HandlePreExecute(log logging.Logger, err error) bool {
@@ -86,7 +77,7 @@ func BuildBlock(
 	// If the parent block is not yet verified, we will attempt to
 	// execute it.
 	mempoolSize := vm.Mempool().Len(ctx)
-	changesEstimate := min(mempoolSize, maxViewPreallocation)
+	changesEstimate := min(mempoolSize, vm.GetBuildMaxViewPreallocation())
 	parentView, err := parent.View(ctx, true)
 	if err != nil {
 		log.Warn("block building failed: couldn't get parent db", zap.Error(err))
@@ -105,7 +96,7 @@ func BuildBlock(
 		return nil, err
 	}
 	maxUnits := r.GetMaxBlockUnits()
-	targetUnits := r.GetWindowTargetUnits()
+	round := vm.GetBuildPolicy().Start(ctx, r, parent, nextTime)
 
 	var (
 		ts            = tstate.New(changesEstimate)
@@ -129,6 +120,9 @@ func BuildBlock(
 
 		sm = vm.StateManager()
 
+		streamBatch             = vm.GetBuildStreamBatch()
+		streamPrefetchThreshold = streamBatch / 2
+
 		// prepareStreamLock ensures we don't overwrite stream prefetching spawned
 		// asynchronously.
 		prepareStreamLock sync.Mutex
@@ -157,18 +151,28 @@ func BuildBlock(
 			break
 		}
 
-		e := executor.New(streamBatch, vm.GetTransactionExecutionCores(), MaxKeyDependencies, vm.GetExecutorBuildRecorder())
-		pending := make(map[ids.ID]*Transaction, streamBatch)
-		var pendingLock sync.Mutex
-		for li, ltx := range txs {
-			txsAttempted++
-			i := li
-			tx := ltx
-
-			// Skip any duplicates before going async
+		// Skip any duplicates and let the policy pick (and order) the
+		// transactions to attempt
+		txsAttempted += len(txs)
+		candidates := make([]*Transaction, 0, len(txs))
+		for i, tx := range txs {
 			if dup.Contains(i) {
 				continue
 			}
+			candidates = append(candidates, tx)
+		}
+		ordered, skipped, ignored := orderBatch(ctx, round, candidates)
+		if ignored > 0 {
+			log.Warn("build policy returned unknown or repeated transactions", zap.Int("count", ignored))
+		}
+		restorable = append(restorable, skipped...)
+
+		e := executor.New(len(ordered), vm.GetTransactionExecutionCores(), MaxKeyDependencies, vm.GetExecutorBuildRecorder())
+		pending := make(map[ids.ID]*Transaction, len(ordered))
+		var pendingLock sync.Mutex
+		for li, ltx := range ordered {
+			i := li
+			tx := ltx
 
 			stateKeys, err := tx.StateKeys(sm)
 			if err != nil {
@@ -300,10 +304,9 @@ func BuildBlock(
 					)
 					restore = true
 
-					// If we are above the target for the dimension we can't consume, we will
-					// stop building. This prevents a full mempool iteration looking for the
-					// "perfect fit".
-					if feeManager.LastConsumed(dimension) >= targetUnits[dimension] {
+					// Let the policy decide whether to keep looking for transactions
+					// that fit.
+					if round.Full(tx, dimension, feeManager.UnitsConsumed()) {
 						stop = true
 						return errBlockFull
 					}
@@ -369,6 +372,9 @@ func BuildBlock(
 	}
 
 	// Update chain metadata
+	if err := b.commitFees(ctx, ts, parentView, results); err != nil {
+		return nil, fmt.Errorf("%w: unable to commit fees", err)
+	}
 	heightKey := HeightKey(sm.HeightKey())
 	heightKeyStr := string(heightKey)
 	timestampKey := TimestampKey(b.vm.StateManager().TimestampKey())
@@ -410,7 +416,7 @@ func BuildBlock(
 	}
 
 	// Compute block hash and marshaled representation
-	if err := b.initializeBuilt(ctx, view, results, feeManager); err != nil {
+	if err := b.initializeBuilt(ctx, view, results, feeManager, ts.ChangedKeys()); err != nil {
 		log.Warn("block failed", zap.Int("txs", len(b.Txs)), zap.Any("consumed", feeManager.UnitsConsumed()))
 		return nil, err
 	}
@@ -77,6 +77,9 @@ type VM interface {
 	Mempool() Mempool
 	IsRepeat(context.Context, []*Transaction, set.Bits, bool) set.Bits
 	GetTargetBuildDuration() time.Duration
+	GetBuildPolicy() BuildPolicy
+	GetBuildStreamBatch() int
+	GetBuildMaxViewPreallocation() int
 	GetTransactionExecutionCores() int
 	GetStateFetchConcurrency() int
 
@@ -152,6 +155,20 @@ type Rules interface {
 	FetchCustom(string) (any, bool)
 }
 
+// ScheduledRules is an optional extension of [Rules] for VMs that schedule
+// the activation of [Action]s and [Auth]s (i.e. using upgradeBytes).
+type ScheduledRules interface {
+	Rules
+
+	// GetActionValidRange returns the timestamp range (in ms) that the [Action]
+	// with [typeID] is valid. -1 means no start/end.
+	GetActionValidRange(typeID uint8) (start int64, end int64)
+
+	// GetAuthValidRange returns the timestamp range (in ms) that the [Auth]
+	// with [typeID] is valid. -1 means no start/end.
+	GetAuthValidRange(typeID uint8) (start int64, end int64)
+}
+
 type MetadataManager interface {
 	HeightKey() []byte
 	TimestampKey() []byte
@@ -174,6 +191,20 @@ type FeeHandler interface {
 	Deduct(ctx context.Context, addr codec.Address, mu state.Mutable, amount uint64) error
 }
 
+// BlockFeeHandler can be implemented by a [StateManager] to act on the fees
+// paid by all transactions of a block at once (once they are executed), which
+// avoids making every fee payment write to the same keys (which would prevent
+// transactions from being executed in parallel).
+type BlockFeeHandler interface {
+	// BlockStateKeys is a full enumeration of all database keys that could be
+	// touched by [BlockFees] (suffixed with their max chunks).
+	BlockStateKeys() state.Keys
+
+	// BlockFees is called with the sum of the fees paid by the transactions of
+	// a block after they are executed.
+	BlockFees(ctx context.Context, mu state.Mutable, fees uint64) error
+}
+
 // StateManager allows [Chain] to safely store certain types of items in state
 // in a structured manner. If we did not use [StateManager], we may overwrite
 // state written by actions or auth.
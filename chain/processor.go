@@ -6,8 +6,10 @@ package chain
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/maybe"
 
 	"github.com/ava-labs/hypersdk/executor"
 	"github.com/ava-labs/hypersdk/fees"
@@ -29,6 +31,21 @@ func (b *StatelessBlock) Execute(
 	im state.Immutable,
 	feeManager *fees.Manager,
 	r Rules,
+) ([]*Result, *tstate.TState, error) {
+	return b.execute(ctx, tracer, im, feeManager, r, nil, nil)
+}
+
+// execute processes all transactions in [b]. If [txChanges] and [traces] are
+// provided, the changes made by and the trace of each transaction are stored
+// at its index.
+func (b *StatelessBlock) execute(
+	ctx context.Context,
+	tracer trace.Tracer, //nolint:interfacer
+	im state.Immutable,
+	feeManager *fees.Manager,
+	r Rules,
+	txChanges []map[string]maybe.Maybe[[]byte],
+	traces []*TxTrace,
 ) ([]*Result, *tstate.TState, error) {
 	ctx, span := tracer.Start(ctx, "Processor.Execute")
 	defer span.End()
@@ -86,17 +103,34 @@ func (b *StatelessBlock) Execute(
 			// It is critical we explicitly set the scope before each transaction is
 			// processed
 			tsv := ts.NewView(stateKeys, storage)
+			var (
+				trace *TxTrace
+				start time.Time
+			)
+			if traces != nil {
+				tsv.EnableTrace()
+				trace = &TxTrace{TxID: txID}
+				traces[i] = trace
+				start = time.Now()
+			}
 
 			// Ensure we have enough funds to pay fees
 			if err := tx.PreExecute(ctx, feeManager, sm, r, tsv, t); err != nil {
 				return err
 			}
 
-			result, err := tx.Execute(ctx, feeManager, sm, r, tsv, t)
+			result, err := tx.execute(ctx, feeManager, sm, r, tsv, t, trace)
 			if err != nil {
 				return err
 			}
 			results[i] = result
+			if trace != nil {
+				trace.Result = result
+				trace.Duration = time.Since(start)
+			}
+			if txChanges != nil {
+				txChanges[i] = tsv.ChangedKeys()
+			}
 
 			// Commit results to parent [TState]
 			tsv.Commit()
@@ -0,0 +1,94 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+
+	"github.com/ava-labs/hypersdk/fees"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// Execution is the outcome of re-executing a block with [StatelessBlock.Replay].
+type Execution struct {
+	Results []*Result
+
+	// TxChanges are the keys modified by each transaction (indexed like
+	// [Results]) and their new values (Nothing if the key was removed).
+	TxChanges []map[string]maybe.Maybe[[]byte]
+
+	// Traces record every state operation performed by each transaction
+	// (indexed like [Results]).
+	Traces []*TxTrace
+
+	// Changes are all keys modified by the block (including chain metadata).
+	Changes map[string]maybe.Maybe[[]byte]
+
+	FeeManager *fees.Manager
+	Root       ids.ID
+}
+
+// Replay executes [b] on top of [parent] (the post-execution state of the
+// parent of [b]) and returns everything it produced.
+//
+// Unlike [Verify], Replay does not check the timestamp, repeats, signatures, or
+// the state root of [b] and does not modify [b] or [parent] (so it can be
+// called on blocks that were already accepted).
+func (b *StatelessBlock) Replay(ctx context.Context, parent state.View) (*Execution, error) {
+	var (
+		sm = b.vm.StateManager()
+		r  = b.vm.Rules(b.Tmstmp)
+	)
+	parentHeightRaw, err := parent.GetValue(ctx, HeightKey(sm.HeightKey()))
+	if err != nil {
+		return nil, err
+	}
+	parentTimestampRaw, err := parent.GetValue(ctx, TimestampKey(sm.TimestampKey()))
+	if err != nil {
+		return nil, err
+	}
+	feeRaw, err := parent.GetValue(ctx, FeeKey(sm.FeeKey()))
+	if err != nil {
+		return nil, err
+	}
+	parentFeeManager := fees.NewManager(feeRaw)
+	feeManager, err := parentFeeManager.ComputeNext(b.Tmstmp, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		txChanges = make([]map[string]maybe.Maybe[[]byte], len(b.Txs))
+		traces    = make([]*TxTrace, len(b.Txs))
+	)
+	results, ts, err := b.execute(ctx, b.vm.Tracer(), parent, feeManager, r, txChanges, traces)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.commitFees(ctx, ts, parent, results); err != nil {
+		return nil, err
+	}
+	if err := b.commitMetadata(ctx, ts, parentHeightRaw, parentTimestampRaw, parentFeeManager, feeManager); err != nil {
+		return nil, err
+	}
+	view, err := ts.ExportMerkleDBView(ctx, b.vm.Tracer(), parent)
+	if err != nil {
+		return nil, err
+	}
+	root, err := view.GetMerkleRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Execution{
+		Results:    results,
+		TxChanges:  txChanges,
+		Traces:     traces,
+		Changes:    ts.ChangedKeys(),
+		FeeManager: feeManager,
+		Root:       root,
+	}, nil
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/tstate"
+)
+
+// TxTrace records all state operations performed while executing a
+// transaction (see [StatelessBlock.Replay]).
+type TxTrace struct {
+	TxID ids.ID `json:"txId"`
+
+	// FeeOps are the operations performed while checking and deducting the
+	// fee of the transaction.
+	FeeOps  []*tstate.TraceOp `json:"feeOps"`
+	Actions []*ActionTrace    `json:"actions"`
+
+	Result   *Result       `json:"result"`
+	Duration time.Duration `json:"duration"`
+}
+
+// ActionTrace records the operations performed by an action and its outputs.
+//
+// If the action failed, [Outputs] is nil and the operations were reverted.
+type ActionTrace struct {
+	Ops     []*tstate.TraceOp `json:"ops"`
+	Outputs [][]byte          `json:"outputs"`
+}
@@ -295,6 +295,20 @@ func (t *Transaction) Execute(
 	r Rules,
 	ts *tstate.TStateView,
 	timestamp int64,
+) (*Result, error) {
+	return t.execute(ctx, feeManager, s, r, ts, timestamp, nil)
+}
+
+// execute processes [t] and, if [trace] is provided, records the operations
+// performed by each action (tracing must be enabled on [ts]).
+func (t *Transaction) execute(
+	ctx context.Context,
+	feeManager *fees.Manager,
+	s StateManager,
+	r Rules,
+	ts *tstate.TStateView,
+	timestamp int64,
+	trace *TxTrace,
 ) (*Result, error) {
 	// Always charge fee first
 	units, err := t.Units(s, r)
@@ -312,6 +326,9 @@ func (t *Transaction) Execute(
 		// immediately before).
 		return nil, err
 	}
+	if trace != nil {
+		trace.FeeOps = ts.TakeTrace()
+	}
 
 	// We create a temp state checkpoint to ensure we don't commit failed actions to state.
 	//
@@ -323,6 +340,9 @@ func (t *Transaction) Execute(
 	)
 	for i, action := range t.Actions {
 		outputs, err := action.Execute(ctx, r, ts, timestamp, t.Auth.Actor(), CreateActionID(t.ID(), uint8(i)))
+		if trace != nil {
+			trace.Actions = append(trace.Actions, &ActionTrace{Ops: ts.TakeTrace(), Outputs: outputs})
+		}
 		if err != nil {
 			ts.Rollback(ctx, actionStart)
 			return &Result{false, utils.ErrBytes(err), resultOutputs, units, fee}, nil
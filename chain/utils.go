@@ -16,3 +16,21 @@ func CreateActionID(txID ids.ID, i uint8) ids.ID {
 	actionBytes[ids.IDLen] = i
 	return utils.ToID(actionBytes)
 }
+
+// ActionValidRange returns the range scheduled for the [Action] with [typeID]
+// if [r] implements [ScheduledRules]. Otherwise, the [Action] is always valid.
+func ActionValidRange(r Rules, typeID uint8) (int64, int64) {
+	if sr, ok := r.(ScheduledRules); ok {
+		return sr.GetActionValidRange(typeID)
+	}
+	return -1, -1
+}
+
+// AuthValidRange returns the range scheduled for the [Auth] with [typeID] if
+// [r] implements [ScheduledRules]. Otherwise, the [Auth] is always valid.
+func AuthValidRange(r Rules, typeID uint8) (int64, int64) {
+	if sr, ok := r.(ScheduledRules); ok {
+		return sr.GetAuthValidRange(typeID)
+	}
+	return -1, -1
+}
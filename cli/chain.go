@@ -191,6 +191,41 @@ func (h *Handler) PrintChainInfo() error {
 	return nil
 }
 
+// ExportSnapshot writes a snapshot of the default chain at [height] (or at
+// the last accepted block if [height] is nil) to [output]. The snapshot can
+// be used to initialize a new node by setting "snapshotPath" in its chain
+// config.
+func (h *Handler) ExportSnapshot(output string, height *uint64) error {
+	chainID, uris, err := h.GetDefaultChain(true)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(output, os.O_CREATE|os.O_EXCL|os.O_WRONLY, fsModeWrite)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	start := time.Now()
+	snapshotHeight, root, keys, err := rpc.NewJSONRPCClient(uris[0]).Snapshot(context.Background(), height, f)
+	if err != nil {
+		_ = os.Remove(output)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	utils.Outf(
+		"{{green}}exported snapshot of %s to %s{{/}} {{yellow}}height:{{/}} %d {{yellow}}root:{{/}} %s {{yellow}}keys:{{/}} %d {{yellow}}t:{{/}} %v\n",
+		chainID,
+		output,
+		snapshotHeight,
+		root,
+		keys,
+		time.Since(start),
+	)
+	return nil
+}
+
 func (h *Handler) WatchChain(hideTxs bool, getParser func(string, uint32, ids.ID) (chain.Parser, error), handleTx func(*chain.Transaction, *chain.Result)) error {
 	ctx := context.Background()
 	chainID, uris, err := h.PromptChain("select chainID", nil)
@@ -0,0 +1,221 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/programs"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/x/programs/runtime"
+
+	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+)
+
+var _ chain.Action = (*CallProgram)(nil)
+
+type CallProgram struct {
+	// Program is the address of the account to call.
+	Program codec.Address `json:"program"`
+
+	// ProgramID is the program [Program] is expected to be bound to. The call
+	// fails if it is bound to a different program.
+	ProgramID ids.ID `json:"programID"`
+
+	// Function is the name of the program function to call.
+	Function string `json:"function"`
+
+	// Params are the serialized parameters passed to [Function].
+	Params []byte `json:"params"`
+
+	// Fuel is the maximum amount of fuel the call can consume. It is charged
+	// as compute units whether or not it is consumed.
+	Fuel uint64 `json:"fuel"`
+
+	// Keys are the state keys the call may access (in addition to those of
+	// [Program] and [ProgramID]), which can be determined by simulating the
+	// call. Accessing any other key fails the call.
+	Keys []StateKey `json:"keys"`
+}
+
+// StateKey is a state key (and the permissions required to access it)
+// declared by a [CallProgram].
+type StateKey struct {
+	Key         []byte            `json:"key"`
+	Permissions state.Permissions `json:"permissions"`
+}
+
+func (*CallProgram) GetTypeID() uint8 {
+	return mconsts.CallProgramID
+}
+
+func (c *CallProgram) StateKeys(codec.Address, ids.ID) state.Keys {
+	stateKeys := state.Keys{
+		string(chain.HeightKey(storage.HeightKey())): state.Read,
+		string(storage.AccountProgramKey(c.Program)): state.Read,
+		string(storage.ProgramKey(c.ProgramID)):      state.Read,
+	}
+	for _, k := range c.Keys {
+		stateKeys.Add(string(k.Key), k.Permissions)
+	}
+	return stateKeys
+}
+
+func (c *CallProgram) StateKeysMaxChunks() []uint16 {
+	chunks := make([]uint16, 0, 3+len(c.Keys))
+	chunks = append(chunks, chain.HeightKeyChunks, storage.AccountProgramChunks, storage.ProgramChunks)
+	for _, k := range c.Keys {
+		keyChunks, _ := keys.MaxChunks(k.Key)
+		chunks = append(chunks, keyChunks)
+	}
+	return chunks
+}
+
+func (c *CallProgram) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	actionID ids.ID,
+) ([][]byte, error) {
+	return c.ExecuteWith(ctx, programs.Runtime(), r, mu, timestamp, actor, actionID)
+}
+
+// ExecuteWith is like [Execute] but calls the program with [rt] (i.e. to
+// simulate calls with [programs.ReadOnlyRuntime]).
+func (c *CallProgram) ExecuteWith(
+	ctx context.Context,
+	rt *runtime.WasmRuntime,
+	r chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	actionID ids.ID,
+) ([][]byte, error) {
+	if len(c.Function) == 0 {
+		return nil, ErrOutputFunctionEmpty
+	}
+	if c.Fuel == 0 {
+		return nil, ErrOutputFuelZero
+	}
+	programID, exists, err := storage.GetAccountProgram(ctx, mu, c.Program)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, programs.ErrUnknownAccount
+	}
+	if programID != c.ProgramID {
+		return nil, ErrOutputProgramMismatch
+	}
+	// Transactions are executed on top of the parent block, so the height key
+	// holds the parent height.
+	parentHeight, err := mu.GetValue(ctx, chain.HeightKey(storage.HeightKey()))
+	if err != nil {
+		return nil, err
+	}
+	callInfo := &runtime.CallInfo{
+		State:        programs.NewStateManager(mu),
+		Actor:        actor,
+		FunctionName: c.Function,
+		Program:      c.Program,
+		Params:       c.Params,
+		Fuel:         c.Fuel,
+		Height:       binary.BigEndian.Uint64(parentHeight) + 1,
+		Timestamp:    uint64(timestamp),
+		ActionID:     actionID,
+	}
+	result, err := rt.CallProgram(ctx, callInfo)
+	if err != nil {
+		return nil, err
+	}
+	// The result and the events emitted by the call are returned together so
+	// that a single output is enough
+	output, err := (&CallProgramResult{Result: result, Events: callInfo.Events}).Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{output}, nil
+}
+
+// CallProgramResult is the output of a successful [CallProgram].
+type CallProgramResult struct {
+	// Result is the value returned by the called function.
+	Result []byte `json:"result"`
+
+	// Events are the events emitted by the call (and any calls it made).
+	Events []runtime.Event `json:"events"`
+}
+
+func UnmarshalCallProgramResult(b []byte) (*CallProgramResult, error) {
+	return runtime.Deserialize[CallProgramResult](b)
+}
+
+func (c *CallProgramResult) Marshal() ([]byte, error) {
+	return runtime.Serialize(*c)
+}
+
+func (c *CallProgram) ComputeUnits(chain.Rules) uint64 {
+	return CallProgramComputeUnits + c.Fuel/ProgramFuelPerComputeUnit
+}
+
+func (c *CallProgram) Size() int {
+	size := codec.AddressLen + ids.IDLen + codec.StringLen(c.Function) + codec.BytesLen(c.Params) + consts.Uint64Len + consts.IntLen
+	for _, k := range c.Keys {
+		size += codec.BytesLen(k.Key) + consts.ByteLen
+	}
+	return size
+}
+
+func (c *CallProgram) Marshal(p *codec.Packer) {
+	p.PackAddress(c.Program)
+	p.PackID(c.ProgramID)
+	p.PackString(c.Function)
+	p.PackBytes(c.Params)
+	p.PackUint64(c.Fuel)
+	p.PackInt(len(c.Keys))
+	for _, k := range c.Keys {
+		p.PackBytes(k.Key)
+		p.PackByte(byte(k.Permissions))
+	}
+}
+
+func UnmarshalCallProgram(p *codec.Packer) (chain.Action, error) {
+	var call CallProgram
+	p.UnpackAddress(&call.Program)
+	p.UnpackID(true, &call.ProgramID)
+	call.Function = p.UnpackString(true)
+	p.UnpackBytes(MaxProgramParamsSize, false, &call.Params)
+	call.Fuel = p.UnpackUint64(true)
+	numKeys := p.UnpackInt(false)
+	if numKeys > MaxCallProgramKeys {
+		return nil, ErrOutputTooManyStateKeys
+	}
+	call.Keys = make([]StateKey, numKeys)
+	for i := range call.Keys {
+		k := &call.Keys[i]
+		p.UnpackBytes(MaxCallProgramKeySize, true, &k.Key)
+		k.Permissions = state.Permissions(p.UnpackByte())
+		if !keys.Valid(string(k.Key)) || k.Permissions == state.None || !state.All.Has(k.Permissions) {
+			return nil, ErrOutputInvalidStateKey
+		}
+	}
+	if len(call.Function) > MaxProgramFunctionSize {
+		return nil, ErrOutputFunctionTooLarge
+	}
+	return &call, p.Err()
+}
+
+func (c *CallProgram) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, c.GetTypeID())
+}
@@ -3,7 +3,24 @@
 
 package actions
 
+import "github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+
 const (
-	TransferComputeUnits = 1
-	MaxMemoSize          = 256
+	TransferComputeUnits       = 1
+	PublishProgramComputeUnits = 5
+	DeployProgramComputeUnits  = 2
+	CallProgramComputeUnits    = 5 // plus 1 per [ProgramFuelPerComputeUnit] of fuel
+	UpgradeProgramComputeUnits = 5 // plus 1 per [ProgramFuelPerComputeUnit] of fuel
+
+	// ProgramFuelPerComputeUnit is the amount of fuel a program call can
+	// consume per compute unit charged.
+	ProgramFuelPerComputeUnit = 10_000
+
+	MaxMemoSize                = 256
+	MaxProgramSize             = storage.MaxProgramSize
+	MaxProgramCreationDataSize = 256
+	MaxProgramFunctionSize     = 256
+	MaxProgramParamsSize       = 8_192
+	MaxCallProgramKeys         = 64
+	MaxCallProgramKeySize      = 512
 )
@@ -0,0 +1,91 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/programs"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+)
+
+var _ chain.Action = (*DeployProgram)(nil)
+
+type DeployProgram struct {
+	// ProgramID is the ID of a published program.
+	ProgramID ids.ID `json:"programID"`
+
+	// CreationData distinguishes accounts deployed with the same program
+	// (the account address is derived from [ProgramID] and [CreationData]).
+	CreationData []byte `json:"creationData"`
+}
+
+func (*DeployProgram) GetTypeID() uint8 {
+	return mconsts.DeployProgramID
+}
+
+// Address returns the address of the account created by [d].
+func (d *DeployProgram) Address() codec.Address {
+	return programs.ProgramAddress(d.ProgramID, d.CreationData)
+}
+
+func (d *DeployProgram) StateKeys(codec.Address, ids.ID) state.Keys {
+	return state.Keys{
+		string(storage.ProgramKey(d.ProgramID)):        state.Read,
+		string(storage.AccountProgramKey(d.Address())): state.Allocate | state.Write,
+	}
+}
+
+func (*DeployProgram) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ProgramChunks, storage.AccountProgramChunks}
+}
+
+func (d *DeployProgram) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	_ codec.Address,
+	_ ids.ID,
+) ([][]byte, error) {
+	if len(d.CreationData) > MaxProgramCreationDataSize {
+		return nil, ErrOutputCreationDataTooLarge
+	}
+	account, err := programs.NewStateManager(mu).NewAccountWithProgram(ctx, d.ProgramID, d.CreationData)
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{account[:]}, nil
+}
+
+func (*DeployProgram) ComputeUnits(chain.Rules) uint64 {
+	return DeployProgramComputeUnits
+}
+
+func (d *DeployProgram) Size() int {
+	return ids.IDLen + codec.BytesLen(d.CreationData)
+}
+
+func (d *DeployProgram) Marshal(p *codec.Packer) {
+	p.PackID(d.ProgramID)
+	p.PackBytes(d.CreationData)
+}
+
+func UnmarshalDeployProgram(p *codec.Packer) (chain.Action, error) {
+	var deploy DeployProgram
+	p.UnpackID(true, &deploy.ProgramID)
+	p.UnpackBytes(MaxProgramCreationDataSize, false, &deploy.CreationData)
+	return &deploy, p.Err()
+}
+
+func (d *DeployProgram) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, d.GetTypeID())
+}
@@ -6,6 +6,15 @@ package actions
 import "errors"
 
 var (
-	ErrOutputValueZero    = errors.New("value is zero")
-	ErrOutputMemoTooLarge = errors.New("memo is too large")
+	ErrOutputValueZero            = errors.New("value is zero")
+	ErrOutputMemoTooLarge         = errors.New("memo is too large")
+	ErrOutputProgramEmpty         = errors.New("program is empty")
+	ErrOutputProgramTooLarge      = errors.New("program is too large")
+	ErrOutputProgramMismatch      = errors.New("account is bound to a different program")
+	ErrOutputCreationDataTooLarge = errors.New("creation data is too large")
+	ErrOutputFunctionEmpty        = errors.New("function is empty")
+	ErrOutputFunctionTooLarge     = errors.New("function is too large")
+	ErrOutputFuelZero             = errors.New("fuel is zero")
+	ErrOutputTooManyStateKeys     = errors.New("too many state keys")
+	ErrOutputInvalidStateKey      = errors.New("invalid state key")
 )
@@ -0,0 +1,290 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/bytecodealliance/wasmtime-go/v14"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/programs"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/tstate"
+	"github.com/ava-labs/hypersdk/x/programs/runtime"
+	"github.com/ava-labs/hypersdk/x/programs/test"
+)
+
+// testProgram stores "k" => "v" when "put" is called, returns the height and
+// timestamp it was called with when "context" is called and emits a "t" => "d"
+// event when "emit" is called.
+const testProgram = `
+(module
+  (import "state" "put" (func $put (param i32 i32)))
+  (import "program" "set_call_result" (func $set_call_result (param i32 i32)))
+  (import "events" "emit" (func $emit (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $next (mut i32) (i32.const 1024))
+  (data (i32.const 8) "\01\00\00\00\01\00\00\00k\01\00\00\00v")
+  (data (i32.const 32) "ok")
+  (data (i32.const 40) "\01\00\00\00t\01\00\00\00d")
+  (func (export "alloc") (param $size i32) (result i32)
+    (local $ptr i32)
+    (local.set $ptr (global.get $next))
+    (global.set $next (i32.add (global.get $next) (local.get $size)))
+    (local.get $ptr))
+  (func (export "put") (param i32)
+    (call $put (i32.const 8) (i32.const 14))
+    (call $set_call_result (i32.const 32) (i32.const 2)))
+  (func (export "context") (param $ctx i32)
+    ;; skip the program and actor addresses
+    (call $set_call_result (i32.add (local.get $ctx) (i32.const 66)) (i32.const 16)))
+  (func (export "emit") (param i32)
+    (call $emit (i32.const 40) (i32.const 10))
+    (call $set_call_result (i32.const 32) (i32.const 2))))
+`
+
+// migratingProgram stores "k" => "m" when it is migrated to.
+const migratingProgram = `
+(module
+  (import "state" "put" (func $put (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $next (mut i32) (i32.const 1024))
+  (data (i32.const 8) "\01\00\00\00\01\00\00\00k\01\00\00\00m")
+  (func (export "alloc") (param $size i32) (result i32)
+    (local $ptr i32)
+    (local.set $ptr (global.get $next))
+    (global.set $next (i32.add (global.get $next) (local.get $size)))
+    (local.get $ptr))
+  (func (export "migrate") (param i32)
+    (call $put (i32.const 8) (i32.const 14))))
+`
+
+// execute runs [action] with the default rules in a view that can only access
+// its declared keys and commits the changes to [db].
+func execute(t *testing.T, db *test.DB, action chain.Action, timestamp int64) ([][]byte, error) {
+	return executeAs(t, db, genesis.Default().Rules(timestamp, 0, ids.Empty), action, timestamp, codec.EmptyAddress)
+}
+
+// executeAs is like [execute] with [r] as the rules and [actor] as the actor
+// of [action].
+func executeAs(t *testing.T, db *test.DB, r chain.Rules, action chain.Action, timestamp int64, actor codec.Address) ([][]byte, error) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	keys := action.StateKeys(actor, ids.Empty)
+	values := map[string][]byte{}
+	for k := range keys {
+		v, err := db.GetValue(ctx, []byte(k))
+		if errors.Is(err, database.ErrNotFound) {
+			continue
+		}
+		require.NoError(err)
+		values[k] = v
+	}
+	ts := tstate.New(len(keys))
+	view := ts.NewView(keys, values)
+	outputs, err := action.Execute(ctx, r, view, timestamp, actor, ids.Empty)
+	if err != nil {
+		return nil, err
+	}
+	view.Commit()
+	for k, v := range ts.ChangedKeys() {
+		if v.IsNothing() {
+			require.NoError(db.Remove(ctx, []byte(k)))
+			continue
+		}
+		require.NoError(db.Insert(ctx, []byte(k), v.Value()))
+	}
+	return outputs, nil
+}
+
+func TestProgramActions(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	db := test.NewTestDB()
+	require.NoError(db.Insert(ctx, chain.HeightKey(storage.HeightKey()), binary.BigEndian.AppendUint64(nil, 9)))
+	program, err := wasmtime.Wat2Wasm(testProgram)
+	require.NoError(err)
+
+	// Deploying requires the program to be published
+	deploy := &DeployProgram{ProgramID: ids.GenerateTestID()}
+	_, err = execute(t, db, deploy, 0)
+	require.ErrorIs(err, programs.ErrUnknownProgram)
+
+	// Programs the runtime can't call can't be published
+	invalid, err := wasmtime.Wat2Wasm(`(module (memory (export "memory") 1))`)
+	require.NoError(err)
+	_, err = execute(t, db, &PublishProgram{Program: invalid}, 0)
+	require.ErrorIs(err, runtime.ErrMissingExport)
+
+	publish := &PublishProgram{Program: program}
+	outputs, err := execute(t, db, publish, 0)
+	require.NoError(err)
+	programID := publish.ProgramID()
+	require.Equal([][]byte{programID[:]}, outputs)
+
+	deploy = &DeployProgram{ProgramID: programID, CreationData: []byte{1}}
+	outputs, err = execute(t, db, deploy, 0)
+	require.NoError(err)
+	account := deploy.Address()
+	require.Equal([][]byte{account[:]}, outputs)
+	accountProgram, exists, err := storage.GetAccountProgram(ctx, db, account)
+	require.NoError(err)
+	require.True(exists)
+	require.Equal(programID, accountProgram)
+
+	// Accounts can't be deployed twice
+	_, err = execute(t, db, deploy, 0)
+	require.ErrorIs(err, programs.ErrAccountExists)
+
+	// The call must target the program the account is bound to
+	call := &CallProgram{
+		Program:   account,
+		ProgramID: ids.GenerateTestID(),
+		Function:  "put",
+		Fuel:      1_000_000,
+	}
+	_, err = execute(t, db, call, 0)
+	require.ErrorIs(err, ErrOutputProgramMismatch)
+
+	// Undeclared state keys can't be accessed
+	call.ProgramID = programID
+	_, err = execute(t, db, call, 0)
+	require.Error(err)
+
+	// Simulating the call records the keys it must declare
+	stateKey := storage.ProgramStateKey(account, []byte("k"))
+	rules := genesis.Default().Rules(0, 0, ids.Empty)
+	recorder := state.NewRecorder(test.NewTestDB())
+	_, err = call.Execute(ctx, rules, recorder, 0, codec.EmptyAddress, ids.Empty)
+	require.ErrorIs(err, programs.ErrUnknownAccount)
+	recorder = state.NewRecorder(db)
+	outputs, err = call.Execute(ctx, rules, recorder, 0, codec.EmptyAddress, ids.Empty)
+	require.NoError(err)
+	require.Equal([]byte("ok"), callResult(t, outputs).Result)
+	require.Equal(state.All, recorder.Keys()[string(stateKey)])
+	require.NoError(db.Remove(ctx, stateKey))
+
+	call.Keys = []StateKey{{Key: stateKey, Permissions: state.All}}
+	outputs, err = execute(t, db, call, 0)
+	require.NoError(err)
+	require.Equal([]byte("ok"), callResult(t, outputs).Result)
+	v, err := db.GetValue(ctx, stateKey)
+	require.NoError(err)
+	require.Equal([]byte("v"), v)
+
+	// Programs are called with the height of the block being built
+	call.Function = "context"
+	outputs, err = execute(t, db, call, 1_000)
+	require.NoError(err)
+	result := callResult(t, outputs).Result
+	require.Equal(uint64(10), binary.LittleEndian.Uint64(result[:8]))
+	require.Equal(uint64(1_000), binary.LittleEndian.Uint64(result[8:]))
+
+	// Events are returned with the result (in a single output, which is all
+	// the default genesis allows)
+	call.Function = "emit"
+	outputs, err = execute(t, db, call, 0)
+	require.NoError(err)
+	require.Equal(&CallProgramResult{
+		Result: []byte("ok"),
+		Events: []runtime.Event{{Program: account, Topic: "t", Data: []byte("d")}},
+	}, callResult(t, outputs))
+}
+
+// callResult returns the [CallProgramResult] of the [outputs] of a
+// [CallProgram].
+func callResult(t *testing.T, outputs [][]byte) *CallProgramResult {
+	require := require.New(t)
+	require.Len(outputs, 1)
+	result, err := UnmarshalCallProgramResult(outputs[0])
+	require.NoError(err)
+	return result
+}
+
+func TestUpgradeProgram(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	db := test.NewTestDB()
+	require.NoError(db.Insert(ctx, chain.HeightKey(storage.HeightKey()), binary.BigEndian.AppendUint64(nil, 9)))
+	program, err := wasmtime.Wat2Wasm(testProgram)
+	require.NoError(err)
+	newProgram, err := wasmtime.Wat2Wasm(migratingProgram)
+	require.NoError(err)
+
+	publish := &PublishProgram{Program: program}
+	_, err = execute(t, db, publish, 0)
+	require.NoError(err)
+	programID := publish.ProgramID()
+	publish = &PublishProgram{Program: newProgram}
+	_, err = execute(t, db, publish, 0)
+	require.NoError(err)
+	newProgramID := publish.ProgramID()
+	deploy := &DeployProgram{ProgramID: programID}
+	_, err = execute(t, db, deploy, 0)
+	require.NoError(err)
+	account := deploy.Address()
+
+	rules := genesis.Default().Rules(0, 0, ids.Empty)
+	stateKey := storage.ProgramStateKey(account, []byte("k"))
+	upgrade := &UpgradeProgram{
+		Program:      account,
+		ProgramID:    programID,
+		NewProgramID: newProgramID,
+		Fuel:         1_000_000,
+		Keys:         []StateKey{{Key: stateKey, Permissions: state.All}},
+	}
+
+	// Accounts without an admin can't be upgraded
+	admin := codec.CreateAddress(0, ids.GenerateTestID())
+	_, err = executeAs(t, db, rules, upgrade, 0, admin)
+	require.ErrorIs(err, runtime.ErrUnauthorized)
+
+	// Only the admin can upgrade an account
+	require.NoError(storage.SetAccountAdmin(ctx, db, account, admin))
+	_, err = executeAs(t, db, rules, upgrade, 0, codec.CreateAddress(0, ids.GenerateTestID()))
+	require.ErrorIs(err, runtime.ErrUnauthorized)
+
+	// The upgrade must target the program the account is bound to
+	upgrade.ProgramID = newProgramID
+	_, err = executeAs(t, db, rules, upgrade, 0, admin)
+	require.ErrorIs(err, ErrOutputProgramMismatch)
+
+	// The account is migrated and bound to the new program
+	upgrade.ProgramID = programID
+	outputs, err := executeAs(t, db, rules, upgrade, 0, admin)
+	require.NoError(err)
+	accountProgram, _, err := storage.GetAccountProgram(ctx, db, account)
+	require.NoError(err)
+	require.Equal(newProgramID, accountProgram)
+	v, err := db.GetValue(ctx, stateKey)
+	require.NoError(err)
+	require.Equal([]byte("m"), v)
+
+	require.Len(outputs, 1)
+	events, err := runtime.Deserialize[[]runtime.Event](outputs[0])
+	require.NoError(err)
+	require.Len(*events, 1)
+	require.Equal(runtime.UpgradeEventTopic, (*events)[0].Topic)
+	event, err := runtime.Deserialize[runtime.UpgradeEvent]((*events)[0].Data)
+	require.NoError(err)
+	require.Equal(runtime.UpgradeEvent{
+		Account:      account,
+		Actor:        admin,
+		ProgramID:    programID,
+		NewProgramID: newProgramID,
+	}, *event)
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/programs"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+
+	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+)
+
+var _ chain.Action = (*PublishProgram)(nil)
+
+type PublishProgram struct {
+	// Program is the wasm bytecode of the program. It is stored under its
+	// hash (the program ID) and can be deployed any number of times.
+	Program []byte `json:"program"`
+
+	// programID is populated lazily.
+	programID ids.ID
+}
+
+func (*PublishProgram) GetTypeID() uint8 {
+	return mconsts.PublishProgramID
+}
+
+// ProgramID returns the ID [p.Program] is stored under.
+func (p *PublishProgram) ProgramID() ids.ID {
+	if p.programID == ids.Empty {
+		p.programID = utils.ToID(p.Program)
+	}
+	return p.programID
+}
+
+func (p *PublishProgram) StateKeys(codec.Address, ids.ID) state.Keys {
+	return state.Keys{
+		string(storage.ProgramKey(p.ProgramID())): state.Allocate | state.Write,
+	}
+}
+
+func (*PublishProgram) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.ProgramChunks}
+}
+
+func (p *PublishProgram) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	_ codec.Address,
+	_ ids.ID,
+) ([][]byte, error) {
+	if len(p.Program) == 0 {
+		return nil, ErrOutputProgramEmpty
+	}
+	if len(p.Program) > MaxProgramSize {
+		return nil, ErrOutputProgramTooLarge
+	}
+	// Rejecting programs the runtime can't call here is much cheaper than
+	// failing every call to them.
+	if err := programs.Runtime().ValidateProgram(p.Program); err != nil {
+		return nil, err
+	}
+	programID := p.ProgramID()
+	// Programs are content-addressed, so publishing the same program again
+	// leaves state unchanged.
+	if err := storage.SetProgram(ctx, mu, programID, p.Program); err != nil {
+		return nil, err
+	}
+	return [][]byte{programID[:]}, nil
+}
+
+func (*PublishProgram) ComputeUnits(chain.Rules) uint64 {
+	return PublishProgramComputeUnits
+}
+
+func (p *PublishProgram) Size() int {
+	return codec.BytesLen(p.Program)
+}
+
+func (p *PublishProgram) Marshal(packer *codec.Packer) {
+	packer.PackBytes(p.Program)
+}
+
+func UnmarshalPublishProgram(p *codec.Packer) (chain.Action, error) {
+	var publish PublishProgram
+	p.UnpackBytes(MaxProgramSize, true, &publish.Program)
+	return &publish, p.Err()
+}
+
+func (p *PublishProgram) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, p.GetTypeID())
+}
@@ -0,0 +1,24 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/x/staking"
+
+	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+)
+
+// Staking provides the staking actions of morpheusvm (see [x/staking]). The
+// stake requirements are kept low so that the module is easy to try out on
+// local networks.
+var Staking = staking.New(staking.Config{
+	Prefix:            storage.StakingPrefix,
+	FirstActionID:     mconsts.StakingFirstActionID,
+	MinValidatorStake: 1_000_000, // 0.001 RED
+	MinDelegatorStake: 10_000,    // 0.00001 RED
+	UnbondingPeriod:   60 * consts.MillisecondsPerSecond,
+	RewardShare:       5_000, // 50% of fees
+}, &storage.StakingBalances{})
@@ -0,0 +1,188 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/programs"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/keys"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/x/programs/runtime"
+
+	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+)
+
+var _ chain.Action = (*UpgradeProgram)(nil)
+
+// UpgradeProgram binds an account to a different program on behalf of its
+// admin (the actor of the transaction must be the admin of the account).
+type UpgradeProgram struct {
+	// Program is the address of the account to upgrade.
+	Program codec.Address `json:"program"`
+
+	// ProgramID is the program [Program] is expected to be bound to. The
+	// upgrade fails if it is bound to a different program.
+	ProgramID ids.ID `json:"programID"`
+
+	// NewProgramID is the ID of the published program to bind [Program] to.
+	NewProgramID ids.ID `json:"newProgramID"`
+
+	// Params are the serialized parameters passed to the migration function
+	// of [NewProgramID] (if it exports one).
+	Params []byte `json:"params"`
+
+	// Fuel is the maximum amount of fuel the migration can consume. It is
+	// charged as compute units whether or not it is consumed.
+	Fuel uint64 `json:"fuel"`
+
+	// Keys are the state keys the migration may access (see
+	// [CallProgram.Keys]).
+	Keys []StateKey `json:"keys"`
+}
+
+func (*UpgradeProgram) GetTypeID() uint8 {
+	return mconsts.UpgradeProgramID
+}
+
+func (u *UpgradeProgram) StateKeys(codec.Address, ids.ID) state.Keys {
+	stateKeys := state.Keys{
+		string(chain.HeightKey(storage.HeightKey())): state.Read,
+		string(storage.AccountProgramKey(u.Program)): state.Read | state.Write,
+		string(storage.AccountAdminKey(u.Program)):   state.Read,
+		string(storage.ProgramKey(u.ProgramID)):      state.Read,
+		string(storage.ProgramKey(u.NewProgramID)):   state.Read,
+	}
+	for _, k := range u.Keys {
+		stateKeys.Add(string(k.Key), k.Permissions)
+	}
+	return stateKeys
+}
+
+func (u *UpgradeProgram) StateKeysMaxChunks() []uint16 {
+	chunks := make([]uint16, 0, 5+len(u.Keys))
+	chunks = append(
+		chunks,
+		chain.HeightKeyChunks,
+		storage.AccountProgramChunks,
+		storage.AccountAdminChunks,
+		storage.ProgramChunks,
+		storage.ProgramChunks,
+	)
+	for _, k := range u.Keys {
+		keyChunks, _ := keys.MaxChunks(k.Key)
+		chunks = append(chunks, keyChunks)
+	}
+	return chunks
+}
+
+func (u *UpgradeProgram) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	actionID ids.ID,
+) ([][]byte, error) {
+	programID, exists, err := storage.GetAccountProgram(ctx, mu, u.Program)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, programs.ErrUnknownAccount
+	}
+	if programID != u.ProgramID {
+		return nil, ErrOutputProgramMismatch
+	}
+	admin, err := storage.GetAccountAdmin(ctx, mu, u.Program)
+	if err != nil {
+		return nil, err
+	}
+	if admin == codec.EmptyAddress || admin != actor {
+		return nil, runtime.ErrUnauthorized
+	}
+	parentHeight, err := mu.GetValue(ctx, chain.HeightKey(storage.HeightKey()))
+	if err != nil {
+		return nil, err
+	}
+	callInfo := &runtime.CallInfo{
+		State:     programs.NewStateManager(mu),
+		Actor:     actor,
+		Program:   u.Program,
+		Params:    u.Params,
+		Fuel:      u.Fuel,
+		Height:    binary.BigEndian.Uint64(parentHeight) + 1,
+		Timestamp: uint64(timestamp),
+		ActionID:  actionID,
+	}
+	if err := programs.Runtime().UpgradeProgram(ctx, callInfo, u.NewProgramID); err != nil {
+		return nil, err
+	}
+	// The events emitted by the upgrade (including the [runtime.UpgradeEvent])
+	// are returned as the output
+	events, err := runtime.Serialize(callInfo.Events)
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{events}, nil
+}
+
+func (u *UpgradeProgram) ComputeUnits(chain.Rules) uint64 {
+	return UpgradeProgramComputeUnits + u.Fuel/ProgramFuelPerComputeUnit
+}
+
+func (u *UpgradeProgram) Size() int {
+	size := codec.AddressLen + 2*ids.IDLen + codec.BytesLen(u.Params) + consts.Uint64Len + consts.IntLen
+	for _, k := range u.Keys {
+		size += codec.BytesLen(k.Key) + consts.ByteLen
+	}
+	return size
+}
+
+func (u *UpgradeProgram) Marshal(p *codec.Packer) {
+	p.PackAddress(u.Program)
+	p.PackID(u.ProgramID)
+	p.PackID(u.NewProgramID)
+	p.PackBytes(u.Params)
+	p.PackUint64(u.Fuel)
+	p.PackInt(len(u.Keys))
+	for _, k := range u.Keys {
+		p.PackBytes(k.Key)
+		p.PackByte(byte(k.Permissions))
+	}
+}
+
+func UnmarshalUpgradeProgram(p *codec.Packer) (chain.Action, error) {
+	var upgrade UpgradeProgram
+	p.UnpackAddress(&upgrade.Program)
+	p.UnpackID(true, &upgrade.ProgramID)
+	p.UnpackID(true, &upgrade.NewProgramID)
+	p.UnpackBytes(MaxProgramParamsSize, false, &upgrade.Params)
+	upgrade.Fuel = p.UnpackUint64(false)
+	numKeys := p.UnpackInt(false)
+	if numKeys > MaxCallProgramKeys {
+		return nil, ErrOutputTooManyStateKeys
+	}
+	upgrade.Keys = make([]StateKey, numKeys)
+	for i := range upgrade.Keys {
+		k := &upgrade.Keys[i]
+		p.UnpackBytes(MaxCallProgramKeySize, true, &k.Key)
+		k.Permissions = state.Permissions(p.UnpackByte())
+		if !keys.Valid(string(k.Key)) || k.Permissions == state.None || !state.All.Has(k.Permissions) {
+			return nil, ErrOutputInvalidStateKey
+		}
+	}
+	return &upgrade, p.Err()
+}
+
+func (u *UpgradeProgram) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, u.GetTypeID())
+}
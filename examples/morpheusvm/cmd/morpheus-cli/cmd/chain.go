@@ -71,3 +71,22 @@ var watchChainCmd = &cobra.Command{
 		}, handleTx)
 	},
 }
+
+var snapshotChainCmd = &cobra.Command{
+	Use:   "snapshot [output file] [options]",
+	Short: "Exports a snapshot of the default chain that can be imported by a new node",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		var height *uint64
+		if snapshotHeight >= 0 {
+			h := uint64(snapshotHeight)
+			height = &h
+		}
+		return handler.Root().ExportSnapshot(args[0], height)
+	},
+}
@@ -77,9 +77,17 @@ func handleTx(tx *chain.Transaction, result *chain.Result) {
 
 	for _, action := range tx.Actions {
 		var summaryStr string
-		switch act := action.(type) { //nolint:gocritic
+		switch act := action.(type) {
 		case *actions.Transfer:
 			summaryStr = fmt.Sprintf("%s %s -> %s\n", utils.FormatBalance(act.Value, consts.Decimals), consts.Symbol, codec.MustAddressBech32(consts.HRP, act.To))
+		case *actions.PublishProgram:
+			summaryStr = fmt.Sprintf("programID: %s size: %d", act.ProgramID(), len(act.Program))
+		case *actions.DeployProgram:
+			summaryStr = fmt.Sprintf("programID: %s -> %s", act.ProgramID, codec.MustAddressBech32(consts.HRP, act.Address()))
+		case *actions.CallProgram:
+			summaryStr = fmt.Sprintf("%s.%s fuel: %d", codec.MustAddressBech32(consts.HRP, act.Program), act.Function, act.Fuel)
+		case *actions.UpgradeProgram:
+			summaryStr = fmt.Sprintf("%s programID: %s -> %s", codec.MustAddressBech32(consts.HRP, act.Program), act.ProgramID, act.NewProgramID)
 		}
 		utils.Outf(
 			"%s {{yellow}}%s{{/}} {{yellow}}actor:{{/}} %s {{yellow}}summary (%s):{{/}} [%s] {{yellow}}fee (max %.2f%%):{{/}} %s %s {{yellow}}consumed:{{/}} [%s]\n",
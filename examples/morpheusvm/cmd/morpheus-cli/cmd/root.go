@@ -35,6 +35,7 @@ var (
 	prometheusFile        string
 	prometheusData        string
 	startPrometheus       bool
+	snapshotHeight        int64
 
 	rootCmd = &cobra.Command{
 		Use:        "morpheus-cli",
@@ -130,6 +131,12 @@ func init() {
 		false,
 		"hide txs",
 	)
+	snapshotChainCmd.PersistentFlags().Int64Var(
+		&snapshotHeight,
+		"height",
+		-1,
+		"height to snapshot (defaults to the last accepted height)",
+	)
 	chainCmd.AddCommand(
 		importChainCmd,
 		importANRChainCmd,
@@ -137,6 +144,7 @@ func init() {
 		setChainCmd,
 		chainInfoCmd,
 		watchChainCmd,
+		snapshotChainCmd,
 	)
 
 	// actions
@@ -15,6 +15,7 @@ import (
 
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/cmd/morpheusvm/version"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/controller"
+	"github.com/ava-labs/hypersdk/vm/replay"
 )
 
 var rootCmd = &cobra.Command{
@@ -31,6 +32,8 @@ func init() {
 func init() {
 	rootCmd.AddCommand(
 		version.NewCommand(),
+		replay.NewCommand(controller.New),
+		replay.NewExportSnapshotCommand(controller.New),
 	)
 }
 
@@ -5,20 +5,43 @@ package config
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
 )
 
 type Config struct {
 	StoreTransactions bool          `json:"storeTransactions"`
 	TestMode          bool          `json:"testMode"` // makes gossip/building manual
 	LogLevel          logging.Level `json:"logLevel"`
+
+	// ProgramCacheSize is the size (in bytes) of the cache of compiled
+	// programs.
+	ProgramCacheSize int `json:"programCacheSize"`
+	// PersistPrograms persists compiled programs to the chain data directory
+	// so that they don't need to be compiled again after a restart.
+	PersistPrograms bool `json:"persistPrograms"`
+	// MaxReadOnlyFuel is the maximum fuel provided to read-only program calls
+	// (which are executed by the node for free).
+	MaxReadOnlyFuel uint64 `json:"maxReadOnlyFuel"`
+	// ReadOnlyTimeout is the maximum duration of read-only program calls
+	// (including simulations), after which they are interrupted.
+	ReadOnlyTimeout time.Duration `json:"readOnlyTimeout"`
+	// MaxReadOnlyCalls is the maximum number of read-only program calls
+	// (including simulations) executed at once.
+	MaxReadOnlyCalls int `json:"maxReadOnlyCalls"`
 }
 
 func New(b []byte) (*Config, error) {
 	c := &Config{
 		StoreTransactions: true,
 		LogLevel:          logging.Info,
+		ProgramCacheSize:  10 * units.MiB,
+		PersistPrograms:   true,
+		MaxReadOnlyFuel:   10_000_000,
+		ReadOnlyTimeout:   time.Second,
+		MaxReadOnlyCalls:  8,
 	}
 
 	if len(b) > 0 {
@@ -5,5 +5,19 @@ package consts
 
 const (
 	// Action TypeIDs
-	TransferID uint8 = 0
+	TransferID       uint8 = 0
+	PublishProgramID uint8 = 1
+	DeployProgramID  uint8 = 2
+	CallProgramID    uint8 = 3
+	UpgradeProgramID uint8 = 4
+
+	// StakingFirstActionID is the TypeID of the first action of
+	// [actions.Staking] (which uses 5-9)
+	StakingFirstActionID uint8 = 5
+)
+
+const (
+	// Address TypeID of accounts created by deploying a program (auth TypeIDs
+	// are used for all other addresses)
+	ProgramAddressID uint8 = 255
 )
@@ -19,9 +19,17 @@ type actionHandler struct {
 
 func (a *actionHandler) Accepted(_ context.Context, tx *chain.Transaction, _ *chain.Result) error {
 	for _, action := range tx.Actions {
-		switch action.(type) { //nolint:gocritic
+		switch action.(type) {
 		case *actions.Transfer:
 			a.c.metrics.transfer.Inc()
+		case *actions.PublishProgram:
+			a.c.metrics.publishProgram.Inc()
+		case *actions.DeployProgram:
+			a.c.metrics.deployProgram.Inc()
+		case *actions.CallProgram:
+			a.c.metrics.callProgram.Inc()
+		case *actions.UpgradeProgram:
+			a.c.metrics.upgradeProgram.Inc()
 		}
 	}
 	return nil
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"path/filepath"
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/snow"
@@ -15,9 +16,11 @@ import (
 	"github.com/ava-labs/hypersdk/auth"
 	"github.com/ava-labs/hypersdk/builder"
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/config"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/programs"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/rpc"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/version"
@@ -25,6 +28,7 @@ import (
 	"github.com/ava-labs/hypersdk/gossiper"
 	"github.com/ava-labs/hypersdk/pebble"
 	"github.com/ava-labs/hypersdk/vm"
+	"github.com/ava-labs/hypersdk/x/programs/runtime"
 
 	ametrics "github.com/ava-labs/avalanchego/api/metrics"
 	hrpc "github.com/ava-labs/hypersdk/rpc"
@@ -39,10 +43,13 @@ type Controller struct {
 	snowCtx      *snow.Context
 	genesis      *genesis.Genesis
 	config       *config.Config
-	stateManager *storage.StateManager
+	stateManager chain.StateManager
 
 	metrics *metrics
 
+	// readOnlyCalls holds a token for each read-only call being executed
+	readOnlyCalls chan struct{}
+
 	txDB               database.Database
 	txIndexer          indexer.TxIndexer
 	acceptedSubscriber indexer.AcceptedSubscriber
@@ -57,7 +64,7 @@ func (c *Controller) Initialize(
 	snowCtx *snow.Context,
 	gatherer ametrics.MultiGatherer,
 	genesisBytes []byte,
-	upgradeBytes []byte, // subnets to allow for AWM
+	upgradeBytes []byte, // scheduled rule changes (see [genesis.Genesis.SetUpgrades])
 	configBytes []byte,
 ) (
 	vm.Genesis,
@@ -71,7 +78,7 @@ func (c *Controller) Initialize(
 ) {
 	c.inner = inner
 	c.snowCtx = snowCtx
-	c.stateManager = &storage.StateManager{}
+	c.stateManager = actions.Staking.StateManager(&storage.StateManager{})
 
 	// Instantiate metrics
 	var err error
@@ -98,6 +105,20 @@ func (c *Controller) Initialize(
 	}
 	snowCtx.Log.Info("loaded genesis", zap.Any("genesis", c.genesis))
 
+	// Create the runtimes used to execute program actions and to serve
+	// read-only calls (each runtime needs its own config)
+	newRuntimeConfig := func() *runtime.Config {
+		runtimeConfig := runtime.NewConfig()
+		runtimeConfig.ProgramCacheSize = c.config.ProgramCacheSize
+		if c.config.PersistPrograms {
+			runtimeConfig.ProgramCacheDir = filepath.Join(snowCtx.ChainDataDir, "programs")
+		}
+		return runtimeConfig
+	}
+	programs.Initialize(newRuntimeConfig(), snowCtx.Log)
+	programs.InitializeReadOnly(newRuntimeConfig(), snowCtx.Log)
+	c.readOnlyCalls = make(chan struct{}, c.config.MaxReadOnlyCalls)
+
 	c.txDB, err = hstorage.New(pebble.NewDefaultConfig(), snowCtx.ChainDataDir, "db", gatherer)
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, err
@@ -148,7 +169,6 @@ func (c *Controller) Initialize(
 }
 
 func (c *Controller) Rules(t int64) chain.Rules {
-	// TODO: extend with [UpgradeBytes]
 	return c.genesis.Rules(t, c.snowCtx.NetworkID, c.snowCtx.ChainID)
 }
 
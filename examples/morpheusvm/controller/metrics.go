@@ -13,7 +13,11 @@ import (
 )
 
 type metrics struct {
-	transfer prometheus.Counter
+	transfer       prometheus.Counter
+	publishProgram prometheus.Counter
+	deployProgram  prometheus.Counter
+	callProgram    prometheus.Counter
+	upgradeProgram prometheus.Counter
 }
 
 func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
@@ -23,11 +27,35 @@ func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
 			Name:      "transfer",
 			Help:      "number of transfer actions",
 		}),
+		publishProgram: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "publish_program",
+			Help:      "number of publish program actions",
+		}),
+		deployProgram: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "deploy_program",
+			Help:      "number of deploy program actions",
+		}),
+		callProgram: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "call_program",
+			Help:      "number of call program actions",
+		}),
+		upgradeProgram: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "upgrade_program",
+			Help:      "number of upgrade program actions",
+		}),
 	}
 	r := prometheus.NewRegistry()
 	errs := wrappers.Errs{}
 	errs.Add(
 		r.Register(m.transfer),
+		r.Register(m.publishProgram),
+		r.Register(m.deployProgram),
+		r.Register(m.callProgram),
+		r.Register(m.upgradeProgram),
 
 		gatherer.Register(consts.Name, r),
 	)
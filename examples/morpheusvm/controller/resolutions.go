@@ -4,16 +4,25 @@
 package controller
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"slices"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils/logging"
 
+	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/programs"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
 	"github.com/ava-labs/hypersdk/fees"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/x/programs/runtime"
 )
 
 func (c *Controller) Genesis() *genesis.Genesis {
@@ -32,9 +41,144 @@ func (c *Controller) GetTransaction(txID ids.ID) (bool, int64, bool, fees.Dimens
 	return c.txIndexer.GetTransaction(txID)
 }
 
+// readState returns a [storage.ReadState] for the post-execution state of the
+// accepted block at [height] (or for the latest state if [height] is nil).
+//
+// Reading historical state requires the node to run in archive mode.
+func (c *Controller) readState(height *uint64) storage.ReadState {
+	if height == nil {
+		return c.inner.ReadState
+	}
+	return func(ctx context.Context, keys [][]byte) ([][]byte, []error) {
+		return c.inner.ReadStateAt(ctx, keys, *height)
+	}
+}
+
 func (c *Controller) GetBalanceFromState(
 	ctx context.Context,
 	acct codec.Address,
+	height *uint64,
 ) (uint64, error) {
-	return storage.GetBalanceFromState(ctx, c.inner.ReadState, acct)
+	return storage.GetBalanceFromState(ctx, c.readState(height), acct)
+}
+
+// SimulateCallProgram executes [call] (as [actor]) against the latest state
+// without committing any changes and populates the [ProgramID] and [Keys] it
+// must declare to be executed on-chain. Like [CallProgramReadOnly], the
+// [Fuel] of [call] is clamped to [config.Config.MaxReadOnlyFuel] (which is
+// also used if it is 0). It returns the result of the call.
+//
+// The keys accessed by the call may differ once it is executed on-chain (if
+// state changes in the meantime).
+func (c *Controller) SimulateCallProgram(
+	ctx context.Context,
+	actor codec.Address,
+	call *actions.CallProgram,
+) ([]byte, error) {
+	db, err := c.inner.State()
+	if err != nil {
+		return nil, err
+	}
+	recorder := state.NewRecorder(state.NewSimpleMutable(db))
+	call.ProgramID, err = programs.NewStateManager(recorder).GetAccountProgram(ctx, call.Program)
+	if err != nil {
+		return nil, err
+	}
+	call.Keys = nil
+	call.Fuel = c.readOnlyFuel(call.Fuel)
+	result, err := c.readOnlyCall(ctx, func(ctx context.Context) ([]byte, error) {
+		now := time.Now().UnixMilli()
+		outputs, err := call.ExecuteWith(ctx, programs.ReadOnlyRuntime(), c.Rules(now), recorder, now, actor, ids.Empty)
+		if err != nil {
+			return nil, err
+		}
+		output, err := actions.UnmarshalCallProgramResult(outputs[0])
+		if err != nil {
+			return nil, err
+		}
+		return output.Result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Only declare the keys that [call] doesn't declare already
+	declared := call.StateKeys(actor, ids.Empty)
+	for k, perm := range recorder.Keys() {
+		if declared[k].Has(perm) {
+			continue
+		}
+		call.Keys = append(call.Keys, actions.StateKey{Key: []byte(k), Permissions: perm})
+	}
+	slices.SortFunc(call.Keys, func(a, b actions.StateKey) int {
+		return bytes.Compare(a.Key, b.Key)
+	})
+	return result, nil
+}
+
+// CallProgramReadOnly calls [function] of [program] (as [actor]) against the
+// last accepted state with at most [config.Config.MaxReadOnlyFuel] fuel (which
+// is also used if [fuel] is 0) and returns its result. The call fails if it
+// attempts to modify state or exceeds [config.Config.ReadOnlyTimeout].
+func (c *Controller) CallProgramReadOnly(
+	ctx context.Context,
+	actor codec.Address,
+	program codec.Address,
+	function string,
+	params []byte,
+	fuel uint64,
+) ([]byte, error) {
+	fuel = c.readOnlyFuel(fuel)
+	db, err := c.inner.State()
+	if err != nil {
+		return nil, err
+	}
+	mu := state.NewSimpleMutable(db)
+	parentHeight, err := mu.GetValue(ctx, chain.HeightKey(storage.HeightKey()))
+	if err != nil {
+		return nil, err
+	}
+	return c.readOnlyCall(ctx, func(ctx context.Context) ([]byte, error) {
+		return programs.ReadOnlyRuntime().CallProgram(ctx, &runtime.CallInfo{
+			State:        programs.NewStateManager(mu),
+			Actor:        actor,
+			FunctionName: function,
+			Program:      program,
+			Params:       params,
+			Fuel:         fuel,
+			Height:       binary.BigEndian.Uint64(parentHeight) + 1,
+			Timestamp:    uint64(time.Now().UnixMilli()),
+			ReadOnly:     true,
+		})
+	})
+}
+
+// readOnlyFuel returns [fuel] clamped to [config.Config.MaxReadOnlyFuel] (or
+// the maximum if [fuel] is 0).
+func (c *Controller) readOnlyFuel(fuel uint64) uint64 {
+	if fuel == 0 || fuel > c.config.MaxReadOnlyFuel {
+		return c.config.MaxReadOnlyFuel
+	}
+	return fuel
+}
+
+// readOnlyCall returns the result of [call], which must execute programs with
+// [programs.ReadOnlyRuntime] so that it is interrupted if it doesn't return
+// within [config.Config.ReadOnlyTimeout].
+//
+// At most [config.Config.MaxReadOnlyCalls] calls are executed at once. Other
+// calls wait for their turn (which counts towards their timeout).
+func (c *Controller) readOnlyCall(ctx context.Context, call func(context.Context) ([]byte, error)) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.ReadOnlyTimeout)
+	defer cancel()
+
+	select {
+	case c.readOnlyCalls <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() {
+		<-c.readOnlyCalls
+	}()
+	return call(ctx)
 }
@@ -0,0 +1,71 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package controller
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/config"
+)
+
+func TestReadOnlyFuel(t *testing.T) {
+	require := require.New(t)
+
+	c := &Controller{config: &config.Config{MaxReadOnlyFuel: 1_000}}
+	require.Equal(uint64(1_000), c.readOnlyFuel(0))
+	require.Equal(uint64(10), c.readOnlyFuel(10))
+	require.Equal(uint64(1_000), c.readOnlyFuel(math.MaxUint64))
+}
+
+func TestReadOnlyCallTimeout(t *testing.T) {
+	require := require.New(t)
+
+	c := &Controller{
+		config:        &config.Config{ReadOnlyTimeout: 10 * time.Millisecond},
+		readOnlyCalls: make(chan struct{}, 1),
+	}
+	result, err := c.readOnlyCall(context.Background(), func(context.Context) ([]byte, error) {
+		return []byte{1}, nil
+	})
+	require.NoError(err)
+	require.Equal([]byte{1}, result)
+
+	_, err = c.readOnlyCall(context.Background(), func(ctx context.Context) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	require.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func TestReadOnlyCallLimit(t *testing.T) {
+	require := require.New(t)
+
+	c := &Controller{
+		config:        &config.Config{ReadOnlyTimeout: 10 * time.Millisecond},
+		readOnlyCalls: make(chan struct{}, 1),
+	}
+
+	// A call waiting for a slot times out without being executed
+	c.readOnlyCalls <- struct{}{}
+	_, err := c.readOnlyCall(context.Background(), func(context.Context) ([]byte, error) {
+		require.FailNow("call executed without a slot")
+		return nil, nil
+	})
+	require.ErrorIs(err, context.DeadlineExceeded)
+
+	// Slots are released once calls return
+	<-c.readOnlyCalls
+	for i := 0; i < 2; i++ {
+		_, err = c.readOnlyCall(context.Background(), func(context.Context) ([]byte, error) {
+			return nil, nil
+		})
+		require.NoError(err)
+	}
+	require.Empty(c.readOnlyCalls)
+}
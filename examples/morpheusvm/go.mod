@@ -6,6 +6,7 @@ require (
 	github.com/ava-labs/avalanche-network-runner v1.7.4-rc.0
 	github.com/ava-labs/avalanchego v1.11.8
 	github.com/ava-labs/hypersdk v0.0.1
+	github.com/bytecodealliance/wasmtime-go/v14 v14.0.0
 	github.com/fatih/color v1.13.0
 	github.com/onsi/ginkgo/v2 v2.13.1
 	github.com/prometheus/client_golang v1.16.0
@@ -0,0 +1,13 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package programs
+
+import "errors"
+
+var (
+	ErrUnknownProgram   = errors.New("unknown program")
+	ErrUnknownAccount   = errors.New("unknown program account")
+	ErrAccountExists    = errors.New("program account already exists")
+	ErrStateKeyTooLarge = errors.New("program state key is too large")
+)
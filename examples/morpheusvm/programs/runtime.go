@@ -0,0 +1,61 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package programs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	"github.com/ava-labs/hypersdk/x/programs/runtime"
+)
+
+// readOnlyTick is the precision of the timeouts of calls executed by the
+// read-only runtime.
+const readOnlyTick = 10 * time.Millisecond
+
+var (
+	shared     *runtime.WasmRuntime
+	sharedOnce sync.Once
+
+	readOnly     *runtime.WasmRuntime
+	readOnlyOnce sync.Once
+)
+
+// Initialize creates the runtime shared by all program actions. Only the first
+// call has any effect.
+func Initialize(cfg *runtime.Config, log logging.Logger) {
+	sharedOnce.Do(func() {
+		shared = runtime.NewRuntime(cfg, log)
+	})
+}
+
+// Runtime returns the shared runtime (initializing it with the default config
+// if [Initialize] was never called).
+func Runtime() *runtime.WasmRuntime {
+	Initialize(runtime.NewConfig(), logging.NoLog{})
+	return shared
+}
+
+// InitializeReadOnly creates the runtime used to serve read-only calls and
+// simulations. Only the first call has any effect.
+//
+// Unlike the shared runtime, it interrupts calls once the deadline of their
+// context passes (see [runtime.WasmRuntime.EnableTimeouts]), so it must never
+// be used to execute actions.
+func InitializeReadOnly(cfg *runtime.Config, log logging.Logger) {
+	readOnlyOnce.Do(func() {
+		readOnly = runtime.NewRuntime(cfg, log)
+		// The runtime is used for the lifetime of the process
+		_ = readOnly.EnableTimeouts(readOnlyTick)
+	})
+}
+
+// ReadOnlyRuntime returns the read-only runtime (initializing it with the
+// default config if [InitializeReadOnly] was never called).
+func ReadOnlyRuntime() *runtime.WasmRuntime {
+	InitializeReadOnly(runtime.NewConfig(), logging.NoLog{})
+	return readOnly
+}
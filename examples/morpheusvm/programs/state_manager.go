@@ -0,0 +1,158 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package programs
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/utils"
+	"github.com/ava-labs/hypersdk/x/programs/runtime"
+
+	mconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
+)
+
+var _ runtime.StateManager = (*StateManager)(nil)
+
+// StateManager exposes morpheusvm state to the program runtime. All keys it
+// accesses must be declared by the action that calls the program.
+//
+// Changes are journaled so that the changes made by failed program calls can
+// be reverted, which requires keys that are written to also be readable.
+type StateManager struct {
+	mu *state.Journal
+}
+
+func NewStateManager(mu state.Mutable) *StateManager {
+	return &StateManager{mu: state.NewJournal(mu)}
+}
+
+// ProgramAddress returns the address of the account created by deploying
+// [programID] with [creationData].
+func ProgramAddress(programID ids.ID, creationData []byte) codec.Address {
+	return codec.CreateAddress(
+		mconsts.ProgramAddressID,
+		utils.ToID(append(programID[:], creationData...)),
+	)
+}
+
+func (s *StateManager) GetBalance(ctx context.Context, address codec.Address) (uint64, error) {
+	return storage.GetBalance(ctx, s.mu, address)
+}
+
+func (s *StateManager) TransferBalance(ctx context.Context, from codec.Address, to codec.Address, amount uint64) error {
+	if err := storage.SubBalance(ctx, s.mu, from, amount); err != nil {
+		return err
+	}
+	return storage.AddBalance(ctx, s.mu, to, amount, true)
+}
+
+func (s *StateManager) GetProgramState(address codec.Address) state.Mutable {
+	return &programState{mu: s.mu, account: address}
+}
+
+// GetAccountProgram also reads the program [account] is bound to. The runtime
+// only reads a program when it is not cached, so otherwise whether the program
+// key is accessed (and must be declared) would depend on the cache.
+func (s *StateManager) GetAccountProgram(ctx context.Context, account codec.Address) (ids.ID, error) {
+	programID, exists, err := storage.GetAccountProgram(ctx, s.mu, account)
+	if err != nil {
+		return ids.Empty, err
+	}
+	if !exists {
+		return ids.Empty, ErrUnknownAccount
+	}
+	if _, err := s.GetProgramBytes(ctx, programID); err != nil {
+		return ids.Empty, err
+	}
+	return programID, nil
+}
+
+func (s *StateManager) GetProgramBytes(ctx context.Context, programID ids.ID) ([]byte, error) {
+	program, exists, err := storage.GetProgram(ctx, s.mu, programID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrUnknownProgram
+	}
+	return program, nil
+}
+
+func (s *StateManager) NewAccountWithProgram(ctx context.Context, programID ids.ID, accountCreationData []byte) (codec.Address, error) {
+	if _, err := s.GetProgramBytes(ctx, programID); err != nil {
+		return codec.EmptyAddress, err
+	}
+	account := ProgramAddress(programID, accountCreationData)
+	_, exists, err := storage.GetAccountProgram(ctx, s.mu, account)
+	if err != nil {
+		return codec.EmptyAddress, err
+	}
+	if exists {
+		return codec.EmptyAddress, ErrAccountExists
+	}
+	return account, storage.SetAccountProgram(ctx, s.mu, account, programID)
+}
+
+func (s *StateManager) SetAccountProgram(ctx context.Context, account codec.Address, programID ids.ID) error {
+	return storage.SetAccountProgram(ctx, s.mu, account, programID)
+}
+
+func (s *StateManager) GetAccountAdmin(ctx context.Context, account codec.Address) (codec.Address, error) {
+	return storage.GetAccountAdmin(ctx, s.mu, account)
+}
+
+func (s *StateManager) SetAccountAdmin(ctx context.Context, account codec.Address, admin codec.Address) error {
+	return storage.SetAccountAdmin(ctx, s.mu, account, admin)
+}
+
+func (s *StateManager) OpIndex() int {
+	return s.mu.OpIndex()
+}
+
+func (s *StateManager) Rollback(ctx context.Context, restorePoint int) error {
+	return s.mu.Rollback(ctx, restorePoint)
+}
+
+// programState maps the keys used by a program to [storage.ProgramStateKey]s
+// of its account.
+type programState struct {
+	mu      state.Mutable
+	account codec.Address
+}
+
+func (p *programState) key(key []byte) ([]byte, error) {
+	if len(key) > storage.MaxProgramStateKeySize {
+		return nil, ErrStateKeyTooLarge
+	}
+	return storage.ProgramStateKey(p.account, key), nil
+}
+
+func (p *programState) GetValue(ctx context.Context, key []byte) ([]byte, error) {
+	k, err := p.key(key)
+	if err != nil {
+		return nil, err
+	}
+	return p.mu.GetValue(ctx, k)
+}
+
+func (p *programState) Insert(ctx context.Context, key []byte, value []byte) error {
+	k, err := p.key(key)
+	if err != nil {
+		return err
+	}
+	return p.mu.Insert(ctx, k, value)
+}
+
+func (p *programState) Remove(ctx context.Context, key []byte) error {
+	k, err := p.key(key)
+	if err != nil {
+		return err
+	}
+	return p.mu.Remove(ctx, k)
+}
@@ -22,6 +22,11 @@ func init() {
 	errs.Add(
 		// When registering new actions, ALWAYS make sure to append at the end.
 		consts.ActionRegistry.Register((&actions.Transfer{}).GetTypeID(), actions.UnmarshalTransfer),
+		consts.ActionRegistry.Register((&actions.PublishProgram{}).GetTypeID(), actions.UnmarshalPublishProgram),
+		consts.ActionRegistry.Register((&actions.DeployProgram{}).GetTypeID(), actions.UnmarshalDeployProgram),
+		consts.ActionRegistry.Register((&actions.CallProgram{}).GetTypeID(), actions.UnmarshalCallProgram),
+		consts.ActionRegistry.Register((&actions.UpgradeProgram{}).GetTypeID(), actions.UnmarshalUpgradeProgram),
+		actions.Staking.Register(consts.ActionRegistry),
 
 		// When registering new auth, ALWAYS make sure to append at the end.
 		consts.AuthRegistry.Register((&auth.ED25519{}).GetTypeID(), auth.UnmarshalED25519),
@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/avalanchego/trace"
 
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
 	"github.com/ava-labs/hypersdk/fees"
 )
@@ -18,5 +19,7 @@ type Controller interface {
 	Genesis() *genesis.Genesis
 	Tracer() trace.Tracer
 	GetTransaction(ids.ID) (bool, int64, bool, fees.Dimensions, uint64, error)
-	GetBalanceFromState(context.Context, codec.Address) (uint64, error)
+	GetBalanceFromState(context.Context, codec.Address, *uint64) (uint64, error)
+	SimulateCallProgram(context.Context, codec.Address, *actions.CallProgram) ([]byte, error)
+	CallProgramReadOnly(ctx context.Context, actor codec.Address, program codec.Address, function string, params []byte, fuel uint64) ([]byte, error)
 }
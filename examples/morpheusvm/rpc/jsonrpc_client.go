@@ -12,6 +12,7 @@ import (
 	_ "github.com/ava-labs/hypersdk/examples/morpheusvm/registry" // ensure registry populated
 
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/storage"
@@ -51,6 +52,13 @@ func (cli *JSONRPCClient) Genesis(ctx context.Context) (*genesis.Genesis, error)
 	if err != nil {
 		return nil, err
 	}
+	if resp.Upgrades != nil {
+		// Ensure we use the same rules as the chain when generating
+		// transactions
+		if err := resp.Genesis.SetUpgrades(resp.Upgrades); err != nil {
+			return nil, err
+		}
+	}
 	cli.g = resp.Genesis
 	return resp.Genesis, nil
 }
@@ -87,6 +95,22 @@ func (cli *JSONRPCClient) Balance(ctx context.Context, addr string) (uint64, err
 	return resp.Amount, err
 }
 
+// BalanceAt returns the balance of [addr] at the accepted block at [height]
+// (only supported by archive nodes).
+func (cli *JSONRPCClient) BalanceAt(ctx context.Context, addr string, height uint64) (uint64, error) {
+	resp := new(BalanceReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"balance",
+		&BalanceArgs{
+			Address: addr,
+			Height:  &height,
+		},
+		resp,
+	)
+	return resp.Amount, err
+}
+
 func (cli *JSONRPCClient) WaitForBalance(
 	ctx context.Context,
 	addr string,
@@ -126,6 +150,65 @@ func (cli *JSONRPCClient) WaitForTransaction(ctx context.Context, txID ids.ID) (
 	return success, fee, nil
 }
 
+// SimulateCallProgram simulates calling [function] of [program] (as [actor])
+// and returns the [actions.CallProgram] to issue to perform the call on-chain
+// (which declares the state keys accessed by the simulation) and the result
+// of the simulation.
+func (cli *JSONRPCClient) SimulateCallProgram(
+	ctx context.Context,
+	actor string,
+	program string,
+	function string,
+	params []byte,
+	fuel uint64,
+) (*actions.CallProgram, []byte, error) {
+	resp := new(SimulateCallProgramReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"simulateCallProgram",
+		&SimulateCallProgramArgs{
+			Actor:    actor,
+			Program:  program,
+			Function: function,
+			Params:   params,
+			Fuel:     fuel,
+		},
+		resp,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Action, resp.Result, nil
+}
+
+// CallProgramReadOnly calls [function] of [program] (as [actor]) against the
+// last accepted state and returns its result. The call fails if it attempts to
+// modify state. If [fuel] is 0 (or exceeds the limit of the node), the maximum
+// fuel allowed by the node is used.
+func (cli *JSONRPCClient) CallProgramReadOnly(
+	ctx context.Context,
+	actor string,
+	program string,
+	function string,
+	params []byte,
+	fuel uint64,
+) ([]byte, error) {
+	resp := new(CallProgramReadOnlyReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"callProgramReadOnly",
+		&CallProgramReadOnlyArgs{
+			Actor:    actor,
+			Program:  program,
+			Function: function,
+			Params:   params,
+			Fuel:     fuel,
+		},
+		resp,
+	)
+	return resp.Result, err
+}
+
 var _ chain.Parser = (*Parser)(nil)
 
 type Parser struct {
@@ -9,9 +9,12 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/morpheusvm/actions"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
 	"github.com/ava-labs/hypersdk/examples/morpheusvm/genesis"
 	"github.com/ava-labs/hypersdk/fees"
+
+	hgenesis "github.com/ava-labs/hypersdk/genesis"
 )
 
 type JSONRPCServer struct {
@@ -23,11 +26,13 @@ func NewJSONRPCServer(c Controller) *JSONRPCServer {
 }
 
 type GenesisReply struct {
-	Genesis *genesis.Genesis `json:"genesis"`
+	Genesis  *genesis.Genesis   `json:"genesis"`
+	Upgrades *hgenesis.Upgrades `json:"upgrades"`
 }
 
 func (j *JSONRPCServer) Genesis(_ *http.Request, _ *struct{}, reply *GenesisReply) (err error) {
 	reply.Genesis = j.c.Genesis()
+	reply.Upgrades = reply.Genesis.Upgrades()
 	return nil
 }
 
@@ -62,6 +67,9 @@ func (j *JSONRPCServer) Tx(req *http.Request, args *TxArgs, reply *TxReply) erro
 
 type BalanceArgs struct {
 	Address string `json:"address"`
+
+	// Height is only supported by archive nodes
+	Height *uint64 `json:"height,omitempty"`
 }
 
 type BalanceReply struct {
@@ -76,10 +84,84 @@ func (j *JSONRPCServer) Balance(req *http.Request, args *BalanceArgs, reply *Bal
 	if err != nil {
 		return err
 	}
-	balance, err := j.c.GetBalanceFromState(ctx, addr)
+	balance, err := j.c.GetBalanceFromState(ctx, addr, args.Height)
 	if err != nil {
 		return err
 	}
 	reply.Amount = balance
 	return err
 }
+
+type SimulateCallProgramArgs struct {
+	Actor    string `json:"actor"`
+	Program  string `json:"program"`
+	Function string `json:"function"`
+	Params   []byte `json:"params"`
+	Fuel     uint64 `json:"fuel"`
+}
+
+type SimulateCallProgramReply struct {
+	// Action is the [actions.CallProgram] to issue, including the state keys
+	// accessed by the simulated call.
+	Action *actions.CallProgram `json:"action"`
+	Result []byte               `json:"result"`
+}
+
+func (j *JSONRPCServer) SimulateCallProgram(req *http.Request, args *SimulateCallProgramArgs, reply *SimulateCallProgramReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.SimulateCallProgram")
+	defer span.End()
+
+	actor, err := codec.ParseAddressBech32(consts.HRP, args.Actor)
+	if err != nil {
+		return err
+	}
+	program, err := codec.ParseAddressBech32(consts.HRP, args.Program)
+	if err != nil {
+		return err
+	}
+	call := &actions.CallProgram{
+		Program:  program,
+		Function: args.Function,
+		Params:   args.Params,
+		Fuel:     args.Fuel,
+	}
+	result, err := j.c.SimulateCallProgram(ctx, actor, call)
+	if err != nil {
+		return err
+	}
+	reply.Action = call
+	reply.Result = result
+	return nil
+}
+
+type CallProgramReadOnlyArgs struct {
+	Actor    string `json:"actor"`
+	Program  string `json:"program"`
+	Function string `json:"function"`
+	Params   []byte `json:"params"`
+	Fuel     uint64 `json:"fuel"`
+}
+
+type CallProgramReadOnlyReply struct {
+	Result []byte `json:"result"`
+}
+
+func (j *JSONRPCServer) CallProgramReadOnly(req *http.Request, args *CallProgramReadOnlyArgs, reply *CallProgramReadOnlyReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.CallProgramReadOnly")
+	defer span.End()
+
+	actor, err := codec.ParseAddressBech32(consts.HRP, args.Actor)
+	if err != nil {
+		return err
+	}
+	program, err := codec.ParseAddressBech32(consts.HRP, args.Program)
+	if err != nil {
+		return err
+	}
+	result, err := j.c.CallProgramReadOnly(ctx, actor, program, args.Function, args.Params, args.Fuel)
+	if err != nil {
+		return err
+	}
+	reply.Result = result
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/x/staking"
+)
+
+var _ staking.BalanceHandler = (*StakingBalances)(nil)
+
+// StakingBalances moves RED in and out of stake.
+type StakingBalances struct{}
+
+func (*StakingBalances) BalanceKey(addr codec.Address) []byte {
+	return BalanceKey(addr)
+}
+
+func (*StakingBalances) BalanceChunks() uint16 {
+	return BalanceChunks
+}
+
+func (*StakingBalances) AddBalance(ctx context.Context, mu state.Mutable, addr codec.Address, amount uint64) error {
+	return AddBalance(ctx, mu, addr, amount, true)
+}
+
+func (*StakingBalances) SubBalance(ctx context.Context, mu state.Mutable, addr codec.Address, amount uint64) error {
+	return SubBalance(ctx, mu, addr, amount)
+}
@@ -10,6 +10,8 @@ import (
 	"fmt"
 
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/units"
 
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
@@ -29,16 +31,43 @@ type ReadState func(context.Context, [][]byte) ([][]byte, []error)
 // 0x1/ (hypersdk-height)
 // 0x2/ (hypersdk-timestamp)
 // 0x3/ (hypersdk-fee)
+// 0x4/ (programs)
+//   -> [programID] => program bytes
+// 0x5/ (program accounts)
+//   -> [account] => programID
+// 0x6/ (program state)
+//   -> [account|key] => value
+// 0x7/ (program account admins)
+//   -> [account] => admin
+// 0x8/ (staking, see [x/staking])
 
 const (
 	// Active state
-	balancePrefix   = 0x0
-	heightPrefix    = 0x1
-	timestampPrefix = 0x2
-	feePrefix       = 0x3
+	balancePrefix        = 0x0
+	heightPrefix         = 0x1
+	timestampPrefix      = 0x2
+	feePrefix            = 0x3
+	programPrefix        = 0x4
+	accountProgramPrefix = 0x5
+	programStatePrefix   = 0x6
+	accountAdminPrefix   = 0x7
+
+	// StakingPrefix is the prefix of all keys written by [actions.Staking].
+	StakingPrefix = 0x8
+)
+
+const (
+	BalanceChunks        uint16 = 1
+	ProgramChunks        uint16 = 1025 // 64 KiB (see [MaxProgramSize])
+	AccountProgramChunks uint16 = 1
+	ProgramStateChunks   uint16 = 16 // 1 KiB
+	AccountAdminChunks   uint16 = 1
 )
 
-const BalanceChunks uint16 = 1
+const (
+	MaxProgramSize         = 64 * units.KiB
+	MaxProgramStateKeySize = 256
+)
 
 var (
 	heightKey    = []byte{heightPrefix}
@@ -176,6 +205,121 @@ func SubBalance(
 	return setBalance(ctx, mu, key, nbal)
 }
 
+// [programPrefix] + [programID]
+func ProgramKey(programID ids.ID) (k []byte) {
+	k = make([]byte, 1+ids.IDLen+consts.Uint16Len)
+	k[0] = programPrefix
+	copy(k[1:], programID[:])
+	binary.BigEndian.PutUint16(k[1+ids.IDLen:], ProgramChunks)
+	return
+}
+
+func GetProgram(
+	ctx context.Context,
+	im state.Immutable,
+	programID ids.ID,
+) ([]byte, bool, error) {
+	v, err := im.GetValue(ctx, ProgramKey(programID))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return v, true, nil
+}
+
+func SetProgram(
+	ctx context.Context,
+	mu state.Mutable,
+	programID ids.ID,
+	program []byte,
+) error {
+	return mu.Insert(ctx, ProgramKey(programID), program)
+}
+
+// [accountProgramPrefix] + [account]
+func AccountProgramKey(account codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+consts.Uint16Len)
+	k[0] = accountProgramPrefix
+	copy(k[1:], account[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen:], AccountProgramChunks)
+	return
+}
+
+func GetAccountProgram(
+	ctx context.Context,
+	im state.Immutable,
+	account codec.Address,
+) (ids.ID, bool, error) {
+	v, err := im.GetValue(ctx, AccountProgramKey(account))
+	if errors.Is(err, database.ErrNotFound) {
+		return ids.Empty, false, nil
+	}
+	if err != nil {
+		return ids.Empty, false, err
+	}
+	return ids.ID(v), true, nil
+}
+
+func SetAccountProgram(
+	ctx context.Context,
+	mu state.Mutable,
+	account codec.Address,
+	programID ids.ID,
+) error {
+	return mu.Insert(ctx, AccountProgramKey(account), programID[:])
+}
+
+// [accountAdminPrefix] + [account]
+func AccountAdminKey(account codec.Address) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+consts.Uint16Len)
+	k[0] = accountAdminPrefix
+	copy(k[1:], account[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen:], AccountAdminChunks)
+	return
+}
+
+// GetAccountAdmin returns [codec.EmptyAddress] if [account] has no admin.
+func GetAccountAdmin(
+	ctx context.Context,
+	im state.Immutable,
+	account codec.Address,
+) (codec.Address, error) {
+	v, err := im.GetValue(ctx, AccountAdminKey(account))
+	if errors.Is(err, database.ErrNotFound) {
+		return codec.EmptyAddress, nil
+	}
+	if err != nil {
+		return codec.EmptyAddress, err
+	}
+	return codec.Address(v), nil
+}
+
+// SetAccountAdmin removes the admin of [account] if [admin] is
+// [codec.EmptyAddress].
+func SetAccountAdmin(
+	ctx context.Context,
+	mu state.Mutable,
+	account codec.Address,
+	admin codec.Address,
+) error {
+	if admin == codec.EmptyAddress {
+		return mu.Remove(ctx, AccountAdminKey(account))
+	}
+	return mu.Insert(ctx, AccountAdminKey(account), admin[:])
+}
+
+// [programStatePrefix] + [account] + [key]
+func ProgramStateKey(account codec.Address, key []byte) (k []byte) {
+	k = make([]byte, 1+codec.AddressLen+len(key)+consts.Uint16Len)
+	k[0] = programStatePrefix
+	copy(k[1:], account[:])
+	copy(k[1+codec.AddressLen:], key)
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen+len(key):], ProgramStateChunks)
+	return
+}
+
 func HeightKey() (k []byte) {
 	return heightKey
 }
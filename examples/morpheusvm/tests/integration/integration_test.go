@@ -5,6 +5,7 @@ package integration_test
 
 import (
 	"context"
+	"crypto"
 	"encoding/hex"
 	"encoding/json"
 	"flag"
@@ -42,7 +43,9 @@ import (
 	"github.com/ava-labs/hypersdk/pubsub"
 	"github.com/ava-labs/hypersdk/rpc"
 	"github.com/ava-labs/hypersdk/vm"
+	"github.com/ava-labs/hypersdk/x/staking"
 
+	astaking "github.com/ava-labs/avalanchego/staking"
 	auth "github.com/ava-labs/hypersdk/auth"
 	hbls "github.com/ava-labs/hypersdk/crypto/bls"
 	lconsts "github.com/ava-labs/hypersdk/examples/morpheusvm/consts"
@@ -898,6 +901,74 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 			require.Equal(balance, bbalance+100)
 		})
 	})
+
+	ginkgo.It("distributes fees to registered validators", func() {
+		ctx := context.Background()
+		tlsCert, err := astaking.NewTLSCert()
+		require.NoError(err)
+		cert, err := astaking.ParseCertificate(tlsCert.Leaf.Raw)
+		require.NoError(err)
+		nodeID := ids.NodeIDFromCert(cert)
+		signer, ok := tlsCert.PrivateKey.(crypto.Signer)
+		require.True(ok)
+
+		issue := func(action chain.Action) [][]byte {
+			parser, err := instances[0].lcli.Parser(ctx)
+			require.NoError(err)
+			submit, _, _, err := instances[0].cli.GenerateTransaction(
+				ctx,
+				parser,
+				[]chain.Action{action},
+				factory,
+			)
+			require.NoError(err)
+			require.NoError(submit(ctx))
+			accept := expectBlk(instances[0])
+			results := accept(false)
+			require.Len(results, 1)
+			require.True(results[0].Success)
+			return results[0].Outputs[0]
+		}
+
+		ginkgo.By("register validator", func() {
+			signature, err := staking.SignRegistration(signer, instances[0].chainID, nodeID, addr)
+			require.NoError(err)
+			issue(actions.Staking.NewRegisterValidator(nodeID, 1_000_000, tlsCert.Leaf.Raw, signature))
+
+			db, err := instances[0].vm.State()
+			require.NoError(err)
+			validator, err := actions.Staking.GetValidator(ctx, db, nodeID)
+			require.NoError(err)
+			require.Equal(addr, validator.Owner)
+			require.Equal(uint64(1_000_000), validator.Stake)
+		})
+
+		ginkgo.By("distribute fees", func() {
+			db, err := instances[0].vm.State()
+			require.NoError(err)
+			totalStake, index, err := actions.Staking.GetRewards(ctx, db)
+			require.NoError(err)
+			require.Equal(uint64(1_000_000), totalStake)
+
+			issue(&actions.Transfer{
+				To:    addr2,
+				Value: 100,
+			})
+
+			_, nindex, err := actions.Staking.GetRewards(ctx, db)
+			require.NoError(err)
+			require.Positive(nindex.Cmp(index))
+		})
+
+		ginkgo.By("claim rewards", func() {
+			outputs := issue(actions.Staking.NewClaim(nodeID))
+			require.Len(outputs, 1)
+			result, err := staking.UnmarshalClaimResult(outputs[0])
+			require.NoError(err)
+			require.Positive(result.Rewards)
+			require.Zero(result.Unbonded)
+		})
+	})
 })
 
 func expectBlk(i instance) func(bool) []*chain.Result {
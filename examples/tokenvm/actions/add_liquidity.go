@@ -0,0 +1,160 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var _ chain.Action = (*AddLiquidity)(nil)
+
+type AddLiquidity struct {
+	// [Pool] is the PoolID you wish to add liquidity to.
+	Pool ids.ID `json:"pool"`
+
+	// [AssetA] and [AssetB] are the assets of the pool. We need to provide
+	// these to populate [StateKeys].
+	AssetA ids.ID `json:"assetA"`
+	AssetB ids.ID `json:"assetB"`
+
+	// [AmountA] and [AmountB] are the max amounts of each asset that will be
+	// deposited. Only the amounts that match the current rate of the pool are
+	// withdrawn.
+	AmountA uint64 `json:"amountA"`
+	AmountB uint64 `json:"amountB"`
+
+	// [MinShares] is the minimum amount of shares that must be minted for the
+	// deposit to succeed.
+	MinShares uint64 `json:"minShares"`
+}
+
+func (*AddLiquidity) GetTypeID() uint8 {
+	return addLiquidityID
+}
+
+func (a *AddLiquidity) StateKeys(actor codec.Address, _ ids.ID) state.Keys {
+	return state.Keys{
+		string(storage.PoolKey(a.Pool)):             state.Read | state.Write,
+		string(storage.AssetKey(a.Pool)):            state.Read | state.Write,
+		string(storage.BalanceKey(actor, a.AssetA)): state.Read | state.Write,
+		string(storage.BalanceKey(actor, a.AssetB)): state.Read | state.Write,
+		string(storage.BalanceKey(actor, a.Pool)):   state.All,
+	}
+}
+
+func (*AddLiquidity) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.PoolChunks, storage.AssetChunks, storage.BalanceChunks, storage.BalanceChunks, storage.BalanceChunks}
+}
+
+func (a *AddLiquidity) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) ([][]byte, error) {
+	if a.AmountA == 0 || a.AmountB == 0 {
+		return nil, ErrOutputValueZero
+	}
+	exists, assetA, assetB, fee, reserveA, reserveB, err := storage.GetPool(ctx, mu, a.Pool)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrOutputPoolMissing
+	}
+	if assetA != a.AssetA || assetB != a.AssetB {
+		return nil, ErrOutputWrongPoolAssets
+	}
+	exists, symbol, decimals, metadata, supply, owner, err := storage.GetAsset(ctx, mu, a.Pool)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrOutputAssetMissing
+	}
+	shares, amountA, amountB, err := AddLiquidityShares(reserveA, reserveB, supply, a.AmountA, a.AmountB)
+	if err != nil {
+		return nil, err
+	}
+	if shares == 0 || shares < a.MinShares {
+		return nil, ErrOutputInsufficientShares
+	}
+	newReserveA, err := smath.Add64(reserveA, amountA)
+	if err != nil {
+		return nil, err
+	}
+	newReserveB, err := smath.Add64(reserveB, amountB)
+	if err != nil {
+		return nil, err
+	}
+	newSupply, err := smath.Add64(supply, shares)
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.SubBalance(ctx, mu, actor, assetA, amountA); err != nil {
+		return nil, err
+	}
+	if err := storage.SubBalance(ctx, mu, actor, assetB, amountB); err != nil {
+		return nil, err
+	}
+	if err := storage.SetPool(ctx, mu, a.Pool, assetA, assetB, fee, newReserveA, newReserveB); err != nil {
+		return nil, err
+	}
+	if err := storage.SetAsset(ctx, mu, a.Pool, symbol, decimals, metadata, newSupply, owner); err != nil {
+		return nil, err
+	}
+	if err := storage.AddBalance(ctx, mu, actor, a.Pool, shares, true); err != nil {
+		return nil, err
+	}
+	lr := &LiquidityResult{A: amountA, B: amountB, Shares: shares}
+	output, err := lr.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{output}, nil
+}
+
+func (*AddLiquidity) ComputeUnits(chain.Rules) uint64 {
+	return AddLiquidityComputeUnits
+}
+
+func (*AddLiquidity) Size() int {
+	return ids.IDLen*3 + consts.Uint64Len*3
+}
+
+func (a *AddLiquidity) Marshal(p *codec.Packer) {
+	p.PackID(a.Pool)
+	p.PackID(a.AssetA)
+	p.PackID(a.AssetB)
+	p.PackUint64(a.AmountA)
+	p.PackUint64(a.AmountB)
+	p.PackUint64(a.MinShares)
+}
+
+func UnmarshalAddLiquidity(p *codec.Packer) (chain.Action, error) {
+	var add AddLiquidity
+	p.UnpackID(true, &add.Pool)
+	p.UnpackID(false, &add.AssetA) // empty ID is the native asset
+	p.UnpackID(false, &add.AssetB) // empty ID is the native asset
+	add.AmountA = p.UnpackUint64(true)
+	add.AmountB = p.UnpackUint64(true)
+	add.MinShares = p.UnpackUint64(false)
+	return &add, p.Err()
+}
+
+func (a *AddLiquidity) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, a.GetTypeID())
+}
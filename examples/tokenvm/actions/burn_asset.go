@@ -93,7 +93,6 @@ func UnmarshalBurnAsset(p *codec.Packer) (chain.Action, error) {
 	return &burn, p.Err()
 }
 
-func (*BurnAsset) ValidRange(chain.Rules) (int64, int64) {
-	// Returning -1, -1 means that the action is always valid.
-	return -1, -1
+func (b *BurnAsset) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, b.GetTypeID())
 }
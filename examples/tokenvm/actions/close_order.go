@@ -90,7 +90,6 @@ func UnmarshalCloseOrder(p *codec.Packer) (chain.Action, error) {
 	return &cl, p.Err()
 }
 
-func (*CloseOrder) ValidRange(chain.Rules) (int64, int64) {
-	// Returning -1, -1 means that the action is always valid.
-	return -1, -1
+func (c *CloseOrder) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, c.GetTypeID())
 }
@@ -5,29 +5,41 @@ package actions
 
 // Note: Registry will error during initialization if a duplicate ID is assigned. We explicitly assign IDs to avoid accidental remapping.
 const (
-	burnAssetID   uint8 = 0
-	closeOrderID  uint8 = 1
-	createAssetID uint8 = 2
-	exportAssetID uint8 = 3
-	importAssetID uint8 = 4
-	createOrderID uint8 = 5
-	fillOrderID   uint8 = 6
-	mintAssetID   uint8 = 7
-	transferID    uint8 = 8
+	burnAssetID       uint8 = 0
+	closeOrderID      uint8 = 1
+	createAssetID     uint8 = 2
+	exportAssetID     uint8 = 3
+	importAssetID     uint8 = 4
+	createOrderID     uint8 = 5
+	fillOrderID       uint8 = 6
+	mintAssetID       uint8 = 7
+	transferID        uint8 = 8
+	marketSwapID      uint8 = 9
+	createPoolID      uint8 = 10
+	addLiquidityID    uint8 = 11
+	removeLiquidityID uint8 = 12
+	swapID            uint8 = 13
 )
 
 const (
 	// TODO: tune this
-	BurnComputeUnits        = 2
-	CloseOrderComputeUnits  = 5
-	CreateAssetComputeUnits = 10
-	CreateOrderComputeUnits = 5
-	FillOrderComputeUnits   = 15
-	MintAssetComputeUnits   = 2
-	TransferComputeUnits    = 1
+	BurnComputeUnits            = 2
+	CloseOrderComputeUnits      = 5
+	CreateAssetComputeUnits     = 10
+	CreateOrderComputeUnits     = 5
+	FillOrderComputeUnits       = 15
+	MarketSwapComputeUnits      = 5 // plus [FillOrderComputeUnits] per candidate order
+	CreatePoolComputeUnits      = 10
+	AddLiquidityComputeUnits    = 5
+	RemoveLiquidityComputeUnits = 5
+	SwapComputeUnits            = 5
+	MintAssetComputeUnits       = 2
+	TransferComputeUnits        = 1
 
 	MaxSymbolSize   = 8
 	MaxMemoSize     = 256
 	MaxMetadataSize = 256
 	MaxDecimals     = 9
+	MaxSwapOrders   = 16
+	MaxPoolFee      = 1_000 // 10%
 )
@@ -91,7 +91,6 @@ func UnmarshalCreateAsset(p *codec.Packer) (chain.Action, error) {
 	return &create, p.Err()
 }
 
-func (*CreateAsset) ValidRange(chain.Rules) (int64, int64) {
-	// Returning -1, -1 means that the action is always valid.
-	return -1, -1
+func (c *CreateAsset) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, c.GetTypeID())
 }
@@ -119,9 +119,8 @@ func UnmarshalCreateOrder(p *codec.Packer) (chain.Action, error) {
 	return &create, p.Err()
 }
 
-func (*CreateOrder) ValidRange(chain.Rules) (int64, int64) {
-	// Returning -1, -1 means that the action is always valid.
-	return -1, -1
+func (c *CreateOrder) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, c.GetTypeID())
 }
 
 func PairID(in, out ids.ID) string {
@@ -0,0 +1,136 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+
+	tconsts "github.com/ava-labs/hypersdk/examples/tokenvm/consts"
+)
+
+var _ chain.Action = (*CreatePool)(nil)
+
+type CreatePool struct {
+	// [AssetA] and [AssetB] are the assets that can be swapped in the pool.
+	AssetA ids.ID `json:"assetA"`
+	AssetB ids.ID `json:"assetB"`
+
+	// [Fee] is charged on the input of each swap (in basis points).
+	Fee uint16 `json:"fee"`
+
+	// [AmountA] and [AmountB] are the initial reserves of the pool, which
+	// set its initial rate.
+	AmountA uint64 `json:"amountA"`
+	AmountB uint64 `json:"amountB"`
+
+	// Notes:
+	// * The pool is identified by the [ActionID] that created it. The shares
+	//   of the pool are tracked as an asset with the same ID, which can only
+	//   be minted and burned by adding and removing liquidity.
+	// * Users are allowed to create any number of pools for the same pair.
+}
+
+func (*CreatePool) GetTypeID() uint8 {
+	return createPoolID
+}
+
+func (c *CreatePool) StateKeys(actor codec.Address, actionID ids.ID) state.Keys {
+	return state.Keys{
+		string(storage.PoolKey(actionID)):           state.Allocate | state.Write,
+		string(storage.AssetKey(actionID)):          state.Allocate | state.Write,
+		string(storage.BalanceKey(actor, c.AssetA)): state.Read | state.Write,
+		string(storage.BalanceKey(actor, c.AssetB)): state.Read | state.Write,
+		string(storage.BalanceKey(actor, actionID)): state.All,
+	}
+}
+
+func (*CreatePool) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.PoolChunks, storage.AssetChunks, storage.BalanceChunks, storage.BalanceChunks, storage.BalanceChunks}
+}
+
+func (c *CreatePool) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	actionID ids.ID,
+) ([][]byte, error) {
+	if c.AssetA == c.AssetB {
+		return nil, ErrOutputSameInOut
+	}
+	if c.Fee > MaxPoolFee {
+		return nil, ErrOutputFeeTooLarge
+	}
+	if c.AmountA == 0 || c.AmountB == 0 {
+		return nil, ErrOutputValueZero
+	}
+	shares := initialShares(c.AmountA, c.AmountB)
+	if err := storage.SubBalance(ctx, mu, actor, c.AssetA, c.AmountA); err != nil {
+		return nil, err
+	}
+	if err := storage.SubBalance(ctx, mu, actor, c.AssetB, c.AmountB); err != nil {
+		return nil, err
+	}
+	if err := storage.SetPool(ctx, mu, actionID, c.AssetA, c.AssetB, c.Fee, c.AmountA, c.AmountB); err != nil {
+		return nil, err
+	}
+	// Nobody can mint the shares of a pool directly, so we set the owner of
+	// the asset to the empty address.
+	metadata := []byte(PairID(c.AssetA, c.AssetB))
+	if err := storage.SetAsset(ctx, mu, actionID, LPSymbol, tconsts.Decimals, metadata, shares, codec.EmptyAddress); err != nil {
+		return nil, err
+	}
+	if err := storage.AddBalance(ctx, mu, actor, actionID, shares, true); err != nil {
+		return nil, err
+	}
+	lr := &LiquidityResult{A: c.AmountA, B: c.AmountB, Shares: shares}
+	output, err := lr.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{output}, nil
+}
+
+func (*CreatePool) ComputeUnits(chain.Rules) uint64 {
+	return CreatePoolComputeUnits
+}
+
+func (*CreatePool) Size() int {
+	return ids.IDLen*2 + consts.IntLen + consts.Uint64Len*2
+}
+
+func (c *CreatePool) Marshal(p *codec.Packer) {
+	p.PackID(c.AssetA)
+	p.PackID(c.AssetB)
+	p.PackInt(int(c.Fee))
+	p.PackUint64(c.AmountA)
+	p.PackUint64(c.AmountB)
+}
+
+func UnmarshalCreatePool(p *codec.Packer) (chain.Action, error) {
+	var create CreatePool
+	p.UnpackID(false, &create.AssetA) // empty ID is the native asset
+	p.UnpackID(false, &create.AssetB) // empty ID is the native asset
+	fee := p.UnpackInt(false)
+	if fee > MaxPoolFee {
+		return nil, ErrOutputFeeTooLarge
+	}
+	create.Fee = uint16(fee)
+	create.AmountA = p.UnpackUint64(true)
+	create.AmountB = p.UnpackUint64(true)
+	return &create, p.Err()
+}
+
+func (c *CreatePool) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, c.GetTypeID())
+}
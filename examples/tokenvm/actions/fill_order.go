@@ -175,9 +175,8 @@ func UnmarshalFillOrder(p *codec.Packer) (chain.Action, error) {
 	return &fill, p.Err()
 }
 
-func (*FillOrder) ValidRange(chain.Rules) (int64, int64) {
-	// Returning -1, -1 means that the action is always valid.
-	return -1, -1
+func (f *FillOrder) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, f.GetTypeID())
 }
 
 // OrderResult is a custom successful response output that provides information
@@ -0,0 +1,301 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var _ chain.Action = (*MarketSwap)(nil)
+
+// SwapOrder is a candidate order that may be filled by a [MarketSwap].
+type SwapOrder struct {
+	// [Order] is the OrderID of the candidate.
+	Order ids.ID `json:"order"`
+
+	// [Owner] is the owner of the order. We need to provide this to populate
+	// [StateKeys].
+	Owner codec.Address `json:"owner"`
+}
+
+type MarketSwap struct {
+	// [In] is the asset that will be sent to the owners of the filled orders.
+	In ids.ID `json:"in"`
+
+	// [Value] is the max amount of [In] that will be swapped for [Out]. Any
+	// amount that is not needed to fill [Orders] is never withdrawn.
+	Value uint64 `json:"value"`
+
+	// [Out] is the asset that will be received from the filled orders.
+	Out ids.ID `json:"out"`
+
+	// [MinOut] is the minimum amount of [Out] that must be received for the
+	// swap to succeed.
+	MinOut uint64 `json:"minOut"`
+
+	// [Orders] are the candidate orders to fill (usually built from the
+	// order book served over RPC). Orders are filled in order of the best
+	// rate, regardless of the order they are provided in. Any candidate that
+	// was already filled or closed is skipped.
+	Orders []*SwapOrder `json:"orders"`
+}
+
+func (*MarketSwap) GetTypeID() uint8 {
+	return marketSwapID
+}
+
+func (m *MarketSwap) StateKeys(actor codec.Address, _ ids.ID) state.Keys {
+	keys := state.Keys{}
+	keys.Add(string(storage.BalanceKey(actor, m.In)), state.Read|state.Write)
+	keys.Add(string(storage.BalanceKey(actor, m.Out)), state.All)
+	for _, order := range m.Orders {
+		keys.Add(string(storage.OrderKey(order.Order)), state.Read|state.Write)
+		keys.Add(string(storage.BalanceKey(order.Owner, m.In)), state.All)
+	}
+	return keys
+}
+
+func (m *MarketSwap) StateKeysMaxChunks() []uint16 {
+	chunks := make([]uint16, 0, 2+len(m.Orders)*2)
+	chunks = append(chunks, storage.BalanceChunks, storage.BalanceChunks)
+	for range m.Orders {
+		chunks = append(chunks, storage.OrderChunks, storage.BalanceChunks)
+	}
+	return chunks
+}
+
+type swapCandidate struct {
+	order     *SwapOrder
+	inTick    uint64
+	outTick   uint64
+	remaining uint64
+}
+
+func (m *MarketSwap) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) ([][]byte, error) {
+	if m.In == m.Out {
+		return nil, ErrOutputSameInOut
+	}
+	if m.Value == 0 {
+		// This should be guarded via [Unmarshal] but we check anyways.
+		return nil, ErrOutputValueZero
+	}
+	if len(m.Orders) == 0 {
+		// This should be guarded via [Unmarshal] but we check anyways.
+		return nil, ErrOutputNoOrders
+	}
+
+	// Collect all candidates that can still be filled
+	candidates := make([]*swapCandidate, 0, len(m.Orders))
+	for _, order := range m.Orders {
+		exists, in, inTick, out, outTick, remaining, owner, err := storage.GetOrder(ctx, mu, order.Order)
+		if err != nil {
+			return nil, err
+		}
+		if !exists || in != m.In || out != m.Out || owner != order.Owner {
+			// Orders may be filled or closed between when the swap is issued and
+			// when it is executed.
+			continue
+		}
+		candidates = append(candidates, &swapCandidate{order, inTick, outTick, remaining})
+	}
+
+	// Fill the best rates first (the smallest amount of [In] per [Out]). We
+	// compare rates using cross-multiplication to avoid any rounding.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a := new(big.Int).Mul(new(big.Int).SetUint64(candidates[i].inTick), new(big.Int).SetUint64(candidates[j].outTick))
+		b := new(big.Int).Mul(new(big.Int).SetUint64(candidates[j].inTick), new(big.Int).SetUint64(candidates[i].outTick))
+		return a.Cmp(b) < 0
+	})
+	var (
+		available = m.Value
+		received  uint64
+		fills     = make([]*SwapFill, 0, len(candidates))
+	)
+	for _, c := range candidates {
+		// Only fill complete ticks of the order, the same as [FillOrder].
+		ticks := available / c.inTick
+		if maxTicks := c.remaining / c.outTick; ticks > maxTicks {
+			ticks = maxTicks
+		}
+		if ticks == 0 {
+			continue
+		}
+		inputAmount, err := smath.Mul64(ticks, c.inTick)
+		if err != nil {
+			return nil, err
+		}
+		outputAmount, err := smath.Mul64(ticks, c.outTick)
+		if err != nil {
+			return nil, err
+		}
+		if err := storage.SubBalance(ctx, mu, actor, m.In, inputAmount); err != nil {
+			return nil, err
+		}
+		if err := storage.AddBalance(ctx, mu, c.order.Owner, m.In, inputAmount, true); err != nil {
+			return nil, err
+		}
+		orderRemaining := c.remaining - outputAmount
+		if orderRemaining == 0 {
+			if err := storage.DeleteOrder(ctx, mu, c.order.Order); err != nil {
+				return nil, err
+			}
+		} else {
+			if err := storage.SetOrder(ctx, mu, c.order.Order, m.In, c.inTick, m.Out, c.outTick, orderRemaining, c.order.Owner); err != nil {
+				return nil, err
+			}
+		}
+		available -= inputAmount
+		received += outputAmount
+		fills = append(fills, &SwapFill{
+			Order:     c.order.Order,
+			Owner:     c.order.Owner,
+			In:        inputAmount,
+			Out:       outputAmount,
+			Remaining: orderRemaining,
+		})
+		if available == 0 {
+			break
+		}
+	}
+	if received == 0 || received < m.MinOut {
+		return nil, ErrOutputInsufficientOutput
+	}
+	if err := storage.AddBalance(ctx, mu, actor, m.Out, received, true); err != nil {
+		return nil, err
+	}
+	sr := &SwapResult{In: m.Value - available, Out: received, Fills: fills}
+	output, err := sr.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{output}, nil
+}
+
+func (m *MarketSwap) ComputeUnits(chain.Rules) uint64 {
+	return MarketSwapComputeUnits + uint64(len(m.Orders))*FillOrderComputeUnits
+}
+
+func (m *MarketSwap) Size() int {
+	return ids.IDLen*2 + consts.Uint64Len*2 + consts.ByteLen + len(m.Orders)*(ids.IDLen+codec.AddressLen)
+}
+
+func (m *MarketSwap) Marshal(p *codec.Packer) {
+	p.PackID(m.In)
+	p.PackUint64(m.Value)
+	p.PackID(m.Out)
+	p.PackUint64(m.MinOut)
+	p.PackByte(uint8(len(m.Orders)))
+	for _, order := range m.Orders {
+		p.PackID(order.Order)
+		p.PackAddress(order.Owner)
+	}
+}
+
+func UnmarshalMarketSwap(p *codec.Packer) (chain.Action, error) {
+	var swap MarketSwap
+	p.UnpackID(false, &swap.In) // empty ID is the native asset
+	swap.Value = p.UnpackUint64(true)
+	p.UnpackID(false, &swap.Out) // empty ID is the native asset
+	swap.MinOut = p.UnpackUint64(false)
+	orders := p.UnpackByte()
+	if orders == 0 {
+		return nil, ErrOutputNoOrders
+	}
+	if orders > MaxSwapOrders {
+		return nil, ErrOutputTooManyOrders
+	}
+	seen := set.NewSet[ids.ID](int(orders))
+	swap.Orders = make([]*SwapOrder, orders)
+	for i := range swap.Orders {
+		order := &SwapOrder{}
+		p.UnpackID(true, &order.Order)
+		p.UnpackAddress(&order.Owner)
+		if seen.Contains(order.Order) {
+			return nil, ErrOutputDuplicateOrder
+		}
+		seen.Add(order.Order)
+		swap.Orders[i] = order
+	}
+	return &swap, p.Err()
+}
+
+func (m *MarketSwap) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, m.GetTypeID())
+}
+
+// SwapFill describes how a single order was filled by a [MarketSwap].
+type SwapFill struct {
+	Order     ids.ID        `json:"order"`
+	Owner     codec.Address `json:"owner"`
+	In        uint64        `json:"in"`
+	Out       uint64        `json:"out"`
+	Remaining uint64        `json:"remaining"`
+}
+
+// SwapResult is a custom successful response output that provides information
+// about a successful [MarketSwap].
+type SwapResult struct {
+	In    uint64      `json:"in"`
+	Out   uint64      `json:"out"`
+	Fills []*SwapFill `json:"fills"`
+}
+
+func swapResultSize(fills int) int {
+	return consts.Uint64Len*2 + consts.ByteLen + fills*(ids.IDLen+codec.AddressLen+consts.Uint64Len*3)
+}
+
+func UnmarshalSwapResult(b []byte) (*SwapResult, error) {
+	p := codec.NewReader(b, swapResultSize(MaxSwapOrders))
+	var result SwapResult
+	result.In = p.UnpackUint64(true)
+	result.Out = p.UnpackUint64(true)
+	fills := p.UnpackByte()
+	result.Fills = make([]*SwapFill, fills)
+	for i := range result.Fills {
+		fill := &SwapFill{}
+		p.UnpackID(true, &fill.Order)
+		p.UnpackAddress(&fill.Owner)
+		fill.In = p.UnpackUint64(true)
+		fill.Out = p.UnpackUint64(true)
+		fill.Remaining = p.UnpackUint64(false) // if 0, deleted
+		result.Fills[i] = fill
+	}
+	return &result, p.Err()
+}
+
+func (s *SwapResult) Marshal() ([]byte, error) {
+	size := swapResultSize(len(s.Fills))
+	p := codec.NewWriter(size, size)
+	p.PackUint64(s.In)
+	p.PackUint64(s.Out)
+	p.PackByte(uint8(len(s.Fills)))
+	for _, fill := range s.Fills {
+		p.PackID(fill.Order)
+		p.PackAddress(fill.Owner)
+		p.PackUint64(fill.In)
+		p.PackUint64(fill.Out)
+		p.PackUint64(fill.Remaining)
+	}
+	return p.Bytes(), p.Err()
+}
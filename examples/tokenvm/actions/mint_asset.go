@@ -107,7 +107,6 @@ func UnmarshalMintAsset(p *codec.Packer) (chain.Action, error) {
 	return &mint, p.Err()
 }
 
-func (*MintAsset) ValidRange(chain.Rules) (int64, int64) {
-	// Returning -1, -1 means that the action is always valid.
-	return -1, -1
+func (m *MintAsset) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, m.GetTypeID())
 }
@@ -34,4 +34,14 @@ var (
 	ErrOutputWrongDestination   = errors.New("wrong destination")
 	ErrOutputMustFill           = errors.New("must fill request")
 	ErrOutputInvalidDestination = errors.New("invalid destination")
+	ErrOutputNoOrders           = errors.New("no orders")
+	ErrOutputTooManyOrders      = errors.New("too many orders")
+	ErrOutputDuplicateOrder     = errors.New("duplicate order")
+	ErrOutputPoolMissing        = errors.New("pool is missing")
+	ErrOutputPoolEmpty          = errors.New("pool is empty")
+	ErrOutputPoolUnowned        = errors.New("pool has reserves but no shares")
+	ErrOutputFeeTooLarge        = errors.New("fee is too large")
+	ErrOutputOverflow           = errors.New("overflow")
+	ErrOutputInsufficientShares = errors.New("insufficient shares")
+	ErrOutputWrongPoolAssets    = errors.New("wrong pool assets")
 )
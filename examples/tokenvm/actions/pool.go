@@ -0,0 +1,182 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+// Pool fees are denominated in basis points of the input of a swap and are
+// paid to the liquidity providers (by remaining in the reserves of the pool).
+const PoolFeeDenominator = 10_000
+
+// LPSymbol is the symbol of the asset that tracks the shares of a pool.
+var LPSymbol = []byte("LP")
+
+// mulDiv returns floor(a*b/c), which must fit in a uint64.
+func mulDiv(a, b, c uint64) (uint64, error) {
+	if c == 0 {
+		return 0, ErrOutputPoolEmpty
+	}
+	r := new(big.Int).Mul(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b))
+	r.Quo(r, new(big.Int).SetUint64(c))
+	if !r.IsUint64() {
+		return 0, ErrOutputOverflow
+	}
+	return r.Uint64(), nil
+}
+
+// mulDivUp returns ceil(a*b/c), which must fit in a uint64.
+func mulDivUp(a, b, c uint64) (uint64, error) {
+	if c == 0 {
+		return 0, ErrOutputPoolEmpty
+	}
+	r := new(big.Int).Mul(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b))
+	r.Add(r, new(big.Int).SetUint64(c-1))
+	r.Quo(r, new(big.Int).SetUint64(c))
+	if !r.IsUint64() {
+		return 0, ErrOutputOverflow
+	}
+	return r.Uint64(), nil
+}
+
+// initialShares returns the shares minted for the first deposit of [a] and [b]
+// into a pool (the geometric mean of the deposit).
+func initialShares(a, b uint64) uint64 {
+	r := new(big.Int).Mul(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b))
+	return r.Sqrt(r).Uint64()
+}
+
+// SwapOutput returns the amount received when swapping [value] into a pool
+// with [reserveIn] and [reserveOut] that charges [fee] basis points, keeping
+// the product of the reserves constant.
+func SwapOutput(reserveIn, reserveOut uint64, fee uint16, value uint64) (uint64, error) {
+	if reserveIn == 0 || reserveOut == 0 {
+		return 0, ErrOutputPoolEmpty
+	}
+	in := new(big.Int).Mul(new(big.Int).SetUint64(value), big.NewInt(int64(PoolFeeDenominator-fee)))
+	num := new(big.Int).Mul(in, new(big.Int).SetUint64(reserveOut))
+	den := new(big.Int).Mul(new(big.Int).SetUint64(reserveIn), big.NewInt(PoolFeeDenominator))
+	den.Add(den, in)
+	return num.Quo(num, den).Uint64(), nil // always less than [reserveOut]
+}
+
+// AddLiquidityShares returns the shares minted for depositing at most
+// [amountA] and [amountB] into a pool with [reserveA], [reserveB], and
+// [supply] shares, along with the amounts of each asset actually deposited.
+func AddLiquidityShares(reserveA, reserveB, supply, amountA, amountB uint64) (uint64, uint64, uint64, error) {
+	if supply == 0 && (reserveA != 0 || reserveB != 0) {
+		// Shares are a regular asset, so they can all be burned while the pool
+		// still has reserves. The next depositor must not be able to claim
+		// them with the initial shares.
+		return 0, 0, 0, ErrOutputPoolUnowned
+	}
+	if supply == 0 || reserveA == 0 || reserveB == 0 {
+		// If all liquidity was removed, the deposit sets a new rate.
+		return initialShares(amountA, amountB), amountA, amountB, nil
+	}
+	sharesA, err := mulDiv(amountA, supply, reserveA)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	sharesB, err := mulDiv(amountB, supply, reserveB)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	shares := min(sharesA, sharesB)
+
+	// We round up the amounts deposited so that adding liquidity can never
+	// dilute existing providers.
+	depositA, err := mulDivUp(shares, reserveA, supply)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	depositB, err := mulDivUp(shares, reserveB, supply)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return shares, depositA, depositB, nil
+}
+
+// RemoveLiquidityAmounts returns the amounts of each asset received for
+// burning [shares] of a pool with [reserveA], [reserveB], and [supply] shares.
+func RemoveLiquidityAmounts(reserveA, reserveB, supply, shares uint64) (uint64, uint64, error) {
+	// We round down the amounts withdrawn so that removing liquidity can never
+	// dilute the remaining providers.
+	amountA, err := mulDiv(shares, reserveA, supply)
+	if err != nil {
+		return 0, 0, err
+	}
+	amountB, err := mulDiv(shares, reserveB, supply)
+	if err != nil {
+		return 0, 0, err
+	}
+	return amountA, amountB, nil
+}
+
+// poolReserves returns the reserves of [pool] ordered as ([in], [out]). It
+// returns false if [in] and [out] are not the assets of [pool].
+func poolReserves(assetA, assetB ids.ID, reserveA, reserveB uint64, in, out ids.ID) (uint64, uint64, bool) {
+	switch {
+	case in == assetA && out == assetB:
+		return reserveA, reserveB, true
+	case in == assetB && out == assetA:
+		return reserveB, reserveA, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// LiquidityResult is a custom successful response output that provides
+// information about a successful [CreatePool], [AddLiquidity], or
+// [RemoveLiquidity].
+type LiquidityResult struct {
+	A      uint64 `json:"a"`
+	B      uint64 `json:"b"`
+	Shares uint64 `json:"shares"`
+}
+
+func UnmarshalLiquidityResult(b []byte) (*LiquidityResult, error) {
+	p := codec.NewReader(b, consts.Uint64Len*3)
+	var result LiquidityResult
+	result.A = p.UnpackUint64(false)
+	result.B = p.UnpackUint64(false)
+	result.Shares = p.UnpackUint64(true)
+	return &result, p.Err()
+}
+
+func (l *LiquidityResult) Marshal() ([]byte, error) {
+	p := codec.NewWriter(consts.Uint64Len*3, consts.Uint64Len*3)
+	p.PackUint64(l.A)
+	p.PackUint64(l.B)
+	p.PackUint64(l.Shares)
+	return p.Bytes(), p.Err()
+}
+
+// PoolSwapResult is a custom successful response output that provides
+// information about a successful [Swap].
+type PoolSwapResult struct {
+	In  uint64 `json:"in"`
+	Out uint64 `json:"out"`
+}
+
+func UnmarshalPoolSwapResult(b []byte) (*PoolSwapResult, error) {
+	p := codec.NewReader(b, consts.Uint64Len*2)
+	var result PoolSwapResult
+	result.In = p.UnpackUint64(true)
+	result.Out = p.UnpackUint64(true)
+	return &result, p.Err()
+}
+
+func (s *PoolSwapResult) Marshal() ([]byte, error) {
+	p := codec.NewWriter(consts.Uint64Len*2, consts.Uint64Len*2)
+	p.PackUint64(s.In)
+	p.PackUint64(s.Out)
+	return p.Bytes(), p.Err()
+}
@@ -0,0 +1,28 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddLiquidityShares(t *testing.T) {
+	require := require.New(t)
+
+	// The first deposit sets the rate
+	shares, a, b, err := AddLiquidityShares(0, 0, 0, 10_000, 4)
+	require.NoError(err)
+	require.Equal([]uint64{200, 10_000, 4}, []uint64{shares, a, b})
+
+	// Later deposits are made at the rate of the pool
+	shares, a, b, err = AddLiquidityShares(20_000, 3, 200, 30_000, 3)
+	require.NoError(err)
+	require.Equal([]uint64{200, 20_000, 3}, []uint64{shares, a, b})
+
+	// Reserves left after all shares were burned can't be claimed
+	_, _, _, err = AddLiquidityShares(20_000, 3, 0, 1, 1)
+	require.ErrorIs(err, ErrOutputPoolUnowned)
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var _ chain.Action = (*RemoveLiquidity)(nil)
+
+type RemoveLiquidity struct {
+	// [Pool] is the PoolID you wish to remove liquidity from.
+	Pool ids.ID `json:"pool"`
+
+	// [AssetA] and [AssetB] are the assets of the pool. We need to provide
+	// these to populate [StateKeys].
+	AssetA ids.ID `json:"assetA"`
+	AssetB ids.ID `json:"assetB"`
+
+	// [Shares] is the amount of shares that will be burned.
+	Shares uint64 `json:"shares"`
+
+	// [MinA] and [MinB] are the minimum amounts of each asset that must be
+	// received for the withdrawal to succeed.
+	MinA uint64 `json:"minA"`
+	MinB uint64 `json:"minB"`
+}
+
+func (*RemoveLiquidity) GetTypeID() uint8 {
+	return removeLiquidityID
+}
+
+func (r *RemoveLiquidity) StateKeys(actor codec.Address, _ ids.ID) state.Keys {
+	return state.Keys{
+		string(storage.PoolKey(r.Pool)):             state.Read | state.Write,
+		string(storage.AssetKey(r.Pool)):            state.Read | state.Write,
+		string(storage.BalanceKey(actor, r.Pool)):   state.Read | state.Write,
+		string(storage.BalanceKey(actor, r.AssetA)): state.All,
+		string(storage.BalanceKey(actor, r.AssetB)): state.All,
+	}
+}
+
+func (*RemoveLiquidity) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.PoolChunks, storage.AssetChunks, storage.BalanceChunks, storage.BalanceChunks, storage.BalanceChunks}
+}
+
+func (r *RemoveLiquidity) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) ([][]byte, error) {
+	if r.Shares == 0 {
+		return nil, ErrOutputValueZero
+	}
+	exists, assetA, assetB, fee, reserveA, reserveB, err := storage.GetPool(ctx, mu, r.Pool)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrOutputPoolMissing
+	}
+	if assetA != r.AssetA || assetB != r.AssetB {
+		return nil, ErrOutputWrongPoolAssets
+	}
+	exists, symbol, decimals, metadata, supply, owner, err := storage.GetAsset(ctx, mu, r.Pool)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrOutputAssetMissing
+	}
+	if r.Shares > supply {
+		return nil, ErrOutputInsufficientShares
+	}
+	amountA, amountB, err := RemoveLiquidityAmounts(reserveA, reserveB, supply, r.Shares)
+	if err != nil {
+		return nil, err
+	}
+	if amountA < r.MinA || amountB < r.MinB {
+		return nil, ErrOutputInsufficientOutput
+	}
+	if err := storage.SubBalance(ctx, mu, actor, r.Pool, r.Shares); err != nil {
+		return nil, err
+	}
+	if err := storage.SetAsset(ctx, mu, r.Pool, symbol, decimals, metadata, supply-r.Shares, owner); err != nil {
+		return nil, err
+	}
+	if err := storage.SetPool(ctx, mu, r.Pool, assetA, assetB, fee, reserveA-amountA, reserveB-amountB); err != nil {
+		return nil, err
+	}
+	if amountA > 0 {
+		if err := storage.AddBalance(ctx, mu, actor, assetA, amountA, true); err != nil {
+			return nil, err
+		}
+	}
+	if amountB > 0 {
+		if err := storage.AddBalance(ctx, mu, actor, assetB, amountB, true); err != nil {
+			return nil, err
+		}
+	}
+	lr := &LiquidityResult{A: amountA, B: amountB, Shares: r.Shares}
+	output, err := lr.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{output}, nil
+}
+
+func (*RemoveLiquidity) ComputeUnits(chain.Rules) uint64 {
+	return RemoveLiquidityComputeUnits
+}
+
+func (*RemoveLiquidity) Size() int {
+	return ids.IDLen*3 + consts.Uint64Len*3
+}
+
+func (r *RemoveLiquidity) Marshal(p *codec.Packer) {
+	p.PackID(r.Pool)
+	p.PackID(r.AssetA)
+	p.PackID(r.AssetB)
+	p.PackUint64(r.Shares)
+	p.PackUint64(r.MinA)
+	p.PackUint64(r.MinB)
+}
+
+func UnmarshalRemoveLiquidity(p *codec.Packer) (chain.Action, error) {
+	var remove RemoveLiquidity
+	p.UnpackID(true, &remove.Pool)
+	p.UnpackID(false, &remove.AssetA) // empty ID is the native asset
+	p.UnpackID(false, &remove.AssetB) // empty ID is the native asset
+	remove.Shares = p.UnpackUint64(true)
+	remove.MinA = p.UnpackUint64(false)
+	remove.MinB = p.UnpackUint64(false)
+	return &remove, p.Err()
+}
+
+func (r *RemoveLiquidity) ValidRange(rules chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(rules, r.GetTypeID())
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var _ chain.Action = (*Swap)(nil)
+
+type Swap struct {
+	// [Pool] is the PoolID you wish to swap with.
+	Pool ids.ID `json:"pool"`
+
+	// [In] is the asset that will be deposited into the pool.
+	In ids.ID `json:"in"`
+
+	// [Out] is the asset that will be received from the pool.
+	Out ids.ID `json:"out"`
+
+	// [Value] is the amount of [In] that will be swapped for [Out].
+	Value uint64 `json:"value"`
+
+	// [MinOut] is the minimum amount of [Out] that must be received for the
+	// swap to succeed.
+	MinOut uint64 `json:"minOut"`
+}
+
+func (*Swap) GetTypeID() uint8 {
+	return swapID
+}
+
+func (s *Swap) StateKeys(actor codec.Address, _ ids.ID) state.Keys {
+	return state.Keys{
+		string(storage.PoolKey(s.Pool)):          state.Read | state.Write,
+		string(storage.BalanceKey(actor, s.In)):  state.Read | state.Write,
+		string(storage.BalanceKey(actor, s.Out)): state.All,
+	}
+}
+
+func (*Swap) StateKeysMaxChunks() []uint16 {
+	return []uint16{storage.PoolChunks, storage.BalanceChunks, storage.BalanceChunks}
+}
+
+func (s *Swap) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) ([][]byte, error) {
+	if s.In == s.Out {
+		return nil, ErrOutputSameInOut
+	}
+	if s.Value == 0 {
+		// This should be guarded via [Unmarshal] but we check anyways.
+		return nil, ErrOutputValueZero
+	}
+	exists, assetA, assetB, fee, reserveA, reserveB, err := storage.GetPool(ctx, mu, s.Pool)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrOutputPoolMissing
+	}
+	reserveIn, reserveOut, ok := poolReserves(assetA, assetB, reserveA, reserveB, s.In, s.Out)
+	if !ok {
+		return nil, ErrOutputWrongPoolAssets
+	}
+	out, err := SwapOutput(reserveIn, reserveOut, fee, s.Value)
+	if err != nil {
+		return nil, err
+	}
+	if out == 0 || out < s.MinOut {
+		return nil, ErrOutputInsufficientOutput
+	}
+	newReserveIn, err := smath.Add64(reserveIn, s.Value)
+	if err != nil {
+		return nil, err
+	}
+	newReserveOut := reserveOut - out
+	if s.In == assetA {
+		reserveA, reserveB = newReserveIn, newReserveOut
+	} else {
+		reserveA, reserveB = newReserveOut, newReserveIn
+	}
+	if err := storage.SubBalance(ctx, mu, actor, s.In, s.Value); err != nil {
+		return nil, err
+	}
+	if err := storage.SetPool(ctx, mu, s.Pool, assetA, assetB, fee, reserveA, reserveB); err != nil {
+		return nil, err
+	}
+	if err := storage.AddBalance(ctx, mu, actor, s.Out, out, true); err != nil {
+		return nil, err
+	}
+	sr := &PoolSwapResult{In: s.Value, Out: out}
+	output, err := sr.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{output}, nil
+}
+
+func (*Swap) ComputeUnits(chain.Rules) uint64 {
+	return SwapComputeUnits
+}
+
+func (*Swap) Size() int {
+	return ids.IDLen*3 + consts.Uint64Len*2
+}
+
+func (s *Swap) Marshal(p *codec.Packer) {
+	p.PackID(s.Pool)
+	p.PackID(s.In)
+	p.PackID(s.Out)
+	p.PackUint64(s.Value)
+	p.PackUint64(s.MinOut)
+}
+
+func UnmarshalSwap(p *codec.Packer) (chain.Action, error) {
+	var swap Swap
+	p.UnpackID(true, &swap.Pool)
+	p.UnpackID(false, &swap.In)  // empty ID is the native asset
+	p.UnpackID(false, &swap.Out) // empty ID is the native asset
+	swap.Value = p.UnpackUint64(true)
+	swap.MinOut = p.UnpackUint64(false)
+	return &swap, p.Err()
+}
+
+func (s *Swap) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, s.GetTypeID())
+}
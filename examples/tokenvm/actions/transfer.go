@@ -97,7 +97,6 @@ func UnmarshalTransfer(p *codec.Packer) (chain.Action, error) {
 	return &transfer, p.Err()
 }
 
-func (*Transfer) ValidRange(chain.Rules) (int64, int64) {
-	// Returning -1, -1 means that the action is always valid.
-	return -1, -1
+func (t *Transfer) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, t.GetTypeID())
 }
@@ -245,6 +245,93 @@ var mintAssetCmd = &cobra.Command{
 	},
 }
 
+var marketSwapCmd = &cobra.Command{
+	Use: "market-swap",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, cli, scli, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Select inbound token
+		inAssetID, err := handler.Root().PromptAsset("in assetID", true)
+		if err != nil {
+			return err
+		}
+		inSymbol, inDecimals, balance, _, err := handler.GetAssetInfo(ctx, tcli, priv.Address, inAssetID, true)
+		if balance == 0 || err != nil {
+			return err
+		}
+
+		// Select outbound token
+		outAssetID, err := handler.Root().PromptAsset("out assetID", true)
+		if err != nil {
+			return err
+		}
+		outSymbol, outDecimals, _, _, err := handler.GetAssetInfo(ctx, tcli, priv.Address, outAssetID, false)
+		if err != nil {
+			return err
+		}
+
+		// Select input to trade
+		value, err := handler.Root().PromptAmount("value", inDecimals, balance, nil)
+		if err != nil {
+			return err
+		}
+
+		// Select orders to fill
+		orders, inAmount, outAmount, err := tcli.SwapOrders(ctx, inAssetID, outAssetID, value)
+		if err != nil {
+			return err
+		}
+		if len(orders) == 0 {
+			utils.Outf("{{red}}no available orders{{/}}\n")
+			utils.Outf("{{red}}exiting...{{/}}\n")
+			return nil
+		}
+		utils.Outf(
+			"{{orange}}orders:{{/}} %d {{orange}}in:{{/}} %s %s {{orange}}out:{{/}} %s %s\n",
+			len(orders),
+			utils.FormatBalance(inAmount, inDecimals),
+			inSymbol,
+			utils.FormatBalance(outAmount, outDecimals),
+			outSymbol,
+		)
+
+		// Select slippage protection
+		minOut, err := handler.Root().PromptAmount(
+			"min out",
+			outDecimals,
+			outAmount,
+			func(input uint64) error {
+				if input == 0 {
+					return ErrMustFill
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		_, err = sendAndWait(ctx, []chain.Action{&actions.MarketSwap{
+			In:     inAssetID,
+			Value:  value,
+			Out:    outAssetID,
+			MinOut: minOut,
+			Orders: orders,
+		}}, cli, scli, tcli, factory)
+		return err
+	},
+}
+
 var closeOrderCmd = &cobra.Command{
 	Use: "close-order",
 	RunE: func(*cobra.Command, []string) error {
@@ -493,3 +580,293 @@ var fillOrderCmd = &cobra.Command{
 		return err
 	},
 }
+
+var createPoolCmd = &cobra.Command{
+	Use: "create-pool",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, cli, scli, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Select first token
+		assetA, err := handler.Root().PromptAsset("assetA", true)
+		if err != nil {
+			return err
+		}
+		_, decimalsA, balanceA, _, err := handler.GetAssetInfo(ctx, tcli, priv.Address, assetA, true)
+		if balanceA == 0 || err != nil {
+			return err
+		}
+		amountA, err := handler.Root().PromptAmount("amountA", decimalsA, balanceA, nil)
+		if err != nil {
+			return err
+		}
+
+		// Select second token
+		assetB, err := handler.Root().PromptAsset("assetB", true)
+		if err != nil {
+			return err
+		}
+		_, decimalsB, balanceB, _, err := handler.GetAssetInfo(ctx, tcli, priv.Address, assetB, true)
+		if balanceB == 0 || err != nil {
+			return err
+		}
+		amountB, err := handler.Root().PromptAmount("amountB", decimalsB, balanceB, nil)
+		if err != nil {
+			return err
+		}
+
+		// Select fee
+		fee, err := handler.Root().PromptInt("fee (basis points)", actions.MaxPoolFee)
+		if err != nil {
+			return err
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		_, err = sendAndWait(ctx, []chain.Action{&actions.CreatePool{
+			AssetA:  assetA,
+			AssetB:  assetB,
+			Fee:     uint16(fee),
+			AmountA: amountA,
+			AmountB: amountB,
+		}}, cli, scli, tcli, factory)
+		return err
+	},
+}
+
+var addLiquidityCmd = &cobra.Command{
+	Use: "add-liquidity",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, cli, scli, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Select pool
+		poolID, err := handler.Root().PromptID("poolID")
+		if err != nil {
+			return err
+		}
+		pool, err := tcli.Pool(ctx, poolID)
+		if err != nil {
+			return err
+		}
+		_, decimalsA, balanceA, _, err := handler.GetAssetInfo(ctx, tcli, priv.Address, pool.AssetA, true)
+		if balanceA == 0 || err != nil {
+			return err
+		}
+		_, decimalsB, balanceB, _, err := handler.GetAssetInfo(ctx, tcli, priv.Address, pool.AssetB, true)
+		if balanceB == 0 || err != nil {
+			return err
+		}
+
+		// Select amounts to deposit
+		amountA, err := handler.Root().PromptAmount("max amountA", decimalsA, balanceA, nil)
+		if err != nil {
+			return err
+		}
+		amountB, err := handler.Root().PromptAmount("max amountB", decimalsB, balanceB, nil)
+		if err != nil {
+			return err
+		}
+		shares, depositA, depositB, err := actions.AddLiquidityShares(pool.ReserveA, pool.ReserveB, pool.Shares, amountA, amountB)
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{orange}}amountA:{{/}} %s {{orange}}amountB:{{/}} %s {{orange}}shares:{{/}} %s\n",
+			utils.FormatBalance(depositA, decimalsA),
+			utils.FormatBalance(depositB, decimalsB),
+			utils.FormatBalance(shares, tconsts.Decimals),
+		)
+
+		// Select slippage protection
+		minShares, err := handler.Root().PromptAmount("min shares", tconsts.Decimals, shares, nil)
+		if err != nil {
+			return err
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		_, err = sendAndWait(ctx, []chain.Action{&actions.AddLiquidity{
+			Pool:      poolID,
+			AssetA:    pool.AssetA,
+			AssetB:    pool.AssetB,
+			AmountA:   amountA,
+			AmountB:   amountB,
+			MinShares: minShares,
+		}}, cli, scli, tcli, factory)
+		return err
+	},
+}
+
+var removeLiquidityCmd = &cobra.Command{
+	Use: "remove-liquidity",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, cli, scli, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Select pool
+		poolID, err := handler.Root().PromptID("poolID")
+		if err != nil {
+			return err
+		}
+		pool, err := tcli.Pool(ctx, poolID)
+		if err != nil {
+			return err
+		}
+		_, _, balance, _, err := handler.GetAssetInfo(ctx, tcli, priv.Address, poolID, true)
+		if balance == 0 || err != nil {
+			return err
+		}
+
+		// Select shares to burn
+		shares, err := handler.Root().PromptAmount("shares", tconsts.Decimals, balance, nil)
+		if err != nil {
+			return err
+		}
+		amountA, amountB, err := actions.RemoveLiquidityAmounts(pool.ReserveA, pool.ReserveB, pool.Shares, shares)
+		if err != nil {
+			return err
+		}
+		symbolA, decimalsA, _, _, err := handler.GetAssetInfo(ctx, tcli, priv.Address, pool.AssetA, false)
+		if err != nil {
+			return err
+		}
+		symbolB, decimalsB, _, _, err := handler.GetAssetInfo(ctx, tcli, priv.Address, pool.AssetB, false)
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{orange}}out:{{/}} %s %s {{orange}}and{{/}} %s %s\n",
+			utils.FormatBalance(amountA, decimalsA),
+			symbolA,
+			utils.FormatBalance(amountB, decimalsB),
+			symbolB,
+		)
+
+		// Select slippage protection
+		minA, err := handler.Root().PromptAmount("min amountA", decimalsA, amountA, nil)
+		if err != nil {
+			return err
+		}
+		minB, err := handler.Root().PromptAmount("min amountB", decimalsB, amountB, nil)
+		if err != nil {
+			return err
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		_, err = sendAndWait(ctx, []chain.Action{&actions.RemoveLiquidity{
+			Pool:   poolID,
+			AssetA: pool.AssetA,
+			AssetB: pool.AssetB,
+			Shares: shares,
+			MinA:   minA,
+			MinB:   minB,
+		}}, cli, scli, tcli, factory)
+		return err
+	},
+}
+
+var swapCmd = &cobra.Command{
+	Use: "swap",
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+		_, priv, factory, cli, scli, tcli, err := handler.DefaultActor()
+		if err != nil {
+			return err
+		}
+
+		// Select pool
+		poolID, err := handler.Root().PromptID("poolID")
+		if err != nil {
+			return err
+		}
+
+		// Select inbound token
+		inAssetID, err := handler.Root().PromptAsset("in assetID", true)
+		if err != nil {
+			return err
+		}
+		inSymbol, inDecimals, balance, _, err := handler.GetAssetInfo(ctx, tcli, priv.Address, inAssetID, true)
+		if balance == 0 || err != nil {
+			return err
+		}
+
+		// Select input to trade
+		value, err := handler.Root().PromptAmount("value", inDecimals, balance, nil)
+		if err != nil {
+			return err
+		}
+		outAssetID, outAmount, err := tcli.QuoteSwap(ctx, poolID, inAssetID, value)
+		if err != nil {
+			return err
+		}
+		outSymbol, outDecimals, _, _, err := handler.GetAssetInfo(ctx, tcli, priv.Address, outAssetID, false)
+		if err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{orange}}in:{{/}} %s %s {{orange}}out:{{/}} %s %s\n",
+			utils.FormatBalance(value, inDecimals),
+			inSymbol,
+			utils.FormatBalance(outAmount, outDecimals),
+			outSymbol,
+		)
+
+		// Select slippage protection
+		minOut, err := handler.Root().PromptAmount(
+			"min out",
+			outDecimals,
+			outAmount,
+			func(input uint64) error {
+				if input == 0 {
+					return ErrMustFill
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		// Confirm action
+		cont, err := handler.Root().PromptContinue()
+		if !cont || err != nil {
+			return err
+		}
+
+		// Generate transaction
+		_, err = sendAndWait(ctx, []chain.Action{&actions.Swap{
+			Pool:   poolID,
+			In:     inAssetID,
+			Out:    outAssetID,
+			Value:  value,
+			MinOut: minOut,
+		}}, cli, scli, tcli, factory)
+		return err
+	},
+}
@@ -6,11 +6,15 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"os"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/spf13/cobra"
 
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/utils"
 
 	trpc "github.com/ava-labs/hypersdk/examples/tokenvm/rpc"
 )
@@ -79,3 +83,91 @@ var watchChainCmd = &cobra.Command{
 		})
 	},
 }
+
+var exportChainCmd = &cobra.Command{
+	Use:   "export [output file] [options]",
+	Short: "Exports the state of the default chain as a genesis or binary snapshot",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		if exportFormat != exportFormatJSON && exportFormat != exportFormatBinary {
+			return ErrInvalidFormat
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		ctx := context.Background()
+		chainID, uris, err := handler.Root().GetDefaultChain(true)
+		if err != nil {
+			return err
+		}
+		networkID, _, _, err := rpc.NewJSONRPCClient(uris[0]).Network(ctx)
+		if err != nil {
+			return err
+		}
+		tcli := trpc.NewJSONRPCClient(uris[0], networkID, chainID)
+		var height *uint64
+		if exportHeight >= 0 {
+			h := uint64(exportHeight)
+			height = &h
+		}
+		reply, err := tcli.ExportState(ctx, height)
+		if err != nil {
+			return err
+		}
+
+		var b []byte
+		switch exportFormat {
+		case exportFormatJSON:
+			// Keep all parameters of the exported chain
+			g, err := tcli.Genesis(ctx)
+			if err != nil {
+				return err
+			}
+			g.State = *reply.State
+			b, err = json.Marshal(g)
+			if err != nil {
+				return err
+			}
+		case exportFormatBinary:
+			b, err = reply.State.Marshal()
+			if err != nil {
+				return err
+			}
+		}
+		if err := os.WriteFile(args[0], b, fsModeWrite); err != nil {
+			return err
+		}
+		utils.Outf(
+			"{{green}}exported state to %s{{/}} {{yellow}}height:{{/}} %d {{yellow}}root:{{/}} %s {{yellow}}assets:{{/}} %d {{yellow}}balances:{{/}} %d {{yellow}}orders:{{/}} %d {{yellow}}pools:{{/}} %d\n",
+			args[0],
+			reply.Height,
+			reply.Root,
+			len(reply.State.CustomAssets),
+			len(reply.State.CustomAllocation)+len(reply.State.CustomBalances),
+			len(reply.State.CustomOrders),
+			len(reply.State.CustomPools),
+		)
+		return nil
+	},
+}
+
+var snapshotChainCmd = &cobra.Command{
+	Use:   "snapshot [output file] [options]",
+	Short: "Exports a snapshot of the default chain that can be imported by a new node",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return ErrInvalidArgs
+		}
+		return nil
+	},
+	RunE: func(_ *cobra.Command, args []string) error {
+		var height *uint64
+		if snapshotHeight >= 0 {
+			h := uint64(snapshotHeight)
+			height = &h
+		}
+		return handler.Root().ExportSnapshot(args[0], height)
+	},
+}
@@ -11,4 +11,5 @@ var (
 	ErrNotMultiple        = errors.New("must be a multiple")
 	ErrInsufficientSupply = errors.New("insufficient supply")
 	ErrMustFill           = errors.New("must fill")
+	ErrInvalidFormat      = errors.New("invalid format")
 )
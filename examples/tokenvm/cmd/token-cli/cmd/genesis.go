@@ -25,7 +25,8 @@ var genGenesisCmd = &cobra.Command{
 	Use:   "generate [custom allocates file] [options]",
 	Short: "Creates a new genesis in the default location",
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 1 {
+		// The genesis state is either the custom allocates or a snapshot
+		if (len(args) == 1) == (len(stateFile) > 0) || len(args) > 1 {
 			return ErrInvalidArgs
 		}
 		return nil
@@ -57,15 +58,27 @@ var genGenesisCmd = &cobra.Command{
 			g.MinBlockGap = minBlockGap
 		}
 
-		a, err := os.ReadFile(args[0])
-		if err != nil {
-			return err
-		}
-		allocs := []*genesis.CustomAllocation{}
-		if err := json.Unmarshal(a, &allocs); err != nil {
-			return err
+		if len(stateFile) > 0 {
+			b, err := os.ReadFile(stateFile)
+			if err != nil {
+				return err
+			}
+			s, err := genesis.UnmarshalState(b)
+			if err != nil {
+				return err
+			}
+			g.State = *s
+		} else {
+			a, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			allocs := []*genesis.CustomAllocation{}
+			if err := json.Unmarshal(a, &allocs); err != nil {
+				return err
+			}
+			g.CustomAllocation = allocs
 		}
-		g.CustomAllocation = allocs
 
 		b, err := json.Marshal(g)
 		if err != nil {
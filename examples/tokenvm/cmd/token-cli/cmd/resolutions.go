@@ -136,8 +136,50 @@ func handleTx(c *trpc.JSONRPCClient, tx *chain.Transaction, result *chain.Result
 				"%s %s -> %s %s (remaining: %s %s)",
 				inAmtStr, inSymbol, outAmtStr, outSymbol, remainingStr, outSymbol,
 			)
+		case *actions.MarketSwap:
+			sr, _ := actions.UnmarshalSwapResult(result.Outputs[i][0])
+			_, inSymbol, inDecimals, _, _, _, err := c.Asset(context.TODO(), action.In, true)
+			if err != nil {
+				utils.Outf("{{red}}could not fetch asset info:{{/}} %v", err)
+				return
+			}
+			inAmtStr := utils.FormatBalance(sr.In, inDecimals)
+			_, outSymbol, outDecimals, _, _, _, err := c.Asset(context.TODO(), action.Out, true)
+			if err != nil {
+				utils.Outf("{{red}}could not fetch asset info:{{/}} %v", err)
+				return
+			}
+			outAmtStr := utils.FormatBalance(sr.Out, outDecimals)
+			summaryStr = fmt.Sprintf(
+				"%s %s -> %s %s (orders filled: %d)",
+				inAmtStr, inSymbol, outAmtStr, outSymbol, len(sr.Fills),
+			)
 		case *actions.CloseOrder:
 			summaryStr = fmt.Sprintf("orderID: %s", action.Order)
+		case *actions.CreatePool:
+			lr, _ := actions.UnmarshalLiquidityResult(result.Outputs[i][0])
+			summaryStr = fmt.Sprintf("poolID: %s fee: %d shares: %s", actionID, action.Fee, utils.FormatBalance(lr.Shares, tconsts.Decimals))
+		case *actions.AddLiquidity:
+			lr, _ := actions.UnmarshalLiquidityResult(result.Outputs[i][0])
+			summaryStr = fmt.Sprintf("poolID: %s amountA: %d amountB: %d -> shares: %s", action.Pool, lr.A, lr.B, utils.FormatBalance(lr.Shares, tconsts.Decimals))
+		case *actions.RemoveLiquidity:
+			lr, _ := actions.UnmarshalLiquidityResult(result.Outputs[i][0])
+			summaryStr = fmt.Sprintf("poolID: %s shares: %s -> amountA: %d amountB: %d", action.Pool, utils.FormatBalance(lr.Shares, tconsts.Decimals), lr.A, lr.B)
+		case *actions.Swap:
+			sr, _ := actions.UnmarshalPoolSwapResult(result.Outputs[i][0])
+			_, inSymbol, inDecimals, _, _, _, err := c.Asset(context.TODO(), action.In, true)
+			if err != nil {
+				utils.Outf("{{red}}could not fetch asset info:{{/}} %v", err)
+				return
+			}
+			inAmtStr := utils.FormatBalance(sr.In, inDecimals)
+			_, outSymbol, outDecimals, _, _, _, err := c.Asset(context.TODO(), action.Out, true)
+			if err != nil {
+				utils.Outf("{{red}}could not fetch asset info:{{/}} %v", err)
+				return
+			}
+			outAmtStr := utils.FormatBalance(sr.Out, outDecimals)
+			summaryStr = fmt.Sprintf("%s %s -> %s %s (poolID: %s)", inAmtStr, inSymbol, outAmtStr, outSymbol, action.Pool)
 		}
 		utils.Outf(
 			"%s {{yellow}}%s{{/}} {{yellow}}actor:{{/}} %s {{yellow}}summary (%s):{{/}} [%s] {{yellow}}fee (max %.2f%%):{{/}} %s %s {{yellow}}consumed:{{/}} [%s]\n",
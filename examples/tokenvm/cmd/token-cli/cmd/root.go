@@ -17,6 +17,9 @@ const (
 	fsModeWrite     = 0o600
 	defaultDatabase = ".token-cli"
 	defaultGenesis  = "genesis.json"
+
+	exportFormatJSON   = "json"
+	exportFormatBinary = "binary"
 )
 
 var (
@@ -36,6 +39,10 @@ var (
 	prometheusData        string
 	startPrometheus       bool
 	numCores              int
+	exportHeight          int64
+	exportFormat          string
+	snapshotHeight        int64
+	stateFile             string
 
 	rootCmd = &cobra.Command{
 		Use:        "token-cli",
@@ -106,6 +113,12 @@ func init() {
 		-1,
 		"minimum block gap (ms)",
 	)
+	genGenesisCmd.PersistentFlags().StringVar(
+		&stateFile,
+		"state-file",
+		"",
+		"binary snapshot created by \"chain export --format binary\" to use as the genesis state (instead of custom allocates)",
+	)
 	genesisCmd.AddCommand(
 		genGenesisCmd,
 	)
@@ -138,6 +151,24 @@ func init() {
 		false,
 		"hide txs",
 	)
+	exportChainCmd.PersistentFlags().Int64Var(
+		&exportHeight,
+		"height",
+		-1,
+		"height to export (defaults to the last accepted height)",
+	)
+	exportChainCmd.PersistentFlags().StringVar(
+		&exportFormat,
+		"format",
+		exportFormatJSON,
+		"output format (json genesis or binary snapshot)",
+	)
+	snapshotChainCmd.PersistentFlags().Int64Var(
+		&snapshotHeight,
+		"height",
+		-1,
+		"height to snapshot (defaults to the last accepted height)",
+	)
 	chainCmd.AddCommand(
 		importChainCmd,
 		importANRChainCmd,
@@ -145,6 +176,8 @@ func init() {
 		setChainCmd,
 		chainInfoCmd,
 		watchChainCmd,
+		exportChainCmd,
+		snapshotChainCmd,
 	)
 
 	// actions
@@ -159,7 +192,13 @@ func init() {
 
 		createOrderCmd,
 		fillOrderCmd,
+		marketSwapCmd,
 		closeOrderCmd,
+
+		createPoolCmd,
+		addLiquidityCmd,
+		removeLiquidityCmd,
+		swapCmd,
 	)
 
 	// spam
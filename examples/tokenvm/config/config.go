@@ -19,11 +19,17 @@ type Config struct {
 	NoGossipBuilderDiff int   `json:"noGossipBuilderDiff"`
 	VerifyTimeout       int64 `json:"verifyTimeout"`
 
+	// Pull-based gossip
+	GossipAnnounce         bool `json:"gossipAnnounce"`
+	GossipPushProposerDiff int  `json:"gossipPushProposerDiff"`
+
 	// Order Book
 	//
 	// This is denoted as <asset 1>-<asset 2>
-	MaxOrdersPerPair int      `json:"maxOrdersPerPair"`
-	TrackedPairs     []string `json:"trackedPairs"` // which asset ID pairs we care about
+	MaxOrdersPerPair int               `json:"maxOrdersPerPair"`
+	TrackedPairs     []string          `json:"trackedPairs"`     // which asset ID pairs we care about
+	MinOrderSupply   map[string]uint64 `json:"minOrderSupply"`   // min supply of orders we track ("*" for all pairs)
+	MaxTradesPerPair int               `json:"maxTradesPerPair"` // how many recent trades we keep per pair
 
 	// Misc
 	StoreTransactions bool          `json:"storeTransactions"`
@@ -42,6 +48,10 @@ func New(b []byte) (*Config, error) {
 		VerifyTimeout:       gcfg.VerifyTimeout,
 		StoreTransactions:   true,
 		MaxOrdersPerPair:    1024,
+		MaxTradesPerPair:    1024,
+
+		GossipAnnounce:         gcfg.GossipAnnounce,
+		GossipPushProposerDiff: gcfg.GossipPushProposerDiff,
 	}
 
 	if len(b) > 0 {
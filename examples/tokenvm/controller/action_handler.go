@@ -6,18 +6,46 @@ package controller
 import (
 	"context"
 
+	"github.com/ava-labs/avalanchego/database"
+
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/orderbook"
 	"github.com/ava-labs/hypersdk/extension/indexer"
 )
 
-var _ indexer.SuccessfulTxSubscriber = (*actionHandler)(nil)
+var _ indexer.AcceptedSubscriber = (*actionHandler)(nil)
 
 type actionHandler struct {
 	c *Controller
 }
 
-func (a *actionHandler) Accepted(_ context.Context, tx *chain.Transaction, result *chain.Result) error {
+// Accepted updates the order book with all successful transactions in [blk]
+// and persists the changes in a single batch.
+func (a *actionHandler) Accepted(_ context.Context, blk *chain.StatelessBlock) error {
+	batch := a.c.orderBookDB.NewBatch()
+	defer batch.Reset()
+
+	results := blk.Results()
+	for j, tx := range blk.Txs {
+		result := results[j]
+		if !result.Success {
+			continue
+		}
+		if err := a.acceptedTx(batch, blk, j, tx, result); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+func (a *actionHandler) acceptedTx(
+	batch database.KeyValueWriterDeleter,
+	blk *chain.StatelessBlock,
+	txIndex int,
+	tx *chain.Transaction,
+	result *chain.Result,
+) error {
 	for i, act := range tx.Actions {
 		switch action := act.(type) {
 		case *actions.CreateAsset:
@@ -30,7 +58,9 @@ func (a *actionHandler) Accepted(_ context.Context, tx *chain.Transaction, resul
 			a.c.metrics.transfer.Inc()
 		case *actions.CreateOrder:
 			a.c.metrics.createOrder.Inc()
-			a.c.orderBook.Add(chain.CreateActionID(tx.ID(), uint8(i)), tx.Auth.Actor(), action)
+			if err := a.c.orderBook.Add(batch, chain.CreateActionID(tx.ID(), uint8(i)), tx.Auth.Actor(), action); err != nil {
+				return err
+			}
 		case *actions.FillOrder:
 			a.c.metrics.fillOrder.Inc()
 			outputs := result.Outputs[i]
@@ -40,17 +70,61 @@ func (a *actionHandler) Accepted(_ context.Context, tx *chain.Transaction, resul
 					// This should never happen
 					return err
 				}
-				if orderResult.Remaining == 0 {
-					a.c.orderBook.Remove(action.Order)
-					continue
+				trade := orderbook.NewTrade(
+					tx.ID(), blk.Hght, blk.Tmstmp, txIndex, i, 0,
+					action.Order, action.Owner, tx.Auth.Actor(),
+					action.In, orderResult.In, action.Out, orderResult.Out,
+				)
+				if err := a.c.updateOrderBook(batch, trade, orderResult.Remaining); err != nil {
+					return err
+				}
+			}
+		case *actions.MarketSwap:
+			a.c.metrics.marketSwap.Inc()
+			outputs := result.Outputs[i]
+			for _, output := range outputs {
+				swapResult, err := actions.UnmarshalSwapResult(output)
+				if err != nil {
+					// This should never happen
+					return err
+				}
+				for k, fill := range swapResult.Fills {
+					trade := orderbook.NewTrade(
+						tx.ID(), blk.Hght, blk.Tmstmp, txIndex, i, k,
+						fill.Order, fill.Owner, tx.Auth.Actor(),
+						action.In, fill.In, action.Out, fill.Out,
+					)
+					if err := a.c.updateOrderBook(batch, trade, fill.Remaining); err != nil {
+						return err
+					}
 				}
-				a.c.orderBook.UpdateRemaining(action.Order, orderResult.Remaining)
 			}
 		case *actions.CloseOrder:
 			a.c.metrics.closeOrder.Inc()
-			a.c.orderBook.Remove(action.Order)
+			if err := a.c.orderBook.Remove(batch, action.Order); err != nil {
+				return err
+			}
+		case *actions.CreatePool:
+			a.c.metrics.createPool.Inc()
+		case *actions.AddLiquidity:
+			a.c.metrics.addLiquidity.Inc()
+		case *actions.RemoveLiquidity:
+			a.c.metrics.removeLiquidity.Inc()
+		case *actions.Swap:
+			a.c.metrics.swap.Inc()
 		}
 	}
-
 	return nil
 }
+
+// updateOrderBook records [trade] and updates the [remaining] supply of the
+// filled order.
+func (c *Controller) updateOrderBook(batch database.KeyValueWriterDeleter, trade *orderbook.Trade, remaining uint64) error {
+	if err := c.orderBook.RecordTrade(batch, trade); err != nil {
+		return err
+	}
+	if remaining == 0 {
+		return c.orderBook.Remove(batch, trade.OrderID)
+	}
+	return c.orderBook.UpdateRemaining(batch, trade.OrderID, remaining)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/x/merkledb"
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/hypersdk/auth"
@@ -31,7 +32,10 @@ import (
 	hstorage "github.com/ava-labs/hypersdk/storage"
 )
 
-var _ vm.Controller = (*Controller)(nil)
+var (
+	_ vm.Controller            = (*Controller)(nil)
+	_ vm.StateLoadedController = (*Controller)(nil)
+)
 
 type Controller struct {
 	inner *vm.VM
@@ -47,7 +51,8 @@ type Controller struct {
 	txIndexer          indexer.TxIndexer
 	acceptedSubscriber indexer.AcceptedSubscriber
 
-	orderBook *orderbook.OrderBook
+	orderBookDB database.Database
+	orderBook   *orderbook.OrderBook
 }
 
 func New() *vm.VM {
@@ -59,7 +64,7 @@ func (c *Controller) Initialize(
 	snowCtx *snow.Context,
 	gatherer ametrics.MultiGatherer,
 	genesisBytes []byte,
-	upgradeBytes []byte, // subnets to allow for AWM
+	upgradeBytes []byte, // scheduled rule changes (see [genesis.Genesis.SetUpgrades])
 	configBytes []byte,
 ) (
 	vm.Genesis,
@@ -105,8 +110,12 @@ func (c *Controller) Initialize(
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, err
 	}
+	c.orderBookDB, err = hstorage.New(pebble.NewDefaultConfig(), snowCtx.ChainDataDir, "orderbook", gatherer)
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, err
+	}
 	acceptedSubscribers := []indexer.AcceptedSubscriber{
-		indexer.NewSuccessfulTxSubscriber(&actionHandler{c: c}),
+		&actionHandler{c: c},
 	}
 	if c.config.StoreTransactions {
 		c.txIndexer = indexer.NewTxDBIndexer(c.txDB)
@@ -147,19 +156,43 @@ func (c *Controller) Initialize(
 		gcfg.GossipProposerDepth = c.config.GossipProposerDepth
 		gcfg.NoGossipBuilderDiff = c.config.NoGossipBuilderDiff
 		gcfg.VerifyTimeout = c.config.VerifyTimeout
+		gcfg.GossipAnnounce = c.config.GossipAnnounce
+		gcfg.GossipPushProposerDiff = c.config.GossipPushProposerDiff
 		gossip, err = gossiper.NewProposer(inner, gcfg)
 		if err != nil {
 			return nil, nil, nil, nil, nil, nil, nil, err
 		}
 	}
 
-	// Initialize order book used to track all open orders
-	c.orderBook = orderbook.New(c, c.config.TrackedPairs, c.config.MaxOrdersPerPair)
+	// Initialize order book used to track all open orders (restoring any
+	// orders and trades persisted before a restart)
+	c.orderBook, err = orderbook.New(
+		c,
+		c.orderBookDB,
+		c.config.TrackedPairs,
+		c.config.MaxOrdersPerPair,
+		c.config.MinOrderSupply,
+		c.config.MaxTradesPerPair,
+	)
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, err
+	}
 	return c.genesis, build, gossip, apis, consts.ActionRegistry, consts.AuthRegistry, auth.Engines(), nil
 }
 
+// StateLoaded reconciles the order book with the open orders in [state], which
+// may have been created in blocks the order book never processed.
+func (c *Controller) StateLoaded(_ context.Context, state merkledb.MerkleDB) error {
+	batch := c.orderBookDB.NewBatch()
+	defer batch.Reset()
+
+	if err := c.orderBook.Reconcile(batch, state); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
 func (c *Controller) Rules(t int64) chain.Rules {
-	// TODO: extend with [UpgradeBytes]
 	return c.genesis.Rules(t, c.snowCtx.NetworkID, c.snowCtx.ChainID)
 }
 
@@ -173,5 +206,8 @@ func (c *Controller) Accepted(ctx context.Context, blk *chain.StatelessBlock) er
 
 func (c *Controller) Shutdown(context.Context) error {
 	// Close any databases created during initialization
+	if err := c.orderBookDB.Close(); err != nil {
+		return err
+	}
 	return c.txDB.Close()
 }
@@ -22,6 +22,12 @@ type metrics struct {
 	createOrder prometheus.Counter
 	fillOrder   prometheus.Counter
 	closeOrder  prometheus.Counter
+	marketSwap  prometheus.Counter
+
+	createPool      prometheus.Counter
+	addLiquidity    prometheus.Counter
+	removeLiquidity prometheus.Counter
+	swap            prometheus.Counter
 
 	importAsset prometheus.Counter
 	exportAsset prometheus.Counter
@@ -64,6 +70,31 @@ func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
 			Name:      "close_order",
 			Help:      "number of close order actions",
 		}),
+		marketSwap: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "market_swap",
+			Help:      "number of market swap actions",
+		}),
+		createPool: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "create_pool",
+			Help:      "number of create pool actions",
+		}),
+		addLiquidity: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "add_liquidity",
+			Help:      "number of add liquidity actions",
+		}),
+		removeLiquidity: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "remove_liquidity",
+			Help:      "number of remove liquidity actions",
+		}),
+		swap: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "actions",
+			Name:      "swap",
+			Help:      "number of swap actions",
+		}),
 		importAsset: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: "actions",
 			Name:      "import_asset",
@@ -87,6 +118,12 @@ func newMetrics(gatherer ametrics.MultiGatherer) (*metrics, error) {
 		r.Register(m.createOrder),
 		r.Register(m.fillOrder),
 		r.Register(m.closeOrder),
+		r.Register(m.marketSwap),
+
+		r.Register(m.createPool),
+		r.Register(m.addLiquidity),
+		r.Register(m.removeLiquidity),
+		r.Register(m.swap),
 
 		r.Register(m.importAsset),
 		r.Register(m.exportAsset),
@@ -5,6 +5,7 @@ package controller
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/trace"
@@ -33,25 +34,48 @@ func (c *Controller) GetTransaction(txID ids.ID) (bool, int64, bool, fees.Dimens
 	return c.txIndexer.GetTransaction(txID)
 }
 
+// readState returns a [storage.ReadState] for the post-execution state of the
+// accepted block at [height] (or for the latest state if [height] is nil).
+//
+// Reading historical state requires the node to run in archive mode.
+func (c *Controller) readState(height *uint64) storage.ReadState {
+	if height == nil {
+		return c.inner.ReadState
+	}
+	return func(ctx context.Context, keys [][]byte) ([][]byte, []error) {
+		return c.inner.ReadStateAt(ctx, keys, *height)
+	}
+}
+
 func (c *Controller) GetAssetFromState(
 	ctx context.Context,
 	asset ids.ID,
+	height *uint64,
 ) (bool, []byte, uint8, []byte, uint64, codec.Address, error) {
-	return storage.GetAssetFromState(ctx, c.inner.ReadState, asset)
+	return storage.GetAssetFromState(ctx, c.readState(height), asset)
 }
 
 func (c *Controller) GetBalanceFromState(
 	ctx context.Context,
 	addr codec.Address,
 	asset ids.ID,
+	height *uint64,
 ) (uint64, error) {
-	return storage.GetBalanceFromState(ctx, c.inner.ReadState, addr, asset)
+	return storage.GetBalanceFromState(ctx, c.readState(height), addr, asset)
 }
 
 func (c *Controller) Orders(pair string, limit int) []*orderbook.Order {
 	return c.orderBook.Orders(pair, limit)
 }
 
+func (c *Controller) Depth(pair string, limit int) []*orderbook.Level {
+	return c.orderBook.Depth(pair, limit)
+}
+
+func (c *Controller) Trades(pair string, limit int) []*orderbook.Trade {
+	return c.orderBook.Trades(pair, limit)
+}
+
 func (c *Controller) GetOrderFromState(
 	ctx context.Context,
 	orderID ids.ID,
@@ -67,3 +91,62 @@ func (c *Controller) GetOrderFromState(
 ) {
 	return storage.GetOrderFromState(ctx, c.inner.ReadState, orderID)
 }
+
+func (c *Controller) GetPoolFromState(
+	ctx context.Context,
+	poolID ids.ID,
+) (
+	bool, // exists
+	ids.ID, // assetA
+	ids.ID, // assetB
+	uint16, // fee
+	uint64, // reserveA
+	uint64, // reserveB
+	error,
+) {
+	return storage.GetPoolFromState(ctx, c.inner.ReadState, poolID)
+}
+
+// ExportState returns all balances, assets, orders, and pools at [height] (or
+// at the last accepted height if [height] is nil).
+//
+// Because the state root of each block is the result of executing its parent,
+// historical state can only be exported for heights whose child is still on
+// disk and whose root is still within [StateHistoryLength].
+func (c *Controller) ExportState(ctx context.Context, height *uint64) (uint64, ids.ID, *genesis.State, error) {
+	db, err := c.inner.State()
+	if err != nil {
+		return 0, ids.Empty, nil, err
+	}
+	var (
+		lastAccepted = c.inner.LastAcceptedBlock()
+		exportHeight = lastAccepted.Hght
+		root         ids.ID
+	)
+	if height == nil || *height == lastAccepted.Hght {
+		// The post-execution state of the last accepted block is always
+		// committed to disk.
+		root, err = db.GetMerkleRoot(ctx)
+		if err != nil {
+			return 0, ids.Empty, nil, err
+		}
+	} else {
+		if *height > lastAccepted.Hght {
+			return 0, ids.Empty, nil, fmt.Errorf("%w: height=%d, last accepted=%d", ErrHeightNotAccepted, *height, lastAccepted.Hght)
+		}
+		blk, err := c.inner.GetDiskBlock(ctx, *height+1)
+		if err != nil {
+			return 0, ids.Empty, nil, fmt.Errorf("%w: could not load block at height %d", err, *height+1)
+		}
+		exportHeight = *height
+		root = blk.StateRoot
+	}
+	s := &genesis.State{}
+	if err := c.inner.IterateStateAtRoot(ctx, root, func(k []byte, v []byte) error {
+		s.Add(k, v)
+		return nil
+	}); err != nil {
+		return 0, ids.Empty, nil, fmt.Errorf("%w: could not read state at root %s", err, root)
+	}
+	return exportHeight, root, s, nil
+}
@@ -8,4 +8,8 @@ import "errors"
 var (
 	ErrInvalidHRP    = errors.New("invalid HRP")
 	ErrInvalidTarget = errors.New("invalid target")
+
+	ErrInvalidAsset    = errors.New("invalid asset")
+	ErrInvalidAddress  = errors.New("invalid address")
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
 )
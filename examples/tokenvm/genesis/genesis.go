@@ -8,58 +8,31 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/x/merkledb"
 
-	"github.com/ava-labs/hypersdk/codec"
-	"github.com/ava-labs/hypersdk/examples/tokenvm/consts"
-	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 	"github.com/ava-labs/hypersdk/fees"
 	"github.com/ava-labs/hypersdk/state"
 	"github.com/ava-labs/hypersdk/vm"
 
-	smath "github.com/ava-labs/avalanchego/utils/math"
 	hconsts "github.com/ava-labs/hypersdk/consts"
+	hgenesis "github.com/ava-labs/hypersdk/genesis"
 )
 
 var _ vm.Genesis = (*Genesis)(nil)
 
-type CustomAllocation struct {
-	Address string `json:"address"` // bech32 address
-	Balance uint64 `json:"balance"`
-}
-
 type Genesis struct {
 	// State Parameters
 	StateBranchFactor merkledb.BranchFactor `json:"stateBranchFactor"`
 
-	// Chain Parameters
-	MinBlockGap      int64 `json:"minBlockGap"`      // ms
-	MinEmptyBlockGap int64 `json:"minEmptyBlockGap"` // ms
-
-	// Chain Fee Parameters
-	MinUnitPrice               fees.Dimensions `json:"minUnitPrice"`
-	UnitPriceChangeDenominator fees.Dimensions `json:"unitPriceChangeDenominator"`
-	WindowTargetUnits          fees.Dimensions `json:"windowTargetUnits"` // 10s
-	MaxBlockUnits              fees.Dimensions `json:"maxBlockUnits"`     // must be possible to reach before block too large
-
-	// Tx Parameters
-	ValidityWindow      int64 `json:"validityWindow"` // ms
-	MaxActionsPerTx     uint8 `json:"maxActionsPerTx"`
-	MaxOutputsPerAction uint8 `json:"maxOutputsPerAction"`
-
-	// Tx Fee Parameters
-	BaseComputeUnits          uint64 `json:"baseUnits"`
-	StorageKeyReadUnits       uint64 `json:"storageKeyReadUnits"`
-	StorageValueReadUnits     uint64 `json:"storageValueReadUnits"` // per chunk
-	StorageKeyAllocateUnits   uint64 `json:"storageKeyAllocateUnits"`
-	StorageValueAllocateUnits uint64 `json:"storageValueAllocateUnits"` // per chunk
-	StorageKeyWriteUnits      uint64 `json:"storageKeyWriteUnits"`
-	StorageValueWriteUnits    uint64 `json:"storageValueWriteUnits"` // per chunk
+	// Chain, Tx, and Fee Parameters (can be changed by [hgenesis.Upgrades])
+	hgenesis.Params
 
 	// Allocates
-	CustomAllocation []*CustomAllocation `json:"customAllocation"`
+	State
+
+	upgrades *hgenesis.Upgrades
+	schedule *hgenesis.Schedule
 }
 
 func Default() *Genesis {
@@ -67,46 +40,71 @@ func Default() *Genesis {
 		// State Parameters
 		StateBranchFactor: merkledb.BranchFactor16,
 
-		// Chain Parameters
-		MinBlockGap:      100,
-		MinEmptyBlockGap: 750,
-
-		// Chain Fee Parameters
-		MinUnitPrice:               fees.Dimensions{100, 100, 100, 100, 100},
-		UnitPriceChangeDenominator: fees.Dimensions{48, 48, 48, 48, 48},
-		WindowTargetUnits:          fees.Dimensions{20_000_000, 1_000, 1_000, 1_000, 1_000},
-		MaxBlockUnits:              fees.Dimensions{1_800_000, 2_000, 2_000, 2_000, 2_000},
-
-		// Tx Parameters
-		ValidityWindow:      60 * hconsts.MillisecondsPerSecond, // ms
-		MaxActionsPerTx:     16,
-		MaxOutputsPerAction: 1,
-
-		// Tx Fee Compute Parameters
-		BaseComputeUnits: 1,
-
-		// Tx Fee Storage Parameters
-		//
-		// TODO: tune this
-		StorageKeyReadUnits:       5,
-		StorageValueReadUnits:     2,
-		StorageKeyAllocateUnits:   20,
-		StorageValueAllocateUnits: 5,
-		StorageKeyWriteUnits:      10,
-		StorageValueWriteUnits:    3,
+		Params: hgenesis.Params{
+			// Chain Parameters
+			MinBlockGap:      100,
+			MinEmptyBlockGap: 750,
+
+			// Chain Fee Parameters
+			MinUnitPrice:               fees.Dimensions{100, 100, 100, 100, 100},
+			UnitPriceChangeDenominator: fees.Dimensions{48, 48, 48, 48, 48},
+			WindowTargetUnits:          fees.Dimensions{20_000_000, 1_000, 1_000, 1_000, 1_000},
+			MaxBlockUnits:              fees.Dimensions{1_800_000, 2_000, 2_000, 2_000, 2_000},
+
+			// Tx Parameters
+			ValidityWindow:      60 * hconsts.MillisecondsPerSecond, // ms
+			MaxActionsPerTx:     16,
+			MaxOutputsPerAction: 1,
+
+			// Tx Fee Compute Parameters
+			BaseComputeUnits: 1,
+
+			// Tx Fee Storage Parameters
+			//
+			// TODO: tune this
+			StorageKeyReadUnits:       5,
+			StorageValueReadUnits:     2,
+			StorageKeyAllocateUnits:   20,
+			StorageValueAllocateUnits: 5,
+			StorageKeyWriteUnits:      10,
+			StorageValueWriteUnits:    3,
+		},
 	}
 }
 
-func New(b []byte, _ []byte /* upgradeBytes */) (*Genesis, error) {
+func New(b []byte, upgradeBytes []byte) (*Genesis, error) {
 	g := Default()
 	if len(b) > 0 {
 		if err := json.Unmarshal(b, g); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal config %s: %w", string(b), err)
 		}
 	}
+	u, err := hgenesis.ParseUpgrades(upgradeBytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.SetUpgrades(u); err != nil {
+		return nil, err
+	}
 	return g, nil
 }
 
+// SetUpgrades schedules [u] on top of the parameters of [g].
+func (g *Genesis) SetUpgrades(u *hgenesis.Upgrades) error {
+	s, err := hgenesis.NewSchedule(&g.Params, u)
+	if err != nil {
+		return err
+	}
+	g.upgrades = u
+	g.schedule = s
+	return nil
+}
+
+// Upgrades returns the upgrades scheduled on [g] (if any).
+func (g *Genesis) Upgrades() *hgenesis.Upgrades {
+	return g.upgrades
+}
+
 func (g *Genesis) Load(ctx context.Context, tracer trace.Tracer, mu state.Mutable) error {
 	ctx, span := tracer.Start(ctx, "Genesis.Load")
 	defer span.End()
@@ -115,30 +113,7 @@ func (g *Genesis) Load(ctx context.Context, tracer trace.Tracer, mu state.Mutabl
 		return err
 	}
 
-	supply := uint64(0)
-	for _, alloc := range g.CustomAllocation {
-		pk, err := codec.ParseAddressBech32(consts.HRP, alloc.Address)
-		if err != nil {
-			return err
-		}
-		supply, err = smath.Add64(supply, alloc.Balance)
-		if err != nil {
-			return err
-		}
-		if err := storage.SetBalance(ctx, mu, pk, ids.Empty, alloc.Balance); err != nil {
-			return fmt.Errorf("%w: addr=%s, bal=%d", err, alloc.Address, alloc.Balance)
-		}
-	}
-	return storage.SetAsset(
-		ctx,
-		mu,
-		ids.Empty,
-		[]byte(consts.Symbol),
-		consts.Decimals,
-		[]byte(consts.Name),
-		supply,
-		codec.EmptyAddress,
-	)
+	return g.State.load(ctx, mu)
 }
 
 func (g *Genesis) GetStateBranchFactor() merkledb.BranchFactor {
@@ -8,99 +8,28 @@ import (
 
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
-	"github.com/ava-labs/hypersdk/fees"
+
+	hgenesis "github.com/ava-labs/hypersdk/genesis"
 )
 
-var _ chain.Rules = (*Rules)(nil)
+var _ chain.ScheduledRules = (*Rules)(nil)
 
+// Rules are the [hgenesis.Rules] in effect at some timestamp with the
+// parameters that are specific to the tokenvm.
 type Rules struct {
-	g *Genesis
-
-	networkID uint32
-	chainID   ids.ID
-}
-
-// TODO: use upgradeBytes
-func (g *Genesis) Rules(_ int64, networkID uint32, chainID ids.ID) *Rules {
-	return &Rules{g, networkID, chainID}
-}
-
-func (r *Rules) NetworkID() uint32 {
-	return r.networkID
-}
-
-func (r *Rules) ChainID() ids.ID {
-	return r.chainID
-}
-
-func (r *Rules) GetMinBlockGap() int64 {
-	return r.g.MinBlockGap
-}
-
-func (r *Rules) GetMinEmptyBlockGap() int64 {
-	return r.g.MinEmptyBlockGap
-}
-
-func (r *Rules) GetValidityWindow() int64 {
-	return r.g.ValidityWindow
-}
-
-func (r *Rules) GetMaxActionsPerTx() uint8 {
-	return r.g.MaxActionsPerTx
-}
-
-func (r *Rules) GetMaxOutputsPerAction() uint8 {
-	return r.g.MaxOutputsPerAction
-}
-
-func (r *Rules) GetMaxBlockUnits() fees.Dimensions {
-	return r.g.MaxBlockUnits
+	*hgenesis.Rules
 }
 
-func (r *Rules) GetBaseComputeUnits() uint64 {
-	return r.g.BaseComputeUnits
+// Rules returns the rules in effect at [t] (after applying all
+// [hgenesis.Upgrades] activated at or before [t]).
+func (g *Genesis) Rules(t int64, networkID uint32, chainID ids.ID) *Rules {
+	return &Rules{hgenesis.NewRules(&g.Params, g.schedule, t, networkID, chainID)}
 }
 
 func (*Rules) GetSponsorStateKeysMaxChunks() []uint16 {
 	return []uint16{storage.BalanceChunks}
 }
 
-func (r *Rules) GetStorageKeyReadUnits() uint64 {
-	return r.g.StorageKeyReadUnits
-}
-
-func (r *Rules) GetStorageValueReadUnits() uint64 {
-	return r.g.StorageValueReadUnits
-}
-
-func (r *Rules) GetStorageKeyAllocateUnits() uint64 {
-	return r.g.StorageKeyAllocateUnits
-}
-
-func (r *Rules) GetStorageValueAllocateUnits() uint64 {
-	return r.g.StorageValueAllocateUnits
-}
-
-func (r *Rules) GetStorageKeyWriteUnits() uint64 {
-	return r.g.StorageKeyWriteUnits
-}
-
-func (r *Rules) GetStorageValueWriteUnits() uint64 {
-	return r.g.StorageValueWriteUnits
-}
-
-func (r *Rules) GetMinUnitPrice() fees.Dimensions {
-	return r.g.MinUnitPrice
-}
-
-func (r *Rules) GetUnitPriceChangeDenominator() fees.Dimensions {
-	return r.g.UnitPriceChangeDenominator
-}
-
-func (r *Rules) GetWindowTargetUnits() fees.Dimensions {
-	return r.g.WindowTargetUnits
-}
-
 func (*Rules) FetchCustom(string) (any, bool) {
 	return nil, false
 }
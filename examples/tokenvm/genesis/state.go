@@ -0,0 +1,390 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+	hconsts "github.com/ava-labs/hypersdk/consts"
+)
+
+type CustomAllocation struct {
+	Address string `json:"address"` // bech32 address
+	Balance uint64 `json:"balance"`
+}
+
+type CustomAsset struct {
+	ID       ids.ID `json:"id"`
+	Symbol   []byte `json:"symbol"`
+	Decimals uint8  `json:"decimals"`
+	Metadata []byte `json:"metadata"`
+	Supply   uint64 `json:"supply"`
+	Owner    string `json:"owner"` // bech32 address
+}
+
+type CustomBalance struct {
+	Address string `json:"address"` // bech32 address
+	Asset   ids.ID `json:"asset"`
+	Balance uint64 `json:"balance"`
+}
+
+type CustomOrder struct {
+	ID        ids.ID `json:"id"`
+	Owner     string `json:"owner"` // bech32 address
+	In        ids.ID `json:"in"`
+	InTick    uint64 `json:"inTick"`
+	Out       ids.ID `json:"out"`
+	OutTick   uint64 `json:"outTick"`
+	Remaining uint64 `json:"remaining"`
+}
+
+type CustomPool struct {
+	ID       ids.ID `json:"id"`
+	AssetA   ids.ID `json:"assetA"`
+	AssetB   ids.ID `json:"assetB"`
+	Fee      uint16 `json:"fee"`
+	ReserveA uint64 `json:"reserveA"`
+	ReserveB uint64 `json:"reserveB"`
+}
+
+// State is the part of [Genesis] that is written to state when the chain is
+// created. It can also be exported from the state of a running chain.
+type State struct {
+	// Native asset balances
+	CustomAllocation []*CustomAllocation `json:"customAllocation"`
+
+	CustomAssets   []*CustomAsset   `json:"customAssets,omitempty"`
+	CustomBalances []*CustomBalance `json:"customBalances,omitempty"`
+	CustomOrders   []*CustomOrder   `json:"customOrders,omitempty"`
+	CustomPools    []*CustomPool    `json:"customPools,omitempty"`
+}
+
+// Add adds the balance, asset, order, or pool stored at [k] to [s]. It returns
+// false if [k] does not store any of them.
+func (s *State) Add(k []byte, v []byte) bool {
+	if addr, asset, balance, ok := storage.ParseBalance(k, v); ok {
+		saddr := codec.MustAddressBech32(consts.HRP, addr)
+		if asset == ids.Empty {
+			s.CustomAllocation = append(s.CustomAllocation, &CustomAllocation{saddr, balance})
+		} else {
+			s.CustomBalances = append(s.CustomBalances, &CustomBalance{saddr, asset, balance})
+		}
+		return true
+	}
+	if asset, symbol, decimals, metadata, supply, owner, ok := storage.ParseAsset(k, v); ok {
+		if asset == ids.Empty {
+			// The native asset is created when loading [CustomAllocation].
+			return true
+		}
+		s.CustomAssets = append(s.CustomAssets, &CustomAsset{
+			ID:       asset,
+			Symbol:   symbol,
+			Decimals: decimals,
+			Metadata: metadata,
+			Supply:   supply,
+			Owner:    codec.MustAddressBech32(consts.HRP, owner),
+		})
+		return true
+	}
+	if order, in, inTick, out, outTick, remaining, owner, ok := storage.ParseOrder(k, v); ok {
+		s.CustomOrders = append(s.CustomOrders, &CustomOrder{
+			ID:        order,
+			Owner:     codec.MustAddressBech32(consts.HRP, owner),
+			In:        in,
+			InTick:    inTick,
+			Out:       out,
+			OutTick:   outTick,
+			Remaining: remaining,
+		})
+		return true
+	}
+	if pool, assetA, assetB, fee, reserveA, reserveB, ok := storage.ParsePool(k, v); ok {
+		s.CustomPools = append(s.CustomPools, &CustomPool{pool, assetA, assetB, fee, reserveA, reserveB})
+		return true
+	}
+	return false
+}
+
+func (s *State) load(ctx context.Context, mu state.Mutable) error {
+	for _, asset := range s.CustomAssets {
+		if asset.ID == ids.Empty {
+			return fmt.Errorf("%w: cannot redefine native asset", ErrInvalidAsset)
+		}
+		exists, _, _, _, _, _, err := storage.GetAsset(ctx, mu, asset.ID) //nolint:dogsled
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("%w: duplicate asset %s", ErrInvalidAsset, asset.ID)
+		}
+		owner, err := codec.ParseAddressBech32(consts.HRP, asset.Owner)
+		if err != nil {
+			return err
+		}
+		if err := storage.SetAsset(ctx, mu, asset.ID, asset.Symbol, asset.Decimals, asset.Metadata, asset.Supply, owner); err != nil {
+			return fmt.Errorf("%w: asset=%s", err, asset.ID)
+		}
+	}
+
+	// Track the native supply held by all allocations
+	supply := uint64(0)
+	addNative := func(asset ids.ID, amount uint64) error {
+		if asset == ids.Empty {
+			var err error
+			supply, err = smath.Add64(supply, amount)
+			return err
+		}
+		exists, _, _, _, _, _, err := storage.GetAsset(ctx, mu, asset) //nolint:dogsled
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: missing asset %s", ErrInvalidAsset, asset)
+		}
+		return nil
+	}
+	for _, alloc := range s.CustomAllocation {
+		pk, err := codec.ParseAddressBech32(consts.HRP, alloc.Address)
+		if err != nil {
+			return err
+		}
+		if err := addNative(ids.Empty, alloc.Balance); err != nil {
+			return err
+		}
+		if err := storage.SetBalance(ctx, mu, pk, ids.Empty, alloc.Balance); err != nil {
+			return fmt.Errorf("%w: addr=%s, bal=%d", err, alloc.Address, alloc.Balance)
+		}
+	}
+	for _, bal := range s.CustomBalances {
+		pk, err := codec.ParseAddressBech32(consts.HRP, bal.Address)
+		if err != nil {
+			return err
+		}
+		if err := addNative(bal.Asset, bal.Balance); err != nil {
+			return err
+		}
+		if err := storage.SetBalance(ctx, mu, pk, bal.Asset, bal.Balance); err != nil {
+			return fmt.Errorf("%w: addr=%s, asset=%s, bal=%d", err, bal.Address, bal.Asset, bal.Balance)
+		}
+	}
+	for _, order := range s.CustomOrders {
+		owner, err := codec.ParseAddressBech32(consts.HRP, order.Owner)
+		if err != nil {
+			return err
+		}
+		if err := addNative(order.In, 0); err != nil {
+			return err
+		}
+		if err := addNative(order.Out, order.Remaining); err != nil {
+			return err
+		}
+		if err := storage.SetOrder(ctx, mu, order.ID, order.In, order.InTick, order.Out, order.OutTick, order.Remaining, owner); err != nil {
+			return fmt.Errorf("%w: order=%s", err, order.ID)
+		}
+	}
+	for _, pool := range s.CustomPools {
+		if err := addNative(pool.ID, 0); err != nil {
+			// The shares of each pool must be included in [CustomAssets].
+			return err
+		}
+		if err := addNative(pool.AssetA, pool.ReserveA); err != nil {
+			return err
+		}
+		if err := addNative(pool.AssetB, pool.ReserveB); err != nil {
+			return err
+		}
+		if err := storage.SetPool(ctx, mu, pool.ID, pool.AssetA, pool.AssetB, pool.Fee, pool.ReserveA, pool.ReserveB); err != nil {
+			return fmt.Errorf("%w: pool=%s", err, pool.ID)
+		}
+	}
+	return storage.SetAsset(
+		ctx,
+		mu,
+		ids.Empty,
+		[]byte(consts.Symbol),
+		consts.Decimals,
+		[]byte(consts.Name),
+		supply,
+		codec.EmptyAddress,
+	)
+}
+
+// Marshal encodes [s] as a compact binary snapshot.
+func (s *State) Marshal() ([]byte, error) {
+	p := codec.NewWriter(0, hconsts.MaxInt)
+	p.PackInt(len(s.CustomAllocation))
+	for _, alloc := range s.CustomAllocation {
+		if err := packAddress(p, alloc.Address); err != nil {
+			return nil, err
+		}
+		p.PackUint64(alloc.Balance)
+	}
+	p.PackInt(len(s.CustomAssets))
+	for _, asset := range s.CustomAssets {
+		p.PackID(asset.ID)
+		p.PackBytes(asset.Symbol)
+		p.PackByte(asset.Decimals)
+		p.PackBytes(asset.Metadata)
+		p.PackUint64(asset.Supply)
+		if err := packAddress(p, asset.Owner); err != nil {
+			return nil, err
+		}
+	}
+	p.PackInt(len(s.CustomBalances))
+	for _, bal := range s.CustomBalances {
+		if err := packAddress(p, bal.Address); err != nil {
+			return nil, err
+		}
+		p.PackID(bal.Asset)
+		p.PackUint64(bal.Balance)
+	}
+	p.PackInt(len(s.CustomOrders))
+	for _, order := range s.CustomOrders {
+		p.PackID(order.ID)
+		if err := packAddress(p, order.Owner); err != nil {
+			return nil, err
+		}
+		p.PackID(order.In)
+		p.PackUint64(order.InTick)
+		p.PackID(order.Out)
+		p.PackUint64(order.OutTick)
+		p.PackUint64(order.Remaining)
+	}
+	p.PackInt(len(s.CustomPools))
+	for _, pool := range s.CustomPools {
+		p.PackID(pool.ID)
+		p.PackID(pool.AssetA)
+		p.PackID(pool.AssetB)
+		p.PackInt(int(pool.Fee))
+		p.PackUint64(pool.ReserveA)
+		p.PackUint64(pool.ReserveB)
+	}
+	return p.Bytes(), p.Err()
+}
+
+// The minimum size of each entry in a binary snapshot
+const (
+	allocationSize = codec.AddressLen + hconsts.Uint64Len
+	assetSize      = ids.IDLen + hconsts.IntLen + hconsts.ByteLen + hconsts.IntLen + hconsts.Uint64Len + codec.AddressLen
+	balanceSize    = codec.AddressLen + ids.IDLen + hconsts.Uint64Len
+	orderSize      = ids.IDLen*3 + codec.AddressLen + hconsts.Uint64Len*3
+	poolSize       = ids.IDLen*3 + hconsts.IntLen + hconsts.Uint64Len*2
+)
+
+// UnmarshalState decodes a binary snapshot created by [State.Marshal].
+func UnmarshalState(b []byte) (*State, error) {
+	p := codec.NewReader(b, len(b))
+	// A count can't be larger than the number of entries that fit in the rest
+	// of [b], so a corrupt snapshot can't cause a large allocation.
+	unpackCount := func(entrySize int) (int, error) {
+		count := p.UnpackInt(false)
+		if err := p.Err(); err != nil {
+			return 0, err
+		}
+		if remaining := len(b) - p.Offset(); count > remaining/entrySize {
+			return 0, fmt.Errorf("%w: %d entries do not fit in %d bytes", ErrInvalidSnapshot, count, remaining)
+		}
+		return count, nil
+	}
+	var s State
+	count, err := unpackCount(allocationSize)
+	if err != nil {
+		return nil, err
+	}
+	s.CustomAllocation = make([]*CustomAllocation, count)
+	for i := range s.CustomAllocation {
+		s.CustomAllocation[i] = &CustomAllocation{
+			Address: unpackAddress(p),
+			Balance: p.UnpackUint64(false),
+		}
+	}
+	if count, err = unpackCount(assetSize); err != nil {
+		return nil, err
+	}
+	s.CustomAssets = make([]*CustomAsset, count)
+	for i := range s.CustomAssets {
+		asset := &CustomAsset{}
+		p.UnpackID(true, &asset.ID)
+		p.UnpackBytes(int(hconsts.MaxUint16), false, &asset.Symbol)
+		asset.Decimals = p.UnpackByte()
+		p.UnpackBytes(int(hconsts.MaxUint16), false, &asset.Metadata)
+		asset.Supply = p.UnpackUint64(false)
+		asset.Owner = unpackAddress(p)
+		s.CustomAssets[i] = asset
+	}
+	if count, err = unpackCount(balanceSize); err != nil {
+		return nil, err
+	}
+	s.CustomBalances = make([]*CustomBalance, count)
+	for i := range s.CustomBalances {
+		bal := &CustomBalance{Address: unpackAddress(p)}
+		p.UnpackID(false, &bal.Asset)
+		bal.Balance = p.UnpackUint64(false)
+		s.CustomBalances[i] = bal
+	}
+	if count, err = unpackCount(orderSize); err != nil {
+		return nil, err
+	}
+	s.CustomOrders = make([]*CustomOrder, count)
+	for i := range s.CustomOrders {
+		order := &CustomOrder{}
+		p.UnpackID(true, &order.ID)
+		order.Owner = unpackAddress(p)
+		p.UnpackID(false, &order.In)
+		order.InTick = p.UnpackUint64(true)
+		p.UnpackID(false, &order.Out)
+		order.OutTick = p.UnpackUint64(true)
+		order.Remaining = p.UnpackUint64(true)
+		s.CustomOrders[i] = order
+	}
+	if count, err = unpackCount(poolSize); err != nil {
+		return nil, err
+	}
+	s.CustomPools = make([]*CustomPool, count)
+	for i := range s.CustomPools {
+		pool := &CustomPool{}
+		p.UnpackID(true, &pool.ID)
+		p.UnpackID(false, &pool.AssetA)
+		p.UnpackID(false, &pool.AssetB)
+		pool.Fee = uint16(p.UnpackInt(false))
+		pool.ReserveA = p.UnpackUint64(false)
+		pool.ReserveB = p.UnpackUint64(false)
+		s.CustomPools[i] = pool
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	if !p.Empty() {
+		return nil, ErrInvalidSnapshot
+	}
+	return &s, nil
+}
+
+// Addresses are stored as bech32 strings in [State] but packed as raw bytes.
+func packAddress(p *codec.Packer, addr string) error {
+	a, err := codec.ParseAddressBech32(consts.HRP, addr)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidAddress, addr, err)
+	}
+	p.PackAddress(a)
+	return nil
+}
+
+// The empty address is allowed (i.e. the owner of the native asset or of
+// pool shares), so we can't use [codec.Packer.UnpackAddress].
+func unpackAddress(p *codec.Packer) string {
+	var addr codec.Address
+	b := addr[:]
+	p.UnpackFixedBytes(codec.AddressLen, &b)
+	return codec.MustAddressBech32(consts.HRP, addr)
+}
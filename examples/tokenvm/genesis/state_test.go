@@ -0,0 +1,74 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/consts"
+)
+
+func TestStateMarshal(t *testing.T) {
+	require := require.New(t)
+
+	addr := codec.MustAddressBech32(consts.HRP, codec.CreateAddress(0, ids.GenerateTestID()))
+	s := &State{
+		CustomAllocation: []*CustomAllocation{{Address: addr, Balance: 10}},
+		CustomAssets: []*CustomAsset{{
+			ID:       ids.GenerateTestID(),
+			Symbol:   []byte("TEST"),
+			Metadata: []byte{},
+			Supply:   5,
+			Owner:    codec.MustAddressBech32(consts.HRP, codec.EmptyAddress),
+		}},
+		CustomBalances: []*CustomBalance{{Address: addr, Asset: ids.GenerateTestID(), Balance: 5}},
+		CustomOrders: []*CustomOrder{{
+			ID:        ids.GenerateTestID(),
+			Owner:     addr,
+			InTick:    1,
+			Out:       ids.GenerateTestID(),
+			OutTick:   2,
+			Remaining: 4,
+		}},
+		CustomPools: []*CustomPool{{ID: ids.GenerateTestID(), AssetB: ids.GenerateTestID(), Fee: 30}},
+	}
+	b, err := s.Marshal()
+	require.NoError(err)
+	parsed, err := UnmarshalState(b)
+	require.NoError(err)
+	require.Equal(s, parsed)
+
+	// Trailing bytes
+	_, err = UnmarshalState(append(b, 0))
+	require.ErrorIs(err, ErrInvalidSnapshot)
+
+	// Invalid addresses are not packed as the empty address
+	s.CustomBalances[0].Address = "invalid"
+	_, err = s.Marshal()
+	require.ErrorIs(err, ErrInvalidAddress)
+}
+
+func TestStateUnmarshalCounts(t *testing.T) {
+	require := require.New(t)
+
+	// A count that can't fit in the remaining bytes is rejected before
+	// anything is allocated.
+	p := codec.NewWriter(0, 1024)
+	p.PackInt(1 << 30)
+	require.NoError(p.Err())
+	_, err := UnmarshalState(p.Bytes())
+	require.ErrorIs(err, ErrInvalidSnapshot)
+
+	p = codec.NewWriter(0, 1024)
+	p.PackInt(0)
+	p.PackInt(1)
+	p.PackFixedBytes(make([]byte, assetSize-1))
+	require.NoError(p.Err())
+	_, err = UnmarshalState(p.Bytes())
+	require.ErrorIs(err, ErrInvalidSnapshot)
+}
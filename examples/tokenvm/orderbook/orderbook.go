@@ -4,19 +4,27 @@
 package orderbook
 
 import (
+	"errors"
+	"fmt"
+	"sort"
 	"sync"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 	"github.com/ava-labs/hypersdk/heap"
 )
 
 const allPairs = "*"
 
+var ErrInvalidOrder = errors.New("invalid order")
+
 type Order struct {
 	ID        ids.ID `json:"id"`
 	Owner     string `json:"owner"` // we always send address over RPC
@@ -29,22 +37,46 @@ type Order struct {
 	owner codec.Address
 }
 
+// Level is the aggregate of all tracked orders in a pair that share
+// the same rate.
+type Level struct {
+	Rate      float64 `json:"rate"` // InTick/OutTick
+	Orders    int     `json:"orders"`
+	Remaining uint64  `json:"remaining"`
+}
+
 type OrderBook struct {
 	c Controller
 
+	// All tracked orders and recent trades are persisted to [db] so that the
+	// order book survives restarts. Updates are written by the caller in a
+	// single batch per accepted block.
+	db database.Database
+
 	// Fee required to create an order should be high enough to prevent too many
-	// dust orders from filling the heap.
-	//
-	// TODO: Allow operator to specify min creation supply per pair to be tracked
+	// dust orders from filling the heap. Operators can further require a
+	// minimum creation supply per pair (or for all pairs using [allPairs]).
 	orders           map[string]*heap.Heap[*Order, float64]
 	orderToPair      map[ids.ID]string // needed to delete from [CloseOrder] actions
 	maxOrdersPerPair int
-	l                sync.RWMutex
+	minSupply        map[string]uint64
+
+	trades           map[string][]*Trade // oldest first
+	maxTradesPerPair int
+
+	l sync.RWMutex
 
 	trackAll bool
 }
 
-func New(c Controller, trackedPairs []string, maxOrdersPerPair int) *OrderBook {
+func New(
+	c Controller,
+	db database.Database,
+	trackedPairs []string,
+	maxOrdersPerPair int,
+	minSupply map[string]uint64,
+	maxTradesPerPair int,
+) (*OrderBook, error) {
 	m := map[string]*heap.Heap[*Order, float64]{}
 	trackAll := false
 	if len(trackedPairs) == 1 && trackedPairs[0] == allPairs {
@@ -52,22 +84,208 @@ func New(c Controller, trackedPairs []string, maxOrdersPerPair int) *OrderBook {
 		c.Logger().Info("tracking all order books")
 	} else {
 		for _, pair := range trackedPairs {
-			// We use a max heap so we return the best rates in order.
+			// We use a min heap so we return the best rates in order.
 			m[pair] = heap.New[*Order, float64](maxOrdersPerPair+1, true)
 			c.Logger().Info("tracking order book", zap.String("pair", pair))
 		}
 	}
-	return &OrderBook{
+	if minSupply == nil {
+		minSupply = map[string]uint64{}
+	}
+	o := &OrderBook{
 		c:                c,
+		db:               db,
 		orders:           m,
 		orderToPair:      map[ids.ID]string{},
 		maxOrdersPerPair: maxOrdersPerPair,
+		minSupply:        minSupply,
+		trades:           map[string][]*Trade{},
+		maxTradesPerPair: maxTradesPerPair,
 		trackAll:         trackAll,
 	}
+	if err := o.load(); err != nil {
+		return nil, err
+	}
+	return o, nil
 }
 
-func (o *OrderBook) Add(actionID ids.ID, actor codec.Address, action *actions.CreateOrder) {
-	pair := actions.PairID(action.In, action.Out)
+// load restores all persisted orders and trades. Any orders or trades that
+// are no longer tracked (because of a config change) are pruned from disk.
+func (o *OrderBook) load() error {
+	batch := o.db.NewBatch()
+	defer batch.Reset()
+
+	o.l.Lock()
+	defer o.l.Unlock()
+
+	orderIterator := o.db.NewIteratorWithPrefix([]byte{orderPrefix})
+	defer orderIterator.Release()
+	var loadedOrders int
+	for orderIterator.Next() {
+		order, err := unmarshalOrder(orderIterator.Key(), orderIterator.Value())
+		if err != nil {
+			return err
+		}
+		tracked, err := o.add(batch, order)
+		if err != nil {
+			return err
+		}
+		if !tracked {
+			if err := batch.Delete(orderIterator.Key()); err != nil {
+				return err
+			}
+			continue
+		}
+		loadedOrders++
+	}
+	if err := orderIterator.Error(); err != nil {
+		return err
+	}
+
+	tradeIterator := o.db.NewIteratorWithPrefix([]byte{tradePrefix})
+	defer tradeIterator.Release()
+	var loadedTrades int
+	for tradeIterator.Next() {
+		trade, err := unmarshalTrade(tradeIterator.Key(), tradeIterator.Value())
+		if err != nil {
+			return err
+		}
+		tracked, err := o.addTrade(batch, trade)
+		if err != nil {
+			return err
+		}
+		if !tracked {
+			if err := batch.Delete(tradeIterator.Key()); err != nil {
+				return err
+			}
+			continue
+		}
+		loadedTrades++
+	}
+	if err := tradeIterator.Error(); err != nil {
+		return err
+	}
+	o.c.Logger().Info("loaded order book",
+		zap.Int("orders", loadedOrders),
+		zap.Int("trades", loadedTrades),
+	)
+	return batch.Write()
+}
+
+// Reconcile updates the tracked orders to match the open orders in [state]
+// (the state of the last accepted block). Orders are only added to the order
+// book when the block that created them is accepted, so the order book misses
+// orders created in blocks it never processed (i.e. blocks skipped by state
+// sync or a snapshot import, or accepted before the order book was persisted).
+//
+// Orders that are no longer open are removed and the remaining supply of
+// tracked orders is updated. Orders that are not tracked are added if their
+// remaining supply is at least [MinSupply] (the supply they were created with
+// is not stored in state).
+func (o *OrderBook) Reconcile(w database.KeyValueWriterDeleter, state database.Iteratee) error {
+	o.l.Lock()
+	defer o.l.Unlock()
+
+	it := state.NewIteratorWithPrefix(storage.OrderPrefix())
+	defer it.Release()
+	var (
+		open                    = set.Set[ids.ID]{}
+		added, updated, removed int
+	)
+	for it.Next() {
+		id, in, inTick, out, outTick, remaining, owner, ok := storage.ParseOrder(it.Key(), it.Value())
+		if !ok {
+			return fmt.Errorf("%w: key=%x", ErrInvalidOrder, it.Key())
+		}
+		open.Add(id)
+		if pair, ok := o.orderToPair[id]; ok {
+			entry, ok := o.orders[pair].Get(id)
+			if !ok || entry.Item.Remaining == remaining {
+				continue
+			}
+			entry.Item.Remaining = remaining
+			if err := w.Put(orderKey(id), marshalOrder(entry.Item)); err != nil {
+				return err
+			}
+			updated++
+			continue
+		}
+		if remaining < o.MinSupply(actions.PairID(in, out)) {
+			continue
+		}
+		order := &Order{
+			ID:        id,
+			Owner:     codec.MustAddressBech32(consts.HRP, owner),
+			InAsset:   in,
+			InTick:    inTick,
+			OutAsset:  out,
+			OutTick:   outTick,
+			Remaining: remaining,
+			owner:     owner,
+		}
+		tracked, err := o.add(w, order)
+		if err != nil {
+			return err
+		}
+		if !tracked {
+			continue
+		}
+		if err := w.Put(orderKey(id), marshalOrder(order)); err != nil {
+			return err
+		}
+		added++
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	for id := range o.orderToPair {
+		if open.Contains(id) {
+			continue
+		}
+		if err := o.remove(w, id); err != nil {
+			return err
+		}
+		removed++
+	}
+	o.c.Logger().Info("reconciled order book with state",
+		zap.Int("added", added),
+		zap.Int("updated", updated),
+		zap.Int("removed", removed),
+	)
+	return nil
+}
+
+// book returns the heap for [pair], creating it if we track all pairs.
+//
+// Assumes [o.l] is held.
+func (o *OrderBook) book(pair string) (*heap.Heap[*Order, float64], bool) {
+	h, ok := o.orders[pair]
+	switch {
+	case !ok && !o.trackAll:
+		return nil, false
+	case !ok && o.trackAll:
+		o.c.Logger().Info("tracking order book", zap.String("pair", pair))
+		h = heap.New[*Order, float64](o.maxOrdersPerPair+1, true)
+		o.orders[pair] = h
+	}
+	return h, true
+}
+
+// MinSupply returns the minimum supply an order in [pair] must be created with
+// to be tracked.
+func (o *OrderBook) MinSupply(pair string) uint64 {
+	if supply, ok := o.minSupply[pair]; ok {
+		return supply
+	}
+	return o.minSupply[allPairs]
+}
+
+func (o *OrderBook) Add(
+	w database.KeyValueWriterDeleter,
+	actionID ids.ID,
+	actor codec.Address,
+	action *actions.CreateOrder,
+) error {
 	order := &Order{
 		actionID,
 		codec.MustAddressBech32(consts.HRP, actor),
@@ -78,17 +296,28 @@ func (o *OrderBook) Add(actionID ids.ID, actor codec.Address, action *actions.Cr
 		action.Supply,
 		actor,
 	}
+	if action.Supply < o.MinSupply(actions.PairID(action.In, action.Out)) {
+		return nil
+	}
 
 	o.l.Lock()
 	defer o.l.Unlock()
-	h, ok := o.orders[pair]
-	switch {
-	case !ok && !o.trackAll:
-		return
-	case !ok && o.trackAll:
-		o.c.Logger().Info("tracking order book", zap.String("pair", pair))
-		h = heap.New[*Order, float64](o.maxOrdersPerPair+1, true)
-		o.orders[pair] = h
+	tracked, err := o.add(w, order)
+	if err != nil || !tracked {
+		return err
+	}
+	return w.Put(orderKey(order.ID), marshalOrder(order))
+}
+
+// add inserts [order] into its book and returns whether it is tracked. Any
+// order evicted to make room is removed from [w].
+//
+// Assumes [o.l] is held.
+func (o *OrderBook) add(w database.KeyValueDeleter, order *Order) (bool, error) {
+	pair := actions.PairID(order.InAsset, order.OutAsset)
+	h, ok := o.book(pair)
+	if !ok {
+		return false, nil
 	}
 	h.Push(&heap.Entry[*Order, float64]{
 		ID:    order.ID,
@@ -103,50 +332,66 @@ func (o *OrderBook) Add(actionID ids.ID, actor codec.Address, action *actions.Cr
 	if l := h.Len(); l > o.maxOrdersPerPair {
 		e := h.Remove(l - 1)
 		delete(o.orderToPair, e.ID)
+		if e.ID == order.ID {
+			return false, nil
+		}
+		if err := w.Delete(orderKey(e.ID)); err != nil {
+			return false, err
+		}
 	}
+	return true, nil
 }
 
-func (o *OrderBook) Remove(id ids.ID) {
+func (o *OrderBook) Remove(w database.KeyValueDeleter, id ids.ID) error {
 	o.l.Lock()
 	defer o.l.Unlock()
 
+	return o.remove(w, id)
+}
+
+// remove deletes [id] from its book (if tracked) and from [w].
+//
+// Assumes [o.l] is held.
+func (o *OrderBook) remove(w database.KeyValueDeleter, id ids.ID) error {
 	pair, ok := o.orderToPair[id]
 	if !ok {
-		return
+		return nil
 	}
 	delete(o.orderToPair, id)
 	h, ok := o.orders[pair]
 	if !ok {
 		// This should never happen
-		return
+		return nil
 	}
 	entry, ok := h.Get(id) // O(log 1)
 	if !ok {
 		// This should never happen
-		return
+		return nil
 	}
 	h.Remove(entry.Index) // O(log N)
+	return w.Delete(orderKey(id))
 }
 
-func (o *OrderBook) UpdateRemaining(id ids.ID, remaining uint64) {
+func (o *OrderBook) UpdateRemaining(w database.KeyValueWriter, id ids.ID, remaining uint64) error {
 	o.l.Lock()
 	defer o.l.Unlock()
 
 	pair, ok := o.orderToPair[id]
 	if !ok {
-		return
+		return nil
 	}
 	h, ok := o.orders[pair]
 	if !ok {
 		// This should never happen
-		return
+		return nil
 	}
 	entry, ok := h.Get(id)
 	if !ok {
 		// This should never happen
-		return
+		return nil
 	}
 	entry.Item.Remaining = remaining
+	return w.Put(orderKey(id), marshalOrder(entry.Item))
 }
 
 func (o *OrderBook) Orders(pair string, limit int) []*Order {
@@ -169,3 +414,37 @@ func (o *OrderBook) Orders(pair string, limit int) []*Order {
 	}
 	return orders
 }
+
+// Depth aggregates the tracked orders in [pair] by rate, returning at most
+// [limit] levels ordered from best to worst rate.
+func (o *OrderBook) Depth(pair string, limit int) []*Level {
+	o.l.RLock()
+	defer o.l.RUnlock()
+
+	h, ok := o.orders[pair]
+	if !ok {
+		// Clients often prefer an empty slice instead of null
+		return []*Level{}
+	}
+	levels := map[float64]*Level{}
+	for _, item := range h.Items() {
+		level, ok := levels[item.Val]
+		if !ok {
+			level = &Level{Rate: item.Val}
+			levels[item.Val] = level
+		}
+		level.Orders++
+		level.Remaining += item.Item.Remaining
+	}
+	depth := make([]*Level, 0, len(levels))
+	for _, level := range levels {
+		depth = append(depth, level)
+	}
+	sort.Slice(depth, func(i, j int) bool {
+		return depth[i].Rate < depth[j].Rate
+	})
+	if limit < len(depth) {
+		depth = depth[:limit]
+	}
+	return depth
+}
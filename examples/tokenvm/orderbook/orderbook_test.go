@@ -0,0 +1,138 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package orderbook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+type testController struct{}
+
+func (testController) Logger() logging.Logger {
+	return logging.NoLog{}
+}
+
+func TestOrderBookRestore(t *testing.T) {
+	require := require.New(t)
+
+	db := memdb.New()
+	in, out := ids.GenerateTestID(), ids.GenerateTestID()
+	pair := actions.PairID(in, out)
+	maker := codec.CreateAddress(0, ids.GenerateTestID())
+	taker := codec.CreateAddress(0, ids.GenerateTestID())
+
+	o, err := New(testController{}, db, []string{allPairs}, 16, map[string]uint64{allPairs: 5}, 2)
+	require.NoError(err)
+
+	orders := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()}
+	batch := db.NewBatch()
+	require.NoError(o.Add(batch, orders[0], maker, &actions.CreateOrder{In: in, InTick: 1, Out: out, OutTick: 2, Supply: 10}))
+	require.NoError(o.Add(batch, orders[1], maker, &actions.CreateOrder{In: in, InTick: 2, Out: out, OutTick: 4, Supply: 20}))
+	require.NoError(o.Add(batch, orders[2], maker, &actions.CreateOrder{In: in, InTick: 1, Out: out, OutTick: 1, Supply: 4})) // below min supply
+	for i := 0; i < 3; i++ {
+		trade := NewTrade(ids.GenerateTestID(), uint64(i), int64(i), 0, 0, 0, orders[0], maker, taker, in, 1, out, 2)
+		require.NoError(o.RecordTrade(batch, trade))
+		require.NoError(o.UpdateRemaining(batch, orders[0], 8-uint64(i)*2))
+	}
+	require.NoError(batch.Write())
+
+	depth := o.Depth(pair, 10)
+	require.Len(depth, 1)
+	require.Equal(0.5, depth[0].Rate)
+	require.Equal(2, depth[0].Orders)
+	require.Equal(uint64(24), depth[0].Remaining)
+
+	// Restore from disk
+	restored, err := New(testController{}, db, []string{allPairs}, 16, map[string]uint64{allPairs: 5}, 2)
+	require.NoError(err)
+	require.ElementsMatch(o.Orders(pair, 10), restored.Orders(pair, 10))
+	require.Equal(depth, restored.Depth(pair, 10))
+	trades := restored.Trades(pair, 10)
+	require.Len(trades, 2)
+	require.Equal(uint64(2), trades[0].Height)
+	require.Equal(uint64(1), trades[1].Height)
+	require.Equal(o.Trades(pair, 10), trades)
+
+	// Removed orders are not restored
+	batch = db.NewBatch()
+	require.NoError(restored.Remove(batch, orders[1]))
+	require.NoError(batch.Write())
+	restored, err = New(testController{}, db, []string{allPairs}, 16, nil, 2)
+	require.NoError(err)
+	restoredOrders := restored.Orders(pair, 10)
+	require.Len(restoredOrders, 1)
+	require.Equal(orders[0], restoredOrders[0].ID)
+	require.Equal(uint64(4), restoredOrders[0].Remaining)
+}
+
+func TestOrderBookReconcile(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	db := memdb.New()
+	in, out := ids.GenerateTestID(), ids.GenerateTestID()
+	pair := actions.PairID(in, out)
+	owner := codec.CreateAddress(0, ids.GenerateTestID())
+	o, err := New(testController{}, db, []string{allPairs}, 16, map[string]uint64{allPairs: 5}, 2)
+	require.NoError(err)
+
+	// [filled] and [closed] were seen by the order book, [missed] and [dust]
+	// were created in blocks it never processed
+	filled, closed, missed, dust := ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()
+	batch := db.NewBatch()
+	require.NoError(o.Add(batch, filled, owner, &actions.CreateOrder{In: in, InTick: 1, Out: out, OutTick: 2, Supply: 10}))
+	require.NoError(o.Add(batch, closed, owner, &actions.CreateOrder{In: in, InTick: 1, Out: out, OutTick: 1, Supply: 10}))
+	require.NoError(batch.Write())
+
+	stateDB, err := merkledb.New(ctx, memdb.New(), merkledb.Config{
+		BranchFactor:                merkledb.BranchFactor16,
+		RootGenConcurrency:          1,
+		HistoryLength:               100,
+		ValueNodeCacheSize:          units.MiB,
+		IntermediateNodeCacheSize:   units.MiB,
+		IntermediateWriteBufferSize: units.KiB,
+		IntermediateWriteBatchSize:  units.KiB,
+		Tracer:                      trace.Noop,
+	})
+	require.NoError(err)
+	mu := state.NewSimpleMutable(stateDB)
+	require.NoError(storage.SetOrder(ctx, mu, filled, in, 1, out, 2, 6, owner))
+	require.NoError(storage.SetOrder(ctx, mu, missed, in, 1, out, 4, 8, owner))
+	require.NoError(storage.SetOrder(ctx, mu, dust, in, 1, out, 4, 4, owner)) // below min supply
+	require.NoError(mu.Commit(ctx))
+
+	batch = db.NewBatch()
+	require.NoError(o.Reconcile(batch, stateDB))
+	require.NoError(batch.Write())
+	expected := map[ids.ID]uint64{missed: 8, filled: 6}
+	for _, book := range []*OrderBook{o, mustNew(t, db)} {
+		orders := book.Orders(pair, 10)
+		require.Len(orders, len(expected))
+		for _, order := range orders {
+			require.Equal(expected[order.ID], order.Remaining)
+			require.Equal(owner, order.owner)
+		}
+	}
+}
+
+// mustNew restores the order book persisted to [db].
+func mustNew(t *testing.T, db *memdb.Database) *OrderBook {
+	o, err := New(testController{}, db, []string{allPairs}, 16, map[string]uint64{allPairs: 5}, 2)
+	require.NoError(t, err)
+	return o
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package orderbook
+
+import (
+	"encoding/binary"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+
+	tconsts "github.com/ava-labs/hypersdk/examples/tokenvm/consts"
+)
+
+// Order Book DB
+// 0x0/ (orders)
+//   -> [orderID] => in|inTick|out|outTick|remaining|owner
+// 0x1/ (trades)
+//   -> [in|out|height|txIndex|actionIndex|fillIndex] => orderID|txID|timestamp|maker|taker|in|out
+
+const (
+	orderPrefix = 0x0
+	tradePrefix = 0x1
+
+	orderLen = ids.IDLen*2 + consts.Uint64Len*3 + codec.AddressLen
+	tradeLen = ids.IDLen*2 + consts.Uint64Len*3 + codec.AddressLen*2
+
+	tradeKeyLen = 1 + ids.IDLen*2 + consts.Uint64Len + consts.Uint32Len + consts.Uint8Len*2
+)
+
+func orderKey(id ids.ID) []byte {
+	k := make([]byte, 1+ids.IDLen)
+	k[0] = orderPrefix
+	copy(k[1:], id[:])
+	return k
+}
+
+func marshalOrder(order *Order) []byte {
+	p := codec.NewWriter(orderLen, orderLen)
+	p.PackID(order.InAsset)
+	p.PackUint64(order.InTick)
+	p.PackID(order.OutAsset)
+	p.PackUint64(order.OutTick)
+	p.PackUint64(order.Remaining)
+	p.PackAddress(order.owner)
+	return p.Bytes()
+}
+
+func unmarshalOrder(k []byte, v []byte) (*Order, error) {
+	var order Order
+	copy(order.ID[:], k[1:])
+	p := codec.NewReader(v, orderLen)
+	p.UnpackID(false, &order.InAsset)
+	order.InTick = p.UnpackUint64(true)
+	p.UnpackID(false, &order.OutAsset)
+	order.OutTick = p.UnpackUint64(true)
+	order.Remaining = p.UnpackUint64(true)
+	p.UnpackAddress(&order.owner)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	order.Owner = codec.MustAddressBech32(tconsts.HRP, order.owner)
+	return &order, nil
+}
+
+// Trades are keyed by pair and then by their position on-chain, so that
+// iterating over the prefix returns the trades of each pair in the order
+// they were executed.
+func tradeKey(trade *Trade) []byte {
+	k := make([]byte, tradeKeyLen)
+	k[0] = tradePrefix
+	copy(k[1:], trade.InAsset[:])
+	copy(k[1+ids.IDLen:], trade.OutAsset[:])
+	binary.BigEndian.PutUint64(k[1+ids.IDLen*2:], trade.Height)
+	binary.BigEndian.PutUint32(k[1+ids.IDLen*2+consts.Uint64Len:], trade.txIndex)
+	k[tradeKeyLen-2] = trade.actionIndex
+	k[tradeKeyLen-1] = trade.fillIndex
+	return k
+}
+
+func marshalTrade(trade *Trade) []byte {
+	p := codec.NewWriter(tradeLen, tradeLen)
+	p.PackID(trade.OrderID)
+	p.PackID(trade.TxID)
+	p.PackInt64(trade.Timestamp)
+	p.PackAddress(trade.maker)
+	p.PackAddress(trade.taker)
+	p.PackUint64(trade.In)
+	p.PackUint64(trade.Out)
+	return p.Bytes()
+}
+
+func unmarshalTrade(k []byte, v []byte) (*Trade, error) {
+	var trade Trade
+	copy(trade.InAsset[:], k[1:])
+	copy(trade.OutAsset[:], k[1+ids.IDLen:])
+	trade.Height = binary.BigEndian.Uint64(k[1+ids.IDLen*2:])
+	trade.txIndex = binary.BigEndian.Uint32(k[1+ids.IDLen*2+consts.Uint64Len:])
+	trade.actionIndex = k[tradeKeyLen-2]
+	trade.fillIndex = k[tradeKeyLen-1]
+	p := codec.NewReader(v, tradeLen)
+	p.UnpackID(true, &trade.OrderID)
+	p.UnpackID(true, &trade.TxID)
+	trade.Timestamp = p.UnpackInt64(true)
+	p.UnpackAddress(&trade.maker)
+	p.UnpackAddress(&trade.taker)
+	trade.In = p.UnpackUint64(true)
+	trade.Out = p.UnpackUint64(true)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	trade.Maker = codec.MustAddressBech32(tconsts.HRP, trade.maker)
+	trade.Taker = codec.MustAddressBech32(tconsts.HRP, trade.taker)
+	return &trade, nil
+}
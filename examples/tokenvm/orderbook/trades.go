@@ -0,0 +1,125 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package orderbook
+
+import (
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/consts"
+)
+
+// Trade is a successful fill of an order.
+type Trade struct {
+	OrderID   ids.ID `json:"orderID"`
+	TxID      ids.ID `json:"txID"`
+	Height    uint64 `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Maker     string `json:"maker"` // owner of the order
+	Taker     string `json:"taker"` // actor that filled the order
+
+	// [In] of [InAsset] was sent to the maker and [Out] of [OutAsset] was
+	// sent to the taker.
+	InAsset  ids.ID `json:"inAsset"`
+	In       uint64 `json:"in"`
+	OutAsset ids.ID `json:"outAsset"`
+	Out      uint64 `json:"out"`
+
+	txIndex     uint32
+	actionIndex uint8
+	fillIndex   uint8
+	maker       codec.Address
+	taker       codec.Address
+}
+
+// NewTrade creates a record of the [fillIndex]th fill of an order by the
+// [actionIndex]th action of the [txIndex]th transaction in a block.
+func NewTrade(
+	txID ids.ID,
+	height uint64,
+	timestamp int64,
+	txIndex int,
+	actionIndex int,
+	fillIndex int,
+	orderID ids.ID,
+	maker codec.Address,
+	taker codec.Address,
+	inAsset ids.ID,
+	in uint64,
+	outAsset ids.ID,
+	out uint64,
+) *Trade {
+	return &Trade{
+		OrderID:     orderID,
+		TxID:        txID,
+		Height:      height,
+		Timestamp:   timestamp,
+		Maker:       codec.MustAddressBech32(consts.HRP, maker),
+		Taker:       codec.MustAddressBech32(consts.HRP, taker),
+		InAsset:     inAsset,
+		In:          in,
+		OutAsset:    outAsset,
+		Out:         out,
+		txIndex:     uint32(txIndex),
+		actionIndex: uint8(actionIndex),
+		fillIndex:   uint8(fillIndex),
+		maker:       maker,
+		taker:       taker,
+	}
+}
+
+// RecordTrade adds [trade] to the history of its pair, if tracked.
+func (o *OrderBook) RecordTrade(w database.KeyValueWriterDeleter, trade *Trade) error {
+	o.l.Lock()
+	defer o.l.Unlock()
+
+	tracked, err := o.addTrade(w, trade)
+	if err != nil || !tracked {
+		return err
+	}
+	return w.Put(tradeKey(trade), marshalTrade(trade))
+}
+
+// addTrade appends [trade] to the history of its pair and returns whether it
+// is tracked. Any trades that no longer fit in the history are removed
+// from [w].
+//
+// Assumes [o.l] is held.
+func (o *OrderBook) addTrade(w database.KeyValueDeleter, trade *Trade) (bool, error) {
+	pair := actions.PairID(trade.InAsset, trade.OutAsset)
+	if _, ok := o.book(pair); !ok || o.maxTradesPerPair <= 0 {
+		return false, nil
+	}
+	trades := append(o.trades[pair], trade)
+	if l := len(trades); l > o.maxTradesPerPair {
+		for _, evicted := range trades[:l-o.maxTradesPerPair] {
+			if err := w.Delete(tradeKey(evicted)); err != nil {
+				return false, err
+			}
+		}
+		trades = trades[l-o.maxTradesPerPair:]
+	}
+	o.trades[pair] = trades
+	return true, nil
+}
+
+// Trades returns at most [limit] of the most recent trades in [pair], most
+// recent first.
+func (o *OrderBook) Trades(pair string, limit int) []*Trade {
+	o.l.RLock()
+	defer o.l.RUnlock()
+
+	trades := o.trades[pair]
+	arrLen := len(trades)
+	if limit < arrLen {
+		arrLen = limit
+	}
+	recent := make([]*Trade, arrLen)
+	for i := 0; i < arrLen; i++ {
+		recent[i] = trades[len(trades)-1-i]
+	}
+	return recent
+}
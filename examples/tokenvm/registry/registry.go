@@ -30,6 +30,12 @@ func init() {
 		consts.ActionRegistry.Register((&actions.CreateOrder{}).GetTypeID(), actions.UnmarshalCreateOrder),
 		consts.ActionRegistry.Register((&actions.FillOrder{}).GetTypeID(), actions.UnmarshalFillOrder),
 		consts.ActionRegistry.Register((&actions.CloseOrder{}).GetTypeID(), actions.UnmarshalCloseOrder),
+		consts.ActionRegistry.Register((&actions.MarketSwap{}).GetTypeID(), actions.UnmarshalMarketSwap),
+
+		consts.ActionRegistry.Register((&actions.CreatePool{}).GetTypeID(), actions.UnmarshalCreatePool),
+		consts.ActionRegistry.Register((&actions.AddLiquidity{}).GetTypeID(), actions.UnmarshalAddLiquidity),
+		consts.ActionRegistry.Register((&actions.RemoveLiquidity{}).GetTypeID(), actions.UnmarshalRemoveLiquidity),
+		consts.ActionRegistry.Register((&actions.Swap{}).GetTypeID(), actions.UnmarshalSwap),
 
 		// When registering new auth, ALWAYS make sure to append at the end.
 		consts.AuthRegistry.Register((&auth.ED25519{}).GetTypeID(), auth.UnmarshalED25519),
@@ -19,9 +19,11 @@ type Controller interface {
 	Genesis() *genesis.Genesis
 	Tracer() trace.Tracer
 	GetTransaction(ids.ID) (bool, int64, bool, fees.Dimensions, uint64, error)
-	GetAssetFromState(context.Context, ids.ID) (bool, []byte, uint8, []byte, uint64, codec.Address, error)
-	GetBalanceFromState(context.Context, codec.Address, ids.ID) (uint64, error)
+	GetAssetFromState(context.Context, ids.ID, *uint64) (bool, []byte, uint8, []byte, uint64, codec.Address, error)
+	GetBalanceFromState(context.Context, codec.Address, ids.ID, *uint64) (uint64, error)
 	Orders(pair string, limit int) []*orderbook.Order
+	Depth(pair string, limit int) []*orderbook.Level
+	Trades(pair string, limit int) []*orderbook.Trade
 	GetOrderFromState(context.Context, ids.ID) (
 		bool, // exists
 		ids.ID, // in
@@ -32,4 +34,19 @@ type Controller interface {
 		codec.Address, // owner
 		error,
 	)
+	GetPoolFromState(context.Context, ids.ID) (
+		bool, // exists
+		ids.ID, // assetA
+		ids.ID, // assetB
+		uint16, // fee
+		uint64, // reserveA
+		uint64, // reserveB
+		error,
+	)
+	ExportState(context.Context, *uint64) (
+		uint64, // height
+		ids.ID, // root
+		*genesis.State,
+		error,
+	)
 }
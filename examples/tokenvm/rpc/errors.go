@@ -9,4 +9,5 @@ var (
 	ErrTxNotFound    = errors.New("tx not found")
 	ErrAssetNotFound = errors.New("asset not found")
 	ErrOrderNotFound = errors.New("order not found")
+	ErrPoolNotFound  = errors.New("pool not found")
 )
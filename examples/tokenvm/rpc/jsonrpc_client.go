@@ -6,6 +6,8 @@ package rpc
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"sort"
 	"strings"
 	"sync"
 
@@ -14,6 +16,8 @@ import (
 	_ "github.com/ava-labs/hypersdk/examples/tokenvm/registry" // ensure registry populated
 
 	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/consts"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/genesis"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/orderbook"
@@ -60,6 +64,13 @@ func (cli *JSONRPCClient) Genesis(ctx context.Context) (*genesis.Genesis, error)
 	if err != nil {
 		return nil, err
 	}
+	if resp.Upgrades != nil {
+		// Ensure we use the same rules as the chain when generating
+		// transactions
+		if err := resp.Genesis.SetUpgrades(resp.Upgrades); err != nil {
+			return nil, err
+		}
+	}
 	cli.g = resp.Genesis
 	return resp.Genesis, nil
 }
@@ -131,6 +142,49 @@ func (cli *JSONRPCClient) Balance(ctx context.Context, addr string, asset ids.ID
 	return resp.Amount, err
 }
 
+// AssetAt returns the asset at the accepted block at [height] (only supported
+// by archive nodes).
+func (cli *JSONRPCClient) AssetAt(
+	ctx context.Context,
+	asset ids.ID,
+	height uint64,
+) (bool, []byte, uint8, []byte, uint64, string, error) {
+	resp := new(AssetReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"asset",
+		&AssetArgs{
+			Asset:  asset,
+			Height: &height,
+		},
+		resp,
+	)
+	switch {
+	case err != nil && strings.Contains(err.Error(), ErrAssetNotFound.Error()):
+		return false, nil, 0, nil, 0, "", nil
+	case err != nil:
+		return false, nil, 0, nil, 0, "", err
+	}
+	return true, resp.Symbol, resp.Decimals, resp.Metadata, resp.Supply, resp.Owner, nil
+}
+
+// BalanceAt returns the balance of [addr] at the accepted block at [height]
+// (only supported by archive nodes).
+func (cli *JSONRPCClient) BalanceAt(ctx context.Context, addr string, asset ids.ID, height uint64) (uint64, error) {
+	resp := new(BalanceReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"balance",
+		&BalanceArgs{
+			Address: addr,
+			Asset:   asset,
+			Height:  &height,
+		},
+		resp,
+	)
+	return resp.Amount, err
+}
+
 func (cli *JSONRPCClient) Orders(ctx context.Context, pair string) ([]*orderbook.Order, error) {
 	resp := new(OrdersReply)
 	err := cli.requester.SendRequest(
@@ -144,6 +198,79 @@ func (cli *JSONRPCClient) Orders(ctx context.Context, pair string) ([]*orderbook
 	return resp.Orders, err
 }
 
+// SwapOrders selects the best orders (at most [actions.MaxSwapOrders]) to use
+// as candidates for a [actions.MarketSwap] of [value] of [in] for [out]. It
+// also returns the amount of [in] that would be used and the amount of [out]
+// that would be received if none of the orders are modified before the swap
+// is executed.
+func (cli *JSONRPCClient) SwapOrders(
+	ctx context.Context,
+	in ids.ID,
+	out ids.ID,
+	value uint64,
+) ([]*actions.SwapOrder, uint64, uint64, error) {
+	orders, err := cli.Orders(ctx, actions.PairID(in, out))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	sort.SliceStable(orders, func(i, j int) bool {
+		a := new(big.Int).Mul(new(big.Int).SetUint64(orders[i].InTick), new(big.Int).SetUint64(orders[j].OutTick))
+		b := new(big.Int).Mul(new(big.Int).SetUint64(orders[j].InTick), new(big.Int).SetUint64(orders[i].OutTick))
+		return a.Cmp(b) < 0
+	})
+	var (
+		candidates = []*actions.SwapOrder{}
+		available  = value
+		received   uint64
+	)
+	for _, order := range orders {
+		if len(candidates) == actions.MaxSwapOrders || available == 0 {
+			break
+		}
+		ticks := available / order.InTick
+		if maxTicks := order.Remaining / order.OutTick; ticks > maxTicks {
+			ticks = maxTicks
+		}
+		if ticks == 0 {
+			continue
+		}
+		owner, err := codec.ParseAddressBech32(consts.HRP, order.Owner)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		candidates = append(candidates, &actions.SwapOrder{Order: order.ID, Owner: owner})
+		available -= ticks * order.InTick
+		received += ticks * order.OutTick
+	}
+	return candidates, value - available, received, nil
+}
+
+func (cli *JSONRPCClient) Depth(ctx context.Context, pair string) ([]*orderbook.Level, error) {
+	resp := new(DepthReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"depth",
+		&DepthArgs{
+			Pair: pair,
+		},
+		resp,
+	)
+	return resp.Levels, err
+}
+
+func (cli *JSONRPCClient) Trades(ctx context.Context, pair string) ([]*orderbook.Trade, error) {
+	resp := new(TradesReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"trades",
+		&TradesArgs{
+			Pair: pair,
+		},
+		resp,
+	)
+	return resp.Trades, err
+}
+
 func (cli *JSONRPCClient) GetOrder(ctx context.Context, orderID ids.ID) (*orderbook.Order, error) {
 	resp := new(GetOrderReply)
 	err := cli.requester.SendRequest(
@@ -157,6 +284,57 @@ func (cli *JSONRPCClient) GetOrder(ctx context.Context, orderID ids.ID) (*orderb
 	return resp.Order, err
 }
 
+func (cli *JSONRPCClient) Pool(ctx context.Context, poolID ids.ID) (*PoolReply, error) {
+	resp := new(PoolReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"pool",
+		&PoolArgs{
+			PoolID: poolID,
+		},
+		resp,
+	)
+	return resp, err
+}
+
+// QuoteSwap returns the asset and the amount that would be received for
+// swapping [value] of [in] with [poolID] if the reserves of the pool are not
+// modified before the swap is executed.
+func (cli *JSONRPCClient) QuoteSwap(
+	ctx context.Context,
+	poolID ids.ID,
+	in ids.ID,
+	value uint64,
+) (ids.ID, uint64, error) {
+	resp := new(QuoteSwapReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"quoteSwap",
+		&QuoteSwapArgs{
+			PoolID: poolID,
+			In:     in,
+			Value:  value,
+		},
+		resp,
+	)
+	return resp.Out, resp.Amount, err
+}
+
+// ExportState returns all balances, assets, orders, and pools at [height]. If
+// [height] is nil, the state of the last accepted height is returned.
+func (cli *JSONRPCClient) ExportState(ctx context.Context, height *uint64) (*ExportStateReply, error) {
+	resp := new(ExportStateReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"exportState",
+		&ExportStateArgs{
+			Height: height,
+		},
+		resp,
+	)
+	return resp, err
+}
+
 func (cli *JSONRPCClient) WaitForBalance(
 	ctx context.Context,
 	addr string,
@@ -9,10 +9,13 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/consts"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/genesis"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/orderbook"
 	"github.com/ava-labs/hypersdk/fees"
+
+	hgenesis "github.com/ava-labs/hypersdk/genesis"
 )
 
 type JSONRPCServer struct {
@@ -24,11 +27,13 @@ func NewJSONRPCServer(c Controller) *JSONRPCServer {
 }
 
 type GenesisReply struct {
-	Genesis *genesis.Genesis `json:"genesis"`
+	Genesis  *genesis.Genesis   `json:"genesis"`
+	Upgrades *hgenesis.Upgrades `json:"upgrades"`
 }
 
 func (j *JSONRPCServer) Genesis(_ *http.Request, _ *struct{}, reply *GenesisReply) (err error) {
 	reply.Genesis = j.c.Genesis()
+	reply.Upgrades = reply.Genesis.Upgrades()
 	return nil
 }
 
@@ -63,6 +68,9 @@ func (j *JSONRPCServer) Tx(req *http.Request, args *TxArgs, reply *TxReply) erro
 
 type AssetArgs struct {
 	Asset ids.ID `json:"asset"`
+
+	// Height is only supported by archive nodes
+	Height *uint64 `json:"height,omitempty"`
 }
 
 type AssetReply struct {
@@ -77,7 +85,7 @@ func (j *JSONRPCServer) Asset(req *http.Request, args *AssetArgs, reply *AssetRe
 	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Asset")
 	defer span.End()
 
-	exists, symbol, decimals, metadata, supply, owner, err := j.c.GetAssetFromState(ctx, args.Asset)
+	exists, symbol, decimals, metadata, supply, owner, err := j.c.GetAssetFromState(ctx, args.Asset, args.Height)
 	if err != nil {
 		return err
 	}
@@ -95,6 +103,9 @@ func (j *JSONRPCServer) Asset(req *http.Request, args *AssetArgs, reply *AssetRe
 type BalanceArgs struct {
 	Address string `json:"address"`
 	Asset   ids.ID `json:"asset"`
+
+	// Height is only supported by archive nodes
+	Height *uint64 `json:"height,omitempty"`
 }
 
 type BalanceReply struct {
@@ -109,7 +120,7 @@ func (j *JSONRPCServer) Balance(req *http.Request, args *BalanceArgs, reply *Bal
 	if err != nil {
 		return err
 	}
-	balance, err := j.c.GetBalanceFromState(ctx, addr, args.Asset)
+	balance, err := j.c.GetBalanceFromState(ctx, addr, args.Asset, args.Height)
 	if err != nil {
 		return err
 	}
@@ -133,6 +144,38 @@ func (j *JSONRPCServer) Orders(req *http.Request, args *OrdersArgs, reply *Order
 	return nil
 }
 
+type DepthArgs struct {
+	Pair string `json:"pair"`
+}
+
+type DepthReply struct {
+	Levels []*orderbook.Level `json:"levels"`
+}
+
+func (j *JSONRPCServer) Depth(req *http.Request, args *DepthArgs, reply *DepthReply) error {
+	_, span := j.c.Tracer().Start(req.Context(), "Server.Depth")
+	defer span.End()
+
+	reply.Levels = j.c.Depth(args.Pair, levelsToSend)
+	return nil
+}
+
+type TradesArgs struct {
+	Pair string `json:"pair"`
+}
+
+type TradesReply struct {
+	Trades []*orderbook.Trade `json:"trades"`
+}
+
+func (j *JSONRPCServer) Trades(req *http.Request, args *TradesArgs, reply *TradesReply) error {
+	_, span := j.c.Tracer().Start(req.Context(), "Server.Trades")
+	defer span.End()
+
+	reply.Trades = j.c.Trades(args.Pair, tradesToSend)
+	return nil
+}
+
 type GetOrderArgs struct {
 	OrderID ids.ID `json:"orderID"`
 }
@@ -163,3 +206,106 @@ func (j *JSONRPCServer) GetOrder(req *http.Request, args *GetOrderArgs, reply *G
 	}
 	return nil
 }
+
+type PoolArgs struct {
+	PoolID ids.ID `json:"poolID"`
+}
+
+type PoolReply struct {
+	AssetA   ids.ID `json:"assetA"`
+	AssetB   ids.ID `json:"assetB"`
+	Fee      uint16 `json:"fee"`
+	ReserveA uint64 `json:"reserveA"`
+	ReserveB uint64 `json:"reserveB"`
+	Shares   uint64 `json:"shares"`
+}
+
+func (j *JSONRPCServer) Pool(req *http.Request, args *PoolArgs, reply *PoolReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.Pool")
+	defer span.End()
+
+	exists, assetA, assetB, fee, reserveA, reserveB, err := j.c.GetPoolFromState(ctx, args.PoolID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrPoolNotFound
+	}
+	// The shares of a pool are tracked as an asset with the same ID.
+	_, _, _, _, shares, _, err := j.c.GetAssetFromState(ctx, args.PoolID, nil)
+	if err != nil {
+		return err
+	}
+	reply.AssetA = assetA
+	reply.AssetB = assetB
+	reply.Fee = fee
+	reply.ReserveA = reserveA
+	reply.ReserveB = reserveB
+	reply.Shares = shares
+	return nil
+}
+
+type QuoteSwapArgs struct {
+	PoolID ids.ID `json:"poolID"`
+	In     ids.ID `json:"in"`
+	Value  uint64 `json:"value"`
+}
+
+type QuoteSwapReply struct {
+	Out    ids.ID `json:"out"`
+	Amount uint64 `json:"amount"`
+}
+
+func (j *JSONRPCServer) QuoteSwap(req *http.Request, args *QuoteSwapArgs, reply *QuoteSwapReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.QuoteSwap")
+	defer span.End()
+
+	exists, assetA, assetB, fee, reserveA, reserveB, err := j.c.GetPoolFromState(ctx, args.PoolID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrPoolNotFound
+	}
+	var reserveIn, reserveOut uint64
+	switch args.In {
+	case assetA:
+		reply.Out, reserveIn, reserveOut = assetB, reserveA, reserveB
+	case assetB:
+		reply.Out, reserveIn, reserveOut = assetA, reserveB, reserveA
+	default:
+		return actions.ErrOutputWrongPoolAssets
+	}
+	amount, err := actions.SwapOutput(reserveIn, reserveOut, fee, args.Value)
+	if err != nil {
+		return err
+	}
+	reply.Amount = amount
+	return nil
+}
+
+type ExportStateArgs struct {
+	// [Height] is the height to export state at. If nil, the state of the
+	// last accepted height is exported.
+	Height *uint64 `json:"height"`
+}
+
+type ExportStateReply struct {
+	Height uint64         `json:"height"`
+	Root   ids.ID         `json:"root"`
+	State  *genesis.State `json:"state"`
+}
+
+func (j *JSONRPCServer) ExportState(req *http.Request, args *ExportStateArgs, reply *ExportStateReply) error {
+	ctx, span := j.c.Tracer().Start(req.Context(), "Server.ExportState")
+	defer span.End()
+
+	height, root, state, err := j.c.ExportState(ctx, args.Height)
+	if err != nil {
+		return err
+	}
+	reply.Height = height
+	reply.Root = root
+	reply.State = state
+	return nil
+}
@@ -33,6 +33,8 @@ type ReadState func(context.Context, [][]byte) ([][]byte, []error)
 // 0x3/ (hypersdk-height)
 // 0x4/ (hypersdk-timestamp)
 // 0x5/ (hypersdk-fee)
+// 0x6/ (pools)
+//   -> [poolID] => assetA|assetB|fee|reserveA|reserveB
 
 const (
 	// Active state
@@ -42,12 +44,14 @@ const (
 	heightPrefix    = 0x3
 	timestampPrefix = 0x4
 	feePrefix       = 0x5
+	poolPrefix      = 0x6
 )
 
 const (
 	BalanceChunks uint16 = 1
 	AssetChunks   uint16 = 5
 	OrderChunks   uint16 = 2
+	PoolChunks    uint16 = 2
 )
 
 var (
@@ -290,6 +294,11 @@ func DeleteAsset(ctx context.Context, mu state.Mutable, asset ids.ID) error {
 	return mu.Remove(ctx, k)
 }
 
+// OrderPrefix is the prefix of all keys returned by [OrderKey].
+func OrderPrefix() []byte {
+	return []byte{orderPrefix}
+}
+
 // [orderPrefix] + [actionID]
 func OrderKey(actionID ids.ID) (k []byte) {
 	k = make([]byte, 1+ids.IDLen+consts.Uint16Len)
@@ -392,6 +401,141 @@ func DeleteOrder(ctx context.Context, mu state.Mutable, order ids.ID) error {
 	return mu.Remove(ctx, k)
 }
 
+// [poolPrefix] + [actionID]
+func PoolKey(pool ids.ID) (k []byte) {
+	k = make([]byte, 1+ids.IDLen+consts.Uint16Len)
+	k[0] = poolPrefix
+	copy(k[1:], pool[:])
+	binary.BigEndian.PutUint16(k[1+ids.IDLen:], PoolChunks)
+	return
+}
+
+func SetPool(
+	ctx context.Context,
+	mu state.Mutable,
+	pool ids.ID,
+	assetA ids.ID,
+	assetB ids.ID,
+	fee uint16,
+	reserveA uint64,
+	reserveB uint64,
+) error {
+	k := PoolKey(pool)
+	v := make([]byte, ids.IDLen*2+consts.Uint16Len+consts.Uint64Len*2)
+	copy(v, assetA[:])
+	copy(v[ids.IDLen:], assetB[:])
+	binary.BigEndian.PutUint16(v[ids.IDLen*2:], fee)
+	binary.BigEndian.PutUint64(v[ids.IDLen*2+consts.Uint16Len:], reserveA)
+	binary.BigEndian.PutUint64(v[ids.IDLen*2+consts.Uint16Len+consts.Uint64Len:], reserveB)
+	return mu.Insert(ctx, k, v)
+}
+
+func GetPool(
+	ctx context.Context,
+	im state.Immutable,
+	pool ids.ID,
+) (
+	bool, // exists
+	ids.ID, // assetA
+	ids.ID, // assetB
+	uint16, // fee
+	uint64, // reserveA
+	uint64, // reserveB
+	error,
+) {
+	k := PoolKey(pool)
+	v, err := im.GetValue(ctx, k)
+	return innerGetPool(v, err)
+}
+
+// Used to serve RPC queries
+func GetPoolFromState(
+	ctx context.Context,
+	f ReadState,
+	pool ids.ID,
+) (
+	bool, // exists
+	ids.ID, // assetA
+	ids.ID, // assetB
+	uint16, // fee
+	uint64, // reserveA
+	uint64, // reserveB
+	error,
+) {
+	values, errs := f(ctx, [][]byte{PoolKey(pool)})
+	return innerGetPool(values[0], errs[0])
+}
+
+func innerGetPool(v []byte, err error) (
+	bool, // exists
+	ids.ID, // assetA
+	ids.ID, // assetB
+	uint16, // fee
+	uint64, // reserveA
+	uint64, // reserveB
+	error,
+) {
+	if errors.Is(err, database.ErrNotFound) {
+		return false, ids.Empty, ids.Empty, 0, 0, 0, nil
+	}
+	if err != nil {
+		return false, ids.Empty, ids.Empty, 0, 0, 0, err
+	}
+	var assetA ids.ID
+	copy(assetA[:], v[:ids.IDLen])
+	var assetB ids.ID
+	copy(assetB[:], v[ids.IDLen:ids.IDLen*2])
+	fee := binary.BigEndian.Uint16(v[ids.IDLen*2:])
+	reserveA := binary.BigEndian.Uint64(v[ids.IDLen*2+consts.Uint16Len:])
+	reserveB := binary.BigEndian.Uint64(v[ids.IDLen*2+consts.Uint16Len+consts.Uint64Len:])
+	return true, assetA, assetB, fee, reserveA, reserveB, nil
+}
+
+// The following are used to export state. They return false if [k] does not
+// store the requested type.
+
+func ParseBalance(k []byte, v []byte) (codec.Address, ids.ID, uint64, bool) {
+	if len(k) != 1+codec.AddressLen+ids.IDLen+consts.Uint16Len || k[0] != balancePrefix {
+		return codec.EmptyAddress, ids.Empty, 0, false
+	}
+	var addr codec.Address
+	copy(addr[:], k[1:])
+	var asset ids.ID
+	copy(asset[:], k[1+codec.AddressLen:])
+	bal, _, err := innerGetBalance(v, nil)
+	return addr, asset, bal, err == nil
+}
+
+func ParseAsset(k []byte, v []byte) (ids.ID, []byte, uint8, []byte, uint64, codec.Address, bool) {
+	if len(k) != 1+ids.IDLen+consts.Uint16Len || k[0] != assetPrefix {
+		return ids.Empty, nil, 0, nil, 0, codec.EmptyAddress, false
+	}
+	var asset ids.ID
+	copy(asset[:], k[1:])
+	_, symbol, decimals, metadata, supply, owner, err := innerGetAsset(v, nil)
+	return asset, symbol, decimals, metadata, supply, owner, err == nil
+}
+
+func ParseOrder(k []byte, v []byte) (ids.ID, ids.ID, uint64, ids.ID, uint64, uint64, codec.Address, bool) {
+	if len(k) != 1+ids.IDLen+consts.Uint16Len || k[0] != orderPrefix {
+		return ids.Empty, ids.Empty, 0, ids.Empty, 0, 0, codec.EmptyAddress, false
+	}
+	var order ids.ID
+	copy(order[:], k[1:])
+	_, in, inTick, out, outTick, remaining, owner, err := innerGetOrder(v, nil)
+	return order, in, inTick, out, outTick, remaining, owner, err == nil
+}
+
+func ParsePool(k []byte, v []byte) (ids.ID, ids.ID, ids.ID, uint16, uint64, uint64, bool) {
+	if len(k) != 1+ids.IDLen+consts.Uint16Len || k[0] != poolPrefix {
+		return ids.Empty, ids.Empty, ids.Empty, 0, 0, 0, false
+	}
+	var pool ids.ID
+	copy(pool[:], k[1:])
+	_, assetA, assetB, fee, reserveA, reserveB, err := innerGetPool(v, nil)
+	return pool, assetA, assetB, fee, reserveA, reserveB, err == nil
+}
+
 func HeightKey() (k []byte) {
 	return heightKey
 }
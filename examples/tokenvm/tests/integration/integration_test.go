@@ -4,6 +4,7 @@
 package integration_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
@@ -12,6 +13,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -38,9 +40,11 @@ import (
 	"github.com/ava-labs/hypersdk/examples/tokenvm/actions"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/controller"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/genesis"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 	"github.com/ava-labs/hypersdk/fees"
 	"github.com/ava-labs/hypersdk/pubsub"
 	"github.com/ava-labs/hypersdk/rpc"
+	"github.com/ava-labs/hypersdk/tstate"
 	"github.com/ava-labs/hypersdk/vm"
 
 	tconsts "github.com/ava-labs/hypersdk/examples/tokenvm/consts"
@@ -243,6 +247,9 @@ var _ = ginkgo.BeforeSuite(func() {
 			nil,
 			[]byte(
 				`{
+				  "archiveMode":true,
+				  "txTracing":true,
+				  "snapshotServing":true,
 				  "config": {
 				    "testMode":true,
 				    "logLevel":"debug",
@@ -954,6 +961,27 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		require.Equal(metadata, asset1)
 		require.Zero(supply)
 		require.Equal(owner, sender)
+
+		// Trace the creation of the asset
+		height, blkID, trace, err := instances[0].cli.TraceTx(context.TODO(), tx.ID())
+		require.NoError(err)
+		require.Equal(height, instances[0].vm.LastAcceptedBlock().Hght)
+		require.Equal(blkID, instances[0].vm.LastAcceptedBlock().ID())
+		require.Equal(trace.TxID, tx.ID())
+		require.True(trace.Result.Success)
+		require.NotEmpty(trace.FeeOps)
+		require.Len(trace.Actions, 1)
+		var created bool
+		for _, op := range trace.Actions[0].Ops {
+			if op.Type == tstate.TraceInsert && bytes.Equal(op.Key, storage.AssetKey(asset1ID)) {
+				require.False(op.Exists)
+				created = true
+			}
+		}
+		require.True(created)
+
+		_, _, _, err = instances[0].cli.TraceTx(context.TODO(), ids.GenerateTestID())
+		require.ErrorContains(err, vm.ErrTxNotFound.Error())
 	})
 
 	ginkgo.It("mint a new asset", func() {
@@ -1697,6 +1725,537 @@ var _ = ginkgo.Describe("[Tx Processing]", func() {
 		require.Len(orders, 0)
 	})
 
+	ginkgo.It("create multiple orders for market swap", func() {
+		parser, err := instances[0].tcli.Parser(context.Background())
+		require.NoError(err)
+		submit, tx, _, err := instances[0].cli.GenerateTransaction(
+			context.Background(),
+			parser,
+			[]chain.Action{
+				&actions.CreateOrder{
+					In:      asset2ID,
+					InTick:  2,
+					Out:     asset3ID,
+					OutTick: 1,
+					Supply:  2,
+				},
+				&actions.CreateOrder{
+					In:      asset2ID,
+					InTick:  1,
+					Out:     asset3ID,
+					OutTick: 1,
+					Supply:  2,
+				},
+			},
+			factory2,
+		)
+		require.NoError(err)
+		require.NoError(submit(context.Background()))
+
+		accept := expectBlk(instances[0])
+		results := accept(false)
+		require.Len(results, 1)
+		require.True(results[0].Success)
+
+		balance, err := instances[0].tcli.Balance(context.TODO(), sender2, asset3ID)
+		require.NoError(err)
+		require.Equal(balance, uint64(6))
+
+		orders, err := instances[0].tcli.Orders(context.TODO(), actions.PairID(asset2ID, asset3ID))
+		require.NoError(err)
+		require.Len(orders, 2)
+		depth, err := instances[0].tcli.Depth(context.TODO(), actions.PairID(asset2ID, asset3ID))
+		require.NoError(err)
+		require.Len(depth, 2)
+		require.Equal(depth[0].Rate, float64(1))
+		require.Equal(depth[0].Remaining, uint64(2))
+		require.Equal(depth[1].Rate, float64(2))
+		require.Equal(depth[1].Remaining, uint64(2))
+		for _, order := range orders {
+			require.True(order.ID == chain.CreateActionID(tx.ID(), 0) || order.ID == chain.CreateActionID(tx.ID(), 1))
+		}
+	})
+
+	ginkgo.It("market swap with insufficient output", func() {
+		candidates, in, out, err := instances[0].tcli.SwapOrders(context.TODO(), asset2ID, asset3ID, 4)
+		require.NoError(err)
+		require.Len(candidates, 2)
+		require.Equal(in, uint64(4))
+		require.Equal(out, uint64(3))
+		parser, err := instances[0].tcli.Parser(context.Background())
+		require.NoError(err)
+		submit, _, _, err := instances[0].cli.GenerateTransaction(
+			context.Background(),
+			parser,
+			[]chain.Action{&actions.MarketSwap{
+				In:     asset2ID,
+				Value:  4,
+				Out:    asset3ID,
+				MinOut: 4,
+				Orders: candidates,
+			}},
+			factory,
+		)
+		require.NoError(err)
+		require.NoError(submit(context.Background()))
+
+		accept := expectBlk(instances[0])
+		results := accept(false)
+		require.Len(results, 1)
+		result := results[0]
+		require.False(result.Success)
+		require.Contains(string(result.Error), "insufficient output")
+	})
+
+	ginkgo.It("market swap across multiple orders", func() {
+		candidates, _, _, err := instances[0].tcli.SwapOrders(context.TODO(), asset2ID, asset3ID, 4)
+		require.NoError(err)
+		require.Len(candidates, 2)
+		parser, err := instances[0].tcli.Parser(context.Background())
+		require.NoError(err)
+		submit, _, _, err := instances[0].cli.GenerateTransaction(
+			context.Background(),
+			parser,
+			[]chain.Action{&actions.MarketSwap{
+				In:     asset2ID,
+				Value:  4,
+				Out:    asset3ID,
+				MinOut: 3,
+				// Orders are filled by rate regardless of the order they are
+				// provided in.
+				Orders: []*actions.SwapOrder{candidates[1], candidates[0]},
+			}},
+			factory,
+		)
+		require.NoError(err)
+		require.NoError(submit(context.Background()))
+
+		accept := expectBlk(instances[0])
+		results := accept(false)
+		require.Len(results, 1)
+		result := results[0]
+		require.True(result.Success)
+		sr, err := actions.UnmarshalSwapResult(result.Outputs[0][0])
+		require.NoError(err)
+		require.Equal(sr.In, uint64(4))
+		require.Equal(sr.Out, uint64(3))
+		require.Len(sr.Fills, 2)
+		require.Equal(sr.Fills[0].Order, candidates[0].Order)
+		require.Equal(sr.Fills[0].Remaining, uint64(0))
+		require.Equal(sr.Fills[1].Order, candidates[1].Order)
+		require.Equal(sr.Fills[1].Remaining, uint64(1))
+
+		balance, err := instances[0].tcli.Balance(context.TODO(), sender, asset2ID)
+		require.NoError(err)
+		require.Zero(balance)
+		balance, err = instances[0].tcli.Balance(context.TODO(), sender, asset3ID)
+		require.NoError(err)
+		require.Equal(balance, uint64(3))
+		balance, err = instances[0].tcli.Balance(context.TODO(), sender2, asset2ID)
+		require.NoError(err)
+		require.Equal(balance, uint64(6))
+
+		orders, err := instances[0].tcli.Orders(context.TODO(), actions.PairID(asset2ID, asset3ID))
+		require.NoError(err)
+		require.Len(orders, 1)
+		require.Equal(orders[0].ID, candidates[1].Order)
+		require.Equal(orders[0].Remaining, uint64(1))
+		trades, err := instances[0].tcli.Trades(context.TODO(), actions.PairID(asset2ID, asset3ID))
+		require.NoError(err)
+		require.Len(trades, 4) // includes prior fills
+		require.Equal(trades[0].OrderID, candidates[1].Order)
+		require.Equal(trades[0].Taker, sender)
+		require.Equal(trades[1].OrderID, candidates[0].Order)
+	})
+
+	var poolID ids.ID
+	ginkgo.It("create pool", func() {
+		parser, err := instances[0].tcli.Parser(context.Background())
+		require.NoError(err)
+		submit, tx, _, err := instances[0].cli.GenerateTransaction(
+			context.Background(),
+			parser,
+			[]chain.Action{&actions.CreatePool{
+				AssetA:  ids.Empty,
+				AssetB:  asset3ID,
+				Fee:     30,
+				AmountA: 10_000,
+				AmountB: 4,
+			}},
+			factory2,
+		)
+		require.NoError(err)
+		require.NoError(submit(context.Background()))
+
+		accept := expectBlk(instances[0])
+		results := accept(false)
+		require.Len(results, 1)
+		result := results[0]
+		require.True(result.Success)
+		lr, err := actions.UnmarshalLiquidityResult(result.Outputs[0][0])
+		require.NoError(err)
+		require.Equal(lr.Shares, uint64(200))
+
+		poolID = chain.CreateActionID(tx.ID(), 0)
+		pool, err := instances[0].tcli.Pool(context.TODO(), poolID)
+		require.NoError(err)
+		require.Equal(pool.AssetA, ids.Empty)
+		require.Equal(pool.AssetB, asset3ID)
+		require.Equal(pool.ReserveA, uint64(10_000))
+		require.Equal(pool.ReserveB, uint64(4))
+		require.Equal(pool.Shares, uint64(200))
+		balance, err := instances[0].tcli.Balance(context.TODO(), sender2, poolID)
+		require.NoError(err)
+		require.Equal(balance, uint64(200))
+		balance, err = instances[0].tcli.Balance(context.TODO(), sender2, asset3ID)
+		require.NoError(err)
+		require.Equal(balance, uint64(2))
+	})
+
+	ginkgo.It("swap with insufficient output", func() {
+		out, amount, err := instances[0].tcli.QuoteSwap(context.TODO(), poolID, ids.Empty, 10_000)
+		require.NoError(err)
+		require.Equal(out, asset3ID)
+		require.Equal(amount, uint64(1))
+		parser, err := instances[0].tcli.Parser(context.Background())
+		require.NoError(err)
+		submit, _, _, err := instances[0].cli.GenerateTransaction(
+			context.Background(),
+			parser,
+			[]chain.Action{&actions.Swap{
+				Pool:   poolID,
+				In:     ids.Empty,
+				Out:    asset3ID,
+				Value:  10_000,
+				MinOut: 2,
+			}},
+			factory,
+		)
+		require.NoError(err)
+		require.NoError(submit(context.Background()))
+
+		accept := expectBlk(instances[0])
+		results := accept(false)
+		require.Len(results, 1)
+		result := results[0]
+		require.False(result.Success)
+		require.Contains(string(result.Error), "insufficient output")
+	})
+
+	ginkgo.It("swap with pool", func() {
+		parser, err := instances[0].tcli.Parser(context.Background())
+		require.NoError(err)
+		submit, _, _, err := instances[0].cli.GenerateTransaction(
+			context.Background(),
+			parser,
+			[]chain.Action{&actions.Swap{
+				Pool:   poolID,
+				In:     ids.Empty,
+				Out:    asset3ID,
+				Value:  10_000,
+				MinOut: 1,
+			}},
+			factory,
+		)
+		require.NoError(err)
+		require.NoError(submit(context.Background()))
+
+		accept := expectBlk(instances[0])
+		results := accept(false)
+		require.Len(results, 1)
+		result := results[0]
+		require.True(result.Success)
+		sr, err := actions.UnmarshalPoolSwapResult(result.Outputs[0][0])
+		require.NoError(err)
+		require.Equal(sr.In, uint64(10_000))
+		require.Equal(sr.Out, uint64(1))
+
+		pool, err := instances[0].tcli.Pool(context.TODO(), poolID)
+		require.NoError(err)
+		require.Equal(pool.ReserveA, uint64(20_000))
+		require.Equal(pool.ReserveB, uint64(3))
+		balance, err := instances[0].tcli.Balance(context.TODO(), sender, asset3ID)
+		require.NoError(err)
+		require.Equal(balance, uint64(4))
+	})
+
+	ginkgo.It("add and remove liquidity", func() {
+		parser, err := instances[0].tcli.Parser(context.Background())
+		require.NoError(err)
+		submit, _, _, err := instances[0].cli.GenerateTransaction(
+			context.Background(),
+			parser,
+			[]chain.Action{&actions.AddLiquidity{
+				Pool:      poolID,
+				AssetA:    ids.Empty,
+				AssetB:    asset3ID,
+				AmountA:   30_000,
+				AmountB:   3,
+				MinShares: 200,
+			}},
+			factory,
+		)
+		require.NoError(err)
+		require.NoError(submit(context.Background()))
+
+		accept := expectBlk(instances[0])
+		results := accept(false)
+		require.Len(results, 1)
+		result := results[0]
+		require.True(result.Success)
+		lr, err := actions.UnmarshalLiquidityResult(result.Outputs[0][0])
+		require.NoError(err)
+		require.Equal(lr.A, uint64(20_000)) // only the amount at the pool rate is used
+		require.Equal(lr.B, uint64(3))
+		require.Equal(lr.Shares, uint64(200))
+
+		submit, _, _, err = instances[0].cli.GenerateTransaction(
+			context.Background(),
+			parser,
+			[]chain.Action{&actions.RemoveLiquidity{
+				Pool:   poolID,
+				AssetA: ids.Empty,
+				AssetB: asset3ID,
+				Shares: 100,
+				MinA:   10_000,
+				MinB:   1,
+			}},
+			factory,
+		)
+		require.NoError(err)
+		require.NoError(submit(context.Background()))
+
+		accept = expectBlk(instances[0])
+		results = accept(false)
+		require.Len(results, 1)
+		result = results[0]
+		require.True(result.Success)
+		lr, err = actions.UnmarshalLiquidityResult(result.Outputs[0][0])
+		require.NoError(err)
+		require.Equal(lr.A, uint64(10_000))
+		require.Equal(lr.B, uint64(1)) // rounded down
+		require.Equal(lr.Shares, uint64(100))
+
+		pool, err := instances[0].tcli.Pool(context.TODO(), poolID)
+		require.NoError(err)
+		require.Equal(pool.ReserveA, uint64(30_000))
+		require.Equal(pool.ReserveB, uint64(5))
+		require.Equal(pool.Shares, uint64(300))
+		balance, err := instances[0].tcli.Balance(context.TODO(), sender, poolID)
+		require.NoError(err)
+		require.Equal(balance, uint64(100))
+		balance, err = instances[0].tcli.Balance(context.TODO(), sender, asset3ID)
+		require.NoError(err)
+		require.Equal(balance, uint64(2))
+	})
+
+	ginkgo.It("export state", func() {
+		reply, err := instances[0].tcli.ExportState(context.TODO(), nil)
+		require.NoError(err)
+		require.Equal(reply.Height, instances[0].vm.LastAcceptedBlock().Hght)
+		require.NotEmpty(reply.State.CustomAllocation)
+
+		var pool *genesis.CustomPool
+		for _, p := range reply.State.CustomPools {
+			if p.ID == poolID {
+				pool = p
+			}
+		}
+		require.NotNil(pool)
+		require.Equal(pool.AssetA, ids.Empty)
+		require.Equal(pool.AssetB, asset3ID)
+		require.Equal(pool.ReserveA, uint64(30_000))
+		require.Equal(pool.ReserveB, uint64(5))
+		var shares *genesis.CustomAsset
+		for _, a := range reply.State.CustomAssets {
+			if a.ID == poolID {
+				shares = a
+			}
+		}
+		require.NotNil(shares)
+		require.Equal(shares.Supply, uint64(300))
+
+		// Binary snapshots should contain the same state
+		b, err := reply.State.Marshal()
+		require.NoError(err)
+		s, err := genesis.UnmarshalState(b)
+		require.NoError(err)
+		require.Equal(reply.State, s)
+
+		// State before liquidity was removed
+		height := reply.Height - 1
+		prev, err := instances[0].tcli.ExportState(context.TODO(), &height)
+		require.NoError(err)
+		require.Equal(prev.Height, height)
+		require.NotEqual(prev.Root, reply.Root)
+		for _, p := range prev.State.CustomPools {
+			if p.ID == poolID {
+				require.Equal(p.ReserveA, uint64(40_000))
+				require.Equal(p.ReserveB, uint64(6))
+			}
+		}
+	})
+
+	ginkgo.It("read historical state", func() {
+		// Genesis allocation
+		balance, err := instances[0].tcli.BalanceAt(context.TODO(), sender, ids.Empty, 0)
+		require.NoError(err)
+		require.Equal(balance, uint64(10_000_000))
+		exists, _, _, _, _, _, err := instances[0].tcli.AssetAt(context.TODO(), asset1ID, 0)
+		require.NoError(err)
+		require.False(exists)
+
+		// Latest state
+		height := instances[0].vm.LastAcceptedBlock().Hght
+		balance, err = instances[0].tcli.BalanceAt(context.TODO(), sender, ids.Empty, height)
+		require.NoError(err)
+		current, err := instances[0].tcli.Balance(context.TODO(), sender, ids.Empty)
+		require.NoError(err)
+		require.Equal(balance, current)
+		exists, _, _, _, _, _, err = instances[0].tcli.AssetAt(context.TODO(), asset1ID, height)
+		require.NoError(err)
+		require.True(exists)
+
+		// Heights that are not yet accepted can't be read
+		_, err = instances[0].tcli.BalanceAt(context.TODO(), sender, ids.Empty, height+1)
+		require.ErrorContains(err, vm.ErrArchiveIncomplete.Error())
+	})
+
+	ginkgo.It("bootstrap a new node from a snapshot", func() {
+		f, err := os.CreateTemp("", "snapshot")
+		require.NoError(err)
+		defer os.Remove(f.Name())
+		height, root, keys, err := instances[0].cli.Snapshot(context.TODO(), nil, f)
+		require.NoError(err)
+		require.NoError(f.Close())
+		require.Equal(height, instances[0].vm.LastAcceptedBlock().Hght)
+		require.Equal(root, instances[0].vm.LastAcceptedBlock().StateRoot)
+		require.Positive(keys)
+
+		nodeID := ids.GenerateTestNodeID()
+		sk, err := bls.NewSecretKey()
+		require.NoError(err)
+		l, err := logFactory.Make(nodeID.String())
+		require.NoError(err)
+		dname, err := os.MkdirTemp("", fmt.Sprintf("%s-chainData", nodeID.String()))
+		require.NoError(err)
+		snowCtx := &snow.Context{
+			NetworkID:      networkID,
+			SubnetID:       ids.GenerateTestID(),
+			ChainID:        instances[0].chainID,
+			NodeID:         nodeID,
+			Log:            l,
+			ChainDataDir:   dname,
+			Metrics:        metrics.NewPrefixGatherer(),
+			PublicKey:      bls.PublicFromSecretKey(sk),
+			ValidatorState: &validators.TestState{},
+		}
+		config, err := json.Marshal(map[string]any{
+			"snapshotPath": f.Name(),
+			"config":       map[string]any{"testMode": true, "trackedPairs": []string{"*"}},
+		})
+		require.NoError(err)
+		db := memdb.New()
+		v := controller.New()
+		require.NoError(v.Initialize(
+			context.TODO(),
+			snowCtx,
+			db,
+			genesisBytes,
+			nil,
+			config,
+			make(chan common.Message, 1),
+			nil,
+			&appSender{},
+		))
+		require.Equal(v.LastAcceptedBlock().ID(), instances[0].vm.LastAcceptedBlock().ID())
+
+		// The new node never processed the blocks that created the open
+		// orders, so its order book must be rebuilt from state
+		requireOrders := func(v *vm.VM) {
+			hd, err := v.CreateHandlers(context.TODO())
+			require.NoError(err)
+			server := httptest.NewServer(hd[trpc.JSONRPCEndpoint])
+			defer server.Close()
+			cli := trpc.NewJSONRPCClient(server.URL, networkID, instances[0].chainID)
+			var open int
+			for _, pair := range []string{actions.PairID(asset2ID, asset3ID), actions.PairID(asset3ID, asset2ID)} {
+				expected, err := instances[0].tcli.Orders(context.TODO(), pair)
+				require.NoError(err)
+				orders, err := cli.Orders(context.TODO(), pair)
+				require.NoError(err)
+				require.ElementsMatch(expected, orders)
+				open += len(orders)
+			}
+			require.Positive(open)
+		}
+		requireOrders(v)
+		require.NoError(v.Shutdown(context.TODO()))
+
+		// Nodes that restart with an empty order book (i.e. after upgrading)
+		// also rebuild it from state
+		require.NoError(os.RemoveAll(filepath.Join(dname, "orderbook")))
+		snowCtx.Metrics = metrics.NewPrefixGatherer()
+		v = controller.New()
+		require.NoError(v.Initialize(
+			context.TODO(),
+			snowCtx,
+			db,
+			genesisBytes,
+			nil,
+			config,
+			make(chan common.Message, 1),
+			nil,
+			&appSender{},
+		))
+		requireOrders(v)
+		require.NoError(v.Shutdown(context.TODO()))
+
+		// The last accepted block can be replayed from the database of the
+		// stopped node
+		report, err := controller.New().ReplayOffline(context.TODO(), &vm.ReplayConfig{
+			NetworkID:    networkID,
+			ChainID:      instances[0].chainID,
+			ChainDataDir: dname,
+			Genesis:      genesisBytes,
+			Config:       config,
+		}, height)
+		require.NoError(err)
+		require.Equal(report.ParentRoot, root)
+		require.Empty(report.Mismatches)
+
+		// The same snapshot can be exported from the database of the stopped
+		// node
+		var buf bytes.Buffer
+		header, offlineRoot, offlineKeys, err := controller.New().ExportSnapshotOffline(context.TODO(), &vm.ReplayConfig{
+			NetworkID:    networkID,
+			ChainID:      instances[0].chainID,
+			ChainDataDir: dname,
+			Genesis:      genesisBytes,
+			Config:       config,
+		}, nil, &buf)
+		require.NoError(err)
+		require.Equal(height, header.Height)
+		require.Equal(root, offlineRoot)
+		require.Equal(keys, offlineKeys)
+		exported, err := os.ReadFile(f.Name())
+		require.NoError(err)
+		require.Equal(exported, buf.Bytes())
+	})
+
+	ginkgo.It("replay accepted blocks", func() {
+		for height := uint64(1); height <= instances[0].vm.LastAcceptedBlock().Hght; height++ {
+			report, err := instances[0].vm.Replay(context.TODO(), height)
+			require.NoError(err)
+			require.Equal(report.Root, report.StoredRoot)
+			require.True(report.ComparedValues)
+			require.Empty(report.Mismatches)
+		}
+
+		_, err := instances[0].vm.Replay(context.TODO(), 0)
+		require.ErrorIs(err, vm.ErrReplayUnavailable)
+	})
+
 	// Use new instance to make balance checks easier (note, instances are in different
 	// states and would never agree)
 	ginkgo.It("transfer to multiple accounts in a single tx", func() {
@@ -0,0 +1,8 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import "errors"
+
+var ErrInvalidUpgrade = errors.New("invalid upgrade")
@@ -0,0 +1,38 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package genesis provides the chain parameters shared by hypersdk VMs and the
+// schedule of [Upgrade]s (provided in the upgradeBytes of a chain) that
+// changes them over time.
+package genesis
+
+import "github.com/ava-labs/hypersdk/fees"
+
+// Params are the rules of a chain that can be changed by an [Upgrade]. VMs
+// embed them in their genesis (so they are encoded next to any VM-specific
+// fields).
+type Params struct {
+	// Chain Parameters
+	MinBlockGap      int64 `json:"minBlockGap"`      // ms
+	MinEmptyBlockGap int64 `json:"minEmptyBlockGap"` // ms
+
+	// Chain Fee Parameters
+	MinUnitPrice               fees.Dimensions `json:"minUnitPrice"`
+	UnitPriceChangeDenominator fees.Dimensions `json:"unitPriceChangeDenominator"`
+	WindowTargetUnits          fees.Dimensions `json:"windowTargetUnits"` // 10s
+	MaxBlockUnits              fees.Dimensions `json:"maxBlockUnits"`     // must be possible to reach before block too large
+
+	// Tx Parameters
+	ValidityWindow      int64 `json:"validityWindow"` // ms
+	MaxActionsPerTx     uint8 `json:"maxActionsPerTx"`
+	MaxOutputsPerAction uint8 `json:"maxOutputsPerAction"`
+
+	// Tx Fee Parameters
+	BaseComputeUnits          uint64 `json:"baseUnits"`
+	StorageKeyReadUnits       uint64 `json:"storageKeyReadUnits"`
+	StorageValueReadUnits     uint64 `json:"storageValueReadUnits"` // per chunk
+	StorageKeyAllocateUnits   uint64 `json:"storageKeyAllocateUnits"`
+	StorageValueAllocateUnits uint64 `json:"storageValueAllocateUnits"` // per chunk
+	StorageKeyWriteUnits      uint64 `json:"storageKeyWriteUnits"`
+	StorageValueWriteUnits    uint64 `json:"storageValueWriteUnits"` // per chunk
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/fees"
+)
+
+// Rules implements all methods of [chain.ScheduledRules] that are derived from
+// [Params] and a [Schedule]. VMs embed it and implement the rest
+// (GetSponsorStateKeysMaxChunks and FetchCustom).
+type Rules struct {
+	p *Params
+	s *Schedule
+
+	networkID uint32
+	chainID   ids.ID
+}
+
+// NewRules returns the rules in effect at [t] (after applying all upgrades of
+// [s] activated at or before [t]). If [s] is nil, [genesis] is always in effect.
+func NewRules(genesis *Params, s *Schedule, t int64, networkID uint32, chainID ids.ID) *Rules {
+	if s == nil {
+		return &Rules{genesis, nil, networkID, chainID}
+	}
+	return &Rules{s.Active(t), s, networkID, chainID}
+}
+
+func (r *Rules) NetworkID() uint32 {
+	return r.networkID
+}
+
+func (r *Rules) ChainID() ids.ID {
+	return r.chainID
+}
+
+func (r *Rules) GetMinBlockGap() int64 {
+	return r.p.MinBlockGap
+}
+
+func (r *Rules) GetMinEmptyBlockGap() int64 {
+	return r.p.MinEmptyBlockGap
+}
+
+func (r *Rules) GetValidityWindow() int64 {
+	return r.p.ValidityWindow
+}
+
+func (r *Rules) GetMaxActionsPerTx() uint8 {
+	return r.p.MaxActionsPerTx
+}
+
+func (r *Rules) GetMaxOutputsPerAction() uint8 {
+	return r.p.MaxOutputsPerAction
+}
+
+func (r *Rules) GetMaxBlockUnits() fees.Dimensions {
+	return r.p.MaxBlockUnits
+}
+
+func (r *Rules) GetBaseComputeUnits() uint64 {
+	return r.p.BaseComputeUnits
+}
+
+func (r *Rules) GetStorageKeyReadUnits() uint64 {
+	return r.p.StorageKeyReadUnits
+}
+
+func (r *Rules) GetStorageValueReadUnits() uint64 {
+	return r.p.StorageValueReadUnits
+}
+
+func (r *Rules) GetStorageKeyAllocateUnits() uint64 {
+	return r.p.StorageKeyAllocateUnits
+}
+
+func (r *Rules) GetStorageValueAllocateUnits() uint64 {
+	return r.p.StorageValueAllocateUnits
+}
+
+func (r *Rules) GetStorageKeyWriteUnits() uint64 {
+	return r.p.StorageKeyWriteUnits
+}
+
+func (r *Rules) GetStorageValueWriteUnits() uint64 {
+	return r.p.StorageValueWriteUnits
+}
+
+func (r *Rules) GetMinUnitPrice() fees.Dimensions {
+	return r.p.MinUnitPrice
+}
+
+func (r *Rules) GetUnitPriceChangeDenominator() fees.Dimensions {
+	return r.p.UnitPriceChangeDenominator
+}
+
+func (r *Rules) GetWindowTargetUnits() fees.Dimensions {
+	return r.p.WindowTargetUnits
+}
+
+func (r *Rules) GetActionValidRange(typeID uint8) (int64, int64) {
+	if r.s == nil {
+		return -1, -1
+	}
+	return r.s.ActionRange(typeID)
+}
+
+func (r *Rules) GetAuthValidRange(typeID uint8) (int64, int64) {
+	if r.s == nil {
+		return -1, -1
+	}
+	return r.s.AuthRange(typeID)
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/hypersdk/fees"
+)
+
+// Upgrade overrides a subset of the [Params] of a chain starting at
+// [Timestamp]. Any parameter that is not set is inherited from the previous
+// [Upgrade] (or from the genesis if there is none).
+type Upgrade struct {
+	Name      string `json:"name"`
+	Timestamp int64  `json:"timestamp"` // ms
+
+	// Chain Parameters
+	MinBlockGap      *int64 `json:"minBlockGap,omitempty"`      // ms
+	MinEmptyBlockGap *int64 `json:"minEmptyBlockGap,omitempty"` // ms
+
+	// Chain Fee Parameters
+	MinUnitPrice               *fees.Dimensions `json:"minUnitPrice,omitempty"`
+	UnitPriceChangeDenominator *fees.Dimensions `json:"unitPriceChangeDenominator,omitempty"`
+	WindowTargetUnits          *fees.Dimensions `json:"windowTargetUnits,omitempty"`
+	MaxBlockUnits              *fees.Dimensions `json:"maxBlockUnits,omitempty"`
+
+	// Tx Parameters
+	ValidityWindow      *int64 `json:"validityWindow,omitempty"` // ms
+	MaxActionsPerTx     *uint8 `json:"maxActionsPerTx,omitempty"`
+	MaxOutputsPerAction *uint8 `json:"maxOutputsPerAction,omitempty"`
+
+	// Tx Fee Parameters
+	BaseComputeUnits          *uint64 `json:"baseUnits,omitempty"`
+	StorageKeyReadUnits       *uint64 `json:"storageKeyReadUnits,omitempty"`
+	StorageValueReadUnits     *uint64 `json:"storageValueReadUnits,omitempty"`
+	StorageKeyAllocateUnits   *uint64 `json:"storageKeyAllocateUnits,omitempty"`
+	StorageValueAllocateUnits *uint64 `json:"storageValueAllocateUnits,omitempty"`
+	StorageKeyWriteUnits      *uint64 `json:"storageKeyWriteUnits,omitempty"`
+	StorageValueWriteUnits    *uint64 `json:"storageValueWriteUnits,omitempty"`
+
+	// Action and auth type IDs that become valid (or invalid) at [Timestamp].
+	//
+	// Any type ID that is never activated or deactivated is always valid.
+	ActivateActions   []uint8 `json:"activateActions,omitempty"`
+	DeactivateActions []uint8 `json:"deactivateActions,omitempty"`
+	ActivateAuths     []uint8 `json:"activateAuths,omitempty"`
+	DeactivateAuths   []uint8 `json:"deactivateAuths,omitempty"`
+}
+
+// Upgrades is the schedule provided in the upgradeBytes of the chain.
+type Upgrades struct {
+	Upgrades []*Upgrade `json:"upgrades"`
+}
+
+// ParseUpgrades parses the upgradeBytes of a chain (which may be empty).
+func ParseUpgrades(b []byte) (*Upgrades, error) {
+	u := &Upgrades{}
+	if len(b) == 0 {
+		return u, nil
+	}
+	if err := json.Unmarshal(b, u); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal upgrades %s: %w", string(b), err)
+	}
+	return u, nil
+}
+
+type validRange struct {
+	start int64
+	end   int64
+}
+
+// paramSet is the genesis [Params] with all upgrades up to [timestamp]
+// applied.
+type paramSet struct {
+	timestamp int64
+	p         *Params
+}
+
+// Schedule is the resolved form of [Upgrades].
+type Schedule struct {
+	params  []*paramSet // sorted by timestamp, [params[0]] is the genesis
+	actions map[uint8]*validRange
+	auths   map[uint8]*validRange
+}
+
+// NewSchedule applies [u] (in order of their timestamp) on top of [genesis].
+func NewSchedule(genesis *Params, u *Upgrades) (*Schedule, error) {
+	upgrades := make([]*Upgrade, len(u.Upgrades))
+	copy(upgrades, u.Upgrades)
+	sort.SliceStable(upgrades, func(i, j int) bool {
+		return upgrades[i].Timestamp < upgrades[j].Timestamp
+	})
+
+	s := &Schedule{
+		params:  []*paramSet{{0, genesis}},
+		actions: map[uint8]*validRange{},
+		auths:   map[uint8]*validRange{},
+	}
+	for i, upgrade := range upgrades {
+		if upgrade.Timestamp <= 0 {
+			return nil, fmt.Errorf("%w: %s has invalid timestamp %d", ErrInvalidUpgrade, upgrade.Name, upgrade.Timestamp)
+		}
+		if i > 0 && upgrade.Timestamp == upgrades[i-1].Timestamp {
+			return nil, fmt.Errorf("%w: %s and %s have the same timestamp", ErrInvalidUpgrade, upgrades[i-1].Name, upgrade.Name)
+		}
+		next := *s.params[len(s.params)-1].p
+		upgrade.apply(&next)
+		s.params = append(s.params, &paramSet{upgrade.Timestamp, &next})
+
+		if err := activate(s.actions, upgrade.ActivateActions, upgrade.DeactivateActions, upgrade.Timestamp); err != nil {
+			return nil, fmt.Errorf("%w: %s has invalid action schedule: %w", ErrInvalidUpgrade, upgrade.Name, err)
+		}
+		if err := activate(s.auths, upgrade.ActivateAuths, upgrade.DeactivateAuths, upgrade.Timestamp); err != nil {
+			return nil, fmt.Errorf("%w: %s has invalid auth schedule: %w", ErrInvalidUpgrade, upgrade.Name, err)
+		}
+	}
+	return s, nil
+}
+
+// activate updates [ranges] with the type IDs that are activated and
+// deactivated at [timestamp]. Upgrades must be processed in order.
+func activate(ranges map[uint8]*validRange, activated []uint8, deactivated []uint8, timestamp int64) error {
+	for _, typeID := range activated {
+		if _, ok := ranges[typeID]; ok {
+			return fmt.Errorf("type %d already scheduled", typeID)
+		}
+		ranges[typeID] = &validRange{timestamp, -1}
+	}
+	for _, typeID := range deactivated {
+		r, ok := ranges[typeID]
+		if !ok {
+			// Valid since genesis
+			r = &validRange{-1, -1}
+			ranges[typeID] = r
+		}
+		if r.end >= 0 || r.start == timestamp {
+			return fmt.Errorf("type %d already deactivated", typeID)
+		}
+		r.end = timestamp - 1
+	}
+	return nil
+}
+
+func (u *Upgrade) apply(p *Params) {
+	setIf(&p.MinBlockGap, u.MinBlockGap)
+	setIf(&p.MinEmptyBlockGap, u.MinEmptyBlockGap)
+	setIf(&p.MinUnitPrice, u.MinUnitPrice)
+	setIf(&p.UnitPriceChangeDenominator, u.UnitPriceChangeDenominator)
+	setIf(&p.WindowTargetUnits, u.WindowTargetUnits)
+	setIf(&p.MaxBlockUnits, u.MaxBlockUnits)
+	setIf(&p.ValidityWindow, u.ValidityWindow)
+	setIf(&p.MaxActionsPerTx, u.MaxActionsPerTx)
+	setIf(&p.MaxOutputsPerAction, u.MaxOutputsPerAction)
+	setIf(&p.BaseComputeUnits, u.BaseComputeUnits)
+	setIf(&p.StorageKeyReadUnits, u.StorageKeyReadUnits)
+	setIf(&p.StorageValueReadUnits, u.StorageValueReadUnits)
+	setIf(&p.StorageKeyAllocateUnits, u.StorageKeyAllocateUnits)
+	setIf(&p.StorageValueAllocateUnits, u.StorageValueAllocateUnits)
+	setIf(&p.StorageKeyWriteUnits, u.StorageKeyWriteUnits)
+	setIf(&p.StorageValueWriteUnits, u.StorageValueWriteUnits)
+}
+
+func setIf[T any](dst *T, v *T) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
+// Active returns the parameters in effect at [t].
+func (s *Schedule) Active(t int64) *Params {
+	i := sort.Search(len(s.params), func(i int) bool {
+		return s.params[i].timestamp > t
+	})
+	if i == 0 {
+		return s.params[0].p
+	}
+	return s.params[i-1].p
+}
+
+// ActionRange returns the timestamp range (in ms) that the action with
+// [typeID] is valid. -1 means no start/end.
+func (s *Schedule) ActionRange(typeID uint8) (int64, int64) {
+	if r, ok := s.actions[typeID]; ok {
+		return r.start, r.end
+	}
+	return -1, -1
+}
+
+// AuthRange returns the timestamp range (in ms) that the auth with [typeID]
+// is valid. -1 means no start/end.
+func (s *Schedule) AuthRange(typeID uint8) (int64, int64) {
+	if r, ok := s.auths[typeID]; ok {
+		return r.start, r.end
+	}
+	return -1, -1
+}
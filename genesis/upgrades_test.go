@@ -0,0 +1,105 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/fees"
+)
+
+func testParams() *Params {
+	return &Params{
+		MinBlockGap:     100,
+		MinUnitPrice:    fees.Dimensions{100, 100, 100, 100, 100},
+		ValidityWindow:  60_000,
+		MaxActionsPerTx: 16,
+	}
+}
+
+func TestUpgrades(t *testing.T) {
+	require := require.New(t)
+
+	u, err := ParseUpgrades([]byte(`{"upgrades":[
+		{"name":"second","timestamp":2000,"maxActionsPerTx":4,"deactivateActions":[3],"activateAuths":[1]},
+		{"name":"first","timestamp":1000,"minUnitPrice":[5,5,5,5,5],"activateActions":[10]}
+	]}`))
+	require.NoError(err)
+	def := testParams()
+	s, err := NewSchedule(def, u)
+	require.NoError(err)
+
+	// Before any upgrade
+	r := NewRules(def, s, 999, 1, ids.Empty)
+	require.Equal(def.MinUnitPrice, r.GetMinUnitPrice())
+	require.Equal(def.MaxActionsPerTx, r.GetMaxActionsPerTx())
+
+	// Upgrades are applied in order of their timestamp
+	r = NewRules(def, s, 1000, 1, ids.Empty)
+	require.Equal(fees.Dimensions{5, 5, 5, 5, 5}, r.GetMinUnitPrice())
+	require.Equal(def.MaxActionsPerTx, r.GetMaxActionsPerTx())
+	r = NewRules(def, s, 5000, 1, ids.Empty)
+	require.Equal(fees.Dimensions{5, 5, 5, 5, 5}, r.GetMinUnitPrice())
+	require.Equal(uint8(4), r.GetMaxActionsPerTx())
+	require.Equal(def.ValidityWindow, r.GetValidityWindow())
+
+	// The genesis is not modified
+	require.Equal(testParams(), def)
+
+	// Action and auth ranges are derived from the schedule
+	start, end := r.GetActionValidRange(10)
+	require.Equal(int64(1000), start)
+	require.Equal(int64(-1), end)
+	start, end = r.GetActionValidRange(3)
+	require.Equal(int64(-1), start)
+	require.Equal(int64(1999), end)
+	start, end = r.GetActionValidRange(0)
+	require.Equal(int64(-1), start)
+	require.Equal(int64(-1), end)
+	start, end = r.GetAuthValidRange(1)
+	require.Equal(int64(2000), start)
+	require.Equal(int64(-1), end)
+
+	// Without a schedule, the genesis is always in effect
+	r = NewRules(def, nil, 5000, 1, ids.Empty)
+	require.Equal(def.MinUnitPrice, r.GetMinUnitPrice())
+	start, end = r.GetActionValidRange(10)
+	require.Equal(int64(-1), start)
+	require.Equal(int64(-1), end)
+}
+
+func TestInvalidUpgrades(t *testing.T) {
+	tests := []struct {
+		name     string
+		upgrades string
+	}{
+		{
+			name:     "zero timestamp",
+			upgrades: `{"upgrades":[{"name":"a","timestamp":0}]}`,
+		},
+		{
+			name:     "duplicate timestamp",
+			upgrades: `{"upgrades":[{"name":"a","timestamp":1},{"name":"b","timestamp":1}]}`,
+		},
+		{
+			name:     "activated twice",
+			upgrades: `{"upgrades":[{"name":"a","timestamp":1,"activateActions":[0]},{"name":"b","timestamp":2,"activateActions":[0]}]}`,
+		},
+		{
+			name:     "deactivated twice",
+			upgrades: `{"upgrades":[{"name":"a","timestamp":1,"deactivateAuths":[0]},{"name":"b","timestamp":2,"deactivateAuths":[0]}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := ParseUpgrades([]byte(tt.upgrades))
+			require.NoError(t, err)
+			_, err = NewSchedule(testParams(), u)
+			require.ErrorIs(t, err, ErrInvalidUpgrade)
+		})
+	}
+}
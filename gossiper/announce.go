@@ -0,0 +1,252 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gossiper
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/cache"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+const (
+	// maxAnnouncedIDs is the maximum number of IDs in an announcement or
+	// request.
+	maxAnnouncedIDs = (consts.NetworkSizeLimit - consts.IntLen) / ids.IDLen
+
+	// errCodeNoTxs is sent when none of the requested txs are available.
+	errCodeNoTxs int32 = 1
+)
+
+var (
+	ErrTooManyIDs    = errors.New("too many IDs")
+	ErrUnrequestedTx = errors.New("unrequested tx")
+)
+
+type txRequest struct {
+	nodeID ids.NodeID
+	txIDs  set.Set[ids.ID]
+}
+
+func newAnnouncedCache(size int) (*cache.FIFO[ids.ID, *chain.Transaction], error) {
+	if size <= 0 {
+		size = 1
+	}
+	return cache.NewFIFO[ids.ID, *chain.Transaction](size)
+}
+
+// marshalIDs encodes a batch of transaction IDs (used for both announcements
+// and requests).
+func marshalIDs(txIDs []ids.ID) ([]byte, error) {
+	if len(txIDs) > maxAnnouncedIDs {
+		return nil, ErrTooManyIDs
+	}
+	p := codec.NewWriter(consts.IntLen+len(txIDs)*ids.IDLen, consts.NetworkSizeLimit)
+	p.PackInt(len(txIDs))
+	for _, txID := range txIDs {
+		p.PackID(txID)
+	}
+	return p.Bytes(), p.Err()
+}
+
+// unmarshalIDs decodes a batch of transaction IDs encoded with [marshalIDs].
+func unmarshalIDs(raw []byte) ([]ids.ID, error) {
+	p := codec.NewReader(raw, consts.NetworkSizeLimit)
+	count := p.UnpackInt(true)
+	if count > maxAnnouncedIDs {
+		return nil, ErrTooManyIDs
+	}
+	txIDs := make([]ids.ID, count)
+	for i := range txIDs {
+		p.UnpackID(true, &txIDs[i])
+	}
+	if !p.Empty() {
+		// Ensure no leftover bytes
+		return nil, chain.ErrInvalidObject
+	}
+	return txIDs, p.Err()
+}
+
+// announce sends the IDs of [txs] to [nodeIDs] and holds on to [txs] until
+// they are requested (or evicted).
+func (g *Proposer) announce(ctx context.Context, nodeIDs set.Set[ids.NodeID], txs []*chain.Transaction) error {
+	ctx, span := g.vm.Tracer().Start(ctx, "Gossiper.announce")
+	defer span.End()
+
+	txIDs := make([]ids.ID, len(txs))
+	for i, tx := range txs {
+		txIDs[i] = tx.ID()
+		g.announced.Put(txIDs[i], tx)
+	}
+	b, err := marshalIDs(txIDs)
+	if err != nil {
+		return err
+	}
+	g.vm.RecordTxsAnnounced(len(txs))
+	return g.announceSender.SendAppGossip(ctx, common.SendConfig{NodeIDs: nodeIDs}, b)
+}
+
+// HandleAnnouncement requests any announced transactions that we have not yet
+// seen (or requested) from [nodeID].
+func (g *Proposer) HandleAnnouncement(ctx context.Context, nodeID ids.NodeID, msg []byte) error {
+	txIDs, err := unmarshalIDs(msg)
+	if err != nil {
+		g.vm.Logger().Warn(
+			"received invalid announcement",
+			zap.Stringer("peerID", nodeID),
+			zap.Error(err),
+		)
+		g.vm.PenalizePeer(nodeID, 1)
+		return nil
+	}
+
+	g.rl.Lock()
+	missing := make([]ids.ID, 0, len(txIDs))
+	for _, txID := range txIDs {
+		if _, ok := g.cache.Get(txID); ok || g.pending.Contains(txID) {
+			continue
+		}
+		missing = append(missing, txID)
+	}
+	if len(missing) == 0 {
+		g.rl.Unlock()
+		return nil
+	}
+	requestID := g.requestID
+	g.requestID++
+	g.requests[requestID] = &txRequest{nodeID: nodeID, txIDs: set.Of(missing...)}
+	g.pending.Add(missing...)
+	g.rl.Unlock()
+
+	b, err := marshalIDs(missing)
+	if err != nil {
+		// Should never happen because [missing] is a subset of [txIDs]
+		g.clearRequest(requestID)
+		return nil
+	}
+	g.vm.RecordTxsRequested(len(missing))
+	g.vm.Logger().Debug(
+		"requesting announced txs",
+		zap.Stringer("peerID", nodeID),
+		zap.Int("announced", len(txIDs)),
+		zap.Int("requested", len(missing)),
+	)
+	if err := g.announceSender.SendAppRequest(ctx, set.Of(nodeID), requestID, b); err != nil {
+		g.clearRequest(requestID)
+		return err
+	}
+	return nil
+}
+
+// HandleTxRequest responds with the requested transactions we announced.
+func (g *Proposer) HandleTxRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, msg []byte) error {
+	txIDs, err := unmarshalIDs(msg)
+	if err != nil {
+		g.vm.Logger().Warn(
+			"received invalid tx request",
+			zap.Stringer("peerID", nodeID),
+			zap.Error(err),
+		)
+		g.vm.PenalizePeer(nodeID, 1)
+		return nil
+	}
+	var (
+		txs  = make([]*chain.Transaction, 0, len(txIDs))
+		size = consts.IntLen
+	)
+	for _, txID := range txIDs {
+		tx, ok := g.announced.Get(txID)
+		if !ok {
+			continue
+		}
+		txSize := tx.Size()
+		if size+txSize > consts.NetworkSizeLimit {
+			break
+		}
+		txs = append(txs, tx)
+		size += txSize
+	}
+	if len(txs) == 0 {
+		return g.announceSender.SendAppError(ctx, nodeID, requestID, errCodeNoTxs, "no requested txs available")
+	}
+	b, err := chain.MarshalTxs(txs)
+	if err != nil {
+		return err
+	}
+	return g.announceSender.SendAppResponse(ctx, nodeID, requestID, b)
+}
+
+// HandleTxResponse submits the transactions we requested from [nodeID].
+func (g *Proposer) HandleTxResponse(ctx context.Context, nodeID ids.NodeID, requestID uint32, msg []byte) error {
+	req := g.clearRequest(requestID)
+	if req == nil || req.nodeID != nodeID {
+		g.vm.Logger().Debug(
+			"received unexpected tx response",
+			zap.Stringer("peerID", nodeID),
+			zap.Uint32("requestID", requestID),
+		)
+		return nil
+	}
+	actionRegistry, authRegistry := g.vm.Registry()
+	authCounts, txs, err := chain.UnmarshalTxs(msg, initialCapacity, actionRegistry, authRegistry)
+	if err != nil {
+		g.vm.Logger().Warn(
+			"received invalid tx response",
+			zap.Stringer("peerID", nodeID),
+			zap.Error(err),
+		)
+		g.vm.PenalizePeer(nodeID, 1)
+		return nil
+	}
+	for _, tx := range txs {
+		if !req.txIDs.Contains(tx.ID()) {
+			g.vm.Logger().Warn(
+				"received invalid tx response",
+				zap.Stringer("peerID", nodeID),
+				zap.Stringer("txID", tx.ID()),
+				zap.Error(ErrUnrequestedTx),
+			)
+			g.vm.PenalizePeer(nodeID, 1)
+			return nil
+		}
+	}
+	g.vm.RecordTxsReceived(len(txs))
+	g.handleTxs(ctx, nodeID, authCounts, txs)
+	return nil
+}
+
+// HandleTxRequestFailed allows the transactions requested in [requestID] to be
+// requested again (from whoever announces them next).
+func (g *Proposer) HandleTxRequestFailed(_ context.Context, nodeID ids.NodeID, requestID uint32) error {
+	if req := g.clearRequest(requestID); req != nil {
+		g.vm.Logger().Debug(
+			"tx request failed",
+			zap.Stringer("peerID", nodeID),
+			zap.Uint32("requestID", requestID),
+			zap.Int("txs", req.txIDs.Len()),
+		)
+	}
+	return nil
+}
+
+func (g *Proposer) clearRequest(requestID uint32) *txRequest {
+	g.rl.Lock()
+	defer g.rl.Unlock()
+
+	req, ok := g.requests[requestID]
+	if !ok {
+		return nil
+	}
+	delete(g.requests, requestID)
+	g.pending.Remove(req.txIDs.List()...)
+	return req
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gossiper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+type testVM struct {
+	VM // unused methods panic
+
+	penalties map[ids.NodeID]int
+	requested int
+}
+
+func (*testVM) Logger() logging.Logger {
+	return logging.NoLog{}
+}
+
+func (*testVM) Tracer() trace.Tracer {
+	return trace.Noop
+}
+
+func (vm *testVM) PenalizePeer(nodeID ids.NodeID, invalidTxs int) {
+	vm.penalties[nodeID] += invalidTxs
+}
+
+func (vm *testVM) RecordTxsRequested(c int) {
+	vm.requested += c
+}
+
+func TestMarshalIDs(t *testing.T) {
+	require := require.New(t)
+
+	txIDs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+	b, err := marshalIDs(txIDs)
+	require.NoError(err)
+	parsed, err := unmarshalIDs(b)
+	require.NoError(err)
+	require.Equal(txIDs, parsed)
+
+	_, err = unmarshalIDs(append(b, 0))
+	require.ErrorIs(err, chain.ErrInvalidObject)
+
+	_, err = marshalIDs(make([]ids.ID, maxAnnouncedIDs+1))
+	require.ErrorIs(err, ErrTooManyIDs)
+}
+
+func TestHandleAnnouncement(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	vm := &testVM{penalties: map[ids.NodeID]int{}}
+	g, err := NewProposer(vm, DefaultProposerConfig())
+	require.NoError(err)
+	sender := common.FakeSender{
+		SentAppRequest: make(chan []byte, 1),
+		SentAppError:   make(chan *common.AppError, 1),
+	}
+	g.announceSender = sender
+
+	var (
+		seen    = ids.GenerateTestID()
+		unseen  = ids.GenerateTestID()
+		nodeID  = ids.GenerateTestNodeID()
+		nodeID2 = ids.GenerateTestNodeID()
+	)
+	g.cache.Put(seen, nil)
+	announcement, err := marshalIDs([]ids.ID{seen, unseen})
+	require.NoError(err)
+
+	// Only unseen txs are requested
+	require.NoError(g.HandleAnnouncement(ctx, nodeID, announcement))
+	request := <-sender.SentAppRequest
+	txIDs, err := unmarshalIDs(request)
+	require.NoError(err)
+	require.Equal([]ids.ID{unseen}, txIDs)
+	require.Equal(1, vm.requested)
+
+	// Pending txs are not requested again
+	require.NoError(g.HandleAnnouncement(ctx, nodeID2, announcement))
+	require.Empty(sender.SentAppRequest)
+
+	// Failed requests can be retried
+	require.NoError(g.HandleTxRequestFailed(ctx, nodeID, 0))
+	require.NoError(g.HandleAnnouncement(ctx, nodeID2, announcement))
+	require.Len(sender.SentAppRequest, 1)
+
+	// Unannounced txs are not served
+	require.NoError(g.HandleTxRequest(ctx, nodeID, 0, request))
+	appErr := <-sender.SentAppError
+	require.Equal(errCodeNoTxs, appErr.Code)
+
+	// Invalid announcements are penalized
+	require.NoError(g.HandleAnnouncement(ctx, nodeID, []byte{1}))
+	require.Equal(1, vm.penalties[nodeID])
+}
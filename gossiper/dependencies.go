@@ -29,11 +29,17 @@ type VM interface {
 	Registry() (chain.ActionRegistry, chain.AuthRegistry)
 	NodeID() ids.NodeID
 	Rules(int64) chain.Rules
-	Submit(ctx context.Context, verify bool, txs []*chain.Transaction) []error
+	Submit(ctx context.Context, verify bool, txs []*chain.Transaction) ([]error, error)
 	GetAuthBatchVerifier(authTypeID uint8, cores int, count int) (chain.AuthBatchVerifier, bool)
 	StateManager() chain.StateManager
 
 	RecordTxsGossiped(int)
 	RecordSeenTxsReceived(int)
 	RecordTxsReceived(int)
+	RecordTxsAnnounced(int)
+	RecordTxsRequested(int)
+
+	// PenalizePeer downranks [nodeID] for gossiping [invalidTxs] transactions
+	// that failed verification.
+	PenalizePeer(nodeID ids.NodeID, invalidTxs int)
 }
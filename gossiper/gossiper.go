@@ -5,16 +5,42 @@ package gossiper
 
 import (
 	"context"
+	"errors"
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+
+	"github.com/ava-labs/hypersdk/chain"
 )
 
 type Gossiper interface {
-	Run(common.AppSender)
+	// Run starts gossiping. Full transactions are pushed with [gossipSender]
+	// and announcements (and requests for announced transactions) are sent
+	// with [announceSender].
+	Run(gossipSender common.AppSender, announceSender common.AppSender)
 	Queue(context.Context)
 	Force(context.Context) error // may be triggered by run already
 	HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg []byte) error
+
+	// Pull-based gossip (received by the handler of [announceSender])
+	HandleAnnouncement(ctx context.Context, nodeID ids.NodeID, msg []byte) error
+	HandleTxRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, msg []byte) error
+	HandleTxResponse(ctx context.Context, nodeID ids.NodeID, requestID uint32, msg []byte) error
+	HandleTxRequestFailed(ctx context.Context, nodeID ids.NodeID, requestID uint32) error
+
 	BlockVerified(int64)
 	Done() // wait after stop
 }
+
+// invalidTxs returns the number of gossiped transactions rejected by
+// [VM.Submit] (ignoring duplicates).
+func invalidTxs(errs []error) int {
+	var invalid int
+	for _, err := range errs {
+		if err == nil || errors.Is(err, chain.ErrDuplicateTx) {
+			continue
+		}
+		invalid++
+	}
+	return invalid
+}
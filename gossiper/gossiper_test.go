@@ -0,0 +1,26 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gossiper
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+func TestInvalidTxs(t *testing.T) {
+	errInvalid := errors.New("invalid")
+	require.Equal(t, 2, invalidTxs([]error{
+		nil,
+		errInvalid,
+		chain.ErrDuplicateTx,
+		fmt.Errorf("not added: %w", chain.ErrDuplicateTx),
+		errInvalid,
+	}))
+	require.Zero(t, invalidTxs(nil))
+}
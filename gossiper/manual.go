@@ -30,7 +30,7 @@ func NewManual(vm VM) *Manual {
 	}
 }
 
-func (g *Manual) Run(appSender common.AppSender) {
+func (g *Manual) Run(appSender common.AppSender, _ common.AppSender) {
 	g.appSender = appSender
 
 	// Only respond to explicitly triggered gossip
@@ -93,12 +93,26 @@ func (g *Manual) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg []b
 			zap.Stringer("peerID", nodeID),
 			zap.Error(err),
 		)
+		g.vm.PenalizePeer(nodeID, 1)
 		return nil
 	}
 	g.vm.RecordTxsReceived(len(txs))
 
 	start := time.Now()
-	for _, err := range g.vm.Submit(ctx, true, txs) {
+	errs, err := g.vm.Submit(ctx, true, txs)
+	if err != nil {
+		// We could not verify any of [txs], so [nodeID] is not penalized.
+		g.vm.Logger().Warn(
+			"AppGossip unable to submit txs",
+			zap.Stringer("peerID", nodeID),
+			zap.Error(err),
+		)
+		return nil
+	}
+	if invalid := invalidTxs(errs); invalid > 0 {
+		g.vm.PenalizePeer(nodeID, invalid)
+	}
+	for _, err := range errs {
 		if err == nil {
 			continue
 		}
@@ -117,6 +131,26 @@ func (g *Manual) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg []b
 	return nil
 }
 
+// HandleAnnouncement is a no-op in [Manual] (which never announces).
+func (*Manual) HandleAnnouncement(context.Context, ids.NodeID, []byte) error {
+	return nil
+}
+
+// HandleTxRequest is a no-op in [Manual] (which never announces).
+func (*Manual) HandleTxRequest(context.Context, ids.NodeID, uint32, []byte) error {
+	return nil
+}
+
+// HandleTxResponse is a no-op in [Manual] (which never requests).
+func (*Manual) HandleTxResponse(context.Context, ids.NodeID, uint32, []byte) error {
+	return nil
+}
+
+// HandleTxRequestFailed is a no-op in [Manual] (which never requests).
+func (*Manual) HandleTxRequestFailed(context.Context, ids.NodeID, uint32) error {
+	return nil
+}
+
 func (*Manual) BlockVerified(int64) {}
 
 func (g *Manual) Done() {
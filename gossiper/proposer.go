@@ -27,10 +27,11 @@ import (
 var _ Gossiper = (*Proposer)(nil)
 
 type Proposer struct {
-	vm         VM
-	cfg        *ProposerConfig
-	appSender  common.AppSender
-	doneGossip chan struct{}
+	vm             VM
+	cfg            *ProposerConfig
+	appSender      common.AppSender
+	announceSender common.AppSender
+	doneGossip     chan struct{}
 
 	lastVerified int64
 
@@ -43,6 +44,17 @@ type Proposer struct {
 
 	// cache is thread-safe
 	cache *cache.FIFO[ids.ID, any]
+
+	// announced holds the transactions we announced (which are removed from
+	// the mempool) so we can serve requests for them.
+	//
+	// announced is thread-safe
+	announced *cache.FIFO[ids.ID, *chain.Transaction]
+
+	rl        sync.Mutex
+	requestID uint32
+	requests  map[uint32]*txRequest
+	pending   set.Set[ids.ID] // requested but not yet received
 }
 
 type ProposerConfig struct {
@@ -54,6 +66,14 @@ type ProposerConfig struct {
 	NoGossipBuilderDiff int
 	VerifyTimeout       int64 // ms
 	SeenCacheSize       int
+
+	// If [GossipAnnounce] is enabled, only the proposers within
+	// [GossipPushProposerDiff] are sent full transactions. The rest of the
+	// proposers within [GossipProposerDiff] are sent the IDs of the
+	// transactions and request the ones they haven't seen.
+	GossipAnnounce          bool
+	GossipPushProposerDiff  int
+	GossipAnnounceCacheSize int // announced txs kept to serve requests
 }
 
 func DefaultProposerConfig() *ProposerConfig {
@@ -66,6 +86,10 @@ func DefaultProposerConfig() *ProposerConfig {
 		NoGossipBuilderDiff: 1,
 		VerifyTimeout:       proposer.MaxVerifyDelay.Milliseconds(),
 		SeenCacheSize:       2_500_000,
+
+		GossipAnnounce:          false,
+		GossipPushProposerDiff:  1,
+		GossipAnnounceCacheSize: 65_536,
 	}
 }
 
@@ -79,6 +103,9 @@ func NewProposer(vm VM, cfg *ProposerConfig) (*Proposer, error) {
 
 		q:         make(chan struct{}),
 		lastQueue: -1,
+
+		requests: map[uint32]*txRequest{},
+		pending:  set.NewSet[ids.ID](0),
 	}
 	g.timer = timer.NewTimer(g.handleTimerNotify)
 	cache, err := cache.NewFIFO[ids.ID, any](cfg.SeenCacheSize)
@@ -86,6 +113,11 @@ func NewProposer(vm VM, cfg *ProposerConfig) (*Proposer, error) {
 		return nil, err
 	}
 	g.cache = cache
+	announced, err := newAnnouncedCache(cfg.GossipAnnounceCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	g.announced = announced
 	return g, nil
 }
 
@@ -170,10 +202,25 @@ func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg [
 			zap.Stringer("peerID", nodeID),
 			zap.Error(err),
 		)
+		g.vm.PenalizePeer(nodeID, 1)
 		return nil
 	}
 	g.vm.RecordTxsReceived(len(txs))
+	g.handleTxs(ctx, nodeID, authCounts, txs)
+
+	// only trace error to prevent VM's being shutdown
+	// from "AppGossip" returning an error
+	return nil
+}
 
+// handleTxs verifies and submits [txs] received from [nodeID] (either pushed
+// or requested).
+func (g *Proposer) handleTxs(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	authCounts map[uint8]int,
+	txs []*chain.Transaction,
+) {
 	// Add incoming transactions to our caches to prevent useless gossip and perform
 	// batch signature verification.
 	//
@@ -186,7 +233,7 @@ func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg [
 			zap.Stringer("peerID", nodeID),
 			zap.Error(err),
 		)
-		return nil
+		return
 	}
 	batchVerifier := chain.NewAuthBatch(g.vm, job, authCounts)
 	var seen int
@@ -200,7 +247,7 @@ func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg [
 				zap.Error(err),
 			)
 			batchVerifier.Done(nil)
-			return nil
+			return
 		}
 		batchVerifier.Add(txDigest, tx.Auth)
 
@@ -221,7 +268,10 @@ func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg [
 			zap.Stringer("peerID", nodeID),
 			zap.Error(err),
 		)
-		return nil
+		// We don't know which transactions failed verification, so we treat
+		// the entire batch (which we drop) as invalid.
+		g.vm.PenalizePeer(nodeID, len(txs))
+		return
 	}
 
 	// Mark incoming gossip as held by [nodeID], if it is a validator
@@ -236,7 +286,20 @@ func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg [
 
 	// Submit incoming gossip to mempool
 	start := time.Now()
-	for _, err := range g.vm.Submit(ctx, false, txs) {
+	errs, err := g.vm.Submit(ctx, false, txs)
+	if err != nil {
+		// We could not verify any of [txs], so [nodeID] is not penalized.
+		g.vm.Logger().Debug(
+			"unable to submit gossiped txs",
+			zap.Stringer("nodeID", nodeID),
+			zap.Error(err),
+		)
+		return
+	}
+	if invalid := invalidTxs(errs); invalid > 0 {
+		g.vm.PenalizePeer(nodeID, invalid)
+	}
+	for _, err := range errs {
 		if err == nil || errors.Is(err, chain.ErrDuplicateTx) {
 			continue
 		}
@@ -255,10 +318,6 @@ func (g *Proposer) HandleAppGossip(ctx context.Context, nodeID ids.NodeID, msg [
 		zap.Bool("validator", isValidator),
 		zap.Duration("t", time.Since(start)),
 	)
-
-	// only trace error to prevent VM's being shutdown
-	// from "AppGossip" returning an error
-	return nil
 }
 
 func (g *Proposer) notify() {
@@ -293,8 +352,9 @@ func (g *Proposer) Queue(context.Context) {
 }
 
 // periodically but less aggressively force-regossip the pending
-func (g *Proposer) Run(appSender common.AppSender) {
+func (g *Proposer) Run(appSender common.AppSender, announceSender common.AppSender) {
 	g.appSender = appSender
+	g.announceSender = announceSender
 	defer close(g.doneGossip)
 
 	// Timer blocks until stopped
@@ -350,12 +410,6 @@ func (g *Proposer) sendTxs(ctx context.Context, txs []*chain.Transaction) error
 	ctx, span := g.vm.Tracer().Start(ctx, "Gossiper.sendTxs")
 	defer span.End()
 
-	// Marshal gossip
-	b, err := chain.MarshalTxs(txs)
-	if err != nil {
-		return err
-	}
-
 	// Select next set of proposers and send gossip to them
 	proposers, err := g.vm.Proposers(
 		ctx,
@@ -368,13 +422,48 @@ func (g *Proposer) sendTxs(ctx context.Context, txs []*chain.Transaction) error
 	if proposers.Len() == 0 {
 		return errors.New("no proposers to gossip to")
 	}
-	recipients := set.NewSet[ids.NodeID](len(proposers))
+
+	// If announcements are enabled, only push txs to the proposers that will
+	// produce soonest
+	var pushProposers set.Set[ids.NodeID]
+	if g.cfg.GossipAnnounce {
+		pushProposers, err = g.vm.Proposers(
+			ctx,
+			g.cfg.GossipPushProposerDiff,
+			g.cfg.GossipProposerDepth,
+		)
+		if err != nil {
+			return fmt.Errorf("%w: unable to fetch push proposers", err)
+		}
+	}
+	var (
+		recipients = set.NewSet[ids.NodeID](len(proposers))
+		announcees = set.NewSet[ids.NodeID](len(proposers))
+	)
 	for proposer := range proposers {
 		// Don't gossip to self
 		if proposer == g.vm.NodeID() {
 			continue
 		}
+		if g.cfg.GossipAnnounce && !pushProposers.Contains(proposer) {
+			announcees.Add(proposer)
+			continue
+		}
 		recipients.Add(proposer)
 	}
+	if announcees.Len() > 0 {
+		if err := g.announce(ctx, announcees, txs); err != nil {
+			return err
+		}
+	}
+	if recipients.Len() == 0 {
+		return nil
+	}
+
+	// Marshal gossip
+	b, err := chain.MarshalTxs(txs)
+	if err != nil {
+		return err
+	}
 	return g.appSender.SendAppGossip(ctx, common.SendConfig{NodeIDs: recipients}, b)
 }
@@ -0,0 +1,89 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"math"
+	"time"
+)
+
+// Limit bounds the rate at which a handler accepts messages from a single
+// peer. Each rate is refilled continuously up to its burst. A rate of 0
+// disables the corresponding limit.
+//
+// ByteBurst must be at least the size of the largest message the handler
+// expects, otherwise such messages will always be dropped.
+type Limit struct {
+	MessageRate  float64 `json:"messageRate"` // messages per second
+	MessageBurst int     `json:"messageBurst"`
+	ByteRate     float64 `json:"byteRate"` // bytes per second
+	ByteBurst    int     `json:"byteBurst"`
+}
+
+// bucket is a token bucket that is refilled at [rate] tokens per second up to
+// [burst] tokens.
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rate float64, burst int, now time.Time) *bucket {
+	if rate <= 0 {
+		return nil
+	}
+	b := math.Max(float64(burst), 1)
+	return &bucket{
+		rate:   rate,
+		burst:  b,
+		tokens: b,
+		last:   now,
+	}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// limiter enforces a [Limit] for a single (peer, handler) pair.
+type limiter struct {
+	messages *bucket // nil if unlimited
+	bytes    *bucket // nil if unlimited
+}
+
+func newLimiter(l Limit, now time.Time) *limiter {
+	return &limiter{
+		messages: newBucket(l.MessageRate, l.MessageBurst, now),
+		bytes:    newBucket(l.ByteRate, l.ByteBurst, now),
+	}
+}
+
+// allow returns true (and consumes the corresponding tokens) if a message of
+// [size] bytes can be accepted at [now]. Nothing is consumed if the message is
+// rejected.
+func (l *limiter) allow(now time.Time, size int) bool {
+	if l.messages != nil {
+		l.messages.refill(now)
+		if l.messages.tokens < 1 {
+			return false
+		}
+	}
+	if l.bytes != nil {
+		l.bytes.refill(now)
+		if l.bytes.tokens < float64(size) {
+			return false
+		}
+	}
+	if l.messages != nil {
+		l.messages.tokens--
+	}
+	if l.bytes != nil {
+		l.bytes.tokens -= float64(size)
+	}
+	return true
+}
@@ -13,9 +13,15 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/version"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
+// ErrCodeRateLimited is sent in response to AppRequests that exceed the limit
+// of their handler. It is negative so it does not collide with the error codes
+// of handlers.
+const ErrCodeRateLimited int32 = -2
+
 type nodeIDRequester struct {
 	requestID     uint32
 	requestMapper map[uint32]*request
@@ -37,9 +43,22 @@ type Manager struct {
 	handlers        map[uint8]Handler
 
 	requesters map[ids.NodeID]*nodeIDRequester
+
+	// peers is thread-safe
+	peers *peers
 }
 
-func NewManager(log logging.Logger, nodeID ids.NodeID, sender common.AppSender) *Manager {
+func NewManager(
+	log logging.Logger,
+	nodeID ids.NodeID,
+	sender common.AppSender,
+	config *Config,
+	r prometheus.Registerer,
+) (*Manager, error) {
+	peers, err := newPeers(config, r)
+	if err != nil {
+		return nil, err
+	}
 	return &Manager{
 		log:             log,
 		nodeID:          nodeID,
@@ -47,7 +66,8 @@ func NewManager(log logging.Logger, nodeID ids.NodeID, sender common.AppSender)
 		handlers:        map[uint8]Handler{},
 		pendingHandlers: map[uint8]struct{}{},
 		requesters:      map[ids.NodeID]*nodeIDRequester{},
-	}
+		peers:           peers,
+	}, nil
 }
 
 type Handler interface {
@@ -105,6 +125,26 @@ func (n *Manager) SetHandler(handler uint8, h Handler) {
 	n.handlers[handler] = h
 }
 
+// SetLimit sets the [Limit] applied to the AppGossip and AppRequest messages
+// each peer sends to [handler]. Handlers are unlimited by default.
+func (n *Manager) SetLimit(handler uint8, l Limit) {
+	n.peers.setLimit(handler, l)
+}
+
+// Penalize adds [amount] to the penalty of [nodeID] (for example, for each
+// invalid transaction it gossiped). Once the penalty of a peer reaches
+// [Config.PenaltyThreshold], all of its AppGossip is dropped until the penalty
+// decays.
+func (n *Manager) Penalize(nodeID ids.NodeID, amount float64) {
+	penalty := n.peers.penalize(nodeID, amount)
+	n.log.Debug(
+		"penalized peer",
+		zap.Stringer("nodeID", nodeID),
+		zap.Float64("amount", amount),
+		zap.Float64("penalty", penalty),
+	)
+}
+
 func (n *Manager) getSharedRequestID(
 	handler uint8,
 	nodeID ids.NodeID,
@@ -126,17 +166,17 @@ func (n *Manager) getSharedRequestID(
 	return newID
 }
 
-func (n *Manager) routeIncomingMessage(msg []byte) ([]byte, Handler, bool) {
+func (n *Manager) routeIncomingMessage(msg []byte) ([]byte, uint8, Handler, bool) {
 	n.l.RLock()
 	defer n.l.RUnlock()
 
 	l := len(msg)
 	if l == 0 {
-		return nil, nil, false
+		return nil, 0, nil, false
 	}
 	handlerID := msg[0]
 	handler, ok := n.handlers[handlerID]
-	return msg[1:], handler, ok
+	return msg[1:], handlerID, handler, ok
 }
 
 func (n *Manager) handleSharedRequestID(
@@ -167,7 +207,7 @@ func (n *Manager) handleSharedRequestID(
 // assume gossip via proposervm has been activated
 // ref. "avalanchego/vms/platformvm/network.AppGossip"
 func (n *Manager) AppGossip(ctx context.Context, nodeID ids.NodeID, msg []byte) error {
-	parsedMsg, handler, ok := n.routeIncomingMessage(msg)
+	parsedMsg, handlerID, handler, ok := n.routeIncomingMessage(msg)
 	if !ok {
 		n.log.Debug(
 			"could not route incoming AppGossip",
@@ -175,6 +215,14 @@ func (n *Manager) AppGossip(ctx context.Context, nodeID ids.NodeID, msg []byte)
 		)
 		return nil
 	}
+	if !n.peers.allow(nodeID, handlerID, len(msg), true) {
+		n.log.Debug(
+			"dropping incoming AppGossip",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint8("handler", handlerID),
+		)
+		return nil
+	}
 	return handler.AppGossip(ctx, nodeID, parsedMsg)
 }
 
@@ -186,7 +234,7 @@ func (n *Manager) AppRequest(
 	deadline time.Time,
 	request []byte,
 ) error {
-	parsedMsg, handler, ok := n.routeIncomingMessage(request)
+	parsedMsg, handlerID, handler, ok := n.routeIncomingMessage(request)
 	if !ok {
 		n.log.Debug(
 			"could not route incoming AppRequest",
@@ -195,6 +243,16 @@ func (n *Manager) AppRequest(
 		)
 		return nil
 	}
+	if !n.peers.allow(nodeID, handlerID, len(request), false) {
+		n.log.Debug(
+			"dropping incoming AppRequest",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint8("handler", handlerID),
+			zap.Uint32("requestID", requestID),
+		)
+		// Respond so the requester does not wait for the request to time out
+		return n.sender.SendAppError(ctx, nodeID, requestID, ErrCodeRateLimited, "rate limited")
+	}
 	return handler.AppRequest(ctx, nodeID, requestID, deadline, parsedMsg)
 }
 
@@ -258,6 +316,8 @@ func (n *Manager) Connected(
 
 // implements "block.ChainVM.commom.VM.validators.Connector"
 func (n *Manager) Disconnected(ctx context.Context, nodeID ids.NodeID) error {
+	n.peers.disconnected(nodeID)
+
 	n.l.RLock()
 	defer n.l.RUnlock()
 	for k, handler := range n.handlers {
@@ -280,7 +340,7 @@ func (n *Manager) CrossChainAppRequest(
 	deadline time.Time,
 	msg []byte,
 ) error {
-	parsedMsg, handler, ok := n.routeIncomingMessage(msg)
+	parsedMsg, _, handler, ok := n.routeIncomingMessage(msg)
 	if !ok {
 		n.log.Debug(
 			"could not route incoming CrossChainAppRequest",
@@ -0,0 +1,171 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/version"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type countingHandler struct {
+	gossip   int
+	requests int
+}
+
+func (*countingHandler) Connected(context.Context, ids.NodeID, *version.Application) error {
+	return nil
+}
+
+func (*countingHandler) Disconnected(context.Context, ids.NodeID) error {
+	return nil
+}
+
+func (h *countingHandler) AppGossip(context.Context, ids.NodeID, []byte) error {
+	h.gossip++
+	return nil
+}
+
+func (h *countingHandler) AppRequest(context.Context, ids.NodeID, uint32, time.Time, []byte) error {
+	h.requests++
+	return nil
+}
+
+func (*countingHandler) AppRequestFailed(context.Context, ids.NodeID, uint32) error {
+	return nil
+}
+
+func (*countingHandler) AppResponse(context.Context, ids.NodeID, uint32, []byte) error {
+	return nil
+}
+
+func (*countingHandler) CrossChainAppRequest(context.Context, ids.ID, uint32, time.Time, []byte) error {
+	return nil
+}
+
+func (*countingHandler) CrossChainAppRequestFailed(context.Context, ids.ID, uint32) error {
+	return nil
+}
+
+func (*countingHandler) CrossChainAppResponse(context.Context, ids.ID, uint32, []byte) error {
+	return nil
+}
+
+func newTestManager(t *testing.T, config *Config, l Limit) (*Manager, uint8, *countingHandler) {
+	require := require.New(t)
+
+	sender := common.FakeSender{SentAppError: make(chan *common.AppError, 16)}
+	n, err := NewManager(logging.NoLog{}, ids.GenerateTestNodeID(), sender, config, prometheus.NewRegistry())
+	require.NoError(err)
+	n.peers.clock.Set(time.Unix(1_000, 0))
+	handlerID, _ := n.Register()
+	h := &countingHandler{}
+	n.SetLimit(handlerID, l)
+	n.SetHandler(handlerID, h)
+	return n, handlerID, h
+}
+
+func TestManagerMessageLimit(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	n, handlerID, h := newTestManager(t, &Config{}, Limit{MessageRate: 1, MessageBurst: 2})
+	nodeID := ids.GenerateTestNodeID()
+	msg := []byte{handlerID, 1, 2, 3}
+
+	// Burst is accepted, then messages are dropped until the bucket refills
+	for i := 0; i < 4; i++ {
+		require.NoError(n.AppGossip(ctx, nodeID, msg))
+	}
+	require.Equal(2, h.gossip)
+
+	// Limits are tracked per peer
+	require.NoError(n.AppGossip(ctx, ids.GenerateTestNodeID(), msg))
+	require.Equal(3, h.gossip)
+
+	// Requests share the same limit and are answered with an error when
+	// dropped
+	require.NoError(n.AppRequest(ctx, nodeID, 0, time.Time{}, msg))
+	require.Zero(h.requests)
+	appErr := <-n.sender.(common.FakeSender).SentAppError
+	require.Equal(ErrCodeRateLimited, appErr.Code)
+
+	n.peers.clock.Set(n.peers.clock.Time().Add(time.Second))
+	require.NoError(n.AppRequest(ctx, nodeID, 0, time.Time{}, msg))
+	require.Equal(1, h.requests)
+	require.NoError(n.AppGossip(ctx, nodeID, msg))
+	require.Equal(3, h.gossip)
+
+	peerLabel := nodeID.String()
+	require.Equal(7.0, testutil.ToFloat64(n.peers.metrics.messagesReceived.WithLabelValues(peerLabel, "0")))
+	require.Equal(28.0, testutil.ToFloat64(n.peers.metrics.bytesReceived.WithLabelValues(peerLabel, "0")))
+	require.Equal(4.0, testutil.ToFloat64(n.peers.metrics.messagesDropped.WithLabelValues(peerLabel, "0", dropRateLimited)))
+}
+
+func TestManagerByteLimit(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	n, handlerID, h := newTestManager(t, &Config{}, Limit{ByteRate: 10, ByteBurst: 10})
+	nodeID := ids.GenerateTestNodeID()
+
+	require.NoError(n.AppGossip(ctx, nodeID, make([]byte, 8)))
+	require.Equal(1, h.gossip)
+
+	// Rejected messages do not consume tokens
+	require.NoError(n.AppGossip(ctx, nodeID, make([]byte, 4)))
+	require.NoError(n.AppGossip(ctx, nodeID, make([]byte, 2)))
+	require.Equal(2, h.gossip)
+
+	n.peers.clock.Set(n.peers.clock.Time().Add(500 * time.Millisecond))
+	msg := make([]byte, 5)
+	msg[0] = handlerID
+	require.NoError(n.AppGossip(ctx, nodeID, msg))
+	require.Equal(3, h.gossip)
+}
+
+func TestManagerPenalty(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	n, handlerID, h := newTestManager(t, &Config{PenaltyThreshold: 10, PenaltyHalfLife: time.Minute}, Limit{})
+	nodeID := ids.GenerateTestNodeID()
+	msg := []byte{handlerID}
+
+	n.Penalize(nodeID, 9)
+	require.NoError(n.AppGossip(ctx, nodeID, msg))
+	require.Equal(1, h.gossip)
+
+	// Gossip is dropped once the threshold is reached but requests are still
+	// served
+	n.Penalize(nodeID, 1)
+	require.NoError(n.AppGossip(ctx, nodeID, msg))
+	require.Equal(1, h.gossip)
+	require.NoError(n.AppRequest(ctx, nodeID, 0, time.Time{}, msg))
+	require.Equal(1, h.requests)
+
+	// Reconnecting does not reset the penalty
+	require.NoError(n.Disconnected(ctx, nodeID))
+	require.NoError(n.AppGossip(ctx, nodeID, msg))
+	require.Equal(1, h.gossip)
+
+	// Penalty halves every half-life
+	n.peers.clock.Set(n.peers.clock.Time().Add(time.Minute))
+	require.NoError(n.AppGossip(ctx, nodeID, msg))
+	require.Equal(2, h.gossip)
+	require.InDelta(5.0, n.peers.penalize(nodeID, 0), 0.001)
+
+	// Decayed penalties are forgotten on disconnect
+	n.peers.clock.Set(n.peers.clock.Time().Add(10 * time.Minute))
+	require.NoError(n.Disconnected(ctx, nodeID))
+	require.NotContains(n.peers.peers, nodeID)
+}
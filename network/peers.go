@@ -0,0 +1,202 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	dropRateLimited = "rate_limited"
+	dropPenalized   = "penalized"
+)
+
+// Config configures the limits [Manager] applies to incoming messages.
+type Config struct {
+	// PenaltyThreshold is the penalty at which AppGossip from a peer is
+	// dropped (regardless of handler). A threshold of 0 disables scoring.
+	PenaltyThreshold float64 `json:"penaltyThreshold"`
+
+	// PenaltyHalfLife is how long it takes for the penalty of a peer to decay
+	// by half.
+	PenaltyHalfLife time.Duration `json:"penaltyHalfLife"`
+}
+
+type peer struct {
+	limiters map[uint8]*limiter
+
+	penalty     float64
+	lastPenalty time.Time
+}
+
+// decay updates the penalty of [p] to [now] and returns it.
+func (p *peer) decay(now time.Time, halfLife time.Duration) float64 {
+	if p.penalty == 0 {
+		return 0
+	}
+	if elapsed := now.Sub(p.lastPenalty); elapsed > 0 && halfLife > 0 {
+		p.penalty *= math.Exp2(-float64(elapsed) / float64(halfLife))
+		p.lastPenalty = now
+	}
+	return p.penalty
+}
+
+type peerMetrics struct {
+	messagesReceived *prometheus.CounterVec
+	bytesReceived    *prometheus.CounterVec
+	messagesDropped  *prometheus.CounterVec
+	penalty          *prometheus.GaugeVec
+}
+
+func newPeerMetrics(r prometheus.Registerer) (*peerMetrics, error) {
+	m := &peerMetrics{
+		messagesReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "network",
+			Name:      "peer_messages_received",
+			Help:      "number of messages received from each peer",
+		}, []string{"peer", "handler"}),
+		bytesReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "network",
+			Name:      "peer_bytes_received",
+			Help:      "number of bytes received from each peer",
+		}, []string{"peer", "handler"}),
+		messagesDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "network",
+			Name:      "peer_messages_dropped",
+			Help:      "number of messages from each peer dropped by limits",
+		}, []string{"peer", "handler", "reason"}),
+		penalty: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "network",
+			Name:      "peer_penalty",
+			Help:      "penalty of each peer (as of its last update)",
+		}, []string{"peer"}),
+	}
+	for _, c := range []prometheus.Collector{
+		m.messagesReceived,
+		m.bytesReceived,
+		m.messagesDropped,
+		m.penalty,
+	} {
+		if err := r.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// peers tracks the rate limits and penalty of each peer.
+type peers struct {
+	config  *Config
+	metrics *peerMetrics
+	clock   mockable.Clock
+
+	l      sync.Mutex
+	limits map[uint8]Limit
+	peers  map[ids.NodeID]*peer
+}
+
+func newPeers(config *Config, r prometheus.Registerer) (*peers, error) {
+	m, err := newPeerMetrics(r)
+	if err != nil {
+		return nil, err
+	}
+	return &peers{
+		config:  config,
+		metrics: m,
+		limits:  map[uint8]Limit{},
+		peers:   map[ids.NodeID]*peer{},
+	}, nil
+}
+
+func (p *peers) setLimit(handler uint8, l Limit) {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	p.limits[handler] = l
+	for _, pr := range p.peers {
+		delete(pr.limiters, handler)
+	}
+}
+
+func (p *peers) get(nodeID ids.NodeID) *peer {
+	pr, ok := p.peers[nodeID]
+	if !ok {
+		pr = &peer{limiters: map[uint8]*limiter{}}
+		p.peers[nodeID] = pr
+	}
+	return pr
+}
+
+// allow records a message of [size] bytes from [nodeID] to [handler] and
+// returns false if it should be dropped.
+func (p *peers) allow(nodeID ids.NodeID, handler uint8, size int, gossip bool) bool {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	var (
+		now       = p.clock.Time()
+		peerLabel = nodeID.String()
+		hLabel    = strconv.Itoa(int(handler))
+		pr        = p.get(nodeID)
+	)
+	p.metrics.messagesReceived.WithLabelValues(peerLabel, hLabel).Inc()
+	p.metrics.bytesReceived.WithLabelValues(peerLabel, hLabel).Add(float64(size))
+	if gossip && p.config.PenaltyThreshold > 0 && pr.decay(now, p.config.PenaltyHalfLife) >= p.config.PenaltyThreshold {
+		p.metrics.messagesDropped.WithLabelValues(peerLabel, hLabel, dropPenalized).Inc()
+		return false
+	}
+	l, ok := pr.limiters[handler]
+	if !ok {
+		l = newLimiter(p.limits[handler], now)
+		pr.limiters[handler] = l
+	}
+	if !l.allow(now, size) {
+		p.metrics.messagesDropped.WithLabelValues(peerLabel, hLabel, dropRateLimited).Inc()
+		return false
+	}
+	return true
+}
+
+// penalize adds [amount] to the penalty of [nodeID] and returns the new
+// penalty.
+func (p *peers) penalize(nodeID ids.NodeID, amount float64) float64 {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	now := p.clock.Time()
+	pr := p.get(nodeID)
+	pr.penalty = pr.decay(now, p.config.PenaltyHalfLife) + amount
+	pr.lastPenalty = now
+	p.metrics.penalty.WithLabelValues(nodeID.String()).Set(pr.penalty)
+	return pr.penalty
+}
+
+// disconnected clears the limits of [nodeID]. The penalty is retained until it
+// decays so that a peer cannot reset it by reconnecting.
+func (p *peers) disconnected(nodeID ids.NodeID) {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	pr, ok := p.peers[nodeID]
+	if !ok {
+		return
+	}
+	peerLabel := prometheus.Labels{"peer": nodeID.String()}
+	p.metrics.messagesReceived.DeletePartialMatch(peerLabel)
+	p.metrics.bytesReceived.DeletePartialMatch(peerLabel)
+	p.metrics.messagesDropped.DeletePartialMatch(peerLabel)
+	if pr.decay(p.clock.Time(), p.config.PenaltyHalfLife) >= 1 {
+		pr.limiters = map[uint8]*limiter{}
+		return
+	}
+	p.metrics.penalty.DeletePartialMatch(peerLabel)
+	delete(p.peers, nodeID)
+}
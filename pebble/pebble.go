@@ -44,6 +44,10 @@ type Config struct {
 	MemTableSize                int // B
 	MaxOpenFiles                int
 	ConcurrentCompactions       func() int
+
+	// ReadOnly opens the database without allowing any writes (the database
+	// must already exist).
+	ReadOnly bool
 }
 
 func NewDefaultConfig() Config {
@@ -69,6 +73,7 @@ func New(file string, cfg Config) (database.Database, *prometheus.Registry, erro
 		MaxOpenFiles:                cfg.MaxOpenFiles,
 		MaxConcurrentCompactions:    cfg.ConcurrentCompactions, // TODO: may want to tweak this?
 		Levels:                      make([]pebble.LevelOptions, 7),
+		ReadOnly:                    cfg.ReadOnly,
 		// TODO: add support for adding a custom logger
 
 		EventListener: &pebble.EventListener{
@@ -11,4 +11,8 @@ const (
 	WebSocketEndpoint = "/corews"
 
 	DefaultHandshakeTimeout = 10 * time.Second
+
+	// snapshotPageSize is the max number of key-values returned by
+	// [JSONRPCServer.SnapshotKeyValues].
+	snapshotPageSize = 2_048
 )
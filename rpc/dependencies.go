@@ -10,9 +10,12 @@ import (
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/x/merkledb"
 
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/fees"
+	"github.com/ava-labs/hypersdk/snapshot"
 )
 
 type VM interface {
@@ -26,11 +29,19 @@ type VM interface {
 		ctx context.Context,
 		verifySig bool,
 		txs []*chain.Transaction,
-	) (errs []error)
+	) ([]error, error)
 	LastAcceptedBlock() *chain.StatelessBlock
 	UnitPrices(context.Context) (fees.Dimensions, error)
 	CurrentValidators(
 		context.Context,
 	) (map[ids.NodeID]*validators.GetValidatorOutput, map[string]struct{})
 	GetVerifyAuth() bool
+	SnapshotHeader(ctx context.Context, height *uint64) (*snapshot.Header, ids.ID, error)
+	SnapshotKeyValues(
+		ctx context.Context,
+		root ids.ID,
+		start []byte,
+		limit int,
+	) ([]merkledb.KeyValue, maybe.Maybe[[]byte], error)
+	TraceTx(ctx context.Context, txID ids.ID) (*chain.StatelessBlock, *chain.TxTrace, error)
 }
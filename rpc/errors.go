@@ -9,4 +9,6 @@ var (
 	ErrClosed         = errors.New("closed")
 	ErrExpired        = errors.New("expired")
 	ErrMessageMissing = errors.New("message missing")
+
+	ErrInvalidSnapshotPage = errors.New("invalid snapshot page")
 )
@@ -6,6 +6,7 @@ package rpc
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/fees"
 	"github.com/ava-labs/hypersdk/requester"
+	"github.com/ava-labs/hypersdk/snapshot"
 	"github.com/ava-labs/hypersdk/utils"
 )
 
@@ -115,6 +117,72 @@ func (cli *JSONRPCClient) SubmitTx(ctx context.Context, d []byte) (ids.ID, error
 	return resp.TxID, err
 }
 
+// TraceTx returns the trace of an accepted transaction (and the height and ID
+// of its block). The node must have [TxTracing] enabled.
+func (cli *JSONRPCClient) TraceTx(ctx context.Context, txID ids.ID) (uint64, ids.ID, *chain.TxTrace, error) {
+	resp := new(TraceTxReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"traceTx",
+		&TraceTxArgs{TxID: txID},
+		resp,
+	)
+	return resp.Height, resp.BlockID, resp.Trace, err
+}
+
+// Snapshot writes a snapshot of the state at [height] (or at the last
+// accepted block if [height] is nil) to [w]. It returns the height and root
+// of the snapshot.
+//
+// The node must enable [vm.Config.SnapshotServing] and retain the state at
+// [height] for the entire export (see [vm.VM.SnapshotHeader]), otherwise the
+// export will fail and must be retried at a newer height. Large states should
+// be exported from a stopped node with [vm.VM.ExportSnapshotOffline].
+func (cli *JSONRPCClient) Snapshot(ctx context.Context, height *uint64, w io.Writer) (uint64, ids.ID, int, error) {
+	header := new(SnapshotHeaderReply)
+	if err := cli.requester.SendRequest(
+		ctx,
+		"snapshotHeader",
+		&SnapshotHeaderArgs{Height: height},
+		header,
+	); err != nil {
+		return 0, ids.Empty, 0, err
+	}
+	sw, err := snapshot.NewWriter(w, &snapshot.Header{Height: header.Height, Blocks: header.Blocks})
+	if err != nil {
+		return 0, ids.Empty, 0, err
+	}
+	var (
+		start []byte
+		count int
+	)
+	for {
+		resp := new(SnapshotKeyValuesReply)
+		if err := cli.requester.SendRequest(
+			ctx,
+			"snapshotKeyValues",
+			&SnapshotKeyValuesArgs{Root: header.Root, Start: start},
+			resp,
+		); err != nil {
+			return 0, ids.Empty, 0, err
+		}
+		if len(resp.Keys) != len(resp.Values) {
+			return 0, ids.Empty, 0, ErrInvalidSnapshotPage
+		}
+		for i, k := range resp.Keys {
+			if err := sw.Put(k, resp.Values[i]); err != nil {
+				return 0, ids.Empty, 0, err
+			}
+		}
+		count += len(resp.Keys)
+		if resp.Done {
+			break
+		}
+		start = resp.Next
+	}
+	return header.Height, header.Root, count, sw.Close()
+}
+
 type Modifier interface {
 	Base(*chain.Base)
 }
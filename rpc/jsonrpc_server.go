@@ -82,7 +82,11 @@ func (j *JSONRPCServer) SubmitTx(
 	}
 	txID := tx.ID()
 	reply.TxID = txID
-	return j.vm.Submit(ctx, false, []*chain.Transaction{tx})[0]
+	errs, err := j.vm.Submit(ctx, false, []*chain.Transaction{tx})
+	if err != nil {
+		return err
+	}
+	return errs[0]
 }
 
 type LastAcceptedReply struct {
@@ -118,3 +122,94 @@ func (j *JSONRPCServer) UnitPrices(
 	reply.UnitPrices = unitPrices
 	return nil
 }
+
+type SnapshotHeaderArgs struct {
+	Height *uint64 `json:"height"`
+}
+
+type SnapshotHeaderReply struct {
+	Height uint64   `json:"height"`
+	Root   ids.ID   `json:"root"`
+	Blocks [][]byte `json:"blocks"`
+}
+
+func (j *JSONRPCServer) SnapshotHeader(
+	req *http.Request,
+	args *SnapshotHeaderArgs,
+	reply *SnapshotHeaderReply,
+) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "JSONRPCServer.SnapshotHeader")
+	defer span.End()
+
+	header, root, err := j.vm.SnapshotHeader(ctx, args.Height)
+	if err != nil {
+		return err
+	}
+	reply.Height = header.Height
+	reply.Root = root
+	reply.Blocks = header.Blocks
+	return nil
+}
+
+type SnapshotKeyValuesArgs struct {
+	Root  ids.ID `json:"root"`
+	Start []byte `json:"start"`
+}
+
+type SnapshotKeyValuesReply struct {
+	Keys   [][]byte `json:"keys"`
+	Values [][]byte `json:"values"`
+	Next   []byte   `json:"next"`
+	Done   bool     `json:"done"`
+}
+
+func (j *JSONRPCServer) SnapshotKeyValues(
+	req *http.Request,
+	args *SnapshotKeyValuesArgs,
+	reply *SnapshotKeyValuesReply,
+) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "JSONRPCServer.SnapshotKeyValues")
+	defer span.End()
+
+	kvs, next, err := j.vm.SnapshotKeyValues(ctx, args.Root, args.Start, snapshotPageSize)
+	if err != nil {
+		return err
+	}
+	reply.Keys = make([][]byte, len(kvs))
+	reply.Values = make([][]byte, len(kvs))
+	for i, kv := range kvs {
+		reply.Keys[i] = kv.Key
+		reply.Values[i] = kv.Value
+	}
+	reply.Next = next.Value()
+	reply.Done = next.IsNothing()
+	return nil
+}
+
+type TraceTxArgs struct {
+	TxID ids.ID `json:"txId"`
+}
+
+type TraceTxReply struct {
+	Height  uint64         `json:"height"`
+	BlockID ids.ID         `json:"blockId"`
+	Trace   *chain.TxTrace `json:"trace"`
+}
+
+func (j *JSONRPCServer) TraceTx(
+	req *http.Request,
+	args *TraceTxArgs,
+	reply *TraceTxReply,
+) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "JSONRPCServer.TraceTx")
+	defer span.End()
+
+	blk, trace, err := j.vm.TraceTx(ctx, args.TxID)
+	if err != nil {
+		return err
+	}
+	reply.Height = blk.Hght
+	reply.BlockID = blk.ID()
+	reply.Trace = trace
+	return nil
+}
@@ -0,0 +1,11 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import "errors"
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported snapshot version")
+	ErrInvalidSnapshot    = errors.New("invalid snapshot")
+)
@@ -0,0 +1,189 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package snapshot defines the file format used to bootstrap a node from an
+// exported copy of the state at an accepted height (instead of state syncing
+// from peers).
+//
+// A snapshot is a header (which includes the blocks required to resume
+// from the snapshot) followed by all key-values in state.
+package snapshot
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+)
+
+const (
+	Version = 0
+
+	// MaxHeaderSize is the largest header that will be read.
+	MaxHeaderSize = units.GiB
+	// MaxRecordSize is the largest key or value that will be read.
+	MaxRecordSize = 64 * units.MiB
+
+	recordKeyValue = 0x1
+	recordEnd      = 0x0
+)
+
+// Header describes the contents of a snapshot.
+type Header struct {
+	// Height is the height of the last block in [Blocks]. The state in the
+	// snapshot is the state committed by [StateRoot] in this block (the
+	// post-execution state of its parent).
+	Height uint64
+
+	// Blocks contains the genesis block followed by all blocks (in ascending
+	// order) that are needed to populate replay protection at [Height].
+	Blocks [][]byte
+}
+
+func (h *Header) Marshal() ([]byte, error) {
+	p := codec.NewWriter(0, MaxHeaderSize)
+	p.PackByte(Version)
+	p.PackUint64(h.Height)
+	p.PackInt(len(h.Blocks))
+	for _, blk := range h.Blocks {
+		p.PackBytes(blk)
+	}
+	return p.Bytes(), p.Err()
+}
+
+func UnmarshalHeader(b []byte) (*Header, error) {
+	p := codec.NewReader(b, MaxHeaderSize)
+	if v := p.UnpackByte(); v != Version {
+		return nil, ErrUnsupportedVersion
+	}
+	h := &Header{Height: p.UnpackUint64(false)}
+	h.Blocks = make([][]byte, p.UnpackInt(true))
+	for i := range h.Blocks {
+		p.UnpackBytes(-1, true, &h.Blocks[i])
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	if !p.Empty() {
+		return nil, ErrInvalidSnapshot
+	}
+	return h, nil
+}
+
+// Writer writes a snapshot to an [io.Writer].
+type Writer struct {
+	w *bufio.Writer
+}
+
+// NewWriter writes [h] to [w] and returns a [Writer] that can be used to
+// write all key-values in state. [Close] must be called once all key-values
+// are written.
+func NewWriter(w io.Writer, h *Header) (*Writer, error) {
+	hb, err := h.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	sw := &Writer{bufio.NewWriter(w)}
+	if err := sw.writeBytes(hb); err != nil {
+		return nil, err
+	}
+	return sw, nil
+}
+
+func (w *Writer) writeBytes(b []byte) error {
+	if _, err := w.w.Write(binary.BigEndian.AppendUint32(nil, uint32(len(b)))); err != nil {
+		return err
+	}
+	_, err := w.w.Write(b)
+	return err
+}
+
+// Put writes a single key-value. Key-values should be written in ascending
+// order.
+func (w *Writer) Put(k []byte, v []byte) error {
+	if err := w.w.WriteByte(recordKeyValue); err != nil {
+		return err
+	}
+	if err := w.writeBytes(k); err != nil {
+		return err
+	}
+	return w.writeBytes(v)
+}
+
+// Close marks the end of the snapshot and flushes any buffered data.
+func (w *Writer) Close() error {
+	if err := w.w.WriteByte(recordEnd); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+// Reader reads a snapshot from an [io.Reader].
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader reads the [Header] from [r] and returns a [Reader] that can be
+// used to read all key-values in state.
+func NewReader(r io.Reader) (*Reader, *Header, error) {
+	sr := &Reader{bufio.NewReader(r)}
+	hb, err := sr.readBytes(MaxHeaderSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	h, err := UnmarshalHeader(hb)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sr, h, nil
+}
+
+func (r *Reader) readBytes(limit int) ([]byte, error) {
+	var l [consts.Uint32Len]byte
+	if _, err := io.ReadFull(r.r, l[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(l[:])
+	if uint64(size) > uint64(limit) {
+		return nil, ErrInvalidSnapshot
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Next returns the next key-value in the snapshot. It returns [io.EOF] once
+// all key-values have been read.
+func (r *Reader) Next() ([]byte, []byte, error) {
+	t, err := r.r.ReadByte()
+	if errors.Is(err, io.EOF) {
+		// The snapshot must end with [recordEnd]
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	switch t {
+	case recordEnd:
+		return nil, nil, io.EOF
+	case recordKeyValue:
+	default:
+		return nil, nil, ErrInvalidSnapshot
+	}
+	k, err := r.readBytes(MaxRecordSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	v, err := r.readBytes(MaxRecordSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return k, v, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	header := &Header{
+		Height: 10,
+		Blocks: [][]byte{{0x0}, {0x1, 0x2}},
+	}
+	var b bytes.Buffer
+	w, err := NewWriter(&b, header)
+	require.NoError(err)
+	require.NoError(w.Put([]byte{0x1}, []byte{0x2}))
+	require.NoError(w.Put([]byte{0x2}, []byte{}))
+	require.NoError(w.Close())
+
+	r, h, err := NewReader(&b)
+	require.NoError(err)
+	require.Equal(header, h)
+	k, v, err := r.Next()
+	require.NoError(err)
+	require.Equal([]byte{0x1}, k)
+	require.Equal([]byte{0x2}, v)
+	k, v, err = r.Next()
+	require.NoError(err)
+	require.Equal([]byte{0x2}, k)
+	require.Empty(v)
+	_, _, err = r.Next()
+	require.ErrorIs(err, io.EOF)
+}
+
+func TestSnapshotTruncated(t *testing.T) {
+	require := require.New(t)
+
+	var b bytes.Buffer
+	w, err := NewWriter(&b, &Header{Height: 1, Blocks: [][]byte{{0x0}}})
+	require.NoError(err)
+	require.NoError(w.Put([]byte{0x1}, []byte{0x2}))
+	require.NoError(w.Close())
+
+	// A snapshot missing its end marker must not be treated as complete
+	r, _, err := NewReader(bytes.NewReader(b.Bytes()[:b.Len()-1]))
+	require.NoError(err)
+	_, _, err = r.Next()
+	require.NoError(err)
+	_, _, err = r.Next()
+	require.ErrorIs(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+var _ Mutable = (*Journal)(nil)
+
+// Journal is a [Mutable] that records the previous value of every key modified
+// through it so that the modifications can be reverted to any earlier
+// [Journal.OpIndex] (which allows nested checkpoints).
+//
+// Because the previous value of a key is read before it is modified, keys
+// modified through a [Journal] must also be readable.
+type Journal struct {
+	inner Mutable
+	ops   []journalOp
+}
+
+type journalOp struct {
+	key       []byte
+	prev      []byte
+	prevExist bool
+}
+
+func NewJournal(inner Mutable) *Journal {
+	return &Journal{inner: inner}
+}
+
+func (j *Journal) GetValue(ctx context.Context, key []byte) ([]byte, error) {
+	return j.inner.GetValue(ctx, key)
+}
+
+func (j *Journal) Insert(ctx context.Context, key []byte, value []byte) error {
+	if err := j.record(ctx, key); err != nil {
+		return err
+	}
+	if err := j.inner.Insert(ctx, key, value); err != nil {
+		j.ops = j.ops[:len(j.ops)-1]
+		return err
+	}
+	return nil
+}
+
+func (j *Journal) Remove(ctx context.Context, key []byte) error {
+	if err := j.record(ctx, key); err != nil {
+		return err
+	}
+	if err := j.inner.Remove(ctx, key); err != nil {
+		j.ops = j.ops[:len(j.ops)-1]
+		return err
+	}
+	return nil
+}
+
+func (j *Journal) record(ctx context.Context, key []byte) error {
+	prev, err := j.inner.GetValue(ctx, key)
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		j.ops = append(j.ops, journalOp{key: key})
+	case err != nil:
+		return err
+	default:
+		j.ops = append(j.ops, journalOp{key: key, prev: prev, prevExist: true})
+	}
+	return nil
+}
+
+// OpIndex returns the number of modifications made so far, which can be passed
+// to [Journal.Rollback] to revert any modifications made after this call.
+func (j *Journal) OpIndex() int {
+	return len(j.ops)
+}
+
+// Rollback reverts all modifications made after [restorePoint] (in reverse
+// order).
+func (j *Journal) Rollback(ctx context.Context, restorePoint int) error {
+	for i := len(j.ops) - 1; i >= restorePoint; i-- {
+		op := j.ops[i]
+		if op.prevExist {
+			if err := j.inner.Insert(ctx, op.key, op.prev); err != nil {
+				return err
+			}
+		} else if err := j.inner.Remove(ctx, op.key); err != nil {
+			return err
+		}
+		j.ops = j.ops[:i]
+	}
+	return nil
+}
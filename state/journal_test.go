@@ -0,0 +1,62 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/stretchr/testify/require"
+)
+
+type testDB struct {
+	database.Database
+}
+
+func (db testDB) GetValue(_ context.Context, key []byte) ([]byte, error) {
+	return db.Get(key)
+}
+
+func (db testDB) Insert(_ context.Context, key []byte, value []byte) error {
+	return db.Put(key, value)
+}
+
+func (db testDB) Remove(_ context.Context, key []byte) error {
+	return db.Delete(key)
+}
+
+func TestJournal(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	db := testDB{memdb.New()}
+	require.NoError(db.Insert(ctx, []byte("a"), []byte{1}))
+	j := NewJournal(db)
+
+	outer := j.OpIndex()
+	require.NoError(j.Insert(ctx, []byte("a"), []byte{2}))
+	require.NoError(j.Insert(ctx, []byte("b"), []byte{1}))
+
+	// Nested checkpoints can be reverted independently
+	inner := j.OpIndex()
+	require.NoError(j.Remove(ctx, []byte("a")))
+	require.NoError(j.Insert(ctx, []byte("b"), []byte{2}))
+	require.NoError(j.Rollback(ctx, inner))
+	v, err := j.GetValue(ctx, []byte("a"))
+	require.NoError(err)
+	require.Equal([]byte{2}, v)
+	v, err = j.GetValue(ctx, []byte("b"))
+	require.NoError(err)
+	require.Equal([]byte{1}, v)
+	require.Equal(inner, j.OpIndex())
+
+	require.NoError(j.Rollback(ctx, outer))
+	v, err = db.GetValue(ctx, []byte("a"))
+	require.NoError(err)
+	require.Equal([]byte{1}, v)
+	_, err = db.GetValue(ctx, []byte("b"))
+	require.ErrorIs(err, database.ErrNotFound)
+}
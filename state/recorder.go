@@ -0,0 +1,43 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import "context"
+
+var _ Mutable = (*Recorder)(nil)
+
+// Recorder is a [Mutable] that records the [Keys] (and [Permissions]) needed
+// to perform the operations applied to it. It can be used to simulate
+// execution that can't determine the keys it accesses in advance.
+type Recorder struct {
+	inner Mutable
+	keys  Keys
+}
+
+func NewRecorder(inner Mutable) *Recorder {
+	return &Recorder{inner: inner, keys: make(Keys)}
+}
+
+func (r *Recorder) GetValue(ctx context.Context, key []byte) ([]byte, error) {
+	r.keys.Add(string(key), Read)
+	return r.inner.GetValue(ctx, key)
+}
+
+// Insert records [All] permissions for [key] because whether it must be
+// allocated depends on the state it is eventually inserted into.
+func (r *Recorder) Insert(ctx context.Context, key []byte, value []byte) error {
+	r.keys.Add(string(key), All)
+	return r.inner.Insert(ctx, key, value)
+}
+
+func (r *Recorder) Remove(ctx context.Context, key []byte) error {
+	r.keys.Add(string(key), Write)
+	return r.inner.Remove(ctx, key)
+}
+
+// Keys returns the keys accessed so far. Keys that are not well-formatted
+// (see [Keys.Add]) are never recorded.
+func (r *Recorder) Keys() Keys {
+	return r.keys
+}
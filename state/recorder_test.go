@@ -0,0 +1,31 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	r := NewRecorder(NewSimpleMutable(nil))
+	require.NoError(r.Insert(ctx, []byte("written"), []byte{1}))
+	v, err := r.GetValue(ctx, []byte("written"))
+	require.NoError(err)
+	require.Equal([]byte{1}, v)
+	require.NoError(r.Remove(ctx, []byte("removed")))
+
+	// Malformed keys are not recorded
+	require.NoError(r.Insert(ctx, []byte{0}, []byte{1}))
+
+	require.Equal(Keys{
+		"written": All,
+		"removed": Write,
+	}, r.Keys())
+}
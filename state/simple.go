@@ -5,6 +5,8 @@ package state
 
 import (
 	"context"
+	"errors"
+	"maps"
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/utils/maybe"
@@ -17,10 +19,17 @@ type SimpleMutable struct {
 	v View
 
 	changes map[string]maybe.Maybe[[]byte]
+
+	// original holds the values in [v] of the keys that were first modified
+	// after a snapshot was taken (it is nil until then).
+	original map[string]maybe.Maybe[[]byte]
 }
 
+// Snapshot is the state of a [SimpleMutable] at some point in time.
+type Snapshot map[string]maybe.Maybe[[]byte]
+
 func NewSimpleMutable(v View) *SimpleMutable {
-	return &SimpleMutable{v, make(map[string]maybe.Maybe[[]byte])}
+	return &SimpleMutable{v, make(map[string]maybe.Maybe[[]byte]), nil}
 }
 
 func (s *SimpleMutable) GetValue(ctx context.Context, k []byte) ([]byte, error) {
@@ -33,16 +42,67 @@ func (s *SimpleMutable) GetValue(ctx context.Context, k []byte) ([]byte, error)
 	return s.v.GetValue(ctx, k)
 }
 
-func (s *SimpleMutable) Insert(_ context.Context, k []byte, v []byte) error {
+func (s *SimpleMutable) Insert(ctx context.Context, k []byte, v []byte) error {
+	if err := s.recordOriginal(ctx, k); err != nil {
+		return err
+	}
 	s.changes[string(k)] = maybe.Some(v)
 	return nil
 }
 
-func (s *SimpleMutable) Remove(_ context.Context, k []byte) error {
+func (s *SimpleMutable) Remove(ctx context.Context, k []byte) error {
+	if err := s.recordOriginal(ctx, k); err != nil {
+		return err
+	}
 	s.changes[string(k)] = maybe.Nothing[[]byte]()
 	return nil
 }
 
+// recordOriginal stores the value of [k] in [v] the first time [k] is
+// modified, if a snapshot was taken.
+func (s *SimpleMutable) recordOriginal(ctx context.Context, k []byte) error {
+	if s.original == nil {
+		return nil
+	}
+	if _, ok := s.changes[string(k)]; ok {
+		return nil
+	}
+	v, err := s.v.GetValue(ctx, k)
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		s.original[string(k)] = maybe.Nothing[[]byte]()
+	case err != nil:
+		return err
+	default:
+		s.original[string(k)] = maybe.Some(v)
+	}
+	return nil
+}
+
+// Snapshot returns the current state of [s], which can be restored later with
+// [Restore].
+func (s *SimpleMutable) Snapshot() Snapshot {
+	if s.original == nil {
+		s.original = make(map[string]maybe.Maybe[[]byte])
+	}
+	return maps.Clone(s.changes)
+}
+
+// Restore reverts all the changes made to [s] since [snapshot] was taken.
+//
+// Because [Commit] doesn't discard the changes of [s], snapshots can be
+// restored after they are committed (the reverted changes are written to the
+// underlying view on the next [Commit]).
+func (s *SimpleMutable) Restore(snapshot Snapshot) {
+	for k := range s.changes {
+		if v, ok := snapshot[k]; ok {
+			s.changes[k] = v
+			continue
+		}
+		s.changes[k] = s.original[k]
+	}
+}
+
 func (s *SimpleMutable) Commit(ctx context.Context) error {
 	view, err := s.v.NewView(ctx, merkledb.ViewChanges{MapOps: s.changes})
 	if err != nil {
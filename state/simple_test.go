@@ -0,0 +1,64 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimpleMutableSnapshot(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	db, err := merkledb.New(ctx, memdb.New(), merkledb.Config{
+		BranchFactor:                merkledb.BranchFactor16,
+		RootGenConcurrency:          1,
+		HistoryLength:               100,
+		ValueNodeCacheSize:          units.MiB,
+		IntermediateNodeCacheSize:   units.MiB,
+		IntermediateWriteBufferSize: units.KiB,
+		IntermediateWriteBatchSize:  units.KiB,
+		Tracer:                      trace.Noop,
+	})
+	require.NoError(err)
+	require.NoError(db.Put([]byte("a"), []byte{1}))
+	require.NoError(db.Put([]byte("b"), []byte{1}))
+
+	s := NewSimpleMutable(db)
+	require.NoError(s.Insert(ctx, []byte("a"), []byte{2}))
+	snapshot := s.Snapshot()
+
+	require.NoError(s.Insert(ctx, []byte("a"), []byte{3}))
+	require.NoError(s.Remove(ctx, []byte("b")))
+	require.NoError(s.Insert(ctx, []byte("c"), []byte{1}))
+	require.NoError(s.Commit(ctx))
+
+	// Committed changes are reverted too
+	s.Restore(snapshot)
+	require.NoError(s.Commit(ctx))
+	for _, state := range []Immutable{s, db} {
+		v, err := state.GetValue(ctx, []byte("a"))
+		require.NoError(err)
+		require.Equal([]byte{2}, v)
+		v, err = state.GetValue(ctx, []byte("b"))
+		require.NoError(err)
+		require.Equal([]byte{1}, v)
+		_, err = state.GetValue(ctx, []byte("c"))
+		require.ErrorIs(err, database.ErrNotFound)
+	}
+
+	// Snapshots can be restored more than once
+	require.NoError(s.Insert(ctx, []byte("c"), []byte{2}))
+	s.Restore(snapshot)
+	_, err = s.GetValue(ctx, []byte("c"))
+	require.ErrorIs(err, database.ErrNotFound)
+}
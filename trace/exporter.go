@@ -0,0 +1,95 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	tracerExporterCreationTimeout = 5 * time.Second
+
+	defaultZipkinEndpoint = "http://localhost:9411/api/v2/spans"
+)
+
+var ErrUnknownExporterType = errors.New("unknown exporter type")
+
+type ExporterType string
+
+const (
+	// Zipkin exports spans to [ExporterConfig.Endpoint] (which defaults to a
+	// local Zipkin instance).
+	Zipkin ExporterType = "zipkin"
+	// GRPC and HTTP export spans using OTLP (which is supported by most
+	// collectors, including Jaeger).
+	GRPC ExporterType = "grpc"
+	HTTP ExporterType = "http"
+	// File writes each span as JSON to the file at [ExporterConfig.Endpoint] (or
+	// to stdout if no endpoint is provided).
+	File ExporterType = "file"
+)
+
+type ExporterConfig struct {
+	// Type defaults to [Zipkin] if not provided.
+	Type ExporterType `json:"type"`
+
+	// Endpoint to send spans to (or file to write them to)
+	Endpoint string `json:"endpoint"`
+
+	// Headers to send with spans (only used by OTLP)
+	Headers map[string]string `json:"headers"`
+
+	// If true, don't use TLS (only used by OTLP)
+	Insecure bool `json:"insecure"`
+}
+
+func newExporter(config *ExporterConfig) (sdktrace.SpanExporter, error) {
+	var client otlptrace.Client
+	switch config.Type {
+	case Zipkin, "":
+		endpoint := config.Endpoint
+		if len(endpoint) == 0 {
+			endpoint = defaultZipkinEndpoint
+		}
+		return zipkin.New(endpoint)
+	case File:
+		return newFileExporter(config.Endpoint)
+	case GRPC:
+		opts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(config.Endpoint),
+			otlptracegrpc.WithHeaders(config.Headers),
+			otlptracegrpc.WithTimeout(tracerExportTimeout),
+		}
+		if config.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		client = otlptracegrpc.NewClient(opts...)
+	case HTTP:
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(config.Endpoint),
+			otlptracehttp.WithHeaders(config.Headers),
+			otlptracehttp.WithTimeout(tracerExportTimeout),
+		}
+		if config.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		client = otlptracehttp.NewClient(opts...)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownExporterType, config.Type)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tracerExporterCreationTimeout)
+	defer cancel()
+	return otlptrace.New(ctx, client)
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+var _ sdktrace.SpanExporter = (*fileExporter)(nil)
+
+// fileSpan is the JSON representation of a span written by [fileExporter].
+type fileSpan struct {
+	Name       string            `json:"name"`
+	TraceID    string            `json:"traceId"`
+	SpanID     string            `json:"spanId"`
+	ParentID   string            `json:"parentId,omitempty"`
+	Start      time.Time         `json:"start"`
+	Duration   time.Duration     `json:"duration"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Status     string            `json:"status,omitempty"`
+}
+
+// fileExporter writes spans to a file (one JSON object per line) for local
+// debugging.
+type fileExporter struct {
+	l   sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+}
+
+func newFileExporter(path string) (*fileExporter, error) {
+	var w io.Writer = os.Stdout
+	if len(path) > 0 {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		w = f
+	}
+	return &fileExporter{w: w, enc: json.NewEncoder(w)}, nil
+}
+
+func (e *fileExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	for _, s := range spans {
+		fs := &fileSpan{
+			Name:     s.Name(),
+			TraceID:  s.SpanContext().TraceID().String(),
+			SpanID:   s.SpanContext().SpanID().String(),
+			Start:    s.StartTime(),
+			Duration: s.EndTime().Sub(s.StartTime()),
+			Status:   s.Status().Description,
+		}
+		if s.Parent().IsValid() {
+			fs.ParentID = s.Parent().SpanID().String()
+		}
+		if attrs := s.Attributes(); len(attrs) > 0 {
+			fs.Attributes = make(map[string]string, len(attrs))
+			for _, attr := range attrs {
+				fs.Attributes[string(attr.Key)] = attr.Value.Emit()
+			}
+		}
+		if err := e.enc.Encode(fs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *fileExporter) Shutdown(context.Context) error {
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	if f, ok := e.w.(*os.File); ok && f != os.Stdout {
+		return f.Close()
+	}
+	return nil
+}
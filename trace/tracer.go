@@ -9,7 +9,6 @@ import (
 
 	"github.com/ava-labs/avalanchego/trace"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/zipkin"
 	"go.opentelemetry.io/otel/sdk/resource"
 
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -31,11 +30,20 @@ type Config struct {
 	// The fraction of traces to sample.
 	// If >= 1 always samples.
 	// If <= 0 never samples.
+	//
+	// Spans with a parent are always sampled if their parent is sampled (so
+	// traces are never partially recorded).
 	TraceSampleRate float64 `json:"traceSampleRate"`
 
 	AppName string `json:"appName"`
 	Agent   string `json:"agent"`
 	Version string `json:"version"`
+
+	// Exporter defaults to a local Zipkin instance.
+	Exporter ExporterConfig `json:"exporter"`
+
+	// Attributes are added to the resource of all spans.
+	Attributes map[string]string `json:"attributes"`
 }
 
 type tracer struct {
@@ -50,29 +58,35 @@ func (t *tracer) Close() error {
 	return t.tp.Shutdown(ctx)
 }
 
-func New(config *Config) (trace.Tracer, error) {
+// New returns a tracer that exports spans according to [config]. [attrs] are
+// added to the resource of all spans (in addition to [Config.Attributes]).
+func New(config *Config, attrs ...attribute.KeyValue) (trace.Tracer, error) {
 	if !config.Enabled {
 		return &noOpTracer{}, nil
 	}
 
-	// TODO: remove zipkin exporter
-	exporter, err := zipkin.New(
-		"http://localhost:9411/api/v2/spans",
-	)
+	exporter, err := newExporter(&config.Exporter)
 	if err != nil {
 		return nil, err
 	}
 
+	resourceAttrs := []attribute.KeyValue{
+		attribute.String("version", config.Version),
+		semconv.ServiceNameKey.String(config.Agent),
+	}
+	resourceAttrs = append(resourceAttrs, attrs...)
+	for k, v := range config.Attributes {
+		resourceAttrs = append(resourceAttrs, attribute.String(k, v))
+	}
 	tracerProviderOpts := []sdktrace.TracerProviderOption{
 		sdktrace.WithBatcher(exporter, sdktrace.WithExportTimeout(tracerExportTimeout)),
 		sdktrace.WithResource(
 			resource.NewWithAttributes(
 				semconv.SchemaURL,
-				attribute.String("version", config.Version),
-				semconv.ServiceNameKey.String(config.Agent),
+				resourceAttrs...,
 			),
 		),
-		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(config.TraceSampleRate)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.TraceSampleRate))),
 	}
 
 	tracerProvider := sdktrace.NewTracerProvider(tracerProviderOpts...)
@@ -0,0 +1,82 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package trace
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func readSpans(t *testing.T, path string) []*fileSpan {
+	require := require.New(t)
+
+	f, err := os.Open(path)
+	require.NoError(err)
+	defer f.Close()
+	spans := []*fileSpan{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var s fileSpan
+		require.NoError(json.Unmarshal(scanner.Bytes(), &s))
+		spans = append(spans, &s)
+	}
+	require.NoError(scanner.Err())
+	return spans
+}
+
+func TestFileExporter(t *testing.T) {
+	tests := []struct {
+		name       string
+		sampleRate float64
+		spans      int
+	}{
+		{name: "always sample", sampleRate: 1, spans: 2},
+		{name: "never sample", sampleRate: 0, spans: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			path := filepath.Join(t.TempDir(), "spans.json")
+			tracer, err := New(&Config{
+				Enabled:         true,
+				TraceSampleRate: tt.sampleRate,
+				Exporter:        ExporterConfig{Type: File, Endpoint: path},
+			}, attribute.String("chainID", "test"))
+			require.NoError(err)
+
+			ctx, parent := tracer.Start(context.Background(), "parent")
+			_, child := tracer.Start(ctx, "child", oteltrace.WithAttributes(attribute.Int("txs", 1)))
+			child.End()
+			parent.End()
+			require.NoError(tracer.Close())
+
+			spans := readSpans(t, path)
+			require.Len(spans, tt.spans)
+			if tt.spans == 0 {
+				return
+			}
+			require.Equal("child", spans[0].Name)
+			require.Equal(map[string]string{"txs": "1"}, spans[0].Attributes)
+			require.Equal("parent", spans[1].Name)
+			require.Equal(spans[1].SpanID, spans[0].ParentID)
+			require.Equal(spans[1].TraceID, spans[0].TraceID)
+			require.Empty(spans[1].ParentID)
+		})
+	}
+}
+
+func TestUnknownExporter(t *testing.T) {
+	_, err := New(&Config{Enabled: true, Exporter: ExporterConfig{Type: "unknown"}})
+	require.ErrorIs(t, err, ErrUnknownExporterType)
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tstate
+
+type TraceType string
+
+const (
+	TraceGet    TraceType = "get"
+	TraceInsert TraceType = "insert"
+	TraceRemove TraceType = "remove"
+)
+
+// TraceOp is an operation recorded by a [TStateView] with tracing enabled.
+type TraceOp struct {
+	Type TraceType `json:"type"`
+	Key  []byte    `json:"key"`
+
+	// Exists is true if [Key] had a value before the operation (which is
+	// stored in [Prev] for inserts and removals).
+	Exists bool   `json:"exists"`
+	Prev   []byte `json:"prev,omitempty"`
+
+	// Value is the value read by a get or written by an insert and [Size] is
+	// its length.
+	Value []byte `json:"value,omitempty"`
+	Size  int    `json:"size"`
+}
+
+// EnableTrace records all calls to [GetValue], [Insert], and [Remove] on [ts]
+// (with permission to access the key).
+//
+// Tracing is only intended to be used when debugging (i.e. re-executing
+// accepted blocks).
+func (ts *TStateView) EnableTrace() {
+	ts.trace = []*TraceOp{}
+}
+
+// TakeTrace returns the operations recorded since [EnableTrace] or the last
+// call to [TakeTrace] (nil if tracing is not enabled).
+func (ts *TStateView) TakeTrace() []*TraceOp {
+	trace := ts.trace
+	if trace != nil {
+		ts.trace = []*TraceOp{}
+	}
+	return trace
+}
+
+func (ts *TStateView) record(t TraceType, key string, exists bool, prev []byte, value []byte) {
+	if ts.trace == nil {
+		return
+	}
+	ts.trace = append(ts.trace, &TraceOp{
+		Type:   t,
+		Key:    []byte(key),
+		Exists: exists,
+		Prev:   prev,
+		Value:  value,
+		Size:   len(value),
+	})
+}
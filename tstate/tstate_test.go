@@ -911,3 +911,30 @@ func TestInsertAllocate(t *testing.T) {
 		})
 	}
 }
+
+func TestTrace(t *testing.T) {
+	require := require.New(t)
+	ctx := context.TODO()
+	ts := New(10)
+	tsv := ts.NewView(state.Keys{key1str: state.All, key2str: state.All}, map[string][]byte{key1str: testVal})
+	require.Nil(tsv.TakeTrace())
+
+	tsv.EnableTrace()
+	_, err := tsv.GetValue(ctx, key1)
+	require.NoError(err)
+	require.NoError(tsv.Insert(ctx, key1, []byte("new")))
+	trace := tsv.TakeTrace()
+	require.Equal([]*TraceOp{
+		{Type: TraceGet, Key: key1, Exists: true, Value: testVal, Size: len(testVal)},
+		{Type: TraceInsert, Key: key1, Exists: true, Prev: testVal, Value: []byte("new"), Size: 3},
+	}, trace)
+
+	// Only operations since the last call are returned
+	require.NoError(tsv.Remove(ctx, key2))
+	require.NoError(tsv.Remove(ctx, key1))
+	require.Equal([]*TraceOp{
+		{Type: TraceRemove, Key: key2},
+		{Type: TraceRemove, Key: key1, Exists: true, Prev: []byte("new")},
+	}, tsv.TakeTrace())
+	require.Empty(tsv.TakeTrace())
+}
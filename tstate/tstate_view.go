@@ -50,6 +50,9 @@ type TStateView struct {
 	// Store which keys are modified and how large their values were.
 	allocates map[string]uint16
 	writes    map[string]uint16
+
+	// trace is only populated if [EnableTrace] was called.
+	trace []*TraceOp
 }
 
 func (ts *TState) NewView(scope state.Keys, storage map[string][]byte) *TStateView {
@@ -158,6 +161,7 @@ func (ts *TStateView) GetValue(ctx context.Context, key []byte) ([]byte, error)
 	}
 	k := string(key)
 	v, exists := ts.getValue(ctx, k)
+	ts.record(TraceGet, k, exists, nil, v)
 	if !exists {
 		return nil, database.ErrNotFound
 	}
@@ -207,6 +211,7 @@ func (ts *TStateView) Insert(ctx context.Context, key []byte, value []byte) erro
 	// Invariant: [getValue] is safe to call here because with [state.Write], it
 	// will provide Read and Write access to the state
 	past, exists := ts.getValue(ctx, k)
+	ts.record(TraceInsert, k, exists, past, value)
 	op := &op{
 		k:             k,
 		pastV:         past,
@@ -252,6 +257,7 @@ func (ts *TStateView) Remove(ctx context.Context, key []byte) error {
 	}
 	k := string(key)
 	past, exists := ts.getValue(ctx, k)
+	ts.record(TraceRemove, k, exists, past, nil)
 	if !exists {
 		// We do not update writes if the key does not exist.
 		return nil
@@ -288,6 +294,12 @@ func (ts *TStateView) PendingChanges() int {
 	return len(ts.pendingChangedKeys)
 }
 
+// ChangedKeys returns all keys modified in [ts] and their new values
+// (Nothing if the key was removed). The returned map must not be modified.
+func (ts *TStateView) ChangedKeys() map[string]maybe.Maybe[[]byte] {
+	return ts.pendingChangedKeys
+}
+
 // Commit adds all pending changes to the parent view.
 func (ts *TStateView) Commit() {
 	ts.ts.l.Lock()
@@ -0,0 +1,192 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+// The archive stores the value of every key changed in each accepted block (and
+// the genesis state at height 0) so that the state at any height can be read
+// (regardless of [StateHistoryLength]).
+//
+// Each change is stored as:
+// [archiveChangePrefix] + [key length] + [key] + [MaxUint64 - height]
+//
+// Inverting the height means the most recent change to a key at or below some
+// height is the first entry returned by an iterator started at that height.
+//
+// The keys changed by each block are also indexed by height:
+// [archiveBlockPrefix] + [height] + [key]
+//
+// so that the keys changed after some height can be found without iterating
+// over the whole archive.
+const (
+	archiveChangePrefix = 0x0
+	archiveHeightPrefix = 0x1
+	archiveBlockPrefix  = 0x2
+
+	archiveValue   = 0x1
+	archiveDeleted = 0x0
+)
+
+var archiveHeightKey = []byte{archiveHeightPrefix}
+
+func archiveKeyPrefix(k []byte) []byte {
+	p := make([]byte, 1+consts.Uint16Len+len(k))
+	p[0] = archiveChangePrefix
+	binary.BigEndian.PutUint16(p[1:], uint16(len(k)))
+	copy(p[1+consts.Uint16Len:], k)
+	return p
+}
+
+func archiveKey(k []byte, height uint64) []byte {
+	return binary.BigEndian.AppendUint64(archiveKeyPrefix(k), math.MaxUint64-height)
+}
+
+func archiveBlockKey(height uint64, k []byte) []byte {
+	p := make([]byte, 1+consts.Uint64Len+len(k))
+	p[0] = archiveBlockPrefix
+	binary.BigEndian.PutUint64(p[1:], height)
+	copy(p[1+consts.Uint64Len:], k)
+	return p
+}
+
+// initArchive ensures the archive was populated from genesis (a partial archive
+// would not be able to serve reads of keys that were not modified since it
+// was enabled).
+func (vm *VM) initArchive() error {
+	height, err := vm.GetArchiveHeight()
+	switch {
+	case err == nil:
+		// The archive may be ahead of last accepted if we crashed before
+		// persisting the last accepted block (it will be archived again).
+		if height < vm.lastAccepted.Hght {
+			return fmt.Errorf("%w: archived=%d lastAccepted=%d", ErrArchiveIncomplete, height, vm.lastAccepted.Hght)
+		}
+		return nil
+	case errors.Is(err, database.ErrNotFound):
+		return fmt.Errorf("%w: archive mode must be enabled before genesis is loaded", ErrArchiveIncomplete)
+	default:
+		return err
+	}
+}
+
+// archiveGenesis stores the genesis state at height 0.
+func (vm *VM) archiveGenesis() error {
+	batch := vm.archiveDB.NewBatch()
+	it := vm.stateDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		if err := batch.Put(archiveKey(it.Key(), 0), append([]byte{archiveValue}, it.Value()...)); err != nil {
+			return err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Put(archiveHeightKey, binary.BigEndian.AppendUint64(nil, 0)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// archiveBlock stores all changes made by [blk].
+func (vm *VM) archiveBlock(blk *chain.StatelessBlock) error {
+	changes := blk.StateChanges()
+	if changes == nil {
+		// This should never happen because archive nodes never state sync.
+		return fmt.Errorf("%w: block %d was not processed", ErrArchiveIncomplete, blk.Hght)
+	}
+	if err := vm.archiveChanges(blk.Hght, changes); err != nil {
+		return err
+	}
+	vm.Logger().Debug("archived block", zap.Uint64("height", blk.Hght), zap.Int("changes", len(changes)))
+	return nil
+}
+
+// archiveChanges stores [changes] made at [height] and marks [height] as
+// archived.
+func (vm *VM) archiveChanges(height uint64, changes map[string]maybe.Maybe[[]byte]) error {
+	batch := vm.archiveDB.NewBatch()
+	for k, v := range changes {
+		value := []byte{archiveDeleted}
+		if v.HasValue() {
+			value = append([]byte{archiveValue}, v.Value()...)
+		}
+		if err := batch.Put(archiveKey([]byte(k), height), value); err != nil {
+			return err
+		}
+		if err := batch.Put(archiveBlockKey(height, []byte(k)), nil); err != nil {
+			return err
+		}
+	}
+	if err := batch.Put(archiveHeightKey, binary.BigEndian.AppendUint64(nil, height)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// GetArchiveHeight returns the height of the last block stored in the archive.
+func (vm *VM) GetArchiveHeight() (uint64, error) {
+	if vm.archiveDB == nil {
+		return 0, ErrArchiveDisabled
+	}
+	b, err := vm.archiveDB.Get(archiveHeightKey)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// ReadStateAt reads [keys] from the post-execution state of the accepted block
+// at [height]. Like [ReadState], [database.ErrNotFound] is returned for keys
+// that did not exist.
+//
+// This is only supported when [ArchiveMode] is enabled.
+func (vm *VM) ReadStateAt(_ context.Context, keys [][]byte, height uint64) ([][]byte, []error) {
+	archived, err := vm.GetArchiveHeight()
+	if err == nil && height > archived {
+		err = fmt.Errorf("%w: height=%d archived=%d", ErrArchiveIncomplete, height, archived)
+	}
+	if err != nil {
+		return utils.Repeat[[]byte](nil, len(keys)), utils.Repeat(err, len(keys))
+	}
+	values := make([][]byte, len(keys))
+	errs := make([]error, len(keys))
+	for i, k := range keys {
+		values[i], errs[i] = vm.readArchive(k, height)
+	}
+	return values, errs
+}
+
+func (vm *VM) readArchive(k []byte, height uint64) ([]byte, error) {
+	prefix := archiveKeyPrefix(k)
+	it := vm.archiveDB.NewIteratorWithStartAndPrefix(archiveKey(k, height), prefix)
+	defer it.Release()
+	if !it.Next() {
+		if err := it.Error(); err != nil {
+			return nil, err
+		}
+		return nil, database.ErrNotFound
+	}
+	v := it.Value()
+	if len(v) == 0 || v[0] == archiveDeleted {
+		return nil, database.ErrNotFound
+	}
+	return bytes.Clone(v[1:]), nil
+}
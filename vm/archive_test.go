@@ -0,0 +1,54 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchivedReverts(t *testing.T) {
+	require := require.New(t)
+	vm := &VM{archiveDB: memdb.New()}
+	for height, changes := range []map[string]maybe.Maybe[[]byte]{
+		{"a": maybe.Some([]byte{1}), "b": maybe.Some([]byte{1})},
+		{"a": maybe.Some([]byte{2}), "c": maybe.Some([]byte{1})},
+		{"b": maybe.Nothing[[]byte]()},
+		{"a": maybe.Some([]byte{3})},
+	} {
+		require.NoError(vm.archiveChanges(uint64(height), changes))
+	}
+
+	values, errs := vm.ReadStateAt(context.TODO(), [][]byte{[]byte("a"), []byte("b"), []byte("c")}, 2)
+	require.Equal([][]byte{{2}, nil, {1}}, values)
+	require.NoError(errs[0])
+	require.ErrorIs(errs[1], database.ErrNotFound)
+	require.NoError(errs[2])
+
+	// Only the keys changed after the height are reverted
+	reverts, err := vm.archivedReverts(3)
+	require.NoError(err)
+	require.Empty(reverts)
+	reverts, err = vm.archivedReverts(1)
+	require.NoError(err)
+	require.ElementsMatch([]database.BatchOp{
+		{Key: []byte("a"), Value: []byte{2}},
+		{Key: []byte("b"), Value: []byte{1}},
+	}, reverts)
+	reverts, err = vm.archivedReverts(0)
+	require.NoError(err)
+	require.ElementsMatch([]database.BatchOp{
+		{Key: []byte("a"), Value: []byte{1}},
+		{Key: []byte("b"), Value: []byte{1}},
+		{Key: []byte("c"), Delete: true},
+	}, reverts)
+
+	_, err = vm.archivedReverts(4)
+	require.ErrorIs(err, ErrArchiveIncomplete)
+}
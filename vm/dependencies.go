@@ -16,6 +16,7 @@ import (
 	"github.com/ava-labs/hypersdk/builder"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/gossiper"
+	"github.com/ava-labs/hypersdk/network"
 	"github.com/ava-labs/hypersdk/state"
 	"github.com/ava-labs/hypersdk/trace"
 
@@ -49,9 +50,20 @@ type Config struct {
 	AcceptedBlockWindowCache         int             `json:"acceptedBlockWindowCache"`
 	ContinuousProfilerConfig         profiler.Config `json:"continuousProfilerConfig"`
 	TargetBuildDuration              time.Duration   `json:"targetBuildDuration"`
+	BuildStreamBatch                 int             `json:"buildStreamBatch"`          // txs streamed from the mempool at a time when building
+	BuildStopThreshold               uint64          `json:"buildStopThreshold"`        // units (used by [chain.DefaultBuildPolicy], 0 to disable)
+	BuildMaxViewPreallocation        int             `json:"buildMaxViewPreallocation"` // max state changes preallocated when building
 	ProcessingBuildSkip              int             `json:"processingBuildSkip"`
 	TargetGossipDuration             time.Duration   `json:"targetGossipDuration"`
 	BlockCompactionFrequency         int             `json:"blockCompactionFrequency"`
+	SnapshotPath                     string          `json:"snapshotPath"`    // snapshot to initialize an empty database from
+	ArchiveMode                      bool            `json:"archiveMode"`     // keep the changes of all blocks to serve historical state reads
+	TxTracing                        bool            `json:"txTracing"`       // serve [TraceTx] (which re-executes accepted blocks)
+	SnapshotServing                  bool            `json:"snapshotServing"` // serve [SnapshotHeader] and [SnapshotKeyValues] (which export all of state)
+	NetworkConfig                    network.Config  `json:"networkConfig"`
+	TxGossipLimit                    network.Limit   `json:"txGossipLimit"`   // per peer
+	TxAnnounceLimit                  network.Limit   `json:"txAnnounceLimit"` // per peer (announcements and requests)
+	StateSyncLimit                   network.Limit   `json:"stateSyncLimit"`  // per peer
 	// Config is defined by the Controller
 	Config map[string]any `json:"config"`
 }
@@ -73,16 +85,41 @@ func NewConfig() Config {
 		ValueNodeCacheSize:               2 * units.GiB,
 		AcceptorSize:                     64,
 		StateSyncParallelism:             4,
-		StateSyncMinBlocks:               768, // set to max int for archive nodes to ensure no skips
+		StateSyncMinBlocks:               768, // ignored in archive mode (archive nodes never state sync)
 		StateSyncServerDelay:             0,   // used for testing
 		ParsedBlockCacheSize:             128,
 		AcceptedBlockWindow:              50_000, // ~3.5hr with 250ms block time (100GB at 2MB)
 		AcceptedBlockWindowCache:         128,    // 256MB at 2MB blocks
 		ContinuousProfilerConfig:         profiler.Config{Enabled: false},
 		TargetBuildDuration:              100 * time.Millisecond,
+		BuildStreamBatch:                 256,
+		BuildStopThreshold:               0,
+		BuildMaxViewPreallocation:        10_000,
 		ProcessingBuildSkip:              16,
 		TargetGossipDuration:             20 * time.Millisecond,
 		BlockCompactionFrequency:         32, // 64 MB of deletion if 2 MB blocks
+		NetworkConfig: network.Config{
+			PenaltyThreshold: 256, // invalid txs
+			PenaltyHalfLife:  time.Minute,
+		},
+		TxGossipLimit: network.Limit{
+			MessageRate:  100,
+			MessageBurst: 200,
+			ByteRate:     16 * units.MiB,
+			ByteBurst:    32 * units.MiB,
+		},
+		TxAnnounceLimit: network.Limit{
+			MessageRate:  100,
+			MessageBurst: 200,
+			ByteRate:     2 * units.MiB,
+			ByteBurst:    4 * units.MiB,
+		},
+		StateSyncLimit: network.Limit{
+			MessageRate:  100,
+			MessageBurst: 200,
+			ByteRate:     units.MiB,
+			ByteBurst:    2 * units.MiB,
+		},
 	}
 }
 
@@ -132,3 +169,21 @@ type Controller interface {
 	// `vm.Shutdown` is called.
 	Shutdown(context.Context) error
 }
+
+// BuildPolicyController is implemented by a [Controller] that customizes how
+// blocks are built. It is invoked after [Controller.Initialize]. If a
+// [Controller] does not implement it, [chain.DefaultBuildPolicy] is used.
+type BuildPolicyController interface {
+	BuildPolicy() chain.BuildPolicy
+}
+
+// StateLoadedController is implemented by a [Controller] that indexes state
+// outside of state (and so can't rely on [Controller.Accepted] alone, which is
+// not invoked for blocks skipped by state sync or a snapshot import).
+//
+// It is invoked with the state of the last accepted block when the VM starts
+// (unless an interrupted sync must still complete) and after state sync
+// completes, before any more blocks are processed.
+type StateLoadedController interface {
+	StateLoaded(ctx context.Context, state merkledb.MerkleDB) error
+}
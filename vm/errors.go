@@ -14,5 +14,12 @@ var (
 	ErrStateMissing        = errors.New("state missing")
 	ErrStateSyncing        = errors.New("state still syncing")
 	ErrUnexpectedStateRoot = errors.New("unexpected state root")
+	ErrSnapshotUnavailable = errors.New("snapshot unavailable")
+	ErrSnapshotsDisabled   = errors.New("snapshot serving disabled")
+	ErrArchiveDisabled     = errors.New("archive mode disabled")
+	ErrArchiveIncomplete   = errors.New("archive incomplete")
+	ErrReplayUnavailable   = errors.New("replay unavailable")
+	ErrTxTracingDisabled   = errors.New("tx tracing disabled")
+	ErrTxNotFound          = errors.New("tx not found")
 	ErrTooManyProcessing   = errors.New("too many processing")
 )
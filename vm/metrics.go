@@ -29,6 +29,8 @@ type Metrics struct {
 	txsReceived              prometheus.Counter
 	seenTxsReceived          prometheus.Counter
 	txsGossiped              prometheus.Counter
+	txsAnnounced             prometheus.Counter
+	txsRequested             prometheus.Counter
 	txsVerified              prometheus.Counter
 	txsAccepted              prometheus.Counter
 	stateChanges             prometheus.Counter
@@ -151,6 +153,16 @@ func newMetrics() (*prometheus.Registry, *Metrics, error) {
 			Name:      "txs_gossiped",
 			Help:      "number of txs gossiped by vm",
 		}),
+		txsAnnounced: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "vm",
+			Name:      "txs_announced",
+			Help:      "number of txs announced (by ID) by vm",
+		}),
+		txsRequested: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "vm",
+			Name:      "txs_requested",
+			Help:      "number of announced txs requested by vm",
+		}),
 		txsVerified: prometheus.NewCounter(prometheus.CounterOpts{
 			Namespace: "vm",
 			Name:      "txs_verified",
@@ -269,6 +281,8 @@ func newMetrics() (*prometheus.Registry, *Metrics, error) {
 		r.Register(m.txsReceived),
 		r.Register(m.seenTxsReceived),
 		r.Register(m.txsGossiped),
+		r.Register(m.txsAnnounced),
+		r.Register(m.txsRequested),
 		r.Register(m.txsVerified),
 		r.Register(m.txsAccepted),
 		r.Register(m.stateChanges),
@@ -0,0 +1,90 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/version"
+	"go.uber.org/zap"
+)
+
+// TxAnnounceHandler routes transaction announcements and the requests (and
+// responses) for announced transactions to the gossiper.
+type TxAnnounceHandler struct {
+	vm *VM
+}
+
+func NewTxAnnounceHandler(vm *VM) *TxAnnounceHandler {
+	return &TxAnnounceHandler{vm}
+}
+
+func (*TxAnnounceHandler) Connected(context.Context, ids.NodeID, *version.Application) error {
+	return nil
+}
+
+func (*TxAnnounceHandler) Disconnected(context.Context, ids.NodeID) error {
+	return nil
+}
+
+func (t *TxAnnounceHandler) AppGossip(ctx context.Context, nodeID ids.NodeID, msg []byte) error {
+	if !t.vm.isReady() {
+		t.vm.snowCtx.Log.Warn("handle tx announcement failed", zap.Error(ErrNotReady))
+		return nil
+	}
+
+	return t.vm.gossiper.HandleAnnouncement(ctx, nodeID, msg)
+}
+
+func (t *TxAnnounceHandler) AppRequest(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	requestID uint32,
+	_ time.Time,
+	request []byte,
+) error {
+	return t.vm.gossiper.HandleTxRequest(ctx, nodeID, requestID, request)
+}
+
+func (t *TxAnnounceHandler) AppRequestFailed(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	requestID uint32,
+) error {
+	return t.vm.gossiper.HandleTxRequestFailed(ctx, nodeID, requestID)
+}
+
+func (t *TxAnnounceHandler) AppResponse(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	requestID uint32,
+	response []byte,
+) error {
+	if !t.vm.isReady() {
+		t.vm.snowCtx.Log.Warn("handle tx response failed", zap.Error(ErrNotReady))
+		return t.vm.gossiper.HandleTxRequestFailed(ctx, nodeID, requestID)
+	}
+
+	return t.vm.gossiper.HandleTxResponse(ctx, nodeID, requestID, response)
+}
+
+func (*TxAnnounceHandler) CrossChainAppRequest(
+	context.Context,
+	ids.ID,
+	uint32,
+	time.Time,
+	[]byte,
+) error {
+	return nil
+}
+
+func (*TxAnnounceHandler) CrossChainAppRequestFailed(context.Context, ids.ID, uint32) error {
+	return nil
+}
+
+func (*TxAnnounceHandler) CrossChainAppResponse(context.Context, ids.ID, uint32, []byte) error {
+	return nil
+}
@@ -0,0 +1,482 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ava-labs/avalanchego/api/metrics"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/fees"
+)
+
+// ReplayReport is the outcome of re-executing an accepted block and comparing
+// it with what was stored when the block was accepted.
+type ReplayReport struct {
+	Height     uint64 `json:"height"`
+	BlockID    ids.ID `json:"blockID"`
+	ParentRoot ids.ID `json:"parentRoot"`
+
+	Txs     []*ReplayTx     `json:"txs"`
+	Changes []*ReplayChange `json:"changes"`
+
+	UnitPrices    fees.Dimensions `json:"unitPrices"`
+	UnitsConsumed fees.Dimensions `json:"unitsConsumed"`
+
+	Root ids.ID `json:"root"`
+
+	// StoredRoot is the post-execution root of the block when it was
+	// accepted (empty if it is not known).
+	StoredRoot ids.ID `json:"storedRoot"`
+
+	// ComparedValues and ComparedResults are true if the changes and results
+	// of the block could be compared with what was stored.
+	ComparedValues  bool `json:"comparedValues"`
+	ComparedResults bool `json:"comparedResults"`
+
+	Mismatches []*ReplayMismatch `json:"mismatches"`
+}
+
+type ReplayTx struct {
+	ID      ids.ID          `json:"id"`
+	Result  *chain.Result   `json:"result"`
+	Changes []*ReplayChange `json:"changes"`
+}
+
+// ReplayChange is a change to a key (encoded as hex).
+type ReplayChange struct {
+	Key     string `json:"key"`
+	Value   string `json:"value,omitempty"`
+	Removed bool   `json:"removed,omitempty"`
+}
+
+// ReplayMismatch describes a difference between what was stored and what was
+// produced by replaying a block.
+type ReplayMismatch struct {
+	Field    string `json:"field"`
+	Key      string `json:"key,omitempty"`
+	Stored   string `json:"stored"`
+	Replayed string `json:"replayed"`
+}
+
+func sortedKeys(changes map[string]maybe.Maybe[[]byte]) []string {
+	keys := make([]string, 0, len(changes))
+	for k := range changes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func replayChanges(changes map[string]maybe.Maybe[[]byte]) []*ReplayChange {
+	keys := sortedKeys(changes)
+	rc := make([]*ReplayChange, len(keys))
+	for i, k := range keys {
+		v := changes[k]
+		rc[i] = &ReplayChange{Key: hex.EncodeToString([]byte(k)), Removed: v.IsNothing()}
+		if v.HasValue() {
+			rc[i].Value = hex.EncodeToString(v.Value())
+		}
+	}
+	return rc
+}
+
+// Replay re-executes the accepted block at [height] against the
+// post-execution state of its parent and compares the results, fees, changes,
+// and root with what was stored when the block was accepted.
+//
+// The parent state is read from the archive (if [ArchiveMode] is enabled) or
+// otherwise from [StateHistoryLength].
+func (vm *VM) Replay(ctx context.Context, height uint64) (*ReplayReport, error) {
+	if height == 0 || height > vm.lastAccepted.Hght {
+		return nil, fmt.Errorf("%w: height=%d lastAccepted=%d", ErrReplayUnavailable, height, vm.lastAccepted.Hght)
+	}
+	blk, err := vm.GetDiskBlock(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to load block %d: %w", ErrReplayUnavailable, height, err)
+	}
+	exec, err := vm.replayBlock(ctx, blk)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReplayReport{
+		Height:        blk.Hght,
+		BlockID:       blk.ID(),
+		ParentRoot:    blk.StateRoot,
+		Txs:           make([]*ReplayTx, len(blk.Txs)),
+		Changes:       replayChanges(exec.Changes),
+		UnitPrices:    exec.FeeManager.UnitPrices(),
+		UnitsConsumed: exec.FeeManager.UnitsConsumed(),
+		Root:          exec.Root,
+		Mismatches:    []*ReplayMismatch{},
+	}
+	for i, tx := range blk.Txs {
+		report.Txs[i] = &ReplayTx{
+			ID:      tx.ID(),
+			Result:  exec.Results[i],
+			Changes: replayChanges(exec.TxChanges[i]),
+		}
+	}
+
+	// Compare with the stored post-execution state
+	report.StoredRoot, err = vm.storedRoot(ctx, blk)
+	if err != nil {
+		return nil, err
+	}
+	if report.StoredRoot != ids.Empty && report.StoredRoot != report.Root {
+		report.Mismatches = append(report.Mismatches, &ReplayMismatch{
+			Field:    "root",
+			Stored:   report.StoredRoot.String(),
+			Replayed: report.Root.String(),
+		})
+	}
+	if err := vm.compareValues(ctx, blk, report, exec.Changes); err != nil {
+		return nil, err
+	}
+
+	// Results are not persisted, so we can only compare them if the block is
+	// still in memory.
+	if cached, ok := vm.acceptedBlocksByID.Get(blk.ID()); ok && cached.Results() != nil {
+		report.ComparedResults = true
+		for i, result := range cached.Results() {
+			stored, err := chain.MarshalResults([]*chain.Result{result})
+			if err != nil {
+				return nil, err
+			}
+			replayed, err := chain.MarshalResults([]*chain.Result{exec.Results[i]})
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(stored, replayed) {
+				report.Mismatches = append(report.Mismatches, &ReplayMismatch{
+					Field:    "result",
+					Key:      blk.Txs[i].ID().String(),
+					Stored:   hex.EncodeToString(stored),
+					Replayed: hex.EncodeToString(replayed),
+				})
+			}
+		}
+	}
+	vm.Logger().Info("replayed block",
+		zap.Uint64("height", blk.Hght),
+		zap.Stringer("blockID", blk.ID()),
+		zap.Stringer("root", report.Root),
+		zap.Int("mismatches", len(report.Mismatches)),
+	)
+	return report, nil
+}
+
+// replayBlock re-executes [blk] against the post-execution state of its
+// parent.
+func (vm *VM) replayBlock(ctx context.Context, blk *chain.StatelessBlock) (*chain.Execution, error) {
+	parent, err := vm.replayParentState(ctx, blk)
+	if err != nil {
+		return nil, err
+	}
+	return blk.Replay(ctx, parent)
+}
+
+// replayParentState returns a view of the post-execution state of the parent
+// of [blk]. The view is created on top of the current state by reverting the
+// keys that changed since then (so only those keys are held in memory).
+func (vm *VM) replayParentState(ctx context.Context, blk *chain.StatelessBlock) (merkledb.View, error) {
+	var (
+		reverts []database.BatchOp
+		err     error
+	)
+	if vm.archiveDB != nil {
+		reverts, err = vm.archivedReverts(blk.Hght - 1)
+	} else {
+		reverts, err = vm.historicalReverts(ctx, blk.StateRoot)
+	}
+	if err != nil {
+		return nil, err
+	}
+	view, err := vm.stateDB.NewView(ctx, merkledb.ViewChanges{BatchOps: reverts})
+	if err != nil {
+		return nil, err
+	}
+	root, err := view.GetMerkleRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if root != blk.StateRoot {
+		return nil, fmt.Errorf("%w: expected=%s found=%s", ErrUnexpectedStateRoot, blk.StateRoot, root)
+	}
+	return view, nil
+}
+
+// historicalReverts returns the changes that revert the current state to the
+// state at [root] (which must be within [StateHistoryLength]).
+func (vm *VM) historicalReverts(ctx context.Context, root ids.ID) ([]database.BatchOp, error) {
+	current, err := vm.stateDB.GetMerkleRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if current == root {
+		return nil, nil
+	}
+	var (
+		reverts []database.BatchOp
+		start   = maybe.Nothing[[]byte]()
+	)
+	for {
+		proof, err := vm.stateDB.GetChangeProof(ctx, root, current, start, maybe.Nothing[[]byte](), stateRangePageSize)
+		if errors.Is(err, merkledb.ErrInsufficientHistory) {
+			return nil, fmt.Errorf("%w: unable to read state at %s: %w", ErrReplayUnavailable, root, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range proof.KeyChanges {
+			kvs, _, err := vm.GetStateRange(ctx, root, maybe.Some(change.Key), 1)
+			if err != nil {
+				return nil, err
+			}
+			if len(kvs) == 0 || !bytes.Equal(kvs[0].Key, change.Key) {
+				reverts = append(reverts, database.BatchOp{Key: change.Key, Delete: true})
+				continue
+			}
+			reverts = append(reverts, database.BatchOp{Key: change.Key, Value: kvs[0].Value})
+		}
+		if len(proof.KeyChanges) < stateRangePageSize {
+			return reverts, nil
+		}
+		start = maybe.Some(after(proof.KeyChanges[len(proof.KeyChanges)-1].Key))
+	}
+}
+
+// archivedReverts returns the changes that revert the archived state to the
+// state at [height].
+//
+// Only the keys changed by the blocks after [height] are visited (using the
+// index of changes by height), so the cost depends on the number of blocks
+// reverted rather than on the size of the archive.
+func (vm *VM) archivedReverts(height uint64) ([]database.BatchOp, error) {
+	archived, err := vm.GetArchiveHeight()
+	if err != nil {
+		return nil, err
+	}
+	if height > archived {
+		return nil, fmt.Errorf("%w: height=%d archived=%d", ErrArchiveIncomplete, height, archived)
+	}
+	if height == archived {
+		return nil, nil
+	}
+	it := vm.archiveDB.NewIteratorWithStartAndPrefix(
+		archiveBlockKey(height+1, nil),
+		[]byte{archiveBlockPrefix},
+	)
+	defer it.Release()
+	var (
+		reverts []database.BatchOp
+		seen    = set.Set[string]{}
+	)
+	for it.Next() {
+		key := it.Key()[1+consts.Uint64Len:]
+		if seen.Contains(string(key)) {
+			continue
+		}
+		seen.Add(string(key))
+		value, err := vm.readArchive(key, height)
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			reverts = append(reverts, database.BatchOp{Key: bytes.Clone(key), Delete: true})
+		case err != nil:
+			return nil, err
+		default:
+			reverts = append(reverts, database.BatchOp{Key: bytes.Clone(key), Value: value})
+		}
+	}
+	return reverts, it.Error()
+}
+
+// storedRoot returns the post-execution root of [blk] when it was accepted or
+// [ids.Empty] if it is not known.
+func (vm *VM) storedRoot(ctx context.Context, blk *chain.StatelessBlock) (ids.ID, error) {
+	if blk.Hght < vm.lastAccepted.Hght {
+		child, err := vm.GetDiskBlock(ctx, blk.Hght+1)
+		if err != nil {
+			return ids.Empty, err
+		}
+		return child.StateRoot, nil
+	}
+
+	// If the last accepted block was not processed (after state sync or
+	// importing a snapshot), the current state is the post-execution state
+	// of its parent.
+	root, err := vm.stateDB.GetMerkleRoot(ctx)
+	if err != nil {
+		return ids.Empty, err
+	}
+	if root == blk.StateRoot {
+		return ids.Empty, nil
+	}
+	return root, nil
+}
+
+// compareValues adds a mismatch to [report] for each key in [changes] that
+// does not have the same value in the stored post-execution state of [blk].
+func (vm *VM) compareValues(
+	ctx context.Context,
+	blk *chain.StatelessBlock,
+	report *ReplayReport,
+	changes map[string]maybe.Maybe[[]byte],
+) error {
+	var read func([]byte) ([]byte, error)
+	switch {
+	case vm.archiveDB != nil:
+		read = func(k []byte) ([]byte, error) {
+			return vm.readArchive(k, blk.Hght)
+		}
+	case report.StoredRoot != ids.Empty:
+		read = func(k []byte) ([]byte, error) {
+			proof, err := vm.stateDB.GetRangeProofAtRoot(ctx, report.StoredRoot, maybe.Some(k), maybe.Some(k), 1)
+			if err != nil {
+				return nil, err
+			}
+			if len(proof.KeyValues) == 0 || !bytes.Equal(proof.KeyValues[0].Key, k) {
+				return nil, database.ErrNotFound
+			}
+			return proof.KeyValues[0].Value, nil
+		}
+	default:
+		return nil
+	}
+	feeKey := string(chain.FeeKey(vm.StateManager().FeeKey()))
+	for _, k := range sortedKeys(changes) {
+		v, err := read([]byte(k))
+		switch {
+		case errors.Is(err, merkledb.ErrInsufficientHistory):
+			vm.Logger().Info("unable to compare values", zap.Error(err))
+			return nil
+		case errors.Is(err, database.ErrNotFound):
+			v = nil
+		case err != nil:
+			return err
+		}
+		replayed := changes[k]
+		if (err == nil) == replayed.HasValue() && bytes.Equal(v, replayed.Value()) {
+			continue
+		}
+		field := "value"
+		if k == feeKey {
+			field = "fees"
+		}
+		mismatch := &ReplayMismatch{Field: field, Key: hex.EncodeToString([]byte(k))}
+		if err == nil {
+			mismatch.Stored = hex.EncodeToString(v)
+		}
+		if replayed.HasValue() {
+			mismatch.Replayed = hex.EncodeToString(replayed.Value())
+		}
+		report.Mismatches = append(report.Mismatches, mismatch)
+	}
+	report.ComparedValues = true
+	return nil
+}
+
+// ReplayConfig specifies the node database used by [VM.ReplayOffline] and
+// [VM.ExportSnapshotOffline].
+type ReplayConfig struct {
+	NetworkID uint32
+	ChainID   ids.ID
+
+	// ChainDataDir is the data directory of the chain (which must not be in use
+	// by a running node).
+	ChainDataDir string
+
+	Genesis []byte
+	Upgrade []byte
+	Config  []byte
+
+	// Log defaults to [logging.NoLog] if not provided.
+	Log logging.Logger
+}
+
+// ReplayOffline initializes [vm] from the database of a stopped node without
+// modifying it and re-executes the accepted block at [height] (see
+// [VM.Replay]). [vm] must not have been initialized.
+func (vm *VM) ReplayOffline(ctx context.Context, cfg *ReplayConfig, height uint64) (*ReplayReport, error) {
+	scratchDir, err := os.MkdirTemp("", "hypersdk-replay")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(scratchDir)
+
+	if err := vm.initializeOffline(ctx, cfg, scratchDir); err != nil {
+		return nil, err
+	}
+	report, err := vm.Replay(ctx, height)
+	return report, errors.Join(err, vm.Shutdown(ctx))
+}
+
+// initializeOffline initializes [vm] from the database of a stopped node
+// without modifying it (the controller is given [scratchDir] instead of its
+// data directory).
+func (vm *VM) initializeOffline(ctx context.Context, cfg *ReplayConfig, scratchDir string) error {
+	sk, err := bls.NewSecretKey()
+	if err != nil {
+		return err
+	}
+	log := cfg.Log
+	if log == nil {
+		log = logging.NoLog{}
+	}
+	vm.readOnly = true
+	vm.scratchDir = scratchDir
+	return vm.Initialize(
+		ctx,
+		&snow.Context{
+			NetworkID:    cfg.NetworkID,
+			ChainID:      cfg.ChainID,
+			Log:          log,
+			ChainDataDir: cfg.ChainDataDir,
+			Metrics:      metrics.NewPrefixGatherer(),
+			PublicKey:    bls.PublicFromSecretKey(sk),
+		},
+		nil,
+		cfg.Genesis,
+		cfg.Upgrade,
+		cfg.Config,
+		make(chan common.Message, 1),
+		nil,
+		nil,
+	)
+}
+
+// overlayDB buffers all writes to a read-only database in memory.
+type overlayDB struct {
+	*versiondb.Database
+
+	base database.Database
+}
+
+func newOverlayDB(base database.Database) *overlayDB {
+	return &overlayDB{Database: versiondb.New(base), base: base}
+}
+
+func (o *overlayDB) Close() error {
+	return errors.Join(o.Database.Close(), o.base.Close())
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package replay provides the "replay" and "export-snapshot" commands shared
+// by the binaries of hypersdk VMs, which read the database of a stopped node.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/hypersdk/vm"
+)
+
+// nodeFlags are the flags that locate the database of a stopped node.
+type nodeFlags struct {
+	chainDataDir string
+	networkID    uint32
+	chainID      string
+	genesisFile  string
+	upgradeFile  string
+	configFile   string
+}
+
+func (f *nodeFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.chainDataDir, "chain-data-dir", "", "chain data directory of the node")
+	cmd.Flags().Uint32Var(&f.networkID, "network-id", 0, "network ID of the chain")
+	cmd.Flags().StringVar(&f.chainID, "chain-id", "", "ID of the chain")
+	cmd.Flags().StringVar(&f.genesisFile, "genesis-file", "", "genesis of the chain")
+	cmd.Flags().StringVar(&f.upgradeFile, "upgrade-file", "", "upgrade of the chain (optional)")
+	cmd.Flags().StringVar(&f.configFile, "config-file", "", "config of the chain (optional)")
+}
+
+func (f *nodeFlags) config() (*vm.ReplayConfig, error) {
+	if len(f.chainDataDir) == 0 || len(f.genesisFile) == 0 {
+		return nil, errors.New("--chain-data-dir and --genesis-file are required")
+	}
+	cid, err := ids.FromString(f.chainID)
+	if err != nil {
+		return nil, err
+	}
+	genesisBytes, err := os.ReadFile(f.genesisFile)
+	if err != nil {
+		return nil, err
+	}
+	upgradeBytes, err := readOptional(f.upgradeFile, nil)
+	if err != nil {
+		return nil, err
+	}
+	configBytes, err := readOptional(f.configFile, []byte("{}"))
+	if err != nil {
+		return nil, err
+	}
+	return &vm.ReplayConfig{
+		NetworkID:    f.networkID,
+		ChainID:      cid,
+		ChainDataDir: f.chainDataDir,
+		Genesis:      genesisBytes,
+		Upgrade:      upgradeBytes,
+		Config:       configBytes,
+	}, nil
+}
+
+// NewCommand implements the "replay" command for the VM returned by [newVM]
+// (which must not be initialized).
+func NewCommand(newVM func() *vm.VM) *cobra.Command {
+	var (
+		flags  nodeFlags
+		height uint64
+	)
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Re-executes an accepted block from the database of a stopped node",
+		RunE: func(*cobra.Command, []string) error {
+			cfg, err := flags.config()
+			if err != nil {
+				return err
+			}
+			report, err := newVM().ReplayOffline(context.Background(), cfg, height)
+			if err != nil {
+				return err
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(report)
+		},
+	}
+	flags.register(cmd)
+	cmd.Flags().Uint64Var(&height, "height", 0, "height of the block to replay")
+	return cmd
+}
+
+// NewExportSnapshotCommand implements the "export-snapshot" command for the VM
+// returned by [newVM] (which must not be initialized).
+func NewExportSnapshotCommand(newVM func() *vm.VM) *cobra.Command {
+	var (
+		flags  nodeFlags
+		height uint64
+	)
+	cmd := &cobra.Command{
+		Use:   "export-snapshot [output file]",
+		Short: "Exports a snapshot of state from the database of a stopped node",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := flags.config()
+			if err != nil {
+				return err
+			}
+			var h *uint64
+			if cmd.Flags().Changed("height") {
+				h = &height
+			}
+			f, err := os.Create(args[0])
+			if err != nil {
+				return err
+			}
+			header, root, keys, err := newVM().ExportSnapshotOffline(context.Background(), cfg, h, f)
+			if err := errors.Join(err, f.Close()); err != nil {
+				return err
+			}
+			fmt.Printf("exported snapshot to %s height: %d root: %s keys: %d\n", args[0], header.Height, root, keys)
+			return nil
+		},
+	}
+	flags.register(cmd)
+	cmd.Flags().Uint64Var(&height, "height", 0, "height of the snapshot (defaults to the last accepted block)")
+	return cmd
+}
+
+func readOptional(file string, def []byte) ([]byte, error) {
+	if len(file) == 0 {
+		return def, nil
+	}
+	return os.ReadFile(file)
+}
@@ -222,6 +222,14 @@ func (vm *VM) Accepted(ctx context.Context, b *chain.StatelessBlock) {
 
 	vm.metrics.txsAccepted.Add(float64(len(b.Txs)))
 
+	// Archive state changes before updating last accepted (so we never
+	// consider a block accepted without archiving it)
+	if vm.archiveDB != nil {
+		if err := vm.archiveBlock(b); err != nil {
+			vm.Fatal("unable to archive block", zap.Error(err))
+		}
+	}
+
 	// Update accepted blocks on-disk and caches
 	if err := vm.UpdateLastAccepted(b); err != nil {
 		vm.Fatal("unable to update last accepted", zap.Error(err))
@@ -386,10 +394,22 @@ func (vm *VM) RecordTxsGossiped(c int) {
 	vm.metrics.txsGossiped.Add(float64(c))
 }
 
+func (vm *VM) RecordTxsAnnounced(c int) {
+	vm.metrics.txsAnnounced.Add(float64(c))
+}
+
+func (vm *VM) RecordTxsRequested(c int) {
+	vm.metrics.txsRequested.Add(float64(c))
+}
+
 func (vm *VM) RecordTxsReceived(c int) {
 	vm.metrics.txsReceived.Add(float64(c))
 }
 
+func (vm *VM) PenalizePeer(nodeID ids.NodeID, invalidTxs int) {
+	vm.networkManager.Penalize(nodeID, float64(invalidTxs))
+}
+
 func (vm *VM) RecordSeenTxsReceived(c int) {
 	vm.metrics.seenTxsReceived.Add(float64(c))
 }
@@ -402,6 +422,18 @@ func (vm *VM) GetTargetBuildDuration() time.Duration {
 	return vm.config.TargetBuildDuration
 }
 
+func (vm *VM) GetBuildPolicy() chain.BuildPolicy {
+	return vm.buildPolicy
+}
+
+func (vm *VM) GetBuildStreamBatch() int {
+	return vm.config.BuildStreamBatch
+}
+
+func (vm *VM) GetBuildMaxViewPreallocation() int {
+	return vm.config.BuildMaxViewPreallocation
+}
+
 func (vm *VM) GetTargetGossipDuration() time.Duration {
 	return vm.config.TargetGossipDuration
 }
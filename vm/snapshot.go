@@ -0,0 +1,279 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/snapshot"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// snapshotBatchSize is the number of key-values committed to state at once
+// when importing a snapshot.
+const snapshotBatchSize = 16_384
+
+// SnapshotHeader returns the [snapshot.Header] for a snapshot at [height] (or
+// at the last accepted block if [height] is nil) and the root of the state in
+// the snapshot.
+//
+// The state in the snapshot is the post-execution state of the parent of the
+// block at [height], which is only available for blocks within
+// [StateHistoryLength]. Large states may not be exported before it is
+// discarded, so [ExportSnapshotOffline] should be used instead.
+//
+// This is only supported when [SnapshotServing] is enabled.
+func (vm *VM) SnapshotHeader(ctx context.Context, height *uint64) (*snapshot.Header, ids.ID, error) {
+	if !vm.config.SnapshotServing {
+		return nil, ids.Empty, ErrSnapshotsDisabled
+	}
+	header, blk, err := vm.snapshotHeader(ctx, height)
+	if err != nil {
+		return nil, ids.Empty, err
+	}
+	return header, blk.StateRoot, nil
+}
+
+// snapshotHeader returns the [snapshot.Header] for a snapshot at [height] (or
+// at the last accepted block if [height] is nil) and the block at that height.
+func (vm *VM) snapshotHeader(ctx context.Context, height *uint64) (*snapshot.Header, *chain.StatelessBlock, error) {
+	blk := vm.lastAccepted
+	if height != nil && *height != blk.Hght {
+		if *height == 0 || *height > blk.Hght {
+			return nil, nil, fmt.Errorf("%w: height=%d", ErrSnapshotUnavailable, *height)
+		}
+		var err error
+		blk, err = vm.GetDiskBlock(ctx, *height)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: unable to load block %d: %w", ErrSnapshotUnavailable, *height, err)
+		}
+	}
+	if blk.Hght == 0 {
+		return nil, nil, fmt.Errorf("%w: cannot snapshot genesis", ErrSnapshotUnavailable)
+	}
+
+	// Include all blocks that [backfillSeenTransactions] will walk when
+	// starting from the snapshot (including the first block outside of the
+	// validity window, which it uses to determine it is done).
+	r := vm.Rules(blk.Tmstmp)
+	blocks := [][]byte{blk.Bytes()}
+	for next := blk; next.Hght > 1 && blk.Tmstmp-next.Tmstmp <= r.GetValidityWindow(); {
+		prev, err := vm.GetDiskBlock(ctx, next.Hght-1)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: unable to load block %d: %w", ErrSnapshotUnavailable, next.Hght-1, err)
+		}
+		blocks = append(blocks, prev.Bytes())
+		next = prev
+	}
+	blocks = append(blocks, vm.genesisBlk.Bytes())
+	for i, j := 0, len(blocks)-1; i < j; i, j = i+1, j-1 {
+		blocks[i], blocks[j] = blocks[j], blocks[i]
+	}
+	return &snapshot.Header{Height: blk.Hght, Blocks: blocks}, blk, nil
+}
+
+// SnapshotKeyValues returns up to [limit] key-values in the state at [root],
+// starting at [start]. If there are more key-values, the returned key should be
+// provided as [start] to fetch the next batch.
+//
+// This is only supported when [SnapshotServing] is enabled.
+func (vm *VM) SnapshotKeyValues(
+	ctx context.Context,
+	root ids.ID,
+	start []byte,
+	limit int,
+) ([]merkledb.KeyValue, maybe.Maybe[[]byte], error) {
+	if !vm.config.SnapshotServing {
+		return nil, maybe.Nothing[[]byte](), ErrSnapshotsDisabled
+	}
+	kvs, next, err := vm.GetStateRange(ctx, root, maybe.Some(start), limit)
+	if err != nil {
+		return nil, maybe.Nothing[[]byte](), fmt.Errorf("%w: %w", ErrSnapshotUnavailable, err)
+	}
+	return kvs, next, nil
+}
+
+// exportSnapshot writes a snapshot at [height] (or at the last accepted block
+// if [height] is nil) to [w] and returns its header, root and the number of
+// key-values written.
+//
+// The state is read from a view of the state of the parent of the block at
+// [height] (see [replayParentState]), which is only valid while no blocks are
+// accepted (see [ExportSnapshotOffline]).
+func (vm *VM) exportSnapshot(ctx context.Context, height *uint64, w io.Writer) (*snapshot.Header, ids.ID, int, error) {
+	header, blk, err := vm.snapshotHeader(ctx, height)
+	if err != nil {
+		return nil, ids.Empty, 0, err
+	}
+	view, err := vm.replayParentState(ctx, blk)
+	if err != nil {
+		return nil, ids.Empty, 0, fmt.Errorf("%w: %w", ErrSnapshotUnavailable, err)
+	}
+	sw, err := snapshot.NewWriter(w, header)
+	if err != nil {
+		return nil, ids.Empty, 0, err
+	}
+	it := view.NewIterator()
+	defer it.Release()
+	var count int
+	for it.Next() {
+		if err := sw.Put(it.Key(), it.Value()); err != nil {
+			return nil, ids.Empty, 0, err
+		}
+		count++
+		if count%snapshotBatchSize == 0 {
+			vm.snowCtx.Log.Info("exported snapshot keys", zap.Int("count", count))
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, ids.Empty, 0, err
+	}
+	return header, blk.StateRoot, count, sw.Close()
+}
+
+// ExportSnapshotOffline initializes [vm] from the database of a stopped node
+// without modifying it and writes a snapshot at [height] (or at the last
+// accepted block if [height] is nil) to [w] (see [SnapshotHeader]). It returns
+// the header and root of the snapshot and the number of key-values written.
+// [vm] must not have been initialized.
+//
+// Unlike exporting over RPC, the export is not limited by
+// [StateHistoryLength] because no blocks are accepted during the export. The
+// state of the parent of the block at [height] must be available (see
+// [VM.Replay]), which is always the case for the last accepted block of nodes
+// in [ArchiveMode] or that did not execute it (after state sync or importing
+// a snapshot).
+func (vm *VM) ExportSnapshotOffline(
+	ctx context.Context,
+	cfg *ReplayConfig,
+	height *uint64,
+	w io.Writer,
+) (*snapshot.Header, ids.ID, int, error) {
+	scratchDir, err := os.MkdirTemp("", "hypersdk-snapshot")
+	if err != nil {
+		return nil, ids.Empty, 0, err
+	}
+	defer os.RemoveAll(scratchDir)
+
+	if err := vm.initializeOffline(ctx, cfg, scratchDir); err != nil {
+		return nil, ids.Empty, 0, err
+	}
+	header, root, count, err := vm.exportSnapshot(ctx, height, w)
+	return header, root, count, errors.Join(err, vm.Shutdown(ctx))
+}
+
+// importSnapshot initializes the state and blocks from the snapshot at [path]
+// (clearing any state that was already written). The snapshot must start at
+// [vm.genesisBlk].
+//
+// If the import is interrupted, it must be restarted with the same snapshot.
+func (vm *VM) importSnapshot(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r, header, err := snapshot.NewReader(f)
+	if err != nil {
+		return err
+	}
+
+	// Ensure the blocks in the snapshot form a chain
+	if len(header.Blocks) < 2 {
+		return fmt.Errorf("%w: missing blocks", snapshot.ErrInvalidSnapshot)
+	}
+	blocks := make([]*chain.StatelessBlock, len(header.Blocks))
+	for i, b := range header.Blocks {
+		blk, err := chain.ParseBlock(ctx, b, choices.Accepted, vm)
+		if err != nil {
+			return fmt.Errorf("%w: unable to parse block: %w", snapshot.ErrInvalidSnapshot, err)
+		}
+		switch {
+		case i == 0 && blk.ID() != vm.genesisBlk.ID():
+			return fmt.Errorf("%w: expected genesis %s but found %s", snapshot.ErrInvalidSnapshot, vm.genesisBlk.ID(), blk.ID())
+		case i > 0 && blk.Hght == blocks[i-1].Hght+1 && blk.Prnt != blocks[i-1].ID():
+			return fmt.Errorf("%w: block %d does not extend %d", snapshot.ErrInvalidSnapshot, blk.Hght, blocks[i-1].Hght)
+		case i > 0 && blk.Hght != blocks[i-1].Hght+1 && (i > 1 || blk.Hght == 0):
+			// Only the blocks between genesis and the validity window of the
+			// last block may be omitted.
+			return fmt.Errorf("%w: block %d does not extend %d", snapshot.ErrInvalidSnapshot, blk.Hght, blocks[i-1].Hght)
+		}
+		blocks[i] = blk
+	}
+	last := blocks[len(blocks)-1]
+	if last.Hght != header.Height {
+		return fmt.Errorf("%w: expected height %d but found %d", snapshot.ErrInvalidSnapshot, header.Height, last.Hght)
+	}
+
+	// Write state in batches
+	if err := vm.stateDB.Clear(); err != nil {
+		return err
+	}
+	var (
+		sps   = state.NewSimpleMutable(vm.stateDB)
+		count int
+	)
+	for {
+		k, v, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := sps.Insert(ctx, k, v); err != nil {
+			return err
+		}
+		count++
+		if count%snapshotBatchSize == 0 {
+			if err := sps.Commit(ctx); err != nil {
+				return err
+			}
+			sps = state.NewSimpleMutable(vm.stateDB)
+			vm.snowCtx.Log.Info("imported snapshot keys", zap.Int("count", count))
+		}
+	}
+	if err := sps.Commit(ctx); err != nil {
+		return err
+	}
+
+	// Verify the imported state matches the last block
+	root, err := vm.stateDB.GetMerkleRoot(ctx)
+	if err != nil {
+		return err
+	}
+	if root != last.StateRoot {
+		return fmt.Errorf("%w: expected=%s found=%s", ErrUnexpectedStateRoot, last.StateRoot, root)
+	}
+
+	// Store blocks and update last accepted
+	//
+	// Like after state sync, the last accepted block is not processed (we
+	// have the post-execution state of its parent), so it will be executed
+	// the first time we build or verify a child of it.
+	for _, blk := range blocks {
+		if err := vm.UpdateLastAccepted(blk); err != nil {
+			return err
+		}
+	}
+	vm.preferred, vm.lastAccepted = last.ID(), last
+	vm.snowCtx.Log.Info("imported snapshot",
+		zap.Uint64("height", last.Hght),
+		zap.Stringer("blockID", last.ID()),
+		zap.Stringer("root", root),
+		zap.Int("keys", count),
+	)
+	return nil
+}
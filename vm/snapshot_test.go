@@ -0,0 +1,184 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/cache"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/snapshot"
+	"github.com/ava-labs/hypersdk/trace"
+)
+
+func newTestSnapshotVM(t *testing.T) *VM {
+	tracer, err := trace.New(&trace.Config{Enabled: false})
+	require.NoError(t, err)
+	bByID, err := cache.NewFIFO[ids.ID, *chain.StatelessBlock](16)
+	require.NoError(t, err)
+	bByHeight, err := cache.NewFIFO[uint64, ids.ID](16)
+	require.NoError(t, err)
+	return &VM{
+		snowCtx: &snow.Context{Log: logging.NoLog{}},
+		config:  NewConfig(),
+		genesis: testGenesis{},
+		tracer:  tracer,
+		vmDB:    memdb.New(),
+		stateDB: newTestMerkleDB(t),
+
+		acceptedBlocksByID:     bByID,
+		acceptedBlocksByHeight: bByHeight,
+	}
+}
+
+func newTestBlock(t *testing.T, vm *VM, parent *chain.StatelessBlock, root ids.ID) *chain.StatelessBlock {
+	blk, err := chain.ParseStatefulBlock(
+		context.Background(),
+		&chain.StatefulBlock{
+			Prnt:      parent.ID(),
+			Tmstmp:    parent.Tmstmp + 1_000,
+			Hght:      parent.Hght + 1,
+			StateRoot: root,
+		},
+		nil,
+		choices.Accepted,
+		vm,
+	)
+	require.NoError(t, err)
+	return blk
+}
+
+// writeTestSnapshot writes a snapshot of [blocks] with the keys written by
+// [putTestKeys] and returns its path.
+func writeTestSnapshot(t *testing.T, blocks []*chain.StatelessBlock, keys int) string {
+	require := require.New(t)
+
+	header := &snapshot.Header{Height: blocks[len(blocks)-1].Hght}
+	for _, blk := range blocks {
+		header.Blocks = append(header.Blocks, blk.Bytes())
+	}
+	path := filepath.Join(t.TempDir(), "snapshot")
+	f, err := os.Create(path)
+	require.NoError(err)
+	defer f.Close()
+	w, err := snapshot.NewWriter(f, header)
+	require.NoError(err)
+	for i := 0; i < keys; i++ {
+		require.NoError(w.Put(testKey(i), []byte{byte(i), 0}))
+	}
+	require.NoError(w.Close())
+	return path
+}
+
+func TestImportSnapshot(t *testing.T) {
+	const keys = 100
+
+	// The root of the state in the snapshot
+	root := putTestKeys(t, newTestMerkleDB(t), keys, 0)
+
+	tests := []struct {
+		name   string
+		blocks func(vm *VM) []*chain.StatelessBlock
+		err    error
+	}{
+		{
+			name: "valid",
+			blocks: func(vm *VM) []*chain.StatelessBlock {
+				blk1 := newTestBlock(t, vm, vm.genesisBlk, ids.GenerateTestID())
+				return []*chain.StatelessBlock{vm.genesisBlk, blk1, newTestBlock(t, vm, blk1, root)}
+			},
+		},
+		{
+			name: "blocks before validity window omitted",
+			blocks: func(vm *VM) []*chain.StatelessBlock {
+				blk1 := newTestBlock(t, vm, vm.genesisBlk, ids.GenerateTestID())
+				blk2 := newTestBlock(t, vm, blk1, ids.GenerateTestID())
+				return []*chain.StatelessBlock{vm.genesisBlk, blk2, newTestBlock(t, vm, blk2, root)}
+			},
+		},
+		{
+			name: "foreign genesis",
+			blocks: func(vm *VM) []*chain.StatelessBlock {
+				genesis, err := chain.ParseStatefulBlock(
+					context.Background(),
+					chain.NewGenesisBlock(ids.GenerateTestID()),
+					nil,
+					choices.Accepted,
+					vm,
+				)
+				require.NoError(t, err)
+				blk1 := newTestBlock(t, vm, genesis, ids.GenerateTestID())
+				return []*chain.StatelessBlock{genesis, blk1, newTestBlock(t, vm, blk1, root)}
+			},
+			err: snapshot.ErrInvalidSnapshot,
+		},
+		{
+			name: "block 1 does not extend genesis",
+			blocks: func(vm *VM) []*chain.StatelessBlock {
+				blk1, err := chain.ParseStatefulBlock(
+					context.Background(),
+					&chain.StatefulBlock{
+						Prnt:      ids.GenerateTestID(),
+						Tmstmp:    vm.genesisBlk.Tmstmp + 1_000,
+						Hght:      1,
+						StateRoot: ids.GenerateTestID(),
+					},
+					nil,
+					choices.Accepted,
+					vm,
+				)
+				require.NoError(t, err)
+				return []*chain.StatelessBlock{vm.genesisBlk, blk1, newTestBlock(t, vm, blk1, root)}
+			},
+			err: snapshot.ErrInvalidSnapshot,
+		},
+		{
+			name: "gap after first block",
+			blocks: func(vm *VM) []*chain.StatelessBlock {
+				blk1 := newTestBlock(t, vm, vm.genesisBlk, ids.GenerateTestID())
+				blk2 := newTestBlock(t, vm, blk1, ids.GenerateTestID())
+				return []*chain.StatelessBlock{vm.genesisBlk, blk1, newTestBlock(t, vm, blk2, root)}
+			},
+			err: snapshot.ErrInvalidSnapshot,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			ctx := context.Background()
+
+			vm := newTestSnapshotVM(t)
+			genesisBlk, err := vm.loadGenesis(ctx)
+			require.NoError(err)
+			vm.genesisBlk = genesisBlk
+
+			blocks := tt.blocks(vm)
+			err = vm.importSnapshot(ctx, writeTestSnapshot(t, blocks, keys))
+			require.ErrorIs(err, tt.err)
+			if tt.err != nil {
+				return
+			}
+			last := blocks[len(blocks)-1]
+			require.Equal(last.ID(), vm.lastAccepted.ID())
+			require.Equal(genesisBlk, vm.genesisBlk)
+			imported, err := vm.stateDB.GetMerkleRoot(ctx)
+			require.NoError(err)
+			require.Equal(root, imported)
+
+			// Snapshots are only served over RPC if enabled
+			_, _, err = vm.SnapshotHeader(ctx, nil)
+			require.ErrorIs(err, ErrSnapshotsDisabled)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+)
+
+// stateRangePageSize is the number of key-values read at once by
+// [VM.IterateStateAtRoot].
+const stateRangePageSize = 2048
+
+// GetStateRange returns up to [limit] key-values in the state at [root],
+// starting at [start]. If there may be more key-values, it also returns the
+// key that should be provided as [start] to fetch them.
+//
+// The state at [root] is only available if it is the current state or within
+// [StateHistoryLength].
+func (vm *VM) GetStateRange(
+	ctx context.Context,
+	root ids.ID,
+	start maybe.Maybe[[]byte],
+	limit int,
+) ([]merkledb.KeyValue, maybe.Maybe[[]byte], error) {
+	proof, err := vm.stateDB.GetRangeProofAtRoot(ctx, root, start, maybe.Nothing[[]byte](), limit)
+	if err != nil {
+		return nil, maybe.Nothing[[]byte](), err
+	}
+	kvs := proof.KeyValues
+	if len(kvs) < limit {
+		return kvs, maybe.Nothing[[]byte](), nil
+	}
+	return kvs, maybe.Some(after(kvs[len(kvs)-1].Key)), nil
+}
+
+// IterateStateAtRoot calls [f] with all key-values in the state at [root] (in
+// key order) without loading all of them into memory at once.
+func (vm *VM) IterateStateAtRoot(ctx context.Context, root ids.ID, f func(k []byte, v []byte) error) error {
+	start := maybe.Nothing[[]byte]()
+	for {
+		kvs, next, err := vm.GetStateRange(ctx, root, start, stateRangePageSize)
+		if err != nil {
+			return err
+		}
+		for _, kv := range kvs {
+			if err := f(kv.Key, kv.Value); err != nil {
+				return err
+			}
+		}
+		if next.IsNothing() {
+			return nil
+		}
+		start = next
+	}
+}
@@ -37,6 +37,7 @@ const (
 
 var (
 	isSyncing    = []byte("is_syncing")
+	syncState    = []byte("sync_state")
 	lastAccepted = []byte("last_accepted")
 )
 
@@ -211,3 +212,21 @@ func (vm *VM) PutDiskIsSyncing(v bool) error {
 	}
 	return vm.vmDB.Put(isSyncing, []byte{0x0})
 }
+
+// GetDiskSyncProgress returns the persisted progress of an ongoing state sync
+// (or nil if there is none).
+func (vm *VM) GetDiskSyncProgress() ([]byte, error) {
+	v, err := vm.vmDB.Get(syncState)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	return v, err
+}
+
+func (vm *VM) PutDiskSyncProgress(v []byte) error {
+	return vm.vmDB.Put(syncState, v)
+}
+
+func (vm *VM) DeleteDiskSyncProgress() error {
+	return vm.vmDB.Delete(syncState)
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -20,9 +21,20 @@ import (
 )
 
 type stateSyncerClient struct {
-	vm          *VM
-	gatherer    avametrics.MultiGatherer
+	vm         *VM
+	gatherer   avametrics.MultiGatherer
+	syncClient avasync.Client
+
+	// [syncLock] protects [syncManager] (which is replaced if we fall back to
+	// a full sync), [restarted], [closing], and [target].
+	syncLock    sync.Mutex
 	syncManager *avasync.Manager
+	restarted   chan struct{} // closed when [syncManager] is replaced
+	closing     bool
+
+	// tracks the ranges of state that have been synced so
+	// we can resume if interrupted.
+	progress *syncProgress
 
 	// tracks the sync target so we can update last accepted
 	// block when sync completes.
@@ -44,6 +56,7 @@ func (vm *VM) NewStateSyncClient(
 	return &stateSyncerClient{
 		vm:       vm,
 		gatherer: gatherer,
+		progress: newSyncProgress(vm),
 		done:     make(chan struct{}),
 	}
 }
@@ -56,15 +69,30 @@ func (*stateSyncerClient) StateSyncEnabled(context.Context) (bool, error) {
 	return true, nil
 }
 
-func (*stateSyncerClient) GetOngoingSyncStateSummary(
-	context.Context,
+func (s *stateSyncerClient) GetOngoingSyncStateSummary(
+	ctx context.Context,
 ) (block.StateSummary, error) {
-	// Because the history of MerkleDB change proofs tends to be short, we always
-	// restart syncing from scratch.
+	// If we were interrupted while syncing, we return the last target we
+	// were syncing to so that we can continue from where we left off.
 	//
-	// This is unlike other DB implementations where roots are persisted
-	// indefinitely (and it means we can continue from where we left off).
-	return nil, database.ErrNotFound
+	// If the engine instead selects a newer summary, we still resume from the
+	// ranges we already synced (only fetching the changes made to them since).
+	syncing, err := s.vm.GetDiskIsSyncing()
+	if err != nil {
+		return nil, err
+	}
+	if !syncing {
+		return nil, database.ErrNotFound
+	}
+	ok, err := s.progress.load()
+	if err != nil {
+		s.vm.snowCtx.Log.Warn("unable to load sync progress", zap.Error(err))
+		return nil, database.ErrNotFound
+	}
+	if !ok || len(s.progress.Target()) == 0 {
+		return nil, database.ErrNotFound
+	}
+	return s.vm.ParseStateSummary(ctx, s.progress.Target())
 }
 
 func (s *stateSyncerClient) AcceptedSyncableBlock(
@@ -83,7 +111,9 @@ func (s *stateSyncerClient) AcceptedSyncableBlock(
 		s.vm.snowCtx.Log.Warn("could not determine if syncing", zap.Error(err))
 		return block.StateSyncSkipped, err
 	}
-	if !syncing && (s.vm.lastAccepted.Hght+s.vm.config.StateSyncMinBlocks > sb.Height()) {
+
+	// Archive nodes must execute every block, so they never state sync.
+	if !syncing && (s.vm.config.ArchiveMode || s.vm.lastAccepted.Hght+s.vm.config.StateSyncMinBlocks > sb.Height()) {
 		s.vm.snowCtx.Log.Info(
 			"bypassing state sync",
 			zap.Uint64("lastAccepted", s.vm.lastAccepted.Hght),
@@ -105,11 +135,24 @@ func (s *stateSyncerClient) AcceptedSyncableBlock(
 		return block.StateSyncDynamic, nil
 	}
 
-	// When state syncing after restart (whether successful or not), we restart
-	// from scratch.
+	// When state syncing after restart, we resume from any ranges we synced
+	// before we were interrupted (regardless of whether [sb] is the target
+	// we were previously syncing to). Ranges synced to an older root are
+	// caught up using change proofs and are only synced from scratch if peers
+	// no longer serve that root.
 	//
 	// MerkleDB will handle clearing any keys on-disk that are no
 	// longer necessary.
+	if syncing {
+		if _, err := s.progress.load(); err != nil {
+			s.vm.snowCtx.Log.Warn("unable to load sync progress", zap.Error(err))
+			if err := s.progress.Delete(); err != nil {
+				return block.StateSyncSkipped, err
+			}
+		}
+	} else if err := s.progress.Delete(); err != nil {
+		return block.StateSyncSkipped, err
+	}
 	s.target = sb.StatelessBlock
 	s.vm.snowCtx.Log.Info(
 		"starting state sync",
@@ -138,14 +181,8 @@ func (s *stateSyncerClient) AcceptedSyncableBlock(
 	if err != nil {
 		return block.StateSyncSkipped, err
 	}
-	s.syncManager, err = avasync.NewManager(avasync.ManagerConfig{
-		BranchFactor:          s.vm.genesis.GetStateBranchFactor(),
-		DB:                    s.vm.stateDB,
-		Client:                syncClient,
-		SimultaneousWorkLimit: s.vm.config.StateSyncParallelism,
-		Log:                   s.vm.snowCtx.Log,
-		TargetRoot:            sb.StateRoot,
-	})
+	s.syncClient = syncClient
+	syncManager, err := s.newSyncManager(sb.StateRoot)
 	if err != nil {
 		return block.StateSyncSkipped, err
 	}
@@ -162,6 +199,9 @@ func (s *stateSyncerClient) AcceptedSyncableBlock(
 	if err := s.vm.PutDiskIsSyncing(true); err != nil {
 		return block.StateSyncSkipped, err
 	}
+	if err := s.progress.SetTarget(s.target.Bytes()); err != nil {
+		return block.StateSyncSkipped, err
+	}
 
 	// Update the last accepted to the state target block,
 	// since we don't want bootstrapping to fetch all the blocks
@@ -169,16 +209,51 @@ func (s *stateSyncerClient) AcceptedSyncableBlock(
 	s.target.MarkAccepted(context.Background())
 
 	// Kickoff state syncing from [s.target]
-	if err := s.syncManager.Start(context.Background()); err != nil {
+	if err := s.startSync(syncManager); err != nil {
 		s.vm.snowCtx.Log.Warn("not starting state syncing", zap.Error(err))
 		return block.StateSyncSkipped, err
 	}
+	// TODO: engine will mark VM as ready when we return
+	// [block.StateSyncDynamic]. This should change in v1.9.11.
+	return block.StateSyncDynamic, nil
+}
+
+// newSyncManager returns a manager that syncs the state trie to [root],
+// resuming from any ranges in [s.progress].
+func (s *stateSyncerClient) newSyncManager(root ids.ID) (*avasync.Manager, error) {
+	resumeClient := newResumeClient(s.syncClient, s.vm.stateDB, s.progress, s.vm.snowCtx.Log)
+	return avasync.NewManager(avasync.ManagerConfig{
+		BranchFactor: s.vm.genesis.GetStateBranchFactor(),
+		DB: &progressDB{
+			DB:       s.vm.stateDB,
+			progress: s.progress,
+			client:   resumeClient,
+			log:      s.vm.snowCtx.Log,
+		},
+		Client:                resumeClient,
+		SimultaneousWorkLimit: s.vm.config.StateSyncParallelism,
+		Log:                   s.vm.snowCtx.Log,
+		TargetRoot:            root,
+	})
+}
+
+// startSync starts [syncManager] and closes [s.done] once it is done.
+func (s *stateSyncerClient) startSync(syncManager *avasync.Manager) error {
+	if err := syncManager.Start(context.Background()); err != nil {
+		return err
+	}
+	s.syncLock.Lock()
+	s.syncManager = syncManager
+	s.restarted = make(chan struct{})
+	s.syncLock.Unlock()
+
+	resumed := s.progress.Resumed()
 	go func() {
 		// wait for the work to complete on this goroutine
 		//
 		// [syncManager] guarantees this will always return so it isn't possible to
 		// deadlock.
-		s.stateSyncErr = s.syncManager.Wait(context.Background())
+		s.stateSyncErr = s.wait(syncManager, resumed)
 		s.vm.snowCtx.Log.Info("state sync done", zap.Error(s.stateSyncErr))
 		if s.stateSyncErr == nil {
 			// if the sync was successful, update the last accepted pointers.
@@ -193,9 +268,55 @@ func (s *stateSyncerClient) AcceptedSyncableBlock(
 			close(s.done)
 		})
 	}()
-	// TODO: engine will mark VM as ready when we return
-	// [block.StateSyncDynamic]. This should change in v1.9.11.
-	return block.StateSyncDynamic, nil
+	return nil
+}
+
+// wait waits for [syncManager] to finish.
+//
+// If the sync fails for any reason other than [Shutdown], the synced ranges
+// can't be trusted (i.e. a range resumed from disk was inconsistent with its
+// recorded root), so we clear them. If we [resumed] from synced ranges, we
+// then fall back to a full sync (once) instead of returning the error.
+func (s *stateSyncerClient) wait(syncManager *avasync.Manager, resumed bool) error {
+	for {
+		err := syncManager.Wait(context.Background())
+		if err == nil {
+			return nil
+		}
+
+		s.syncLock.Lock()
+		if s.closing {
+			// The sync was interrupted, so we keep the synced ranges and resume
+			// from them on restart.
+			s.syncLock.Unlock()
+			return err
+		}
+		if rerr := s.progress.Reset(); rerr != nil {
+			s.syncLock.Unlock()
+			return errors.Join(err, rerr)
+		}
+		if !resumed {
+			s.syncLock.Unlock()
+			return err
+		}
+		resumed = false
+		s.vm.snowCtx.Log.Warn("unable to resume state sync, falling back to full sync",
+			zap.Stringer("root", s.target.StateRoot),
+			zap.Error(err),
+		)
+		syncManager, err = s.newSyncManager(s.target.StateRoot)
+		if err == nil {
+			err = syncManager.Start(context.Background())
+		}
+		if err != nil {
+			s.syncLock.Unlock()
+			return err
+		}
+		s.syncManager = syncManager
+		close(s.restarted)
+		s.restarted = make(chan struct{})
+		s.syncLock.Unlock()
+	}
 }
 
 // finishSync is responsible for updating disk and memory pointers
@@ -208,7 +329,13 @@ func (s *stateSyncerClient) finishSync() error {
 		// block.
 		s.target.MarkAccepted(context.Background())
 	}
-	return s.vm.PutDiskIsSyncing(false)
+	if err := s.vm.PutDiskIsSyncing(false); err != nil {
+		return err
+	}
+	if err := s.progress.Delete(); err != nil {
+		return err
+	}
+	return s.vm.stateLoaded(context.Background())
 }
 
 func (s *stateSyncerClient) Started() bool {
@@ -230,8 +357,13 @@ func (s *stateSyncerClient) ForceDone() {
 
 // Shutdown can be called to abort an ongoing sync.
 func (s *stateSyncerClient) Shutdown() error {
-	if s.syncManager != nil {
-		s.syncManager.Close()
+	s.syncLock.Lock()
+	s.closing = true // don't fall back to a full sync once closed
+	syncManager := s.syncManager
+	s.syncLock.Unlock()
+
+	if syncManager != nil {
+		syncManager.Close()
 		<-s.done // wait for goroutine to exit
 	}
 	return s.stateSyncErr // will be nil if [syncManager] is nil
@@ -252,21 +384,39 @@ func (s *stateSyncerClient) StateReady() bool {
 		return false
 	}
 	// Cover the case where initialization failed
+	s.syncLock.Lock()
+	defer s.syncLock.Unlock()
 	return s.syncManager == nil
 }
 
 // UpdateSyncTarget returns a boolean indicating if the root was
 // updated and an error if one occurred while updating the root.
 func (s *stateSyncerClient) UpdateSyncTarget(b *chain.StatelessBlock) (bool, error) {
+	s.syncLock.Lock()
 	err := s.syncManager.UpdateSyncTarget(b.StateRoot)
 	if errors.Is(err, avasync.ErrAlreadyClosed) {
-		<-s.done          // Wait for goroutine to exit for consistent return values with IsSyncing
-		return false, nil // Sync finished before update
+		restarted := s.restarted
+		s.syncLock.Unlock()
+
+		// Wait for goroutine to exit for consistent return values with IsSyncing
+		// (or for it to fall back to a full sync, which we can then update).
+		select {
+		case <-s.done:
+			return false, nil // Sync finished before update
+		case <-restarted:
+			return s.UpdateSyncTarget(b)
+		}
 	}
+	defer s.syncLock.Unlock()
 	if err != nil {
 		return false, err // Unexpected error
 	}
 	s.target = b           // Remember the new target
 	s.targetUpdated = true // Set [targetUpdated] so we call SetLastAccepted on finish
-	return true, nil       // Sync root target updated successfully
+
+	// Persist the new target so we resume syncing to it if interrupted
+	if err := s.progress.SetTarget(b.Bytes()); err != nil {
+		return false, err
+	}
+	return true, nil // Sync root target updated successfully
 }
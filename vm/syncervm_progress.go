@@ -0,0 +1,595 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+
+	pb "github.com/ava-labs/avalanchego/proto/pb/sync"
+	avasync "github.com/ava-labs/avalanchego/x/sync"
+)
+
+var (
+	_ avasync.DB     = (*progressDB)(nil)
+	_ avasync.Client = (*resumeClient)(nil)
+)
+
+// syncRange is a range of keys [start, end) that is stored in the state
+// trie exactly as it is in the trie with [root]. A Nothing [start] is the
+// smallest key and a Nothing [end] is after the largest key.
+type syncRange struct {
+	start maybe.Maybe[[]byte]
+	end   maybe.Maybe[[]byte]
+	root  ids.ID
+}
+
+// contains returns true if [k] is in [r].
+func (r *syncRange) contains(k []byte) bool {
+	if r.start.HasValue() && bytes.Compare(k, r.start.Value()) < 0 {
+		return false
+	}
+	return r.end.IsNothing() || bytes.Compare(k, r.end.Value()) < 0
+}
+
+// compareStart compares two range starts (where Nothing is the smallest).
+func compareStart(a, b maybe.Maybe[[]byte]) int {
+	switch {
+	case a.IsNothing() && b.IsNothing():
+		return 0
+	case a.IsNothing():
+		return -1
+	case b.IsNothing():
+		return 1
+	default:
+		return bytes.Compare(a.Value(), b.Value())
+	}
+}
+
+// compareEnd compares two range ends (where Nothing is the largest).
+func compareEnd(a, b maybe.Maybe[[]byte]) int {
+	switch {
+	case a.IsNothing() && b.IsNothing():
+		return 0
+	case a.IsNothing():
+		return 1
+	case b.IsNothing():
+		return -1
+	default:
+		return bytes.Compare(a.Value(), b.Value())
+	}
+}
+
+// after returns the smallest key that is larger than [k].
+func after(k []byte) []byte {
+	next := make([]byte, len(k)+1)
+	copy(next, k)
+	return next
+}
+
+// syncProgress tracks which ranges of the state trie were synced (and to
+// which root) so that an interrupted sync can resume where it left off.
+//
+// Ranges are only recorded after they are committed to the state trie, so
+// a crash can never cause us to skip a range that was not synced.
+type syncProgress struct {
+	vm *VM
+
+	l      sync.Mutex
+	target []byte // bytes of the target block
+	ranges []*syncRange
+}
+
+func newSyncProgress(vm *VM) *syncProgress {
+	return &syncProgress{vm: vm}
+}
+
+// load reads the last persisted progress from disk. It returns false if there
+// is no progress to resume from.
+func (p *syncProgress) load() (bool, error) {
+	b, err := p.vm.GetDiskSyncProgress()
+	if err != nil {
+		return false, err
+	}
+	if len(b) == 0 {
+		return false, nil
+	}
+	r := codec.NewReader(b, consts.MaxInt)
+	var target []byte
+	r.UnpackBytes(-1, true, &target)
+	ranges := make([]*syncRange, r.UnpackInt(false))
+	for i := range ranges {
+		ranges[i] = &syncRange{
+			start: unpackMaybeBytes(r),
+			end:   unpackMaybeBytes(r),
+		}
+		r.UnpackID(true, &ranges[i].root)
+	}
+	if err := r.Err(); err != nil {
+		return false, err
+	}
+
+	p.l.Lock()
+	defer p.l.Unlock()
+	p.target = target
+	p.ranges = ranges
+	return true, nil
+}
+
+// Target returns the bytes of the persisted target block (if any).
+func (p *syncProgress) Target() []byte {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	return p.target
+}
+
+// Resumed returns true if there are synced ranges to resume from.
+func (p *syncProgress) Resumed() bool {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	return len(p.ranges) > 0
+}
+
+// SetTarget persists [target] as the block we are currently syncing to.
+func (p *syncProgress) SetTarget(target []byte) error {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	p.target = target
+	return p.persist()
+}
+
+// Reset clears all progress (because the state trie was cleared).
+func (p *syncProgress) Reset() error {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	p.ranges = nil
+	return p.persist()
+}
+
+// Delete removes all progress from disk once sync is complete.
+func (p *syncProgress) Delete() error {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	p.target = nil
+	p.ranges = nil
+	return p.vm.DeleteDiskSyncProgress()
+}
+
+// Find returns the range that contains [k] (if any).
+func (p *syncProgress) Find(k []byte) (*syncRange, bool) {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	for _, r := range p.ranges {
+		if r.contains(k) {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// Drop removes [r] from the synced ranges (i.e. if we could not catch it up).
+func (p *syncProgress) Drop(r *syncRange) error {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	for i, pr := range p.ranges {
+		if pr == r {
+			p.ranges = append(p.ranges[:i], p.ranges[i+1:]...)
+			return p.persist()
+		}
+	}
+	return nil
+}
+
+// Record marks [start, end) as synced to [root], replacing any overlapping
+// ranges.
+func (p *syncProgress) Record(start maybe.Maybe[[]byte], end maybe.Maybe[[]byte], root ids.ID) error {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	ranges := make([]*syncRange, 0, len(p.ranges)+2)
+	for _, r := range p.ranges {
+		// Keep ranges that don't overlap
+		if (r.end.HasValue() && compareStart(r.end, start) <= 0) || (end.HasValue() && compareStart(end, r.start) <= 0) {
+			ranges = append(ranges, r)
+			continue
+		}
+		// Keep the parts of overlapping ranges that are outside of [start, end)
+		if compareStart(r.start, start) < 0 {
+			ranges = append(ranges, &syncRange{r.start, start, r.root})
+		}
+		if compareEnd(end, r.end) < 0 {
+			ranges = append(ranges, &syncRange{end, r.end, r.root})
+		}
+	}
+	ranges = append(ranges, &syncRange{start, end, root})
+	sort.Slice(ranges, func(i, j int) bool {
+		return compareStart(ranges[i].start, ranges[j].start) < 0
+	})
+
+	// Merge adjacent ranges synced to the same root
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := merged[len(merged)-1]
+		if last.root == r.root && last.end.HasValue() && r.start.HasValue() && bytes.Equal(last.end.Value(), r.start.Value()) {
+			merged[len(merged)-1] = &syncRange{last.start, r.end, r.root}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	p.ranges = merged
+	return p.persist()
+}
+
+// persist assumes [p.l] is held.
+func (p *syncProgress) persist() error {
+	w := codec.NewWriter(0, consts.MaxInt)
+	w.PackBytes(p.target)
+	w.PackInt(len(p.ranges))
+	for _, r := range p.ranges {
+		packMaybeBytes(w, r.start)
+		packMaybeBytes(w, r.end)
+		w.PackID(r.root)
+	}
+	if err := w.Err(); err != nil {
+		return err
+	}
+	return p.vm.PutDiskSyncProgress(w.Bytes())
+}
+
+func packMaybeBytes(p *codec.Packer, m maybe.Maybe[[]byte]) {
+	p.PackBool(m.HasValue())
+	if m.HasValue() {
+		p.PackBytes(m.Value())
+	}
+}
+
+func unpackMaybeBytes(p *codec.Packer) maybe.Maybe[[]byte] {
+	if !p.UnpackBool() {
+		return maybe.Nothing[[]byte]()
+	}
+	var b []byte
+	p.UnpackBytes(-1, false, &b)
+	return maybe.Some(b)
+}
+
+func fromMaybeBytes(m *pb.MaybeBytes) maybe.Maybe[[]byte] {
+	if m == nil || m.IsNothing {
+		return maybe.Nothing[[]byte]()
+	}
+	return maybe.Some(m.Value)
+}
+
+// pendingProof is a proof returned by [resumeClient] that has not yet been
+// committed.
+type pendingProof struct {
+	root  ids.ID
+	start maybe.Maybe[[]byte]
+	end   maybe.Maybe[[]byte] // inclusive
+}
+
+// progressDB records the ranges that are committed by [avasync.Manager].
+type progressDB struct {
+	avasync.DB
+
+	progress *syncProgress
+	client   *resumeClient
+	log      logging.Logger
+}
+
+func (d *progressDB) Clear() error {
+	if err := d.DB.Clear(); err != nil {
+		return err
+	}
+	return d.progress.Reset()
+}
+
+func (d *progressDB) CommitRangeProof(
+	ctx context.Context,
+	start maybe.Maybe[[]byte],
+	end maybe.Maybe[[]byte],
+	proof *merkledb.RangeProof,
+) error {
+	if err := d.DB.CommitRangeProof(ctx, start, end, proof); err != nil {
+		return err
+	}
+	pending, ok := d.client.popRangeProof(proof)
+	if !ok {
+		return nil
+	}
+	// The committed range ends at the last key in the proof (or at [end] if
+	// there are no keys).
+	largest := end
+	if len(proof.KeyValues) > 0 {
+		largest = maybe.Some(proof.KeyValues[len(proof.KeyValues)-1].Key)
+	}
+	return d.record(start, largest, pending.root)
+}
+
+func (d *progressDB) CommitChangeProof(ctx context.Context, proof *merkledb.ChangeProof) error {
+	if err := d.DB.CommitChangeProof(ctx, proof); err != nil {
+		return err
+	}
+	pending, ok := d.client.popChangeProof(proof)
+	if !ok {
+		return nil
+	}
+	largest := pending.end
+	if len(proof.KeyChanges) > 0 {
+		largest = maybe.Some(proof.KeyChanges[len(proof.KeyChanges)-1].Key)
+	}
+	return d.record(pending.start, largest, pending.root)
+}
+
+func (d *progressDB) record(start maybe.Maybe[[]byte], largest maybe.Maybe[[]byte], root ids.ID) error {
+	end := maybe.Bind(largest, after)
+	if err := d.progress.Record(start, end, root); err != nil {
+		// Failing to persist progress only means that we may need to sync more
+		// on restart.
+		d.log.Warn("unable to persist sync progress", zap.Error(err))
+	}
+	return nil
+}
+
+// resumeClient serves requests for ranges that were synced before a restart
+// from the local state trie (only fetching the changes since they were synced
+// from the network) and forwards all other requests to [avasync.Client].
+type resumeClient struct {
+	avasync.Client
+
+	db       merkledb.MerkleDB
+	progress *syncProgress
+	log      logging.Logger
+
+	l             sync.Mutex
+	pendingRanges map[*merkledb.RangeProof]*pendingProof
+	pendingChange map[*merkledb.ChangeProof]*pendingProof
+}
+
+func newResumeClient(client avasync.Client, db merkledb.MerkleDB, progress *syncProgress, log logging.Logger) *resumeClient {
+	return &resumeClient{
+		Client:        client,
+		db:            db,
+		progress:      progress,
+		log:           log,
+		pendingRanges: map[*merkledb.RangeProof]*pendingProof{},
+		pendingChange: map[*merkledb.ChangeProof]*pendingProof{},
+	}
+}
+
+func (c *resumeClient) GetRangeProof(
+	ctx context.Context,
+	request *pb.SyncGetRangeProofRequest,
+) (*merkledb.RangeProof, error) {
+	root, err := ids.ToID(request.RootHash)
+	if err != nil {
+		return nil, err
+	}
+	start := fromMaybeBytes(request.StartKey)
+	proof, err := c.getLocalRangeProof(ctx, root, start, fromMaybeBytes(request.EndKey), int(request.KeyLimit), request.BytesLimit)
+	if err != nil {
+		return nil, err
+	}
+	if proof == nil {
+		proof, err = c.Client.GetRangeProof(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+	}
+	c.l.Lock()
+	c.pendingRanges[proof] = &pendingProof{root: root}
+	c.l.Unlock()
+	return proof, nil
+}
+
+func (c *resumeClient) GetChangeProof(
+	ctx context.Context,
+	request *pb.SyncGetChangeProofRequest,
+	verificationDB avasync.DB,
+) (*merkledb.ChangeOrRangeProof, error) {
+	proof, err := c.Client.GetChangeProof(ctx, request, verificationDB)
+	if err != nil {
+		return nil, err
+	}
+	root, err := ids.ToID(request.EndRootHash)
+	if err != nil {
+		return nil, err
+	}
+	pending := &pendingProof{
+		root:  root,
+		start: fromMaybeBytes(request.StartKey),
+		end:   fromMaybeBytes(request.EndKey),
+	}
+	c.l.Lock()
+	defer c.l.Unlock()
+	if proof.ChangeProof != nil {
+		c.pendingChange[proof.ChangeProof] = pending
+	} else {
+		c.pendingRanges[proof.RangeProof] = pending
+	}
+	return proof, nil
+}
+
+// getLocalRangeProof returns the key-values at [root] starting at [start]
+// using a range that was synced before a restart. It returns nil if the
+// request can't be served locally.
+//
+// The returned proof has no proof nodes, so [avasync.Manager] will continue
+// to request keys after the last key we return.
+func (c *resumeClient) getLocalRangeProof(
+	ctx context.Context,
+	root ids.ID,
+	start maybe.Maybe[[]byte],
+	end maybe.Maybe[[]byte],
+	keyLimit int,
+	bytesLimit uint32,
+) (*merkledb.RangeProof, error) {
+	startKey := start.Value() // empty if Nothing
+	r, ok := c.progress.Find(startKey)
+	if !ok {
+		return nil, nil
+	}
+
+	// Read the keys we synced that were requested
+	limit := r.end
+	if end.HasValue() && compareEnd(maybe.Some(after(end.Value())), limit) < 0 {
+		limit = maybe.Some(after(end.Value()))
+	}
+	kvs, err := c.readLocal(startKey, limit, keyLimit, int(bytesLimit))
+	if err != nil {
+		return nil, err
+	}
+	if len(kvs) == 0 {
+		// If there are no keys, we can't tell [avasync.Manager] how much of
+		// the range we covered.
+		return nil, nil
+	}
+	if r.root == root {
+		return &merkledb.RangeProof{KeyValues: kvs}, nil
+	}
+
+	// Fetch the changes to the keys we read since they were synced
+	largest := kvs[len(kvs)-1].Key
+	proof, err := c.Client.GetChangeProof(
+		ctx,
+		&pb.SyncGetChangeProofRequest{
+			StartRootHash: r.root[:],
+			EndRootHash:   root[:],
+			StartKey:      &pb.MaybeBytes{Value: start.Value(), IsNothing: start.IsNothing()},
+			EndKey:        &pb.MaybeBytes{Value: largest},
+			KeyLimit:      uint32(keyLimit),
+			BytesLimit:    bytesLimit,
+		},
+		c.db,
+	)
+	if err != nil {
+		// If the local range is inconsistent with [r.root] (i.e. we crashed
+		// before recording that it was updated), we will fail to verify the
+		// change proof and must sync the range from scratch.
+		c.log.Info("unable to resume synced range",
+			zap.Stringer("start", r.start),
+			zap.Stringer("end", r.end),
+			zap.Stringer("root", r.root),
+			zap.Error(err),
+		)
+		return nil, c.progress.Drop(r)
+	}
+	if proof.RangeProof != nil {
+		// The root we synced is no longer in the history of the server, so
+		// the server sent the keys at [root] instead.
+		if len(proof.RangeProof.KeyValues) == 0 {
+			return nil, nil
+		}
+		return &merkledb.RangeProof{KeyValues: proof.RangeProof.KeyValues}, nil
+	}
+	changes := proof.ChangeProof.KeyChanges
+	if len(changes) > 0 {
+		// Changes are only proven up to the last change we received
+		largest = changes[len(changes)-1].Key
+	}
+	updated := applyChanges(kvs, changes, largest)
+	if len(updated) == 0 {
+		return nil, nil
+	}
+	return &merkledb.RangeProof{KeyValues: updated}, nil
+}
+
+// readLocal returns up to [keyLimit] key-values in [start, end).
+func (c *resumeClient) readLocal(start []byte, end maybe.Maybe[[]byte], keyLimit int, bytesLimit int) ([]merkledb.KeyValue, error) {
+	it := c.db.NewIteratorWithStart(start)
+	defer it.Release()
+
+	var (
+		kvs  []merkledb.KeyValue
+		size int
+	)
+	for it.Next() && len(kvs) < keyLimit && size < bytesLimit {
+		if end.HasValue() && bytes.Compare(it.Key(), end.Value()) >= 0 {
+			break
+		}
+		kv := merkledb.KeyValue{
+			Key:   bytes.Clone(it.Key()),
+			Value: bytes.Clone(it.Value()),
+		}
+		kvs = append(kvs, kv)
+		size += len(kv.Key) + len(kv.Value)
+	}
+	return kvs, it.Error()
+}
+
+// applyChanges returns the key-values in [kvs] that are <= [largest] after
+// applying [changes].
+func applyChanges(kvs []merkledb.KeyValue, changes []merkledb.KeyChange, largest []byte) []merkledb.KeyValue {
+	updated := make([]merkledb.KeyValue, 0, len(kvs)+len(changes))
+	i, j := 0, 0
+	for i < len(kvs) || j < len(changes) {
+		var cmp int
+		switch {
+		case i == len(kvs):
+			cmp = 1
+		case j == len(changes):
+			cmp = -1
+		default:
+			cmp = bytes.Compare(kvs[i].Key, changes[j].Key)
+		}
+		var (
+			kv      merkledb.KeyValue
+			include bool
+		)
+		switch {
+		case cmp < 0:
+			kv, include = kvs[i], true
+			i++
+		default:
+			if cmp == 0 {
+				i++
+			}
+			kv = merkledb.KeyValue{Key: changes[j].Key, Value: changes[j].Value.Value()}
+			include = changes[j].Value.HasValue()
+			j++
+		}
+		if bytes.Compare(kv.Key, largest) > 0 {
+			break
+		}
+		if include {
+			updated = append(updated, kv)
+		}
+	}
+	return updated
+}
+
+func (c *resumeClient) popRangeProof(proof *merkledb.RangeProof) (*pendingProof, bool) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	pending, ok := c.pendingRanges[proof]
+	delete(c.pendingRanges, proof)
+	return pending, ok
+}
+
+func (c *resumeClient) popChangeProof(proof *merkledb.ChangeProof) (*pendingProof, bool) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	pending, ok := c.pendingChange[proof]
+	delete(c.pendingChange, proof)
+	return pending, ok
+}
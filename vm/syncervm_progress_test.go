@@ -0,0 +1,340 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/maybe"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/trace"
+
+	pb "github.com/ava-labs/avalanchego/proto/pb/sync"
+	avatrace "github.com/ava-labs/avalanchego/trace"
+	avasync "github.com/ava-labs/avalanchego/x/sync"
+)
+
+func TestSyncProgress(t *testing.T) {
+	require := require.New(t)
+
+	vm := &VM{vmDB: memdb.New()}
+	p := newSyncProgress(vm)
+	ok, err := p.load()
+	require.NoError(err)
+	require.False(ok)
+
+	rootA, rootB := ids.GenerateTestID(), ids.GenerateTestID()
+	require.NoError(p.SetTarget([]byte("target")))
+	require.NoError(p.Record(maybe.Nothing[[]byte](), maybe.Some([]byte{0x10}), rootA))
+	require.NoError(p.Record(maybe.Some([]byte{0x10}), maybe.Some([]byte{0x20}), rootA))
+	require.NoError(p.Record(maybe.Some([]byte{0x18}), maybe.Nothing[[]byte](), rootB))
+
+	// Adjacent ranges with the same root are merged and overlapping ranges
+	// are trimmed
+	r, ok := p.Find([]byte{0x00})
+	require.True(ok)
+	require.True(r.start.IsNothing())
+	require.Equal([]byte{0x18}, r.end.Value())
+	require.Equal(rootA, r.root)
+	r, ok = p.Find([]byte{0xff})
+	require.True(ok)
+	require.Equal([]byte{0x18}, r.start.Value())
+	require.True(r.end.IsNothing())
+	require.Equal(rootB, r.root)
+
+	// Progress is restored after restart
+	p2 := newSyncProgress(vm)
+	ok, err = p2.load()
+	require.NoError(err)
+	require.True(ok)
+	require.Equal([]byte("target"), p2.Target())
+	require.Len(p2.ranges, 2)
+
+	// Dropped ranges are no longer found
+	r, ok = p2.Find([]byte{0xff})
+	require.True(ok)
+	require.NoError(p2.Drop(r))
+	_, ok = p2.Find([]byte{0xff})
+	require.False(ok)
+
+	require.NoError(p2.Delete())
+	ok, err = newSyncProgress(vm).load()
+	require.NoError(err)
+	require.False(ok)
+}
+
+func TestApplyChanges(t *testing.T) {
+	require := require.New(t)
+
+	kvs := []merkledb.KeyValue{
+		{Key: []byte{0x01}, Value: []byte{0x01}},
+		{Key: []byte{0x02}, Value: []byte{0x02}},
+		{Key: []byte{0x04}, Value: []byte{0x04}},
+		{Key: []byte{0x06}, Value: []byte{0x06}},
+	}
+	changes := []merkledb.KeyChange{
+		{Key: []byte{0x02}, Value: maybe.Nothing[[]byte]()},
+		{Key: []byte{0x03}, Value: maybe.Some([]byte{0x03})},
+		{Key: []byte{0x04}, Value: maybe.Some([]byte{0x05})},
+	}
+	require.Equal(
+		[]merkledb.KeyValue{
+			{Key: []byte{0x01}, Value: []byte{0x01}},
+			{Key: []byte{0x03}, Value: []byte{0x03}},
+			{Key: []byte{0x04}, Value: []byte{0x05}},
+		},
+		applyChanges(kvs, changes, []byte{0x04}),
+	)
+}
+
+type testGenesis struct{}
+
+func (testGenesis) Load(context.Context, avatrace.Tracer, state.Mutable) error {
+	return nil
+}
+
+func (testGenesis) GetStateBranchFactor() merkledb.BranchFactor {
+	return merkledb.BranchFactor16
+}
+
+// testSyncClient serves proofs from [db] (like a peer would) and counts the
+// keys it serves in range proofs. Once [limit] range proofs were served (if
+// set), requests block until the sync is closed.
+type testSyncClient struct {
+	db     merkledb.MerkleDB
+	limit  int32
+	served atomic.Int32
+	keys   atomic.Int32
+}
+
+func (c *testSyncClient) GetRangeProof(
+	ctx context.Context,
+	request *pb.SyncGetRangeProofRequest,
+) (*merkledb.RangeProof, error) {
+	if c.limit > 0 && c.served.Add(1) > c.limit {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if c.limit == 0 {
+		c.served.Add(1)
+	}
+	root, err := ids.ToID(request.RootHash)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := c.db.GetRangeProofAtRoot(
+		ctx,
+		root,
+		fromMaybeBytes(request.StartKey),
+		fromMaybeBytes(request.EndKey),
+		int(request.KeyLimit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	c.keys.Add(int32(len(proof.KeyValues)))
+	return proof, nil
+}
+
+func (c *testSyncClient) GetChangeProof(
+	ctx context.Context,
+	request *pb.SyncGetChangeProofRequest,
+	verificationDB avasync.DB,
+) (*merkledb.ChangeOrRangeProof, error) {
+	startRoot, err := ids.ToID(request.StartRootHash)
+	if err != nil {
+		return nil, err
+	}
+	endRoot, err := ids.ToID(request.EndRootHash)
+	if err != nil {
+		return nil, err
+	}
+	start, end := fromMaybeBytes(request.StartKey), fromMaybeBytes(request.EndKey)
+	proof, err := c.db.GetChangeProof(ctx, startRoot, endRoot, start, end, int(request.KeyLimit))
+	if errors.Is(err, merkledb.ErrInsufficientHistory) {
+		rangeProof, err := c.db.GetRangeProofAtRoot(ctx, endRoot, start, end, int(request.KeyLimit))
+		if err != nil {
+			return nil, err
+		}
+		c.keys.Add(int32(len(rangeProof.KeyValues)))
+		return &merkledb.ChangeOrRangeProof{RangeProof: rangeProof}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := verificationDB.VerifyChangeProof(ctx, proof, start, end, endRoot); err != nil {
+		return nil, err
+	}
+	return &merkledb.ChangeOrRangeProof{ChangeProof: proof}, nil
+}
+
+func newTestMerkleDB(t *testing.T) merkledb.MerkleDB {
+	tracer, err := trace.New(&trace.Config{Enabled: false})
+	require.NoError(t, err)
+	db, err := merkledb.New(context.Background(), memdb.New(), merkledb.Config{
+		BranchFactor:                merkledb.BranchFactor16,
+		RootGenConcurrency:          1,
+		HistoryLength:               16,
+		ValueNodeCacheSize:          1024,
+		IntermediateNodeCacheSize:   1024,
+		IntermediateWriteBufferSize: 1024,
+		IntermediateWriteBatchSize:  1024,
+		Reg:                         prometheus.NewRegistry(),
+		TraceLevel:                  merkledb.InfoTrace,
+		Tracer:                      tracer,
+	})
+	require.NoError(t, err)
+	return db
+}
+
+// testKey returns the [i]th key written by [putTestKeys] (keys are spread over
+// the key space like state keys are).
+func testKey(i int) []byte {
+	return hashing.ComputeHash256(binary.BigEndian.AppendUint64(nil, uint64(i)))
+}
+
+// putTestKeys writes [n] keys to [db] (with values derived from [seed]) and
+// returns the new root.
+func putTestKeys(t *testing.T, db merkledb.MerkleDB, n int, seed byte) ids.ID {
+	ctx := context.Background()
+	ops := make([]database.BatchOp, n)
+	for i := range ops {
+		ops[i] = database.BatchOp{Key: testKey(i), Value: []byte{byte(i), seed}}
+	}
+	view, err := db.NewView(ctx, merkledb.ViewChanges{BatchOps: ops})
+	require.NoError(t, err)
+	require.NoError(t, view.CommitToDB(ctx))
+	root, err := db.GetMerkleRoot(ctx)
+	require.NoError(t, err)
+	return root
+}
+
+// newTestSyncer returns a [stateSyncerClient] that syncs [vm.stateDB] from
+// [client] (persisting progress in [vm.vmDB]).
+func newTestSyncer(vm *VM, client avasync.Client, root ids.ID) *stateSyncerClient {
+	s := vm.NewStateSyncClient(nil)
+	s.syncClient = client
+	s.target = &chain.StatelessBlock{StatefulBlock: &chain.StatefulBlock{StateRoot: root}}
+	return s
+}
+
+// syncTo syncs [vm.stateDB] to [root] (resuming from any persisted progress)
+// and returns the number of keys fetched in range proofs.
+func syncTo(t *testing.T, vm *VM, server merkledb.MerkleDB, root ids.ID) int32 {
+	require := require.New(t)
+
+	client := &testSyncClient{db: server}
+	s := newTestSyncer(vm, client, root)
+	_, err := s.progress.load()
+	require.NoError(err)
+	syncManager, err := s.newSyncManager(root)
+	require.NoError(err)
+	require.NoError(s.startSync(syncManager))
+	<-s.done
+	require.NoError(s.Error())
+
+	synced, err := vm.stateDB.GetMerkleRoot(context.Background())
+	require.NoError(err)
+	require.Equal(root, synced)
+	ok, err := newSyncProgress(vm).load()
+	require.NoError(err)
+	require.False(ok) // progress is deleted once synced
+	return client.keys.Load()
+}
+
+// interruptSync starts syncing [vm.stateDB] to [root] and shuts down the sync
+// after [limit] range proofs were served.
+func interruptSync(t *testing.T, vm *VM, server merkledb.MerkleDB, root ids.ID, limit int32) {
+	require := require.New(t)
+
+	client := &testSyncClient{db: server, limit: limit}
+	s := newTestSyncer(vm, client, root)
+	require.NoError(s.progress.SetTarget([]byte("target")))
+	syncManager, err := s.newSyncManager(root)
+	require.NoError(err)
+	require.NoError(s.startSync(syncManager))
+	require.Eventually(func() bool {
+		return client.served.Load() > limit && s.progress.Resumed()
+	}, 10*time.Second, 10*time.Millisecond)
+	_ = s.Shutdown() // returns the error from canceling the sync
+
+	// The synced ranges are kept after shutdown
+	p := newSyncProgress(vm)
+	ok, err := p.load()
+	require.NoError(err)
+	require.True(ok)
+	require.True(p.Resumed())
+}
+
+func newTestSyncVM(t *testing.T) *VM {
+	return &VM{
+		snowCtx: &snow.Context{Log: logging.NoLog{}},
+		config:  NewConfig(),
+		genesis: testGenesis{},
+		vmDB:    memdb.New(),
+		stateDB: newTestMerkleDB(t),
+	}
+}
+
+func TestStateSyncResume(t *testing.T) {
+	require := require.New(t)
+
+	server := newTestMerkleDB(t)
+	root := putTestKeys(t, server, 20_000, 0)
+
+	// A sync from scratch
+	full := syncTo(t, newTestSyncVM(t), server, root)
+
+	// Interrupt a sync and resume it after the target changed
+	vm := newTestSyncVM(t)
+	interruptSync(t, vm, server, root, 2)
+	root = putTestKeys(t, server, 100, 1)
+	resumed := syncTo(t, vm, server, root)
+
+	// Synced ranges were caught up with change proofs instead of being
+	// fetched again
+	require.Less(resumed, full)
+}
+
+func TestStateSyncFallback(t *testing.T) {
+	require := require.New(t)
+
+	server := newTestMerkleDB(t)
+	root := putTestKeys(t, server, 20_000, 0)
+
+	// Interrupt a sync and corrupt a synced key (so the synced ranges are
+	// inconsistent with the root they were recorded at)
+	vm := newTestSyncVM(t)
+	interruptSync(t, vm, server, root, 2)
+	p := newSyncProgress(vm)
+	_, err := p.load()
+	require.NoError(err)
+	it := vm.stateDB.NewIteratorWithStart(p.ranges[0].start.Value())
+	require.True(it.Next())
+	k := bytes.Clone(it.Key())
+	it.Release()
+	_, ok := p.Find(k)
+	require.True(ok)
+	require.NoError(vm.stateDB.Put(k, []byte("corrupt")))
+
+	// Resuming fails to reach [root], so we fall back to a full sync
+	syncTo(t, vm, server, root)
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+// TraceTx re-executes the accepted block containing [txID] and returns the
+// block and the trace of [txID].
+//
+// Only transactions accepted in the last [ValidityWindow] can be traced and
+// the state of the parent of their block must be available (see [Replay]).
+func (vm *VM) TraceTx(ctx context.Context, txID ids.ID) (*chain.StatelessBlock, *chain.TxTrace, error) {
+	if !vm.config.TxTracing {
+		return nil, nil, ErrTxTracingDisabled
+	}
+	blk, i, err := vm.findAcceptedTx(ctx, txID)
+	if err != nil {
+		return nil, nil, err
+	}
+	exec, err := vm.replayBlock(ctx, blk)
+	if err != nil {
+		return nil, nil, err
+	}
+	vm.Logger().Debug("traced tx",
+		zap.Stringer("txID", txID),
+		zap.Uint64("height", blk.Hght),
+		zap.Stringer("root", exec.Root),
+	)
+	return blk, exec.Traces[i], nil
+}
+
+// findAcceptedTx returns the accepted block containing [txID] and the index of
+// [txID] in it.
+func (vm *VM) findAcceptedTx(ctx context.Context, txID ids.ID) (*chain.StatelessBlock, int, error) {
+	var (
+		blk    = vm.lastAccepted
+		oldest = blk.Tmstmp - vm.Rules(blk.Tmstmp).GetValidityWindow()
+		err    error
+	)
+	for blk.Hght > 0 && blk.Tmstmp >= oldest {
+		for i, tx := range blk.Txs {
+			if tx.ID() == txID {
+				return blk, i, nil
+			}
+		}
+		blk, err = vm.GetStatelessBlock(ctx, blk.Prnt)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	return nil, 0, fmt.Errorf("%w: %s", ErrTxNotFound, txID)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/x/merkledb"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/hypersdk/builder"
@@ -52,6 +53,7 @@ import (
 const (
 	blockDB   = "blockdb"
 	stateDB   = "statedb"
+	archiveDB = "archivedb"
 	vmDataDir = "vm"
 )
 
@@ -70,6 +72,7 @@ type VM struct {
 	rawStateDB     database.Database
 	stateDB        merkledb.MerkleDB
 	vmDB           database.Database
+	archiveDB      database.Database // only populated if [ArchiveMode]
 	handlers       Handlers
 	actionRegistry chain.ActionRegistry
 	authRegistry   chain.AuthRegistry
@@ -122,11 +125,19 @@ type VM struct {
 	// Network manager routes p2p messages to pre-registered handlers
 	networkManager *network.Manager
 
+	buildPolicy chain.BuildPolicy
+
 	metrics  *Metrics
 	profiler profiler.ContinuousProfiler
 
 	ready chan struct{}
 	stop  chan struct{}
+
+	// readOnly is set by [ReplayOffline] to open an existing database without
+	// modifying it (the controller is given [scratchDir] instead of its
+	// own data directory).
+	readOnly   bool
+	scratchDir string
 }
 
 func New(c Controller, v *version.Semantic) *VM {
@@ -169,9 +180,9 @@ func (vm *VM) Initialize(
 	}
 	vm.metrics = metrics
 	vm.proposerMonitor = NewProposerMonitor(vm)
-	vm.networkManager = network.NewManager(vm.snowCtx.Log, vm.snowCtx.NodeID, appSender)
 
 	pebbleConfig := pebble.NewDefaultConfig()
+	pebbleConfig.ReadOnly = vm.readOnly
 	vm.vmDB, err = storage.New(pebbleConfig, vm.snowCtx.ChainDataDir, blockDB, vm.snowCtx.Metrics)
 	if err != nil {
 		return err
@@ -181,6 +192,14 @@ func (vm *VM) Initialize(
 	if err != nil {
 		return err
 	}
+	if vm.readOnly {
+		// [merkledb] writes to the database when opened
+		vm.rawStateDB = newOverlayDB(vm.rawStateDB)
+	}
+	controllerDataDir := filepath.Join(vm.snowCtx.ChainDataDir, vmDataDir)
+	if vm.readOnly {
+		controllerDataDir = vm.scratchDir
+	}
 
 	// TODO do not expose entire context to the Controller
 	//
@@ -202,12 +221,28 @@ func (vm *VM) Initialize(
 		Metrics:        vm.snowCtx.Metrics,
 		WarpSigner:     vm.snowCtx.WarpSigner,
 		ValidatorState: vm.snowCtx.ValidatorState,
-		ChainDataDir:   filepath.Join(vm.snowCtx.ChainDataDir, vmDataDir),
+		ChainDataDir:   controllerDataDir,
 	}
 
 	if err := json.Unmarshal(configBytes, &vm.config); err != nil {
 		return fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	vm.networkManager, err = network.NewManager(
+		vm.snowCtx.Log,
+		vm.snowCtx.NodeID,
+		appSender,
+		&vm.config.NetworkConfig,
+		defaultRegistry,
+	)
+	if err != nil {
+		return err
+	}
+	if vm.config.ArchiveMode {
+		vm.archiveDB, err = storage.New(pebbleConfig, vm.snowCtx.ChainDataDir, archiveDB, vm.snowCtx.Metrics)
+		if err != nil {
+			return err
+		}
+	}
 
 	controllerConfigBytes, err := json.Marshal(vm.config.Config)
 	if err != nil {
@@ -226,9 +261,20 @@ func (vm *VM) Initialize(
 	if err != nil {
 		return fmt.Errorf("implementation initialization failed: %w", err)
 	}
+	if c, ok := vm.c.(BuildPolicyController); ok {
+		vm.buildPolicy = c.BuildPolicy()
+	} else {
+		vm.buildPolicy = &chain.DefaultBuildPolicy{StopThreshold: vm.config.BuildStopThreshold}
+	}
 
 	// Setup tracer
-	vm.tracer, err = trace.New(&vm.config.TraceConfig)
+	vm.tracer, err = trace.New(
+		&vm.config.TraceConfig,
+		attribute.Stringer("chainID", vm.snowCtx.ChainID),
+		attribute.Stringer("subnetID", vm.snowCtx.SubnetID),
+		attribute.Stringer("nodeID", vm.snowCtx.NodeID),
+		attribute.Int64("networkID", int64(vm.snowCtx.NetworkID)),
+	)
 	if err != nil {
 		return err
 	}
@@ -319,38 +365,41 @@ func (vm *VM) Initialize(
 		// It is not guaranteed that the last accepted state on-disk matches the post-execution
 		// result of the last accepted block.
 		snowCtx.Log.Info("initialized vm from last accepted", zap.Stringer("block", blk.ID()))
-	} else {
-		// Set balances and compute genesis root
-		sps := state.NewSimpleMutable(vm.stateDB)
-		if err := vm.genesis.Load(ctx, vm.tracer, sps); err != nil {
-			snowCtx.Log.Error("could not set genesis allocation", zap.Error(err))
+		if vm.archiveDB != nil {
+			if err := vm.initArchive(); err != nil {
+				snowCtx.Log.Error("could not initialize archive", zap.Error(err))
+				return err
+			}
+		}
+	} else if len(vm.config.SnapshotPath) > 0 {
+		// Initialize state and blocks from a snapshot instead of genesis
+		if vm.archiveDB != nil {
+			return fmt.Errorf("%w: cannot import snapshot", ErrArchiveIncomplete)
+		}
+		// The snapshot must be of this chain, so we create our genesis block to
+		// compare against the genesis block in the snapshot.
+		genesisBlk, err := vm.loadGenesis(ctx)
+		if err != nil {
 			return err
 		}
-		if err := sps.Commit(ctx); err != nil {
+		vm.genesisBlk = genesisBlk
+		if err := vm.importSnapshot(ctx, vm.config.SnapshotPath); err != nil {
+			snowCtx.Log.Error("could not import snapshot", zap.String("path", vm.config.SnapshotPath), zap.Error(err))
 			return err
 		}
-		root, err := vm.stateDB.GetMerkleRoot(ctx)
-		if err != nil {
-			snowCtx.Log.Error("could not get merkle root", zap.Error(err))
+		if err := vm.loadAcceptedBlocks(ctx); err != nil {
+			snowCtx.Log.Error("could not load accepted blocks from disk", zap.Error(err))
 			return err
 		}
-		snowCtx.Log.Info("genesis state created", zap.Stringer("root", root))
-
-		// Create genesis block
-		genesisBlk, err := chain.ParseStatefulBlock(
-			ctx,
-			chain.NewGenesisBlock(root),
-			nil,
-			choices.Accepted,
-			vm,
-		)
+	} else {
+		// Set balances and create genesis block
+		genesisBlk, err := vm.loadGenesis(ctx)
 		if err != nil {
-			snowCtx.Log.Error("unable to init genesis block", zap.Error(err))
 			return err
 		}
 
 		// Update chain metadata
-		sps = state.NewSimpleMutable(vm.stateDB)
+		sps := state.NewSimpleMutable(vm.stateDB)
 		if err := sps.Insert(ctx, chain.HeightKey(vm.StateManager().HeightKey()), binary.BigEndian.AppendUint64(nil, 0)); err != nil {
 			return err
 		}
@@ -377,6 +426,12 @@ func (vm *VM) Initialize(
 			snowCtx.Log.Error("could not get merkle root", zap.Error(err))
 			return err
 		}
+		if vm.archiveDB != nil {
+			if err := vm.archiveGenesis(); err != nil {
+				snowCtx.Log.Error("could not archive genesis state", zap.Error(err))
+				return err
+			}
+		}
 
 		// Update last accepted and preferred block
 		vm.genesisBlk = genesisBlk
@@ -392,6 +447,20 @@ func (vm *VM) Initialize(
 			zap.Stringer("post-execution root", genesisRoot),
 		)
 	}
+
+	// If we were interrupted while syncing, the state is incomplete and the
+	// controller is only notified once sync completes.
+	syncing, err := vm.GetDiskIsSyncing()
+	if err != nil {
+		snowCtx.Log.Error("could not determine if syncing", zap.Error(err))
+		return err
+	}
+	if !syncing {
+		if err := vm.stateLoaded(ctx); err != nil {
+			snowCtx.Log.Error("controller could not load state", zap.Error(err))
+			return err
+		}
+	}
 	go vm.processAcceptedBlocks()
 
 	// Setup state syncing
@@ -414,15 +483,20 @@ func (vm *VM) Initialize(
 	}
 	vm.stateSyncClient = vm.NewStateSyncClient(vm.snowCtx.Metrics)
 	vm.stateSyncNetworkServer = avasync.NewNetworkServer(stateSyncSender, vm.stateDB, vm.Logger())
+	vm.networkManager.SetLimit(stateSyncHandler, vm.config.StateSyncLimit)
 	vm.networkManager.SetHandler(stateSyncHandler, NewStateSyncHandler(vm))
 
 	// Setup gossip networking
 	gossipHandler, gossipSender := vm.networkManager.Register()
+	vm.networkManager.SetLimit(gossipHandler, vm.config.TxGossipLimit)
 	vm.networkManager.SetHandler(gossipHandler, NewTxGossipHandler(vm))
+	announceHandler, announceSender := vm.networkManager.Register()
+	vm.networkManager.SetLimit(announceHandler, vm.config.TxAnnounceLimit)
+	vm.networkManager.SetHandler(announceHandler, NewTxAnnounceHandler(vm))
 
 	// Startup block builder and gossiper
 	go vm.builder.Run()
-	go vm.gossiper.Run(gossipSender)
+	go vm.gossiper.Run(gossipSender, announceSender)
 
 	// Wait until VM is ready and then send a state sync message to engine
 	go vm.markReady()
@@ -445,6 +519,49 @@ func (vm *VM) Initialize(
 	return nil
 }
 
+// stateLoaded notifies the [Controller] (if it implements
+// [StateLoadedController]) that [vm.stateDB] holds the state of the last
+// accepted block.
+func (vm *VM) stateLoaded(ctx context.Context) error {
+	c, ok := vm.c.(StateLoadedController)
+	if !ok {
+		return nil
+	}
+	return c.StateLoaded(ctx, vm.stateDB)
+}
+
+// loadGenesis writes the genesis allocation to [vm.stateDB] and returns the
+// genesis block (which commits to the resulting root).
+func (vm *VM) loadGenesis(ctx context.Context) (*chain.StatelessBlock, error) {
+	sps := state.NewSimpleMutable(vm.stateDB)
+	if err := vm.genesis.Load(ctx, vm.tracer, sps); err != nil {
+		vm.snowCtx.Log.Error("could not set genesis allocation", zap.Error(err))
+		return nil, err
+	}
+	if err := sps.Commit(ctx); err != nil {
+		return nil, err
+	}
+	root, err := vm.stateDB.GetMerkleRoot(ctx)
+	if err != nil {
+		vm.snowCtx.Log.Error("could not get merkle root", zap.Error(err))
+		return nil, err
+	}
+	vm.snowCtx.Log.Info("genesis state created", zap.Stringer("root", root))
+
+	genesisBlk, err := chain.ParseStatefulBlock(
+		ctx,
+		chain.NewGenesisBlock(root),
+		nil,
+		choices.Accepted,
+		vm,
+	)
+	if err != nil {
+		vm.snowCtx.Log.Error("unable to init genesis block", zap.Error(err))
+		return nil, err
+	}
+	return genesisBlk, nil
+}
+
 func (vm *VM) checkActivity(ctx context.Context) {
 	vm.gossiper.Queue(ctx)
 	vm.builder.Queue(ctx)
@@ -615,6 +732,11 @@ func (vm *VM) Shutdown(ctx context.Context) error {
 	if err := vm.stateDB.Close(); err != nil {
 		return err
 	}
+	if vm.archiveDB != nil {
+		if err := vm.archiveDB.Close(); err != nil {
+			return err
+		}
+	}
 	return vm.rawStateDB.Close()
 }
 
@@ -788,11 +910,14 @@ func (vm *VM) BuildBlock(ctx context.Context) (snowman.Block, error) {
 	return blk, nil
 }
 
+// Submit verifies [txs] and adds the valid ones to the mempool. It returns one
+// error per transaction (nil if it was added) or, if no transaction could be
+// verified (i.e. because the VM is not ready), a single error for the batch.
 func (vm *VM) Submit(
 	ctx context.Context,
 	verifyAuth bool,
 	txs []*chain.Transaction,
-) (errs []error) {
+) ([]error, error) {
 	ctx, span := vm.tracer.Start(ctx, "VM.Submit")
 	defer span.End()
 	vm.metrics.txsSubmitted.Add(float64(len(txs)))
@@ -801,38 +926,39 @@ func (vm *VM) Submit(
 	// ready yet. We should never reach this point because of other checks but it
 	// is good to be defensive.
 	if !vm.isReady() {
-		return []error{ErrNotReady}
+		return nil, ErrNotReady
 	}
 
 	// Create temporary execution context
 	blk, err := vm.GetStatelessBlock(ctx, vm.preferred)
 	if err != nil {
-		return []error{err}
+		return nil, err
 	}
 	view, err := blk.View(ctx, false)
 	if err != nil {
 		// This will error if a block does not yet have processed state.
-		return []error{err}
+		return nil, err
 	}
 	feeRaw, err := view.GetValue(ctx, chain.FeeKey(vm.StateManager().FeeKey()))
 	if err != nil {
-		return []error{err}
+		return nil, err
 	}
 	feeManager := fees.NewManager(feeRaw)
 	now := time.Now().UnixMilli()
 	r := vm.c.Rules(now)
 	nextFeeManager, err := feeManager.ComputeNext(now, r)
 	if err != nil {
-		return []error{err}
+		return nil, err
 	}
 
 	// Find repeats
 	oldestAllowed := now - r.GetValidityWindow()
 	repeats, err := blk.IsRepeat(ctx, oldestAllowed, txs, set.NewBits(), true)
 	if err != nil {
-		return []error{err}
+		return nil, err
 	}
 
+	errs := make([]error, 0, len(txs))
 	validTxs := []*chain.Transaction{}
 	for i, tx := range txs {
 		// Check if transaction is a repeat before doing any extra work
@@ -846,7 +972,7 @@ func (vm *VM) Submit(
 		if vm.mempool.Has(ctx, txID) {
 			// Don't remove from listeners, it will be removed elsewhere if not
 			// included
-			errs = append(errs, ErrNotAdded)
+			errs = append(errs, fmt.Errorf("%w: %w", ErrNotAdded, chain.ErrDuplicateTx))
 			continue
 		}
 
@@ -896,7 +1022,7 @@ func (vm *VM) Submit(
 	vm.mempool.Add(ctx, validTxs)
 	vm.checkActivity(ctx)
 	vm.metrics.mempoolSize.Set(float64(vm.mempool.Len(ctx)))
-	return errs
+	return errs, nil
 }
 
 // "SetPreference" implements "block.ChainVM"
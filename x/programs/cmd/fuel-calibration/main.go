@@ -0,0 +1,314 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// fuel-calibration measures how long wasm instructions and host functions take
+// on this machine and suggests the fuel costs of the host functions, so that
+// a unit of fuel buys roughly the same amount of time whatever a program does
+// with it.
+//
+// The price of fuel is measured by running a compute loop. Each host function
+// is then called in a loop (with its fuel cost set to zero) with a small and
+// a large input, and the time spent outside of wasm is fitted to a base cost
+// and a cost per byte. The suggested costs are printed as JSON and can be set
+// with [runtime.WasmRuntime.SetFuelCost]. They only set [runtime.FuelCost.Base]
+// and [runtime.FuelCost.PerByte], which account for the whole cost of a call.
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/bytecodealliance/wasmtime-go/v14"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/x/programs/runtime"
+	"github.com/ava-labs/hypersdk/x/programs/test"
+)
+
+const (
+	// callFuel is the fuel of every call, which is never exhausted
+	callFuel = uint64(1) << 60
+
+	// maxSize is the largest input the workloads can build in memory
+	maxSize = 512 * 1024
+)
+
+var errInvalidFlags = errors.New("invalid flags")
+
+// workload is a host function called by the function of [calibrationProgram]
+// with the same name.
+type workload struct {
+	module   string
+	function string
+}
+
+var workloads = []workload{
+	{module: "state", function: "put"},
+	{module: "state", function: "get"},
+	{module: "events", function: "emit"},
+	{module: "program", function: "set_call_result"},
+	{module: "balance", function: "get"},
+}
+
+func (w workload) name() string {
+	return w.module + "_" + w.function
+}
+
+// calibrationProgram has a "compute" function and a function per workload.
+// They all read the number of iterations and the size of the input from the
+// (little endian) u32s following the program context.
+var calibrationProgram = `
+(module
+  (import "state" "put" (func $state_put (param i32 i32)))
+  (import "state" "get" (func $state_get (param i32 i32) (result i32)))
+  (import "events" "emit" (func $events_emit (param i32 i32)))
+  (import "program" "set_call_result" (func $program_set_call_result (param i32 i32)))
+  (import "balance" "get" (func $balance_get (param i32 i32) (result i32)))
+  (memory (export "memory") 32)
+  (data (i32.const 8) "\01\00\00\00k")
+  ;; allocations share the same region since they are never kept
+  (func (export "alloc") (param i32) (result i32)
+    (i32.const 1048576))
+  (func (export "compute") (param $ctx i32)
+    (local $n i32)
+    (local $x i32)
+    (local.set $n (i32.load (i32.add (local.get $ctx) (i32.const 114))))
+    (block $done
+      (loop $loop
+        (br_if $done (i32.eqz (local.get $n)))
+        (local.set $x (i32.add (i32.mul (local.get $x) (i32.const 31)) (i32.load (i32.const 64))))
+        (i32.store (i32.const 64) (i32.xor (local.get $x) (local.get $n)))
+        (local.set $n (i32.sub (local.get $n) (i32.const 1)))
+        (br $loop))))
+` +
+	// the key "k" => [size] zeroes
+	workloadFunc("state_put", putInput,
+		`(call $state_put (i32.const 65536) (i32.add (i32.const 13) (local.get $size)))`) +
+	// the key "k", which is first set to [size] zeroes
+	workloadFunc("state_get", putInput+`
+    (call $state_put (i32.const 65536) (i32.add (i32.const 13) (local.get $size)))`,
+		`(drop (call $state_get (i32.const 8) (i32.const 5)))`) +
+	// the topic "k" with [size] zeroes
+	workloadFunc("events_emit", `
+    (i32.store (i32.const 65536) (i32.const 1))
+    (i32.store8 (i32.const 65540) (i32.const 107))
+    (i32.store (i32.const 65541) (local.get $size))`,
+		`(call $events_emit (i32.const 65536) (i32.add (i32.const 9) (local.get $size)))`) +
+	// [size] zeroes
+	workloadFunc("program_set_call_result", "",
+		`(call $program_set_call_result (i32.const 65536) (local.get $size))`) +
+	// the empty address
+	workloadFunc("balance_get", "",
+		`(drop (call $balance_get (i32.const 65536) (i32.const 33)))`) +
+	")"
+
+// putInput writes the input of a put of "k" => [size] zeroes at 65536.
+const putInput = `
+    (i32.store (i32.const 65536) (i32.const 1))
+    (i32.store (i32.const 65540) (i32.const 1))
+    (i32.store8 (i32.const 65544) (i32.const 107))
+    (i32.store (i32.const 65545) (local.get $size))`
+
+// workloadFunc returns a function that runs [setup] and then [call] once per
+// iteration.
+func workloadFunc(name string, setup string, call string) string {
+	return fmt.Sprintf(`
+  (func (export %q) (param $ctx i32)
+    (local $n i32)
+    (local $size i32)
+    (local.set $n (i32.load (i32.add (local.get $ctx) (i32.const 114))))
+    (local.set $size (i32.load (i32.add (local.get $ctx) (i32.const 118))))%s
+    (block $done
+      (loop $loop
+        (br_if $done (i32.eqz (local.get $n)))
+        %s
+        (local.set $n (i32.sub (local.get $n) (i32.const 1)))
+        (br $loop))))
+`, name, setup, call)
+}
+
+// Calibration is the output of the tool.
+type Calibration struct {
+	// NanosPerFuel is the time it takes to run wasm instructions worth one
+	// unit of fuel.
+	NanosPerFuel float64 `json:"nanosPerFuel"`
+	// Costs are the suggested costs of the host functions by module and
+	// function name.
+	Costs map[string]map[string]runtime.FuelCost `json:"costs"`
+}
+
+type stateManager struct {
+	*test.StateManager
+	programID ids.ID
+	program   []byte
+}
+
+func (s *stateManager) GetProgramBytes(_ context.Context, programID ids.ID) ([]byte, error) {
+	if programID != s.programID {
+		return nil, errors.New("couldn't find program")
+	}
+	return s.program, nil
+}
+
+type calibrator struct {
+	runtime *runtime.WasmRuntime
+	state   *stateManager
+	account codec.Address
+	runs    int
+}
+
+// measure calls [function] [runs] times and returns the fastest call and the
+// fuel it consumed.
+func (c *calibrator) measure(ctx context.Context, function string, iterations uint32, size uint32) (time.Duration, uint64, error) {
+	params := binary.LittleEndian.AppendUint32(nil, iterations)
+	params = binary.LittleEndian.AppendUint32(params, size)
+
+	fastest := time.Duration(math.MaxInt64)
+	var fuel uint64
+	for i := 0; i < c.runs; i++ {
+		callInfo := &runtime.CallInfo{
+			State:        c.state,
+			Program:      c.account,
+			FunctionName: function,
+			Params:       params,
+			Fuel:         callFuel,
+		}
+		start := time.Now()
+		if _, err := c.runtime.CallProgram(ctx, callInfo); err != nil {
+			return 0, 0, fmt.Errorf("failed to call %s: %w", function, err)
+		}
+		if elapsed := time.Since(start); elapsed < fastest {
+			fastest = elapsed
+		}
+		fuel = callFuel - callInfo.RemainingFuel()
+	}
+	return fastest, fuel, nil
+}
+
+// hostNanos returns the time a call to the host function of [w] takes with
+// an input of [size] bytes, excluding the time spent running wasm.
+func (c *calibrator) hostNanos(ctx context.Context, w workload, iterations uint32, size uint32, nanosPerFuel float64) (float64, error) {
+	// the time it takes to call the program at all
+	overhead, overheadFuel, err := c.measure(ctx, w.name(), 0, size)
+	if err != nil {
+		return 0, err
+	}
+	elapsed, fuel, err := c.measure(ctx, w.name(), iterations, size)
+	if err != nil {
+		return 0, err
+	}
+	wasmNanos := float64(fuel-overheadFuel) * nanosPerFuel
+	return (float64(elapsed-overhead) - wasmNanos) / float64(iterations), nil
+}
+
+func toFuel(nanos float64, nanosPerFuel float64) uint64 {
+	if nanos <= 0 {
+		return 0
+	}
+	return uint64(math.Ceil(nanos / nanosPerFuel))
+}
+
+func run() error {
+	var (
+		iterations        = flag.Uint("iterations", 1000, "number of host function calls per measurement")
+		computeIterations = flag.Uint("compute-iterations", 10_000_000, "number of iterations of the compute loop")
+		small             = flag.Uint("small", 64, "size of the small inputs in bytes")
+		large             = flag.Uint("large", 16*1024, "size of the large inputs in bytes")
+		runs              = flag.Int("runs", 5, "number of times each measurement is repeated (the fastest run is kept)")
+	)
+	flag.Parse()
+	if *iterations == 0 || *computeIterations == 0 || *runs <= 0 {
+		return fmt.Errorf("%w: iterations and runs must be positive", errInvalidFlags)
+	}
+	if *small == 0 || *small >= *large || *large > maxSize {
+		return fmt.Errorf("%w: sizes must satisfy 0 < small < large <= %d", errInvalidFlags, maxSize)
+	}
+
+	program, err := wasmtime.Wat2Wasm(calibrationProgram)
+	if err != nil {
+		return err
+	}
+	programID := ids.GenerateTestID()
+	account := codec.CreateAddress(0, programID)
+	c := &calibrator{
+		runtime: runtime.NewRuntime(runtime.NewConfig(), logging.NoLog{}),
+		state: &stateManager{
+			StateManager: &test.StateManager{
+				AccountMap: map[codec.Address]ids.ID{account: programID},
+				Balances:   map[codec.Address]uint64{},
+				Mu:         test.NewTestDB(),
+			},
+			programID: programID,
+			program:   program,
+		},
+		account: account,
+		runs:    *runs,
+	}
+	if err := c.runtime.ValidateProgram(program); err != nil {
+		return err
+	}
+	// the host functions are measured without charging for them
+	for _, w := range workloads {
+		c.runtime.SetFuelCost(w.module, w.function, runtime.FuelCost{})
+	}
+
+	ctx := context.Background()
+	elapsed, fuel, err := c.measure(ctx, "compute", uint32(*computeIterations), 0)
+	if err != nil {
+		return err
+	}
+	calibration := &Calibration{
+		NanosPerFuel: float64(elapsed) / float64(fuel),
+		Costs:        map[string]map[string]runtime.FuelCost{},
+	}
+
+	for _, w := range workloads {
+		smallNanos, err := c.hostNanos(ctx, w, uint32(*iterations), uint32(*small), calibration.NanosPerFuel)
+		if err != nil {
+			return err
+		}
+		largeNanos, err := c.hostNanos(ctx, w, uint32(*iterations), uint32(*large), calibration.NanosPerFuel)
+		if err != nil {
+			return err
+		}
+		perByte := (largeNanos - smallNanos) / float64(*large-*small)
+		if w.module == "balance" {
+			// addresses have a fixed size
+			perByte = 0
+		}
+		base := smallNanos - perByte*float64(*small)
+
+		if _, ok := calibration.Costs[w.module]; !ok {
+			calibration.Costs[w.module] = map[string]runtime.FuelCost{}
+		}
+		calibration.Costs[w.module][w.function] = runtime.FuelCost{
+			Base:    toFuel(base, calibration.NanosPerFuel),
+			PerByte: toFuel(perByte, calibration.NanosPerFuel),
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(calibration, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(jsonBytes))
+	return nil
+}
+
+func main() {
+	if err := run(); err != nil {
+		if _, err := fmt.Fprintln(os.Stderr, err); err != nil {
+			panic(err)
+		}
+		os.Exit(1)
+	}
+}
@@ -6,6 +6,9 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/x/programs/runtime"
 )
 
 const (
@@ -22,6 +25,46 @@ type Step struct {
 	MaxUnits uint64 `json:"maxUnits"`
 	// The parameters to pass to the method.
 	Params []Parameter `json:"params"`
+	// A description of the step used in reports.
+	Name string `json:"name,omitempty"`
+	// The assertions to check once the step has completed.
+	Require *Require `json:"require,omitempty"`
+}
+
+// Plan is a sequence of steps run by the test command.
+type Plan struct {
+	// The name of the plan used in reports.
+	Name string `json:"name"`
+	// The steps to run in order. (required)
+	Steps []Step `json:"steps"`
+}
+
+type Require struct {
+	// The expected result of the call.
+	Result []byte `json:"result,omitempty"`
+	// The name of the expected error code of the call (e.g. OutOfFuel).
+	Error string `json:"error,omitempty"`
+	// The expected balances once the step has completed.
+	Balances []BalanceAssertion `json:"balances,omitempty"`
+	// The expected program state once the step has completed.
+	State []StateAssertion `json:"state,omitempty"`
+}
+
+type BalanceAssertion struct {
+	// The account to check, either an id or an address parameter. (required)
+	Account Parameter `json:"account"`
+	// The expected balance of the account.
+	Balance uint64 `json:"balance"`
+}
+
+type StateAssertion struct {
+	// The account of the program to check, either an id or an address
+	// parameter. (required)
+	Account Parameter `json:"account"`
+	// The key in the state of the program. (required)
+	Key []byte `json:"key"`
+	// The expected value of the key, the key must not exist if omitted.
+	Value []byte `json:"value,omitempty"`
 }
 
 type Endpoint string
@@ -33,6 +76,10 @@ const (
 	/// function call. A program's function can internally optionally call other
 	/// functions including program to program.
 	EndpointExecute Endpoint = "execute"
+	/// Take a snapshot of the state named after the method.
+	EndpointSnapshot Endpoint = "snapshot"
+	/// Revert the state to the snapshot named after the method.
+	EndpointRollback Endpoint = "rollback"
 )
 
 func newResponse(id int) *Response {
@@ -75,6 +122,16 @@ func (r *Response) setResponse(response []byte) {
 	r.Result.Response = response
 }
 
+func (r *Response) setEvents(events []runtime.Event) {
+	for _, event := range events {
+		r.Result.Events = append(r.Result.Events, Event{
+			Program: codec.ToHex(event.Program[:]),
+			Topic:   event.Topic,
+			Data:    event.Data,
+		})
+	}
+}
+
 func (r *Response) setTimestamp(timestamp int64) {
 	r.Result.Timestamp = uint64(timestamp)
 }
@@ -88,6 +145,15 @@ type Result struct {
 	Response []byte `json:"response"`
 	// Timestamp of the response.
 	Timestamp uint64 `json:"timestamp,omitempty"`
+	// The events emitted by the call.
+	Events []Event `json:"events,omitempty"`
+}
+
+type Event struct {
+	// The address of the program that emitted the event.
+	Program string `json:"program"`
+	Topic   string `json:"topic"`
+	Data    []byte `json:"data"`
 }
 
 type Parameter struct {
@@ -115,3 +181,12 @@ func unmarshalStep(bytes []byte) (*Step, error) {
 
 	return &s, nil
 }
+
+func unmarshalPlan(bytes []byte) (*Plan, error) {
+	var p Plan
+	if err := json.Unmarshal(bytes, &p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
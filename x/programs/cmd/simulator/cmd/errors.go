@@ -25,4 +25,9 @@ var (
 	ErrConfigMissingRequired     = errors.New("missing required field")
 	ErrFirstParamRequiredPath    = errors.New("first param must be a path")
 	ErrFirstParamRequiredContext = errors.New("first param must be a testContext")
+	ErrSnapshotNameRequired      = errors.New("snapshot name required for this step")
+	ErrSnapshotNotFound          = errors.New("snapshot not found")
+
+	// Plans
+	ErrPlanFailed = errors.New("plan failed")
 )
@@ -42,10 +42,13 @@ type runCmd struct {
 
 	// tracks program IDs created during this simulation
 	programIDStrMap map[int]codec.Address
+	// tracks the named snapshots taken during this simulation
+	snapshots map[string]state.Snapshot
 }
 
-func (c *runCmd) New(parser *argparse.Parser, programIDStrMap map[int]codec.Address, lastStep *int, reader *bufio.Reader) {
+func (c *runCmd) New(parser *argparse.Parser, programIDStrMap map[int]codec.Address, snapshots map[string]state.Snapshot, lastStep *int, reader *bufio.Reader) {
 	c.programIDStrMap = programIDStrMap
+	c.snapshots = snapshots
 	c.cmd = parser.NewCommand("run", "Run a HyperSDK program simulation plan")
 	c.file = c.cmd.String("", "file", &argparse.Options{
 		Required: false,
@@ -110,15 +113,21 @@ func (c *runCmd) Verify() error {
 		return fmt.Errorf("%w: %s", ErrInvalidStep, "no steps found")
 	}
 
-	if step.Params == nil {
-		return fmt.Errorf("%w: %s", ErrInvalidParams, "no params found")
-	}
-
 	// verify endpoint requirements
 	return verifyEndpoint(*c.lastStep, step)
 }
 
 func verifyEndpoint(i int, step *Step) error {
+	if step.Endpoint == EndpointSnapshot || step.Endpoint == EndpointRollback {
+		if len(step.Method) == 0 {
+			return fmt.Errorf("%w %d: %w", ErrInvalidStep, i, ErrSnapshotNameRequired)
+		}
+		return nil
+	}
+
+	if len(step.Params) == 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidParams, "no params found")
+	}
 	firstParamType := step.Params[0].Type
 
 	switch step.Endpoint {
@@ -185,7 +194,7 @@ func (c *runCmd) runStepFunc(
 ) error {
 	defer resp.setTimestamp(time.Now().Unix())
 	switch endpoint {
-	case EndpointExecute: // for now the logic is the same for both TODO: breakout readonly
+	case EndpointExecute:
 		if method == ProgramCreate {
 			// get program path from params
 			programPath := string(params[0].Value)
@@ -215,7 +224,7 @@ func (c *runCmd) runStepFunc(
 			Height:    simulatorTestContext.Height,
 		}
 
-		result, balance, err := programExecuteFunc(ctx, c.log, db, testContext, params[1:], method, maxUnits)
+		result, balance, events, err := programExecuteFunc(ctx, c.log, db, testContext, params[1:], method, maxUnits, false)
 		output := resultToOutput(result, err)
 		if err := db.Commit(ctx); err != nil {
 			return err
@@ -227,6 +236,7 @@ func (c *runCmd) runStepFunc(
 
 		resp.setResponse(response)
 		resp.setBalance(balance)
+		resp.setEvents(events)
 
 		return nil
 	case EndpointReadOnly:
@@ -246,20 +256,30 @@ func (c *runCmd) runStepFunc(
 			Height:    simulatorTestContext.Height,
 		}
 
-		// TODO: implement readonly for now just don't charge for gas
-		result, _, err := programExecuteFunc(ctx, c.log, db, testContext, params[1:], method, math.MaxUint64)
+		// read-only calls are not charged for fuel and can't modify state
+		result, _, events, err := programExecuteFunc(ctx, c.log, db, testContext, params[1:], method, math.MaxUint64, true)
 		output := resultToOutput(result, err)
-		if err := db.Commit(ctx); err != nil {
-			return err
-		}
 		response, err := runtime.Serialize(output)
 		if err != nil {
 			return err
 		}
 
 		resp.setResponse(response)
+		resp.setEvents(events)
 
 		return nil
+	case EndpointSnapshot:
+		c.snapshots[method] = db.Snapshot()
+
+		return nil
+	case EndpointRollback:
+		snapshot, ok := c.snapshots[method]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrSnapshotNotFound, method)
+		}
+		db.Restore(snapshot)
+
+		return db.Commit(ctx)
 	default:
 		return fmt.Errorf("%w: %s", ErrInvalidEndpoint, endpoint)
 	}
@@ -74,6 +74,14 @@ func programCreateFunc(ctx context.Context, db *state.SimpleMutable, path string
 		return codec.EmptyAddress, err
 	}
 
+	// reject programs that can't be called, like the chain does when they
+	// are published
+	if err := runtime.NewRuntime(runtime.NewConfig(), logging.NoLog{}).ValidateProgram(programBytes); err != nil {
+		response := multilineOutput([][]byte{utils.ErrBytes(err)})
+		fmt.Println(response)
+		return codec.EmptyAddress, fmt.Errorf("program creation failed: %w", err)
+	}
+
 	// simulate create program transaction
 	programID, err := generateRandomID()
 	if err != nil {
@@ -111,7 +119,8 @@ func programExecuteFunc(
 	callParams []Parameter,
 	function string,
 	maxUnits uint64,
-) ([]byte, uint64, error) {
+	readOnly bool,
+) ([]byte, uint64, []runtime.Event, error) {
 	// execute the action
 	var bytes []byte
 	for _, param := range callParams {
@@ -120,7 +129,7 @@ func programExecuteFunc(
 
 	rt := runtime.NewRuntime(runtime.NewConfig(), log)
 	callInfo := &runtime.CallInfo{
-		State:        &programStateManager{Mutable: db},
+		State:        &programStateManager{Journal: state.NewJournal(db)},
 		Actor:        rctx.Actor,
 		Program:      rctx.Program,
 		Fuel:         maxUnits,
@@ -128,14 +137,15 @@ func programExecuteFunc(
 		Timestamp:    rctx.Timestamp,
 		FunctionName: function,
 		Params:       bytes,
+		ReadOnly:     readOnly,
 	}
 	result, err := rt.CallProgram(ctx, callInfo)
 	if err != nil {
 		response := string(result)
-		return nil, 0, fmt.Errorf("program execution failed: %s, err: %w", response, err)
+		return nil, 0, nil, fmt.Errorf("program execution failed: %s, err: %w", response, err)
 	}
 
-	return result, callInfo.RemainingFuel(), err
+	return result, callInfo.RemainingFuel(), callInfo.Events, err
 }
 
 func multilineOutput(resp [][]byte) (response string) {
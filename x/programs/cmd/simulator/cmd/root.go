@@ -52,6 +52,7 @@ type Simulator struct {
 	enableWriterDisplaying *bool
 	lastStep               int
 	programIDStrMap        map[int]codec.Address
+	snapshots              map[string]state.Snapshot
 
 	db *state.SimpleMutable
 
@@ -135,6 +136,7 @@ func (s *Simulator) ParseCommandArgs(ctx context.Context, args []string, interpr
 func (s *Simulator) Execute(ctx context.Context) error {
 	s.lastStep = 0
 	s.programIDStrMap = make(map[int]codec.Address)
+	s.snapshots = make(map[string]state.Snapshot)
 
 	defer s.manageCleanup(ctx)
 
@@ -161,13 +163,15 @@ func (s *Simulator) BaseParser() (*argparse.Parser, []Cmd) {
 	s.reader = bufio.NewReader(stdin)
 
 	runCmd := runCmd{}
-	runCmd.New(parser, s.programIDStrMap, &s.lastStep, s.reader)
+	runCmd.New(parser, s.programIDStrMap, s.snapshots, &s.lastStep, s.reader)
+	testCmd := testCmd{}
+	testCmd.New(parser, s.programIDStrMap, s.snapshots, &s.lastStep)
 	programCmd := programCreateCmd{}
 	programCmd.New(parser)
 	keyCmd := keyCreateCmd{}
 	keyCmd.New(parser)
 
-	return parser, []Cmd{&runCmd, &programCmd, &keyCmd}
+	return parser, []Cmd{&runCmd, &testCmd, &programCmd, &keyCmd}
 }
 
 func (s *Simulator) Init() error {
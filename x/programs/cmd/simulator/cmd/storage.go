@@ -27,8 +27,10 @@ const (
 	addressStoragePrefix = 0x3
 )
 
+// programStateManager journals all changes so that the changes made by failed
+// program calls can be reverted.
 type programStateManager struct {
-	state.Mutable
+	*state.Journal
 }
 
 func (s *programStateManager) GetBalance(ctx context.Context, address codec.Address) (uint64, error) {
@@ -43,6 +45,9 @@ func (s *programStateManager) TransferBalance(ctx context.Context, from codec.Ad
 	if fromBalance < amount {
 		return errors.New("insufficient balance")
 	}
+	if err := setAccountBalance(ctx, s, from, fromBalance-amount); err != nil {
+		return err
+	}
 	toBalance, err := getAccountBalance(ctx, s, to)
 	if err != nil {
 		return err
@@ -83,6 +88,24 @@ func (s *programStateManager) SetAccountProgram(ctx context.Context, account cod
 	return setAccountProgram(ctx, s, account, programID)
 }
 
+func (s *programStateManager) GetAccountAdmin(ctx context.Context, account codec.Address) (codec.Address, error) {
+	v, err := s.GetValue(ctx, accountDataKey(account[:], []byte("admin")))
+	if errors.Is(err, database.ErrNotFound) {
+		return codec.EmptyAddress, nil
+	}
+	if err != nil {
+		return codec.EmptyAddress, err
+	}
+	return codec.Address(v), nil
+}
+
+func (s *programStateManager) SetAccountAdmin(ctx context.Context, account codec.Address, admin codec.Address) error {
+	if admin == codec.EmptyAddress {
+		return s.Remove(ctx, accountDataKey(account[:], []byte("admin")))
+	}
+	return s.Insert(ctx, accountDataKey(account[:], []byte("admin")), admin[:])
+}
+
 func (s *programStateManager) GetProgramState(account codec.Address) state.Mutable {
 	return newAccountPrefixedMutable(account, s)
 }
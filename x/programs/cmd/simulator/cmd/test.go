@@ -0,0 +1,331 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/akamensky/argparse"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+	"github.com/ava-labs/hypersdk/x/programs/runtime"
+)
+
+var _ Cmd = (*testCmd)(nil)
+
+type testCmd struct {
+	cmd *argparse.Command
+
+	file   *string
+	report *string
+	junit  *string
+
+	run runCmd
+}
+
+func (c *testCmd) New(parser *argparse.Parser, programIDStrMap map[int]codec.Address, snapshots map[string]state.Snapshot, lastStep *int) {
+	c.cmd = parser.NewCommand("test", "Run a HyperSDK program test plan and check its assertions")
+	c.file = c.cmd.String("", "file", &argparse.Options{
+		Help:     "path to the plan",
+		Required: true,
+	})
+	c.report = c.cmd.String("", "report", &argparse.Options{
+		Help: "path to write a JSON report to",
+	})
+	c.junit = c.cmd.String("", "junit", &argparse.Options{
+		Help: "path to write a JUnit XML report to",
+	})
+	c.run = runCmd{
+		lastStep:        lastStep,
+		programIDStrMap: programIDStrMap,
+		snapshots:       snapshots,
+	}
+}
+
+func (c *testCmd) Run(ctx context.Context, log logging.Logger, db *state.SimpleMutable, _ []string) (*Response, error) {
+	planBytes, err := os.ReadFile(*c.file)
+	if err != nil {
+		return newResponse(0), err
+	}
+	plan, err := unmarshalPlan(planBytes)
+	if err != nil {
+		return newResponse(0), err
+	}
+
+	report := c.RunPlan(ctx, log, db, plan)
+	for _, step := range report.Steps {
+		if err := step.Print(); err != nil {
+			return newResponse(0), err
+		}
+	}
+	if len(*c.report) > 0 {
+		if err := report.writeJSON(*c.report); err != nil {
+			return newResponse(0), err
+		}
+	}
+	if len(*c.junit) > 0 {
+		if err := report.writeJUnit(*c.junit); err != nil {
+			return newResponse(0), err
+		}
+	}
+
+	if report.Failures > 0 {
+		return newResponse(0), fmt.Errorf("%w: %d of %d steps failed", ErrPlanFailed, report.Failures, len(report.Steps))
+	}
+	return newResponse(0), nil
+}
+
+func (c *testCmd) Happened() bool {
+	return c.cmd.Happened()
+}
+
+// RunPlan runs all the steps of [plan] (even if some of them fail) and
+// reports which steps didn't meet their requirements.
+func (c *testCmd) RunPlan(ctx context.Context, log logging.Logger, db *state.SimpleMutable, plan *Plan) *Report {
+	c.run.log = log
+	report := &Report{
+		Name:  plan.Name,
+		Steps: make([]*StepReport, 0, len(plan.Steps)),
+	}
+	start := time.Now()
+	for i := range plan.Steps {
+		stepReport := c.runStep(ctx, db, &plan.Steps[i])
+		if len(stepReport.Failures) > 0 {
+			report.Failures++
+			log.Info("step failed",
+				zap.Int("step", stepReport.ID),
+				zap.Strings("failures", stepReport.Failures),
+			)
+		}
+		report.Steps = append(report.Steps, stepReport)
+	}
+	report.Time = time.Since(start).Seconds()
+
+	return report
+}
+
+func (c *testCmd) runStep(ctx context.Context, db *state.SimpleMutable, step *Step) *StepReport {
+	index := *c.run.lastStep
+	report := &StepReport{
+		ID:       index,
+		Name:     step.Name,
+		Endpoint: step.Endpoint,
+		Method:   step.Method,
+	}
+	if len(report.Name) == 0 {
+		report.Name = fmt.Sprintf("%d %s %s", index, step.Endpoint, step.Method)
+	}
+
+	start := time.Now()
+	defer func() {
+		report.Time = time.Since(start).Seconds()
+		// programs are referenced by the index of the step that created them,
+		// so steps that fail early still need to advance the index
+		*c.run.lastStep = index + 1
+	}()
+
+	c.run.step = step
+	if err := c.run.Verify(); err != nil {
+		report.Failures = []string{err.Error()}
+		return report
+	}
+	resp, err := c.run.RunStep(ctx, db)
+	if err != nil {
+		report.Failures = []string{err.Error()}
+		return report
+	}
+	report.Response = resp
+	if len(resp.Error) > 0 {
+		report.Failures = []string{resp.Error}
+		return report
+	}
+	if step.Require != nil {
+		report.Failures = c.checkRequire(ctx, db, step.Require, resp)
+	}
+
+	return report
+}
+
+// checkRequire returns a description of each requirement of [require] that
+// is not met.
+func (c *testCmd) checkRequire(ctx context.Context, db state.Mutable, require *Require, resp *Response) []string {
+	var failures []string
+
+	if require.Result != nil || len(require.Error) > 0 {
+		output, err := runtime.Deserialize[runtime.Result[runtime.RawBytes, runtime.ProgramCallErrorCode]](resp.Result.Response)
+		if err != nil {
+			return append(failures, fmt.Sprintf("step has no call result: %s", err))
+		}
+		result, ok := output.Ok()
+		code, _ := output.Err()
+		switch {
+		case require.Result != nil && !ok:
+			failures = append(failures, fmt.Sprintf("expected result %x, got error %s", require.Result, code))
+		case require.Result != nil && !bytes.Equal(require.Result, result):
+			failures = append(failures, fmt.Sprintf("expected result %x, got %x", require.Result, []byte(result)))
+		case len(require.Error) > 0 && ok:
+			failures = append(failures, fmt.Sprintf("expected error %s, got result %x", require.Error, []byte(result)))
+		case len(require.Error) > 0 && require.Error != code.String():
+			failures = append(failures, fmt.Sprintf("expected error %s, got error %s", require.Error, code))
+		}
+	}
+
+	for _, assertion := range require.Balances {
+		account, err := c.account(assertion.Account)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		balance, err := getAccountBalance(ctx, db, account)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		if balance != assertion.Balance {
+			failures = append(failures, fmt.Sprintf("expected balance %d for %s, got %d", assertion.Balance, codec.ToHex(account[:]), balance))
+		}
+	}
+
+	for _, assertion := range require.State {
+		account, err := c.account(assertion.Account)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		value, err := newAccountPrefixedMutable(account, db).GetValue(ctx, assertion.Key)
+		switch {
+		case errors.Is(err, database.ErrNotFound) && assertion.Value == nil:
+		case errors.Is(err, database.ErrNotFound):
+			failures = append(failures, fmt.Sprintf("expected %x for key %x of %s, got nothing", assertion.Value, assertion.Key, codec.ToHex(account[:])))
+		case err != nil:
+			failures = append(failures, err.Error())
+		case assertion.Value == nil:
+			failures = append(failures, fmt.Sprintf("expected no value for key %x of %s, got %x", assertion.Key, codec.ToHex(account[:]), value))
+		case !bytes.Equal(assertion.Value, value):
+			failures = append(failures, fmt.Sprintf("expected %x for key %x of %s, got %x", assertion.Value, assertion.Key, codec.ToHex(account[:]), value))
+		}
+	}
+
+	return failures
+}
+
+// account returns the address of the account referenced by [param], which is
+// either an id or an address parameter.
+func (c *testCmd) account(param Parameter) (codec.Address, error) {
+	if param.Type != ID && param.Type != Address {
+		return codec.EmptyAddress, fmt.Errorf("%w: %s is not an account", ErrInvalidParamType, param.Type)
+	}
+	params, err := c.run.createCallParams([]Parameter{param})
+	if err != nil {
+		return codec.EmptyAddress, err
+	}
+	return codec.ToAddress(params[0].Value)
+}
+
+type Report struct {
+	// The name of the plan.
+	Name string `json:"name"`
+	// The number of steps that failed.
+	Failures int `json:"failures"`
+	// The time it took to run the plan in seconds.
+	Time float64 `json:"time"`
+	// The reports of the steps of the plan.
+	Steps []*StepReport `json:"steps"`
+}
+
+type StepReport struct {
+	// The index of the step.
+	ID int `json:"id"`
+	// The name of the step.
+	Name     string   `json:"name"`
+	Endpoint Endpoint `json:"endpoint"`
+	Method   string   `json:"method"`
+	// The requirements of the step that were not met.
+	Failures []string `json:"failures,omitempty"`
+	// The response of the step if it ran.
+	Response *Response `json:"response,omitempty"`
+	// The time it took to run the step in seconds.
+	Time float64 `json:"time"`
+}
+
+func (r *StepReport) Print() error {
+	jsonBytes, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to marshal step report: %w", err)
+	}
+
+	fmt.Println(string(jsonBytes))
+	return nil
+}
+
+func (r *Report) writeJSON(path string) error {
+	jsonBytes, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+	return os.WriteFile(path, jsonBytes, 0o600)
+}
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+func (r *Report) writeJUnit(path string) error {
+	suite := junitTestSuite{
+		Name:      r.Name,
+		Tests:     len(r.Steps),
+		Failures:  r.Failures,
+		Time:      fmt.Sprintf("%.3f", r.Time),
+		TestCases: make([]junitTestCase, 0, len(r.Steps)),
+	}
+	for _, step := range r.Steps {
+		testCase := junitTestCase{
+			Name:      step.Name,
+			ClassName: r.Name,
+			Time:      fmt.Sprintf("%.3f", step.Time),
+		}
+		if len(step.Failures) > 0 {
+			failure := &junitFailure{Message: step.Failures[0]}
+			for _, f := range step.Failures {
+				failure.Contents += f + "\n"
+			}
+			testCase.Failure = failure
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	xmlBytes, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal junit report: %w", err)
+	}
+	return os.WriteFile(path, append([]byte(xml.Header), xmlBytes...), 0o600)
+}
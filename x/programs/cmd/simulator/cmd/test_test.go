@@ -0,0 +1,179 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/trace"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/x/merkledb"
+	"github.com/bytecodealliance/wasmtime-go/v14"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// testProgram stores "k" => "v", emits a "t" => "d" event and returns "ok"
+// when "put" is called and runs out of fuel when "spin" is called.
+const testProgram = `
+(module
+  (import "state" "put" (func $put (param i32 i32)))
+  (import "program" "set_call_result" (func $set_call_result (param i32 i32)))
+  (import "events" "emit" (func $emit (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $next (mut i32) (i32.const 1024))
+  (data (i32.const 8) "\01\00\00\00\01\00\00\00k\01\00\00\00v")
+  (data (i32.const 32) "ok")
+  (data (i32.const 40) "\01\00\00\00t\01\00\00\00d")
+  (func (export "alloc") (param $size i32) (result i32)
+    (local $ptr i32)
+    (local.set $ptr (global.get $next))
+    (global.set $next (i32.add (global.get $next) (local.get $size)))
+    (local.get $ptr))
+  (func (export "put") (param i32)
+    (call $put (i32.const 8) (i32.const 14))
+    (call $emit (i32.const 40) (i32.const 10))
+    (call $set_call_result (i32.const 32) (i32.const 2)))
+  (func (export "spin") (param i32)
+    (loop $loop (br $loop))))
+`
+
+func TestRunPlan(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	program, err := wasmtime.Wat2Wasm(testProgram)
+	require.NoError(err)
+	programPath := filepath.Join(dir, "program.wasm")
+	require.NoError(os.WriteFile(programPath, program, 0o600))
+
+	stateDB, err := merkledb.New(ctx, memdb.New(), merkledb.Config{
+		BranchFactor:                merkledb.BranchFactor16,
+		RootGenConcurrency:          1,
+		HistoryLength:               100,
+		ValueNodeCacheSize:          units.MiB,
+		IntermediateNodeCacheSize:   units.MiB,
+		IntermediateWriteBufferSize: units.KiB,
+		IntermediateWriteBatchSize:  units.KiB,
+		Tracer:                      trace.Noop,
+	})
+	require.NoError(err)
+	db := state.NewSimpleMutable(stateDB)
+
+	testContext, err := json.Marshal(SimulatorTestContext{ProgramID: 0})
+	require.NoError(err)
+	contextParams := []Parameter{{Type: TestContext, Value: testContext}}
+	program0 := Parameter{Type: ID, Value: []byte{0, 0, 0, 0, 0, 0, 0, 0}}
+	stored := StateAssertion{Account: program0, Key: []byte("k"), Value: []byte("v")}
+	plan := &Plan{
+		Name: "test",
+		Steps: []Step{
+			{
+				Endpoint: EndpointExecute,
+				Method:   ProgramCreate,
+				Params:   []Parameter{{Type: Path, Value: []byte(programPath)}},
+			},
+			{
+				Endpoint: EndpointSnapshot,
+				Method:   "created",
+			},
+			{
+				Endpoint: EndpointExecute,
+				Method:   "put",
+				MaxUnits: 1_000_000,
+				Params:   contextParams,
+				Require: &Require{
+					Result:   []byte("ok"),
+					Balances: []BalanceAssertion{{Account: program0}},
+					State:    []StateAssertion{stored},
+				},
+			},
+			{
+				Endpoint: EndpointExecute,
+				Method:   "spin",
+				MaxUnits: 10_000,
+				Params:   contextParams,
+				Require:  &Require{Error: "OutOfFuel"},
+			},
+			{
+				Endpoint: EndpointRollback,
+				Method:   "created",
+				Require: &Require{
+					State: []StateAssertion{{Account: program0, Key: []byte("k")}},
+				},
+			},
+			// fails since the state was reverted
+			{
+				Name:     "reverted",
+				Endpoint: EndpointReadOnly,
+				Method:   "put",
+				Params:   contextParams,
+				Require: &Require{
+					State: []StateAssertion{stored},
+				},
+			},
+			// fails since the snapshot doesn't exist
+			{
+				Endpoint: EndpointRollback,
+				Method:   "unknown",
+			},
+		},
+	}
+
+	lastStep := 0
+	c := &testCmd{
+		run: runCmd{
+			lastStep:        &lastStep,
+			programIDStrMap: map[int]codec.Address{},
+			snapshots:       map[string]state.Snapshot{},
+		},
+	}
+	report := c.RunPlan(ctx, logging.NoLog{}, db, plan)
+	require.Len(report.Steps, len(plan.Steps))
+	for i, step := range report.Steps {
+		require.Equal(i, step.ID)
+		if i < 5 {
+			require.Empty(step.Failures, step.Name)
+		}
+	}
+	require.Equal(2, report.Failures)
+	program0Address := c.run.programIDStrMap[0]
+	require.Equal([]Event{{
+		Program: codec.ToHex(program0Address[:]),
+		Topic:   "t",
+		Data:    []byte("d"),
+	}}, report.Steps[2].Response.Result.Events)
+	require.Equal("reverted", report.Steps[5].Name)
+	require.Len(report.Steps[5].Failures, 1)
+	require.Contains(report.Steps[6].Failures[0], ErrSnapshotNotFound.Error())
+
+	// Reports can be read by CI
+	reportPath := filepath.Join(dir, "report.xml")
+	require.NoError(report.writeJUnit(reportPath))
+	reportBytes, err := os.ReadFile(reportPath)
+	require.NoError(err)
+	var suite junitTestSuite
+	require.NoError(xml.Unmarshal(reportBytes, &suite))
+	require.Equal(len(plan.Steps), suite.Tests)
+	require.Equal(2, suite.Failures)
+	require.NotNil(suite.TestCases[5].Failure)
+
+	reportPath = filepath.Join(dir, "report.json")
+	require.NoError(report.writeJSON(reportPath))
+	reportBytes, err = os.ReadFile(reportPath)
+	require.NoError(err)
+	var parsed Report
+	require.NoError(json.Unmarshal(reportBytes, &parsed))
+	require.Equal(2, parsed.Failures)
+}
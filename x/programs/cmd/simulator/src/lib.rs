@@ -227,6 +227,19 @@ pub struct StepResult {
     /// The result of the function call.
     #[serde(deserialize_with = "base64_decode")]
     response: Vec<u8>,
+    /// The events emitted by the function call.
+    #[serde(default)]
+    pub events: Vec<Event>,
+}
+
+/// An event emitted by a program.
+#[derive(Debug, Deserialize)]
+pub struct Event {
+    /// The hex encoded address of the program that emitted the event.
+    pub program: String,
+    pub topic: String,
+    #[serde(deserialize_with = "base64_decode")]
+    pub data: Vec<u8>,
 }
 
 #[derive(Error, Debug)]
@@ -4,10 +4,23 @@
 package runtime
 
 import (
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/bytecodealliance/wasmtime-go/v14"
+	"golang.org/x/exp/maps"
+
+	"github.com/ava-labs/hypersdk/utils"
+
+	goruntime "runtime"
 )
 
+const wasmtimeModule = "github.com/bytecodealliance/wasmtime-go/v14"
+
 type CompileStrategy uint8
 
 const (
@@ -26,6 +39,8 @@ var (
 	DefaultMultiValue           = false
 
 	defaultProgramCacheSize             = 10 * units.MiB
+	defaultMaxCallDepth                 = 16
+	defaultMaxMemoryPages               = uint32(512) // 32 MiB
 	defaultWasmThreads                  = false
 	defaultFuelMetering                 = true
 	defaultWasmMultiMemory              = false
@@ -40,20 +55,92 @@ var (
 
 // NewConfig creates a new engine config with default settings
 func NewConfig() *Config {
-	return &Config{
-		wasmConfig:       DefaultWasmtimeConfig(),
+	c := &Config{
+		wasmConfig:       wasmtime.NewConfig(),
+		settings:         map[string]string{},
 		ProgramCacheSize: defaultProgramCacheSize,
+		MaxCallDepth:     defaultMaxCallDepth,
+		MaxMemoryPages:   defaultMaxMemoryPages,
 	}
+
+	// non configurable defaults
+	c.SetCraneliftOptLevel(defaultCraneliftOptLevel)
+	c.SetConsumeFuel(defaultFuelMetering)
+	c.SetWasmThreads(defaultWasmThreads)
+	c.SetWasmMultiMemory(defaultWasmMultiMemory)
+	c.SetWasmMemory64(defaultWasmMemory64)
+	c.SetStrategy(defaultCompilerStrategy)
+	c.SetEpochInterruption(defaultEpochInterruption)
+	c.SetCraneliftFlag("enable_nan_canonicalization", defaultNaNCanonicalization)
+
+	// TODO: expose these knobs for developers
+	c.SetCraneliftDebugVerifier(defaultEnableCraneliftDebugVerifier)
+	c.SetDebugInfo(defaultEnableDebugInfo)
+	return c
 }
 
 // Config is wrapper for wasmtime.Config
 type Config struct {
 	wasmConfig *wasmtime.Config
 
+	// settings are the settings applied to [wasmConfig], which identify the
+	// code it compiles (see [Config.Hash])
+	settings map[string]string
+
 	// CompileStrategy helps the engine to understand if the files has been precompiled.
 	CompileStrategy CompileStrategy `json:"compileStrategy,omitempty" yaml:"compile_strategy,omitempty"`
 
 	ProgramCacheSize int
+
+	// ProgramCacheDir is the directory compiled programs are persisted to (so
+	// that they don't need to be compiled again after a restart). If empty,
+	// compiled programs are only cached in memory.
+	ProgramCacheDir string
+
+	// MaxCallDepth is the maximum number of nested program calls (made by
+	// programs calling other programs).
+	MaxCallDepth int
+
+	// MaxMemoryPages is the maximum size (in 64 KiB pages) of the memory of a
+	// program. Programs that declare larger memories are rejected by
+	// [WasmRuntime.ValidateProgram] and memories can't grow past it.
+	MaxMemoryPages uint32
+}
+
+func (c *Config) set(name string, value any) {
+	c.settings[name] = fmt.Sprint(value)
+}
+
+// Hash identifies the code compiled with this config (by the version of
+// wasmtime in use, for the current platform). Code compiled with a config can
+// only be loaded by an engine created with a config with the same hash.
+func (c *Config) Hash() ids.ID {
+	names := maps.Keys(c.settings)
+	slices.Sort(names)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s/%s/%s\n", wasmtimeVersion(), goruntime.GOOS, goruntime.GOARCH)
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s=%s\n", name, c.settings[name])
+	}
+	return utils.ToID([]byte(b.String()))
+}
+
+// wasmtimeVersion returns the version of the wasmtime module this binary was
+// built with.
+func wasmtimeVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == wasmtimeModule {
+			if dep.Replace != nil {
+				return dep.Replace.Path + "@" + dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return ""
 }
 
 // Get returns the underlying wasmtime config.
@@ -75,32 +162,38 @@ func (c *Config) CacheConfigLoadDefault() error {
 
 // EnableCraneliftFlag enables a target-specific flag in Cranelift.
 func (c *Config) EnableCraneliftFlag(flag string) {
+	c.set("cranelift:"+flag, true)
 	c.wasmConfig.EnableCraneliftFlag(flag)
 }
 
 // SetConsumeFuel configures whether fuel is enabled.
 func (c *Config) SetConsumeFuel(enabled bool) {
+	c.set("consume_fuel", enabled)
 	c.wasmConfig.SetConsumeFuel(enabled)
 }
 
 // SetCraneliftDebugVerifier configures whether the cranelift debug verifier
 // will be active when cranelift is used to compile wasm code.
 func (c *Config) SetCraneliftDebugVerifier(enabled bool) {
+	c.set("cranelift_debug_verifier", enabled)
 	c.wasmConfig.SetCraneliftDebugVerifier(enabled)
 }
 
 // SetCraneliftFlag sets a target-specific flag in Cranelift to the specified value.
 func (c *Config) SetCraneliftFlag(name string, value string) {
+	c.set("cranelift:"+name, value)
 	c.wasmConfig.SetCraneliftFlag(name, value)
 }
 
 // SetCraneliftOptLevel configures the cranelift optimization level for generated code.
 func (c *Config) SetCraneliftOptLevel(level wasmtime.OptLevel) {
+	c.set("cranelift_opt_level", level)
 	c.wasmConfig.SetCraneliftOptLevel(level)
 }
 
 // SetDebugInfo configures whether dwarf debug information for JIT code is enabled
 func (c *Config) SetDebugInfo(enabled bool) {
+	c.set("debug_info", enabled)
 	c.wasmConfig.SetDebugInfo(enabled)
 }
 
@@ -108,82 +201,79 @@ func (c *Config) SetDebugInfo(enabled bool) {
 // interrupt WebAssembly execution when the current engine epoch exceeds a
 // defined threshold.
 func (c *Config) SetEpochInterruption(enable bool) {
+	c.set("epoch_interruption", enable)
 	c.wasmConfig.SetEpochInterruption(enable)
 }
 
 // SetMaxWasmStack configures the maximum stack size, in bytes, that JIT code can use.
 func (c *Config) SetMaxWasmStack(size int) {
+	c.set("max_wasm_stack", size)
 	c.wasmConfig.SetMaxWasmStack(size)
 }
 
 // SetProfiler configures what profiler strategy to use for generated code.
 func (c *Config) SetProfiler(profiler wasmtime.ProfilingStrategy) {
+	c.set("profiler", profiler)
 	c.wasmConfig.SetProfiler(profiler)
 }
 
 // SetStrategy configures what compilation strategy is used to compile wasm code.
 func (c *Config) SetStrategy(strategy wasmtime.Strategy) {
+	c.set("strategy", strategy)
 	c.wasmConfig.SetStrategy(strategy)
 }
 
 // SetTarget configures the target triple that this configuration will produce machine code for.
 func (c *Config) SetTarget(target string) error {
+	c.set("target", target)
 	return c.wasmConfig.SetTarget(target)
 }
 
 // SetWasmBulkMemory configures whether the wasm bulk memory proposal is enabled.
 func (c *Config) SetWasmBulkMemory(enabled bool) {
+	c.set("wasm_bulk_memory", enabled)
 	c.wasmConfig.SetWasmBulkMemory(enabled)
 }
 
 // SetWasmMemory64 configures whether the wasm memory64 proposal is enabled.
 func (c *Config) SetWasmMemory64(enabled bool) {
+	c.set("wasm_memory64", enabled)
 	c.wasmConfig.SetWasmMemory64(enabled)
 }
 
 // SetWasmMultiMemory configures whether the wasm multi memory proposal is enabled.
 func (c *Config) SetWasmMultiMemory(enabled bool) {
+	c.set("wasm_multi_memory", enabled)
 	c.wasmConfig.SetWasmMultiMemory(enabled)
 }
 
 // SetWasmMultiValue configures whether the wasm multi value proposal is enabled.
 func (c *Config) SetWasmMultiValue(enabled bool) {
+	c.set("wasm_multi_value", enabled)
 	c.wasmConfig.SetWasmMultiValue(enabled)
 }
 
 // SetWasmReferenceTypes configures whether the wasm reference types proposal is enabled.
 func (c *Config) SetWasmReferenceTypes(enabled bool) {
+	c.set("wasm_reference_types", enabled)
 	c.wasmConfig.SetWasmReferenceTypes(enabled)
 }
 
 // SetWasmSIMD configures whether the wasm SIMD proposal is enabled.
 func (c *Config) SetWasmSIMD(enabled bool) {
+	c.set("wasm_simd", enabled)
 	c.wasmConfig.SetWasmSIMD(enabled)
 }
 
 // SetWasmThreads configures whether the wasm threads proposal is enabled.
 func (c *Config) SetWasmThreads(enabled bool) {
+	c.set("wasm_threads", enabled)
 	c.wasmConfig.SetWasmThreads(enabled)
 }
 
 // DefaultWasmtimeConfig returns a new wasmtime config with default settings.
 func DefaultWasmtimeConfig() *wasmtime.Config {
-	cfg := wasmtime.NewConfig()
-
-	// non configurable defaults
-	cfg.SetCraneliftOptLevel(defaultCraneliftOptLevel)
-	cfg.SetConsumeFuel(defaultFuelMetering)
-	cfg.SetWasmThreads(defaultWasmThreads)
-	cfg.SetWasmMultiMemory(defaultWasmMultiMemory)
-	cfg.SetWasmMemory64(defaultWasmMemory64)
-	cfg.SetStrategy(defaultCompilerStrategy)
-	cfg.SetEpochInterruption(defaultEpochInterruption)
-	cfg.SetCraneliftFlag("enable_nan_canonicalization", defaultNaNCanonicalization)
-
-	// TODO: expose these knobs for developers
-	cfg.SetCraneliftDebugVerifier(defaultEnableCraneliftDebugVerifier)
-	cfg.SetDebugInfo(defaultEnableDebugInfo)
-	return cfg
+	return NewConfig().wasmConfig
 }
 
 // NewConfigBuilder returns a new engine configuration builder with default settings.
@@ -9,6 +9,20 @@ import (
 	"github.com/bytecodealliance/wasmtime-go/v14"
 )
 
+var (
+	ErrValueTooLarge       = errors.New("value too large")
+	ErrCallDepthExceeded   = errors.New("call depth exceeded")
+	ErrReentrancy          = errors.New("reentrant call to non-reentrant program")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrReadOnly            = errors.New("state can't be modified by read-only calls")
+	ErrInvalidProgram      = errors.New("invalid program")
+	ErrUnsupportedImport   = errors.New("unsupported import")
+	ErrMemoryTooLarge      = errors.New("memory too large")
+	ErrMissingExport       = errors.New("missing export")
+	ErrTimeout             = errors.New("call timed out")
+)
+
 func convertToTrap(err error) *wasmtime.Trap {
 	if err == nil {
 		return nil
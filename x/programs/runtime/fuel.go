@@ -0,0 +1,90 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"github.com/ava-labs/avalanchego/utils/math"
+
+	"github.com/ava-labs/hypersdk/keys"
+)
+
+// The default costs of the host functions are estimates relative to the fuel
+// charged by wasmtime (1 per wasm instruction) and have not been measured: a
+// byte copied across the wasm boundary costs as much as ~10 instructions, an
+// entry ~1,000, a state chunk ~5,000 and a level of nesting ~10,000.
+//
+// The costs are part of consensus, so they can't be changed by a single node.
+// A chain that needs measured costs should run the fuel-calibration tool (see
+// x/programs/cmd/fuel-calibration) on its reference hardware and set its output
+// with [WasmRuntime.SetFuelCost] on every node (i.e. when the chain is created
+// or at an upgrade).
+const (
+	defaultFuelPerByte  = 10
+	defaultFuelPerEntry = 1000
+	defaultFuelPerChunk = 5000
+	defaultFuelPerDepth = 10000
+)
+
+// FuelCost is the fuel charged for calling a host function. [Base] is charged
+// for every call and the remaining costs are charged in proportion to the
+// resources used by the call.
+type FuelCost struct {
+	// Base is charged for every call.
+	Base uint64 `json:"base"`
+
+	// PerByte is charged for every byte of input read from and output written
+	// to the memory of the calling program.
+	PerByte uint64 `json:"perByte"`
+
+	// PerEntry is charged for every entry (e.g. key-value pair) processed.
+	PerEntry uint64 `json:"perEntry"`
+
+	// PerChunk is charged for every storage chunk (see [keys.NumChunks]) of
+	// the values read from or written to state. This aligns the cost of state
+	// access with its cost on-chain.
+	PerChunk uint64 `json:"perChunk"`
+
+	// PerDepth is charged for every level of nesting of the call made (for
+	// host functions that call other programs).
+	PerDepth uint64 `json:"perDepth"`
+}
+
+// consume charges [count] * [price] fuel.
+func consume(callInfo *CallInfo, price uint64, count uint64) error {
+	if price == 0 || count == 0 {
+		return nil
+	}
+	fuel, err := math.Mul64(price, count)
+	if err != nil {
+		return err
+	}
+	return callInfo.ConsumeFuel(fuel)
+}
+
+// consumeBytes charges [FuelCost.PerByte] for [n] bytes passed to or returned
+// from the host function being called.
+func (c *CallInfo) consumeBytes(n int) error {
+	return consume(c, c.hostCost.PerByte, uint64(n))
+}
+
+// consumeEntries charges [FuelCost.PerEntry] for [n] entries processed by the
+// host function being called.
+func (c *CallInfo) consumeEntries(n int) error {
+	return consume(c, c.hostCost.PerEntry, uint64(n))
+}
+
+// consumeChunks charges [FuelCost.PerChunk] for each chunk of [value].
+func (c *CallInfo) consumeChunks(value []byte) error {
+	chunks, ok := keys.NumChunks(value)
+	if !ok {
+		return ErrValueTooLarge
+	}
+	return consume(c, c.hostCost.PerChunk, uint64(chunks))
+}
+
+// consumeDepth charges [FuelCost.PerDepth] for each level of nesting of a call
+// made by the host function being called.
+func (c *CallInfo) consumeDepth() error {
+	return consume(c, c.hostCost.PerDepth, uint64(len(c.callers))+1)
+}
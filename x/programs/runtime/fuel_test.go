@@ -0,0 +1,51 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/bytecodealliance/wasmtime-go/v14"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+func TestFuelCost(t *testing.T) {
+	require := require.New(t)
+
+	store := wasmtime.NewStore(wasmtime.NewEngineWithConfig(NewConfig().wasmConfig))
+	require.NoError(store.AddFuel(1_000_000))
+	callInfo := &CallInfo{
+		Fuel: 1_000_000,
+		inst: &ProgramInstance{store: store},
+		hostCost: FuelCost{
+			PerByte:  1,
+			PerEntry: 10,
+			PerChunk: 100,
+			PerDepth: 1000,
+		},
+		callers: []codec.Address{codec.EmptyAddress},
+	}
+
+	require.NoError(callInfo.consumeBytes(5))
+	require.Equal(uint64(1_000_000-5), callInfo.RemainingFuel())
+
+	require.NoError(callInfo.consumeEntries(2))
+	require.Equal(uint64(1_000_000-25), callInfo.RemainingFuel())
+
+	// values are charged by chunk (65 bytes span 2 chunks)
+	require.NoError(callInfo.consumeChunks(make([]byte, 65)))
+	require.Equal(uint64(1_000_000-225), callInfo.RemainingFuel())
+
+	// nested calls are charged by the depth of the new call
+	require.NoError(callInfo.consumeDepth())
+	require.Equal(uint64(1_000_000-2225), callInfo.RemainingFuel())
+
+	// overflows run out of fuel
+	callInfo.hostCost.PerByte = ^uint64(0)
+	require.Error(callInfo.consumeBytes(2))
+	callInfo.hostCost.PerByte = 1_000_000
+	require.Error(callInfo.consumeBytes(1))
+}
@@ -9,9 +9,9 @@ import (
 	"github.com/ava-labs/hypersdk/codec"
 )
 
-const (
-	sendBalanceCost = 10000
-	getBalanceCost  = 10000
+var (
+	sendBalanceCost = FuelCost{Base: 10000, PerByte: defaultFuelPerByte}
+	getBalanceCost  = FuelCost{Base: 10000, PerByte: defaultFuelPerByte}
 )
 
 type transferBalanceInput struct {
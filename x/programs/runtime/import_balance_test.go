@@ -21,7 +21,7 @@ func TestImportBalanceSendBalanceToAnotherProgram(t *testing.T) {
 	defer cancel()
 	program := newTestProgram(ctx, "balance")
 	r := program.Runtime
-	stateManager := r.StateManager.(test.StateManager)
+	stateManager := r.StateManager.(*test.StateManager)
 	stateManager.Balances[program.Address] = 3
 
 	// create a new instance of the balance program
@@ -54,7 +54,7 @@ func TestImportBalanceGetBalance(t *testing.T) {
 	defer cancel()
 	actor := codec.CreateAddress(0, ids.GenerateTestID())
 	program := newTestProgram(ctx, "balance")
-	program.Runtime.StateManager.(test.StateManager).Balances[actor] = 3
+	program.Runtime.StateManager.(*test.StateManager).Balances[actor] = 3
 	result, err := program.WithActor(actor).Call("balance")
 	require.NoError(err)
 	require.Equal(uint64(3), into[uint64](result))
@@ -67,7 +67,7 @@ func TestImportBalanceSend(t *testing.T) {
 	defer cancel()
 	actor := codec.CreateAddress(0, ids.GenerateTestID())
 	program := newTestProgram(ctx, "balance")
-	program.Runtime.StateManager.(test.StateManager).Balances[program.Address] = 3
+	program.Runtime.StateManager.(*test.StateManager).Balances[program.Address] = 3
 	result, err := program.Call("send_balance", actor)
 	require.NoError(err)
 	require.True(into[bool](result))
@@ -0,0 +1,30 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+var emitCost = FuelCost{Base: 10000, PerByte: defaultFuelPerByte}
+
+type eventInput struct {
+	Topic string
+	Data  []byte
+}
+
+// NewEventsModule returns the module programs use to emit events, which are
+// returned in [CallInfo.Events] when the call completes. Unlike the log module,
+// events are available in release builds, so they can be stored on-chain.
+func NewEventsModule() *ImportModule {
+	return &ImportModule{
+		Name: "events",
+		HostFunctions: map[string]HostFunction{
+			"emit": {FuelCost: emitCost, Function: FunctionNoOutput[eventInput](func(callInfo *CallInfo, input eventInput) error {
+				callInfo.Events = append(callInfo.Events, Event{
+					Program: callInfo.Program,
+					Topic:   input.Topic,
+					Data:    input.Data,
+				})
+				return nil
+			})},
+		},
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/bytecodealliance/wasmtime-go/v14"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/x/programs/test"
+)
+
+// eventsProgram emits a "t" => "d" event when "emit" is called, emits it and
+// then panics when "fail" is called and, when "call" is called, emits it
+// before calling "emit" and "fail" of the program whose address is passed as
+// params.
+const eventsProgram = `
+(module
+  (import "events" "emit" (func $emit (param i32 i32)))
+  (import "program" "call_program" (func $call_program (param i32 i32) (result i32)))
+  (memory (export "memory") 1)
+  (global $next (mut i32) (i32.const 4096))
+  (data (i32.const 8) "\01\00\00\00t\01\00\00\00d")
+  (data (i32.const 2081) "\04\00\00\00emit\00\00\00\00\40\42\0f\00\00\00\00\00\00\00\00\00\00\00\00\00")
+  (data (i32.const 3105) "\04\00\00\00fail\00\00\00\00\40\42\0f\00\00\00\00\00\00\00\00\00\00\00\00\00")
+  (func (export "alloc") (param $size i32) (result i32)
+    (local $ptr i32)
+    (local.set $ptr (global.get $next))
+    (global.set $next (i32.add (global.get $next) (local.get $size)))
+    (local.get $ptr))
+  (func (export "emit") (param i32)
+    (call $emit (i32.const 8) (i32.const 10)))
+  (func (export "fail") (param i32)
+    (call $emit (i32.const 8) (i32.const 10))
+    unreachable)
+  (func (export "call") (param $ctx i32)
+    (local $i i32)
+    ;; copy the address following the context into the call inputs
+    (loop $copy
+      (i32.store8
+        (i32.add (i32.const 2048) (local.get $i))
+        (i32.load8_u (i32.add (local.get $ctx) (i32.add (i32.const 114) (local.get $i)))))
+      (i32.store8
+        (i32.add (i32.const 3072) (local.get $i))
+        (i32.load8_u (i32.add (local.get $ctx) (i32.add (i32.const 114) (local.get $i)))))
+      (local.set $i (i32.add (local.get $i) (i32.const 1)))
+      (br_if $copy (i32.lt_u (local.get $i) (i32.const 33))))
+    (call $emit (i32.const 8) (i32.const 10))
+    (drop (call $call_program (i32.const 2048) (i32.const 61)))
+    (drop (call $call_program (i32.const 3072) (i32.const 61)))))
+`
+
+func TestImportEvents(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	program, err := wasmtime.Wat2Wasm(eventsProgram)
+	require.NoError(err)
+	var (
+		programID = ids.GenerateTestID()
+		caller    = codec.CreateAddress(0, ids.GenerateTestID())
+		callee    = codec.CreateAddress(0, ids.GenerateTestID())
+	)
+	state := &watStateManager{
+		StateManager: &test.StateManager{
+			AccountMap: map[codec.Address]ids.ID{caller: programID, callee: programID},
+			Mu:         test.NewTestDB(),
+		},
+		programs: map[ids.ID][]byte{programID: program},
+	}
+	r := NewRuntime(NewConfig(), logging.NoLog{})
+
+	// Events of failed calls are discarded
+	callInfo := &CallInfo{
+		State:        state,
+		Program:      callee,
+		FunctionName: "fail",
+		Fuel:         10_000_000,
+	}
+	_, err = r.CallProgram(ctx, callInfo)
+	require.Error(err)
+	require.Empty(callInfo.Events)
+
+	// Events of nested calls are returned in the order they were emitted
+	callInfo = &CallInfo{
+		State:        state,
+		Program:      caller,
+		FunctionName: "call",
+		Params:       callee[:],
+		Fuel:         10_000_000,
+	}
+	_, err = r.CallProgram(ctx, callInfo)
+	require.NoError(err)
+	require.Equal([]Event{
+		{Program: caller, Topic: "t", Data: []byte("d")},
+		{Program: callee, Topic: "t", Data: []byte("d")},
+	}, callInfo.Events)
+
+	// Events are charged by size
+	emit := func() uint64 {
+		callInfo := &CallInfo{
+			State:        state,
+			Program:      callee,
+			FunctionName: "emit",
+			Fuel:         10_000_000,
+		}
+		_, err := r.CallProgram(ctx, callInfo)
+		require.NoError(err)
+		return callInfo.RemainingFuel()
+	}
+	remaining := emit()
+	require.True(r.SetFuelCost("events", "emit", FuelCost{Base: emitCost.Base}))
+	require.Equal(remaining+10*defaultFuelPerByte, emit())
+}
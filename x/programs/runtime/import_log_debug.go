@@ -10,7 +10,7 @@ import (
 	"os"
 )
 
-const logCost = 1000
+var logCost = FuelCost{Base: 1000, PerByte: defaultFuelPerByte}
 
 func NewLogModule() *ImportModule {
 	return &ImportModule{
@@ -5,7 +5,7 @@
 
 package runtime
 
-const logCost = 1000
+var logCost = FuelCost{Base: 1000, PerByte: defaultFuelPerByte}
 
 func NewLogModule() *ImportModule {
 	return &ImportModule{
@@ -6,6 +6,7 @@ package runtime
 import (
 	"context"
 	"errors"
+	"fmt"
 	"slices"
 
 	"github.com/ava-labs/avalanchego/ids"
@@ -16,11 +17,21 @@ import (
 
 type ProgramCallErrorCode byte
 
-const (
-	callProgramCost   = 10000
-	setCallResultCost = 10000
-	remainingFuelCost = 10000
-	deployCost        = 10000
+var (
+	callProgramCost = FuelCost{
+		Base:     10000,
+		PerByte:  defaultFuelPerByte,
+		PerDepth: defaultFuelPerDepth,
+	}
+	setCallResultCost = FuelCost{Base: 10000, PerByte: defaultFuelPerByte}
+	remainingFuelCost = FuelCost{Base: 10000}
+	deployCost        = FuelCost{Base: 10000, PerByte: defaultFuelPerByte}
+	upgradeCost       = FuelCost{
+		Base:     10000,
+		PerByte:  defaultFuelPerByte,
+		PerDepth: defaultFuelPerDepth,
+	}
+	setAdminCost = FuelCost{Base: 10000, PerByte: defaultFuelPerByte}
 )
 
 const (
@@ -28,8 +39,32 @@ const (
 	CallPanicked
 	OutOfFuel
 	InsufficientBalance
+	CallDepthExceeded
+	Reentrancy
+	Unauthorized
 )
 
+func (c ProgramCallErrorCode) String() string {
+	switch c {
+	case ExecutionFailure:
+		return "ExecutionFailure"
+	case CallPanicked:
+		return "CallPanicked"
+	case OutOfFuel:
+		return "OutOfFuel"
+	case InsufficientBalance:
+		return "InsufficientBalance"
+	case CallDepthExceeded:
+		return "CallDepthExceeded"
+	case Reentrancy:
+		return "Reentrancy"
+	case Unauthorized:
+		return "Unauthorized"
+	default:
+		return fmt.Sprintf("ProgramCallErrorCode(%d)", c)
+	}
+}
+
 type callProgramInput struct {
 	Program      codec.Address
 	FunctionName string
@@ -38,12 +73,29 @@ type callProgramInput struct {
 	Value        uint64
 }
 
+type upgradeInput struct {
+	Account   codec.Address
+	ProgramID ids.ID
+	Params    []byte
+	Fuel      uint64
+}
+
 type deployProgramInput struct {
 	ProgramID           ids.ID
 	AccountCreationData []byte
 }
 
 func ExtractProgramCallErrorCode(err error) (ProgramCallErrorCode, bool) {
+	switch {
+	case errors.Is(err, ErrInsufficientBalance):
+		return InsufficientBalance, true
+	case errors.Is(err, ErrCallDepthExceeded):
+		return CallDepthExceeded, true
+	case errors.Is(err, ErrReentrancy):
+		return Reentrancy, true
+	case errors.Is(err, ErrUnauthorized):
+		return Unauthorized, true
+	}
 	var trap *wasmtime.Trap
 	if errors.As(err, &trap) {
 		switch *trap.Code() {
@@ -65,6 +117,9 @@ func NewProgramModule(r *WasmRuntime) *ImportModule {
 			"call_program": {FuelCost: callProgramCost, Function: Function[callProgramInput, Result[RawBytes, ProgramCallErrorCode]](func(callInfo *CallInfo, input callProgramInput) (Result[RawBytes, ProgramCallErrorCode], error) {
 				newInfo := *callInfo
 
+				if err := callInfo.consumeDepth(); err != nil {
+					return Err[RawBytes, ProgramCallErrorCode](OutOfFuel), nil
+				}
 				if err := callInfo.ConsumeFuel(input.Fuel); err != nil {
 					return Err[RawBytes, ProgramCallErrorCode](OutOfFuel), nil
 				}
@@ -75,6 +130,7 @@ func NewProgramModule(r *WasmRuntime) *ImportModule {
 				newInfo.Params = input.Params
 				newInfo.Fuel = input.Fuel
 				newInfo.Value = input.Value
+				newInfo.callers = append(slices.Clip(callInfo.callers), callInfo.Program)
 
 				result, err := r.CallProgram(
 					context.Background(),
@@ -88,9 +144,51 @@ func NewProgramModule(r *WasmRuntime) *ImportModule {
 
 				// return any remaining fuel to the calling program
 				callInfo.AddFuel(newInfo.RemainingFuel())
+				callInfo.Events = newInfo.Events
 
 				return Ok[RawBytes, ProgramCallErrorCode](result), nil
 			})},
+			"upgrade": {FuelCost: upgradeCost, Function: Function[upgradeInput, Result[Unit, ProgramCallErrorCode]](func(callInfo *CallInfo, input upgradeInput) (Result[Unit, ProgramCallErrorCode], error) {
+				newInfo := *callInfo
+
+				if err := callInfo.consumeDepth(); err != nil {
+					return Err[Unit, ProgramCallErrorCode](OutOfFuel), nil
+				}
+				if err := callInfo.ConsumeFuel(input.Fuel); err != nil {
+					return Err[Unit, ProgramCallErrorCode](OutOfFuel), nil
+				}
+
+				newInfo.Actor = callInfo.Program
+				newInfo.Program = input.Account
+				newInfo.Params = input.Params
+				newInfo.Fuel = input.Fuel
+				newInfo.Value = 0
+				newInfo.callers = append(slices.Clip(callInfo.callers), callInfo.Program)
+				newInfo.inst = nil
+
+				if err := r.UpgradeProgram(context.Background(), &newInfo, input.ProgramID); err != nil {
+					if code, ok := ExtractProgramCallErrorCode(err); ok {
+						return Err[Unit, ProgramCallErrorCode](code), nil
+					}
+					return Err[Unit, ProgramCallErrorCode](ExecutionFailure), err
+				}
+
+				// return any fuel not used by the migration to the calling program
+				if newInfo.inst != nil {
+					callInfo.AddFuel(newInfo.RemainingFuel())
+				} else {
+					callInfo.AddFuel(input.Fuel)
+				}
+				callInfo.Events = newInfo.Events
+
+				return Ok[Unit, ProgramCallErrorCode](Unit{}), nil
+			})},
+			"set_admin": {FuelCost: setAdminCost, Function: FunctionNoOutput[Option[codec.Address]](func(callInfo *CallInfo, input Option[codec.Address]) error {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				admin, _ := input.Some()
+				return callInfo.State.SetAccountAdmin(ctx, callInfo.Program, admin)
+			})},
 			"set_call_result": {FuelCost: setCallResultCost, Function: FunctionNoOutput[RawBytes](func(callInfo *CallInfo, input RawBytes) error {
 				// needs to clone because this points into the current store's linear memory which may be gone when this is read
 				callInfo.inst.result = slices.Clone(input)
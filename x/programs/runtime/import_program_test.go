@@ -5,14 +5,61 @@ package runtime
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/bytecodealliance/wasmtime-go/v14"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/x/programs/test"
 )
 
+// callerProgram "call"s the "fail" function of the program whose address is
+// passed as params with a value of 1 and returns the first 2 bytes of the
+// result. "fail" stores "k" => "v" and then panics.
+const callerProgram = `
+(module
+  (import "state" "put" (func $put (param i32 i32)))
+  (import "program" "call_program" (func $call_program (param i32 i32) (result i32)))
+  (import "program" "set_call_result" (func $set_call_result (param i32 i32)))
+  (memory (export "memory") 1)
+  %s
+  (global $next (mut i32) (i32.const 4096))
+  (data (i32.const 8) "\01\00\00\00\01\00\00\00k\01\00\00\00v")
+  (data (i32.const 2081) "\04\00\00\00fail\00\00\00\00\40\42\0f\00\00\00\00\00\01\00\00\00\00\00\00\00")
+  (func (export "alloc") (param $size i32) (result i32)
+    (local $ptr i32)
+    (local.set $ptr (global.get $next))
+    (global.set $next (i32.add (global.get $next) (local.get $size)))
+    (local.get $ptr))
+  (func (export "fail") (param i32)
+    (call $put (i32.const 8) (i32.const 14))
+    unreachable)
+  (func (export "call") (param $ctx i32)
+    (local $i i32)
+    ;; copy the address following the context into the call input
+    (loop $copy
+      (i32.store8
+        (i32.add (i32.const 2048) (local.get $i))
+        (i32.load8_u (i32.add (local.get $ctx) (i32.add (i32.const 114) (local.get $i)))))
+      (local.set $i (i32.add (local.get $i) (i32.const 1)))
+      (br_if $copy (i32.lt_u (local.get $i) (i32.const 33))))
+    (call $set_call_result (call $call_program (i32.const 2048) (i32.const 61)) (i32.const 2))))
+`
+
+// watStateManager serves programs compiled from WAT.
+type watStateManager struct {
+	*test.StateManager
+	programs map[ids.ID][]byte
+}
+
+func (w *watStateManager) GetProgramBytes(_ context.Context, programID ids.ID) ([]byte, error) {
+	return w.programs[programID], nil
+}
+
 func TestImportProgramDeployProgram(t *testing.T) {
 	require := require.New(t)
 
@@ -164,3 +211,183 @@ func TestImportOutOfFuel(t *testing.T) {
 	require.NoError(err)
 	require.Equal([]byte{byte(OutOfFuel)}, result)
 }
+
+func TestImportProgramCallProgramSemantics(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	reentrantProgram, err := wasmtime.Wat2Wasm(fmt.Sprintf(callerProgram, ""))
+	require.NoError(err)
+	nonReentrantProgram, err := wasmtime.Wat2Wasm(fmt.Sprintf(callerProgram, `(global (export "non_reentrant") i32 (i32.const 0))`))
+	require.NoError(err)
+
+	var (
+		reentrantID    = ids.GenerateTestID()
+		nonReentrantID = ids.GenerateTestID()
+		caller         = codec.CreateAddress(0, ids.GenerateTestID())
+		callee         = codec.CreateAddress(0, ids.GenerateTestID())
+		nonReentrant   = codec.CreateAddress(0, ids.GenerateTestID())
+	)
+	db := test.NewTestDB()
+	state := &watStateManager{
+		StateManager: &test.StateManager{
+			AccountMap: map[codec.Address]ids.ID{
+				caller:       reentrantID,
+				callee:       reentrantID,
+				nonReentrant: nonReentrantID,
+			},
+			Balances: map[codec.Address]uint64{caller: 1, nonReentrant: 1},
+			Mu:       db,
+		},
+		programs: map[ids.ID][]byte{
+			reentrantID:    reentrantProgram,
+			nonReentrantID: nonReentrantProgram,
+		},
+	}
+	cfg := NewConfig()
+	r := NewRuntime(cfg, logging.NoLog{})
+	call := func(program codec.Address, target codec.Address) []byte {
+		result, err := r.CallProgram(ctx, &CallInfo{
+			State:        state,
+			Program:      program,
+			FunctionName: "call",
+			Params:       target[:],
+			Fuel:         10_000_000,
+		})
+		require.NoError(err)
+		return result
+	}
+	expectErr := func(code ProgramCallErrorCode) []byte {
+		b, err := Serialize(Err[RawBytes, ProgramCallErrorCode](code))
+		require.NoError(err)
+		return b
+	}
+
+	// A failed call reverts its state changes and value transfer
+	require.Equal(expectErr(CallPanicked), call(caller, callee))
+	_, err = state.GetProgramState(callee).GetValue(ctx, []byte("k"))
+	require.Error(err)
+	require.Equal(uint64(1), state.Balances[caller])
+	require.Zero(state.Balances[callee])
+
+	// Non-reentrant programs can't be called while they are being called
+	require.Equal(expectErr(CallPanicked), call(caller, caller))
+	require.Equal(expectErr(Reentrancy), call(nonReentrant, nonReentrant))
+
+	// Value can't be transferred without sufficient balance
+	require.Equal(expectErr(InsufficientBalance), call(callee, caller))
+
+	// Nested calls are limited to [Config.MaxCallDepth]
+	cfg.MaxCallDepth = 0
+	require.Equal(expectErr(CallDepthExceeded), call(caller, callee))
+}
+
+// upgradeableProgram "upgrade"s its account to the program ID passed as params
+// and returns the first 2 bytes of the result. "migrate" runs [migrateBody].
+const upgradeableProgram = `
+(module
+  (import "state" "put" (func $put (param i32 i32)))
+  (import "program" "upgrade" (func $upgrade (param i32 i32) (result i32)))
+  (import "program" "set_call_result" (func $set_call_result (param i32 i32)))
+  (memory (export "memory") 1)
+  (global $next (mut i32) (i32.const 4096))
+  (data (i32.const 8) "\01\00\00\00\01\00\00\00k\01\00\00\00v")
+  (data (i32.const 2113) "\00\00\00\00\40\42\0f\00\00\00\00\00")
+  (func (export "alloc") (param $size i32) (result i32)
+    (local $ptr i32)
+    (local.set $ptr (global.get $next))
+    (global.set $next (i32.add (global.get $next) (local.get $size)))
+    (local.get $ptr))
+  (func $copy (param $dst i32) (param $src i32) (param $len i32)
+    (local $i i32)
+    (loop $loop
+      (i32.store8
+        (i32.add (local.get $dst) (local.get $i))
+        (i32.load8_u (i32.add (local.get $src) (local.get $i))))
+      (local.set $i (i32.add (local.get $i) (i32.const 1)))
+      (br_if $loop (i32.lt_u (local.get $i) (local.get $len)))))
+  (func (export "migrate") (param i32)
+    %s)
+  (func (export "upgrade") (param $ctx i32)
+    ;; upgrade the program's own account
+    (call $copy (i32.const 2048) (local.get $ctx) (i32.const 33))
+    (call $copy (i32.const 2081) (i32.add (local.get $ctx) (i32.const 114)) (i32.const 32))
+    (call $set_call_result (call $upgrade (i32.const 2048) (i32.const 77)) (i32.const 2))))
+`
+
+func TestImportProgramUpgrade(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	program, err := wasmtime.Wat2Wasm(fmt.Sprintf(upgradeableProgram, "(call $put (i32.const 8) (i32.const 14))"))
+	require.NoError(err)
+	failingProgram, err := wasmtime.Wat2Wasm(fmt.Sprintf(upgradeableProgram, "unreachable"))
+	require.NoError(err)
+
+	var (
+		programID        = ids.GenerateTestID()
+		newProgramID     = ids.GenerateTestID()
+		failingProgramID = ids.GenerateTestID()
+		account          = codec.CreateAddress(0, ids.GenerateTestID())
+		admin            = codec.CreateAddress(0, ids.GenerateTestID())
+	)
+	state := &watStateManager{
+		StateManager: &test.StateManager{
+			AccountMap: map[codec.Address]ids.ID{account: programID},
+			Mu:         test.NewTestDB(),
+		},
+		programs: map[ids.ID][]byte{
+			programID:        program,
+			newProgramID:     program,
+			failingProgramID: failingProgram,
+		},
+	}
+	r := NewRuntime(NewConfig(), logging.NoLog{})
+
+	// Upgrades fail (and are reverted) if the migration fails
+	callInfo := &CallInfo{
+		State:        state,
+		Program:      account,
+		FunctionName: "upgrade",
+		Params:       failingProgramID[:],
+		Fuel:         10_000_000,
+	}
+	result, err := r.CallProgram(ctx, callInfo)
+	require.NoError(err)
+	require.Equal([]byte{resultErrPrefix, byte(CallPanicked)}, result)
+	require.Equal(programID, state.AccountMap[account])
+	require.Empty(callInfo.Events)
+
+	// Programs can upgrade their own account
+	callInfo.Params = newProgramID[:]
+	result, err = r.CallProgram(ctx, callInfo)
+	require.NoError(err)
+	require.Equal(resultOkPrefix, result[0])
+	require.Equal(newProgramID, state.AccountMap[account])
+	v, err := state.GetProgramState(account).GetValue(ctx, []byte("k"))
+	require.NoError(err)
+	require.Equal([]byte("v"), v)
+	require.Len(callInfo.Events, 1)
+	require.Equal(UpgradeEventTopic, callInfo.Events[0].Topic)
+	event, err := Deserialize[UpgradeEvent](callInfo.Events[0].Data)
+	require.NoError(err)
+	require.Equal(UpgradeEvent{
+		Account:      account,
+		Actor:        account,
+		ProgramID:    programID,
+		NewProgramID: newProgramID,
+	}, *event)
+
+	// Only the admin of an account can upgrade it
+	upgradeInfo := &CallInfo{
+		State:   state,
+		Actor:   admin,
+		Program: account,
+		Fuel:    10_000_000,
+	}
+	require.ErrorIs(r.UpgradeProgram(ctx, upgradeInfo, programID), ErrUnauthorized)
+	require.NoError(state.SetAccountAdmin(ctx, account, admin))
+	require.NoError(r.UpgradeProgram(ctx, upgradeInfo, programID))
+	require.Equal(programID, state.AccountMap[account])
+	require.Len(upgradeInfo.Events, 1)
+}
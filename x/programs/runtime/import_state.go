@@ -10,12 +10,18 @@ import (
 	"github.com/ava-labs/avalanchego/database"
 )
 
-const (
-	getCost    = 10000
-	putCost    = 10000
-	deleteCost = 10000
-
-	putManyCost = 10000
+var (
+	getCost = FuelCost{
+		Base:     10000,
+		PerByte:  defaultFuelPerByte,
+		PerChunk: defaultFuelPerChunk,
+	}
+	putManyCost = FuelCost{
+		Base:     10000,
+		PerByte:  defaultFuelPerByte,
+		PerEntry: defaultFuelPerEntry,
+		PerChunk: defaultFuelPerChunk,
+	}
 )
 
 type keyValueInput struct {
@@ -37,13 +43,22 @@ func NewStateAccessModule() *ImportModule {
 					}
 					return nil, err
 				}
+				if err := callInfo.consumeChunks(val); err != nil {
+					return nil, err
+				}
 				return val, nil
 			})},
 			"put": {FuelCost: putManyCost, Function: FunctionNoOutput[[]keyValueInput](func(callInfo *CallInfo, input []keyValueInput) error {
 				ctx, cancel := context.WithCancel(context.Background())
 				defer cancel()
+				if err := callInfo.consumeEntries(len(input)); err != nil {
+					return err
+				}
 				programState := callInfo.State.GetProgramState(callInfo.Program)
 				for _, entry := range input {
+					if err := callInfo.consumeChunks(entry.Value); err != nil {
+						return err
+					}
 					if len(entry.Value) == 0 {
 						if err := programState.Remove(ctx, entry.Key); err != nil {
 							return err
@@ -19,7 +19,7 @@ type ImportModule struct {
 	HostFunctions map[string]HostFunction
 }
 
-func (i *ImportModule) SetFuelCost(functionName string, fuelCost uint64) bool {
+func (i *ImportModule) SetFuelCost(functionName string, fuelCost FuelCost) bool {
 	hostFunction, ok := i.HostFunctions[functionName]
 	if ok {
 		hostFunction.FuelCost = fuelCost
@@ -37,7 +37,7 @@ func (i *Imports) AddModule(mod *ImportModule) {
 	i.Modules[mod.Name] = mod
 }
 
-func (i *Imports) SetFuelCost(moduleName string, functionName string, fuelCost uint64) bool {
+func (i *Imports) SetFuelCost(moduleName string, functionName string, fuelCost FuelCost) bool {
 	if module, ok := i.Modules[moduleName]; ok {
 		return module.SetFuelCost(functionName, fuelCost)
 	}
@@ -65,15 +65,16 @@ func (i *Imports) createLinker(r *WasmRuntime) (*wasmtime.Linker, error) {
 
 type HostFunction struct {
 	Function HostFunctionType
-	FuelCost uint64
+	FuelCost FuelCost
 }
 
 func (f HostFunction) convert(r *WasmRuntime) func(*wasmtime.Caller, []wasmtime.Val) ([]wasmtime.Val, *wasmtime.Trap) {
 	return func(caller *wasmtime.Caller, vals []wasmtime.Val) ([]wasmtime.Val, *wasmtime.Trap) {
 		callInfo := r.getCallInfo(caller)
-		if err := callInfo.ConsumeFuel(f.FuelCost); err != nil {
+		if err := callInfo.ConsumeFuel(f.FuelCost.Base); err != nil {
 			return nil, convertToTrap(err)
 		}
+		callInfo.hostCost = f.FuelCost
 		return f.Function.call(callInfo, caller, vals)
 	}
 }
@@ -92,7 +93,7 @@ func (Function[T, U]) wasmType() *wasmtime.FuncType {
 }
 
 func (f Function[T, U]) call(callInfo *CallInfo, caller *wasmtime.Caller, vals []wasmtime.Val) ([]wasmtime.Val, *wasmtime.Trap) {
-	input, err := getInputFromMemory[T](caller, vals)
+	input, err := getInputFromMemory[T](callInfo, caller, vals)
 	if err != nil {
 		return writeOutputToMemory[interface{}](callInfo, nil, err)
 	}
@@ -118,7 +119,7 @@ func (FunctionNoOutput[T]) wasmType() *wasmtime.FuncType {
 }
 
 func (f FunctionNoOutput[T]) call(callInfo *CallInfo, caller *wasmtime.Caller, vals []wasmtime.Val) ([]wasmtime.Val, *wasmtime.Trap) {
-	input, err := getInputFromMemory[T](caller, vals)
+	input, err := getInputFromMemory[T](callInfo, caller, vals)
 	if err != nil {
 		return []wasmtime.Val{}, convertToTrap(err)
 	}
@@ -126,13 +127,16 @@ func (f FunctionNoOutput[T]) call(callInfo *CallInfo, caller *wasmtime.Caller, v
 	return []wasmtime.Val{}, convertToTrap(err)
 }
 
-func getInputFromMemory[T any](caller *wasmtime.Caller, vals []wasmtime.Val) (*T, error) {
+func getInputFromMemory[T any](callInfo *CallInfo, caller *wasmtime.Caller, vals []wasmtime.Val) (*T, error) {
 	offset := vals[0].I32()
 	length := vals[1].I32()
 
 	if offset == 0 || length == 0 {
 		return new(T), nil
 	}
+	if err := callInfo.consumeBytes(int(length)); err != nil {
+		return nil, err
+	}
 	return Deserialize[T](caller.GetExport(MemoryName).Memory().UnsafeData(caller)[offset : offset+length])
 }
 
@@ -144,6 +148,9 @@ func writeOutputToMemory[T any](callInfo *CallInfo, results T, err error) ([]was
 	if data == nil || err != nil {
 		return nilResult, convertToTrap(err)
 	}
+	if err := callInfo.consumeBytes(len(data)); err != nil {
+		return nilResult, convertToTrap(err)
+	}
 	offset, err := callInfo.inst.writeToMemory(data)
 	if err != nil {
 		return nilResult, convertToTrap(err)
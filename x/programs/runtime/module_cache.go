@@ -0,0 +1,160 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/bytecodealliance/wasmtime-go/v14"
+	"go.uber.org/zap"
+)
+
+const compiledProgramExt = ".cwasm"
+
+type cachedModule struct {
+	mod  *wasmtime.Module
+	size int
+}
+
+// moduleCache caches compiled programs in memory and (optionally) on disk.
+//
+// Compiled programs are persisted to a subdirectory of [Config.ProgramCacheDir]
+// named after [Config.Hash], so changing the config of the engine invalidates
+// all of the programs compiled with the previous config (which are removed).
+//
+// Persisted programs are loaded without being validated, so the directory
+// must only be writable by the node.
+type moduleCache struct {
+	log    logging.Logger
+	engine *wasmtime.Engine
+	dir    string // empty if compiled programs are not persisted
+
+	mem cache.Cacher[ids.ID, *cachedModule]
+}
+
+func newModuleCache(log logging.Logger, engine *wasmtime.Engine, cfg *Config) *moduleCache {
+	c := &moduleCache{
+		log:    log,
+		engine: engine,
+		mem: cache.NewSizedLRU(cfg.ProgramCacheSize, func(id ids.ID, m *cachedModule) int {
+			return len(id) + m.size
+		}),
+	}
+	if len(cfg.ProgramCacheDir) == 0 {
+		return c
+	}
+	hash := cfg.Hash().String()
+	if err := removeStale(cfg.ProgramCacheDir, hash); err != nil {
+		log.Warn("unable to remove stale compiled programs", zap.Error(err))
+	}
+	dir := filepath.Join(cfg.ProgramCacheDir, hash)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		log.Warn("unable to create compiled program cache", zap.String("dir", dir), zap.Error(err))
+		return c
+	}
+	c.dir = dir
+	return c
+}
+
+// removeStale removes the programs compiled with any config other than [hash].
+func removeStale(root string, hash string) error {
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == hash {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(root, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *moduleCache) path(id ids.ID) string {
+	return filepath.Join(c.dir, id.String()+compiledProgramExt)
+}
+
+// get returns the compiled program [id], compiling (and persisting) the bytes
+// returned by [programBytes] if it isn't cached.
+func (c *moduleCache) get(id ids.ID, programBytes func() ([]byte, error)) (*wasmtime.Module, error) {
+	if m, ok := c.mem.Get(id); ok {
+		return m.mod, nil
+	}
+	if m, ok := c.load(id); ok {
+		c.mem.Put(id, m)
+		return m.mod, nil
+	}
+	b, err := programBytes()
+	if err != nil {
+		return nil, err
+	}
+	mod, err := wasmtime.NewModule(c.engine, b)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := mod.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	c.store(id, compiled)
+	c.mem.Put(id, &cachedModule{mod: mod, size: len(compiled)})
+	return mod, nil
+}
+
+func (c *moduleCache) load(id ids.ID) (*cachedModule, bool) {
+	if len(c.dir) == 0 {
+		return nil, false
+	}
+	path := c.path(id)
+	compiled, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			c.log.Warn("unable to read compiled program", zap.Stringer("programID", id), zap.Error(err))
+		}
+		return nil, false
+	}
+	mod, err := wasmtime.NewModuleDeserialize(c.engine, compiled)
+	if err != nil {
+		c.log.Warn("removing invalid compiled program", zap.Stringer("programID", id), zap.Error(err))
+		_ = os.Remove(path)
+		return nil, false
+	}
+	return &cachedModule{mod: mod, size: len(compiled)}, true
+}
+
+func (c *moduleCache) store(id ids.ID, compiled []byte) {
+	if len(c.dir) == 0 {
+		return
+	}
+	// Write to a temporary file first so that a partially written program is
+	// never loaded (or concurrently written).
+	f, err := os.CreateTemp(c.dir, id.String()+"-*.tmp")
+	if err != nil {
+		c.log.Warn("unable to persist compiled program", zap.Stringer("programID", id), zap.Error(err))
+		return
+	}
+	_, err = f.Write(compiled)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(id))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		c.log.Warn("unable to persist compiled program", zap.Stringer("programID", id), zap.Error(err))
+	}
+}
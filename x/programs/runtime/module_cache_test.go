@@ -0,0 +1,60 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/bytecodealliance/wasmtime-go/v14"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleCache(t *testing.T) {
+	require := require.New(t)
+
+	program, err := wasmtime.Wat2Wasm(`(module (memory (export "memory") 1))`)
+	require.NoError(err)
+	id := ids.GenerateTestID()
+	compiles := 0
+	programBytes := func() ([]byte, error) {
+		compiles++
+		return program, nil
+	}
+	dir := t.TempDir()
+	newCache := func(cfg *Config) *moduleCache {
+		cfg.ProgramCacheDir = dir
+		return newModuleCache(logging.NoLog{}, wasmtime.NewEngineWithConfig(cfg.wasmConfig), cfg)
+	}
+
+	cfg := NewConfig()
+	hash := cfg.Hash()
+	c := newCache(cfg)
+	_, err = c.get(id, programBytes)
+	require.NoError(err)
+	_, err = c.get(id, programBytes)
+	require.NoError(err)
+	require.Equal(1, compiles)
+	require.FileExists(filepath.Join(dir, hash.String(), id.String()+compiledProgramExt))
+
+	// Compiled programs are loaded from disk after a restart
+	c = newCache(NewConfig())
+	_, err = c.get(id, programBytes)
+	require.NoError(err)
+	require.Equal(1, compiles)
+
+	// Changing the config invalidates compiled programs
+	cfg = NewConfig()
+	cfg.SetWasmSIMD(true)
+	require.NotEqual(hash, cfg.Hash())
+	c = newCache(cfg)
+	_, err = c.get(id, programBytes)
+	require.NoError(err)
+	require.Equal(2, compiles)
+	_, err = os.Stat(filepath.Join(dir, hash.String()))
+	require.ErrorIs(err, os.ErrNotExist)
+}
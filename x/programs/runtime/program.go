@@ -18,6 +18,15 @@ import (
 const (
 	AllocName  = "alloc"
 	MemoryName = "memory"
+
+	// MigrateName is the name of the (optional) function of a program that is
+	// called when an account is upgraded to it.
+	MigrateName = "migrate"
+
+	// NonReentrantName is the name of an (optional) export that prevents a
+	// program from being called while it is already being called (by the same
+	// account) further up the call stack.
+	NonReentrantName = "non_reentrant"
 )
 
 type Context struct {
@@ -28,6 +37,14 @@ type Context struct {
 	ActionID  ids.ID
 }
 
+// Event is emitted by a program call.
+type Event struct {
+	// Program is the address of the account that emitted the event.
+	Program codec.Address
+	Topic   string
+	Data    []byte
+}
+
 type CallInfo struct {
 	// the state that the program will run against
 	State StateManager
@@ -57,7 +74,26 @@ type CallInfo struct {
 
 	Value uint64
 
+	// ReadOnly calls (and the calls they make) trap if they attempt to modify
+	// state (or transfer value).
+	ReadOnly bool
+
+	// Events are the events emitted by the call (and the calls it made), which
+	// are discarded if the call fails
+	Events []Event
+
 	inst *ProgramInstance
+
+	// deadline is the epoch after which the call (and the calls it makes) is
+	// interrupted (see [WasmRuntime.EnableTimeouts])
+	deadline uint64
+
+	// callers are the programs (outermost first) that made the calls that
+	// reached this call
+	callers []codec.Address
+
+	// hostCost is the cost of the host function being called
+	hostCost FuelCost
 }
 
 func (c *CallInfo) RemainingFuel() uint64 {
@@ -90,12 +126,6 @@ func (p *ProgramInstance) call(ctx context.Context, callInfo *CallInfo) ([]byte,
 		return nil, err
 	}
 
-	if callInfo.Value > 0 {
-		if err := callInfo.State.TransferBalance(ctx, callInfo.Actor, callInfo.Program, callInfo.Value); err != nil {
-			return nil, errors.New("insufficient balance")
-		}
-	}
-
 	// create the program context
 	programCtx := Context{
 		Program:   callInfo.Program,
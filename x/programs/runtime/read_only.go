@@ -0,0 +1,62 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+var (
+	_ StateManager  = (*readOnlyState)(nil)
+	_ state.Mutable = (*readOnlyMutable)(nil)
+)
+
+// enforceReadOnly prevents read-only calls from modifying state.
+func enforceReadOnly(callInfo *CallInfo) {
+	if _, ok := callInfo.State.(readOnlyState); callInfo.ReadOnly && !ok {
+		callInfo.State = readOnlyState{callInfo.State}
+	}
+}
+
+// readOnlyState rejects all changes to the [StateManager] it wraps.
+type readOnlyState struct {
+	StateManager
+}
+
+func (readOnlyState) TransferBalance(context.Context, codec.Address, codec.Address, uint64) error {
+	return ErrReadOnly
+}
+
+func (r readOnlyState) GetProgramState(address codec.Address) state.Mutable {
+	return readOnlyMutable{r.StateManager.GetProgramState(address)}
+}
+
+func (readOnlyState) NewAccountWithProgram(context.Context, ids.ID, []byte) (codec.Address, error) {
+	return codec.EmptyAddress, ErrReadOnly
+}
+
+func (readOnlyState) SetAccountProgram(context.Context, codec.Address, ids.ID) error {
+	return ErrReadOnly
+}
+
+func (readOnlyState) SetAccountAdmin(context.Context, codec.Address, codec.Address) error {
+	return ErrReadOnly
+}
+
+type readOnlyMutable struct {
+	state.Immutable
+}
+
+func (readOnlyMutable) Insert(context.Context, []byte, []byte) error {
+	return ErrReadOnly
+}
+
+func (readOnlyMutable) Remove(context.Context, []byte) error {
+	return ErrReadOnly
+}
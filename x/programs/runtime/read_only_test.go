@@ -0,0 +1,62 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/bytecodealliance/wasmtime-go/v14"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/x/programs/test"
+)
+
+func TestReadOnly(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	program, err := wasmtime.Wat2Wasm(fmt.Sprintf(callerProgram, ""))
+	require.NoError(err)
+	var (
+		programID = ids.GenerateTestID()
+		caller    = codec.CreateAddress(0, ids.GenerateTestID())
+		callee    = codec.CreateAddress(0, ids.GenerateTestID())
+	)
+	state := &watStateManager{
+		StateManager: &test.StateManager{
+			AccountMap: map[codec.Address]ids.ID{caller: programID, callee: programID},
+			Balances:   map[codec.Address]uint64{caller: 1},
+			Mu:         test.NewTestDB(),
+		},
+		programs: map[ids.ID][]byte{programID: program},
+	}
+	r := NewRuntime(NewConfig(), logging.NoLog{})
+
+	// State writes trap
+	_, err = r.CallProgram(ctx, &CallInfo{
+		State:        state,
+		Program:      callee,
+		FunctionName: "fail",
+		Fuel:         10_000_000,
+		ReadOnly:     true,
+	})
+	require.ErrorContains(err, ErrReadOnly.Error())
+
+	// Value transfers (made by nested calls) trap
+	_, err = r.CallProgram(ctx, &CallInfo{
+		State:        state,
+		Program:      caller,
+		FunctionName: "call",
+		Params:       callee[:],
+		Fuel:         10_000_000,
+		ReadOnly:     true,
+	})
+	require.ErrorContains(err, ErrReadOnly.Error())
+	require.Equal(uint64(1), state.Balances[caller])
+}
@@ -5,9 +5,15 @@ package runtime
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/bytecodealliance/wasmtime-go/v14"
@@ -22,16 +28,25 @@ type WasmRuntime struct {
 	hostImports *Imports
 	cfg         *Config
 
-	programCache cache.Cacher[ids.ID, *wasmtime.Module]
+	modules *moduleCache
 
+	// lock protects [callerInfo] and the linker so that programs can be called
+	// concurrently (e.g. by actions executed in parallel)
+	lock                      sync.RWMutex
 	callerInfo                map[uintptr]*CallInfo
 	linker                    *wasmtime.Linker
 	linkerNeedsInitialization bool
+
+	// epochTick is the interval (in ns) at which [epoch] and the epoch of
+	// [engine] are incremented (0 unless [EnableTimeouts] was called)
+	epochTick atomic.Int64
+	epoch     atomic.Uint64
 }
 
 type StateManager interface {
 	BalanceManager
 	ProgramManager
+	CheckpointManager
 }
 
 type BalanceManager interface {
@@ -45,29 +60,39 @@ type ProgramManager interface {
 	GetProgramBytes(ctx context.Context, programID ids.ID) ([]byte, error)
 	NewAccountWithProgram(ctx context.Context, programID ids.ID, accountCreationData []byte) (codec.Address, error)
 	SetAccountProgram(ctx context.Context, account codec.Address, programID ids.ID) error
+	// GetAccountAdmin returns [codec.EmptyAddress] if [account] has no admin.
+	GetAccountAdmin(ctx context.Context, account codec.Address) (codec.Address, error)
+	// SetAccountAdmin removes the admin of [account] if [admin] is
+	// [codec.EmptyAddress].
+	SetAccountAdmin(ctx context.Context, account codec.Address, admin codec.Address) error
+}
+
+// CheckpointManager reverts the state changes (including balance transfers)
+// made by failed program calls. Checkpoints can be nested.
+type CheckpointManager interface {
+	// OpIndex returns a checkpoint that can be passed to [Rollback].
+	OpIndex() int
+	// Rollback reverts all changes made after [restorePoint].
+	Rollback(ctx context.Context, restorePoint int) error
 }
 
 func NewRuntime(
 	cfg *Config,
 	log logging.Logger,
 ) *WasmRuntime {
+	engine := wasmtime.NewEngineWithConfig(cfg.wasmConfig)
 	runtime := &WasmRuntime{
 		log:                       log,
 		cfg:                       cfg,
-		engine:                    wasmtime.NewEngineWithConfig(cfg.wasmConfig),
+		engine:                    engine,
 		hostImports:               NewImports(),
 		callerInfo:                map[uintptr]*CallInfo{},
 		linkerNeedsInitialization: true,
-		programCache: cache.NewSizedLRU(cfg.ProgramCacheSize, func(id ids.ID, mod *wasmtime.Module) int {
-			bytes, err := mod.Serialize()
-			if err != nil {
-				panic(err)
-			}
-			return len(id) + len(bytes)
-		}),
+		modules:                   newModuleCache(log, engine, cfg),
 	}
 
 	runtime.AddImportModule(NewLogModule())
+	runtime.AddImportModule(NewEventsModule())
 	runtime.AddImportModule(NewBalanceModule())
 	runtime.AddImportModule(NewStateAccessModule())
 	runtime.AddImportModule(NewProgramModule(runtime))
@@ -80,27 +105,107 @@ func (r *WasmRuntime) WithDefaults(callInfo CallInfo) CallContext {
 }
 
 func (r *WasmRuntime) AddImportModule(mod *ImportModule) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
 	r.hostImports.AddModule(mod)
 	r.linkerNeedsInitialization = true
 }
 
-func (r *WasmRuntime) getModule(ctx context.Context, callInfo *CallInfo, id ids.ID) (*wasmtime.Module, error) {
-	if mod, ok := r.programCache.Get(id); ok {
-		return mod, nil
+// EnableTimeouts increments the epoch of the engine every [tick] so that calls
+// are interrupted (and fail with [ErrTimeout]) once the deadline of the
+// [context.Context] they were made with passes. Calls made by programs share
+// the deadline of the outermost call. The returned function stops the timer.
+//
+// When a call is interrupted depends on wall-clock time, so timeouts must only
+// be enabled for runtimes that don't execute calls on behalf of consensus
+// (i.e. runtimes that serve read-only calls).
+func (r *WasmRuntime) EnableTimeouts(tick time.Duration) func() {
+	r.epochTick.Store(int64(tick))
+	ticker := time.NewTicker(tick)
+	done := make(chan struct{})
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.epoch.Add(1)
+				r.engine.IncrementEpoch()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
 	}
-	programBytes, err := callInfo.State.GetProgramBytes(ctx, id)
-	if err != nil {
-		return nil, err
+}
+
+// deadline returns the epoch after which a call made with [ctx] is
+// interrupted (or [math.MaxUint64] if it is never interrupted).
+func (r *WasmRuntime) deadline(ctx context.Context) uint64 {
+	tick := time.Duration(r.epochTick.Load())
+	deadline, ok := ctx.Deadline()
+	if tick == 0 || !ok {
+		return math.MaxUint64
 	}
-	mod, err := wasmtime.NewModule(r.engine, programBytes)
+	return r.epoch.Load() + uint64(max(time.Until(deadline), 0)/tick) + 1
+}
+
+// epochDeadline returns the number of epochs a store created for a call that
+// must be interrupted after [deadline] can run for.
+func (r *WasmRuntime) epochDeadline(deadline uint64) uint64 {
+	if deadline == math.MaxUint64 {
+		// The epoch is only incremented if timeouts are enabled, so the
+		// deadline just needs to be far enough to never be reached.
+		return math.MaxUint32
+	}
+	epoch := r.epoch.Load()
+	if deadline <= epoch {
+		return 0
+	}
+	return deadline - epoch
+}
+
+// SetFuelCost sets the cost of calling [functionName] of [moduleName] and
+// returns false if the host function doesn't exist.
+func (r *WasmRuntime) SetFuelCost(moduleName string, functionName string, fuelCost FuelCost) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	// host functions capture their cost when the linker is created
+	r.linkerNeedsInitialization = true
+	return r.hostImports.SetFuelCost(moduleName, functionName, fuelCost)
+}
+
+func (r *WasmRuntime) getModule(ctx context.Context, callInfo *CallInfo, id ids.ID) (*wasmtime.Module, error) {
+	return r.modules.get(id, func() ([]byte, error) {
+		return callInfo.State.GetProgramBytes(ctx, id)
+	})
+}
+
+// CallProgram calls [callInfo.Program] and transfers [callInfo.Value] from
+// [callInfo.Actor] to it. If the call fails, the transfer and any state changes
+// made by the call are reverted.
+func (r *WasmRuntime) CallProgram(ctx context.Context, callInfo *CallInfo) ([]byte, error) {
+	enforceReadOnly(callInfo)
+	restorePoint := callInfo.State.OpIndex()
+	events := len(callInfo.Events)
+	result, err := r.callProgram(ctx, callInfo)
 	if err != nil {
+		callInfo.Events = callInfo.Events[:events]
+		if rerr := callInfo.State.Rollback(ctx, restorePoint); rerr != nil {
+			return nil, rerr
+		}
 		return nil, err
 	}
-	r.programCache.Put(id, mod)
-	return mod, nil
+	return result, nil
 }
 
-func (r *WasmRuntime) CallProgram(ctx context.Context, callInfo *CallInfo) (result []byte, err error) {
+func (r *WasmRuntime) callProgram(ctx context.Context, callInfo *CallInfo) ([]byte, error) {
+	if len(callInfo.callers) > r.cfg.MaxCallDepth {
+		return nil, ErrCallDepthExceeded
+	}
 	programID, err := callInfo.State.GetAccountProgram(ctx, callInfo.Program)
 	if err != nil {
 		return nil, err
@@ -109,7 +214,26 @@ func (r *WasmRuntime) CallProgram(ctx context.Context, callInfo *CallInfo) (resu
 	if err != nil {
 		return nil, err
 	}
-	inst, err := r.getInstance(programModule, r.hostImports)
+	if slices.Contains(callInfo.callers, callInfo.Program) && hasExport(programModule, NonReentrantName) {
+		return nil, ErrReentrancy
+	}
+	if callInfo.Value > 0 {
+		if callInfo.ReadOnly {
+			return nil, ErrReadOnly
+		}
+		if err := callInfo.State.TransferBalance(ctx, callInfo.Actor, callInfo.Program, callInfo.Value); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInsufficientBalance, err)
+		}
+	}
+	return r.run(ctx, callInfo, programModule)
+}
+
+func (r *WasmRuntime) run(ctx context.Context, callInfo *CallInfo, programModule *wasmtime.Module) ([]byte, error) {
+	if callInfo.deadline == 0 {
+		// Calls made by programs inherit the deadline of their caller
+		callInfo.deadline = r.deadline(ctx)
+	}
+	inst, err := r.getInstance(programModule, r.epochDeadline(callInfo.deadline))
 	if err != nil {
 		return nil, err
 	}
@@ -118,22 +242,48 @@ func (r *WasmRuntime) CallProgram(ctx context.Context, callInfo *CallInfo) (resu
 	r.setCallInfo(inst.store, callInfo)
 	defer r.deleteCallInfo(inst.store)
 
-	return inst.call(ctx, callInfo)
+	result, err := inst.call(ctx, callInfo)
+	var trap *wasmtime.Trap
+	if errors.As(err, &trap) && trap.Code() != nil && *trap.Code() == wasmtime.Interrupt {
+		return nil, fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return result, err
+}
+
+func hasExport(programModule *wasmtime.Module, name string) bool {
+	for _, export := range programModule.Exports() {
+		if export.Name() == name {
+			return true
+		}
+	}
+	return false
 }
 
-func (r *WasmRuntime) getInstance(programModule *wasmtime.Module, imports *Imports) (*ProgramInstance, error) {
+func (r *WasmRuntime) getLinker() (*wasmtime.Linker, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
 	if r.linkerNeedsInitialization {
-		linker, err := imports.createLinker(r)
+		linker, err := r.hostImports.createLinker(r)
 		if err != nil {
 			return nil, err
 		}
 		r.linker = linker
 		r.linkerNeedsInitialization = false
 	}
+	return r.linker, nil
+}
+
+func (r *WasmRuntime) getInstance(programModule *wasmtime.Module, epochDeadline uint64) (*ProgramInstance, error) {
+	linker, err := r.getLinker()
+	if err != nil {
+		return nil, err
+	}
 
 	store := wasmtime.NewStore(r.engine)
-	store.SetEpochDeadline(1)
-	inst, err := r.linker.Instantiate(store, programModule)
+	store.SetEpochDeadline(epochDeadline)
+	store.Limiter(int64(r.cfg.MaxMemoryPages)*wasmPageSize, -1, -1, -1, -1)
+	inst, err := linker.Instantiate(store, programModule)
 	if err != nil {
 		return nil, err
 	}
@@ -145,13 +295,22 @@ func toMapKey(storeLike wasmtime.Storelike) uintptr {
 }
 
 func (r *WasmRuntime) setCallInfo(storeLike wasmtime.Storelike, info *CallInfo) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
 	r.callerInfo[toMapKey(storeLike)] = info
 }
 
 func (r *WasmRuntime) getCallInfo(storeLike wasmtime.Storelike) *CallInfo {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
 	return r.callerInfo[toMapKey(storeLike)]
 }
 
 func (r *WasmRuntime) deleteCallInfo(storeLike wasmtime.Storelike) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
 	delete(r.callerInfo, toMapKey(storeLike))
 }
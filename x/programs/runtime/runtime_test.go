@@ -5,9 +5,13 @@ package runtime
 
 import (
 	"context"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/bytecodealliance/wasmtime-go/v14"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/hypersdk/codec"
@@ -37,7 +41,7 @@ func TestRuntimeCallProgramBasicAttachValue(t *testing.T) {
 
 	program := newTestProgram(ctx, "simple")
 	actor := codec.CreateAddress(0, ids.GenerateTestID())
-	program.Runtime.StateManager.(test.StateManager).Balances[actor] = 10
+	program.Runtime.StateManager.(*test.StateManager).Balances[actor] = 10
 
 	actorBalance, err := program.Runtime.StateManager.GetBalance(context.Background(), actor)
 	require.NoError(err)
@@ -132,3 +136,61 @@ func TestContextInjection(t *testing.T) {
 		})
 	}
 }
+
+// spinProgram loops forever when "spin" is called.
+const spinProgram = `
+(module
+  (memory (export "memory") 1)
+  (global $next (mut i32) (i32.const 1024))
+  (func (export "alloc") (param $size i32) (result i32)
+    (local $ptr i32)
+    (local.set $ptr (global.get $next))
+    (global.set $next (i32.add (global.get $next) (local.get $size)))
+    (local.get $ptr))
+  (func (export "spin") (param i32)
+    (loop $spin
+      (br $spin))))
+`
+
+func TestRuntimeTimeout(t *testing.T) {
+	require := require.New(t)
+
+	program, err := wasmtime.Wat2Wasm(spinProgram)
+	require.NoError(err)
+	var (
+		programID = ids.GenerateTestID()
+		account   = codec.CreateAddress(0, ids.GenerateTestID())
+	)
+	state := &watStateManager{
+		StateManager: &test.StateManager{
+			AccountMap: map[codec.Address]ids.ID{account: programID},
+			Mu:         test.NewTestDB(),
+		},
+		programs: map[ids.ID][]byte{programID: program},
+	}
+	call := func(r *WasmRuntime, fuel uint64) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := r.CallProgram(ctx, &CallInfo{
+			State:        state,
+			Program:      account,
+			FunctionName: "spin",
+			Fuel:         fuel,
+		})
+		return err
+	}
+
+	// Calls are only bounded by their fuel unless timeouts are enabled
+	r := NewRuntime(NewConfig(), logging.NoLog{})
+	err = call(r, 10_000_000)
+	code, ok := ExtractProgramCallErrorCode(err)
+	require.True(ok)
+	require.Equal(OutOfFuel, code)
+
+	r = NewRuntime(NewConfig(), logging.NoLog{})
+	stop := r.EnableTimeouts(time.Millisecond)
+	defer stop()
+	start := time.Now()
+	require.ErrorIs(call(r, math.MaxUint64), ErrTimeout)
+	require.Less(time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// UpgradeEventTopic is the topic of the [Event] emitted when an account is
+// upgraded. Its data is a serialized [UpgradeEvent].
+const UpgradeEventTopic = "upgrade"
+
+type UpgradeEvent struct {
+	Account      codec.Address
+	Actor        codec.Address
+	ProgramID    ids.ID
+	NewProgramID ids.ID
+}
+
+// UpgradeProgram binds [callInfo.Program] to [programID] on behalf of
+// [callInfo.Actor], which must be the account itself or its admin (see
+// [ProgramManager.GetAccountAdmin]).
+//
+// If [programID] exports [MigrateName], it is called with [callInfo.Params]
+// and [callInfo.Fuel] to migrate the state of the account. The upgrade only
+// takes effect if the migration succeeds (otherwise all changes are
+// reverted). A successful upgrade emits an [UpgradeEvent].
+func (r *WasmRuntime) UpgradeProgram(ctx context.Context, callInfo *CallInfo, programID ids.ID) error {
+	enforceReadOnly(callInfo)
+	restorePoint := callInfo.State.OpIndex()
+	events := len(callInfo.Events)
+	if err := r.upgradeProgram(ctx, callInfo, programID); err != nil {
+		callInfo.Events = callInfo.Events[:events]
+		if rerr := callInfo.State.Rollback(ctx, restorePoint); rerr != nil {
+			return rerr
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *WasmRuntime) upgradeProgram(ctx context.Context, callInfo *CallInfo, programID ids.ID) error {
+	if len(callInfo.callers) > r.cfg.MaxCallDepth {
+		return ErrCallDepthExceeded
+	}
+	if callInfo.Actor != callInfo.Program {
+		admin, err := callInfo.State.GetAccountAdmin(ctx, callInfo.Program)
+		if err != nil {
+			return err
+		}
+		if admin == codec.EmptyAddress || admin != callInfo.Actor {
+			return ErrUnauthorized
+		}
+	}
+	prevProgramID, err := callInfo.State.GetAccountProgram(ctx, callInfo.Program)
+	if err != nil {
+		return err
+	}
+	if err := callInfo.State.SetAccountProgram(ctx, callInfo.Program, programID); err != nil {
+		return err
+	}
+	// Load the program the same way it is loaded when called, so that the
+	// state accessed doesn't depend on whether the program is cached.
+	programID, err = callInfo.State.GetAccountProgram(ctx, callInfo.Program)
+	if err != nil {
+		return err
+	}
+	programModule, err := r.getModule(ctx, callInfo, programID)
+	if err != nil {
+		return err
+	}
+	// The migration is requested by the account (or its admin), so it is
+	// allowed even if the account is already being called.
+	if hasExport(programModule, MigrateName) {
+		callInfo.FunctionName = MigrateName
+		if _, err := r.run(ctx, callInfo, programModule); err != nil {
+			return err
+		}
+	}
+	data, err := Serialize(UpgradeEvent{
+		Account:      callInfo.Program,
+		Actor:        callInfo.Actor,
+		ProgramID:    prevProgramID,
+		NewProgramID: programID,
+	})
+	if err != nil {
+		return err
+	}
+	callInfo.Events = append(callInfo.Events, Event{Program: callInfo.Program, Topic: UpgradeEventTopic, Data: data})
+	return nil
+}
@@ -70,7 +70,7 @@ func (t *testRuntime) WithValue(value uint64) *testRuntime {
 }
 
 func (t *testRuntime) AddProgram(programID ids.ID, programName string) {
-	t.StateManager.(test.StateManager).ProgramsMap[programID] = programName
+	t.StateManager.(*test.StateManager).ProgramsMap[programID] = programName
 }
 
 func (t *testRuntime) CallProgram(program codec.Address, function string, params ...interface{}) ([]byte, error) {
@@ -93,7 +93,7 @@ func newTestProgram(ctx context.Context, program string) *testProgram {
 			callContext: NewRuntime(
 				NewConfig(),
 				logging.NoLog{}).WithDefaults(CallInfo{Fuel: 10000000}),
-			StateManager: test.StateManager{
+			StateManager: &test.StateManager{
 				ProgramsMap: map[ids.ID]string{id: program},
 				AccountMap:  map[codec.Address]ids.ID{account: id},
 				Balances:    map[codec.Address]uint64{},
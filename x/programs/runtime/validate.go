@@ -0,0 +1,300 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/bytecodealliance/wasmtime-go/v14"
+)
+
+const (
+	wasmVersion  = 1
+	wasmPageSize = 64 * units.KiB
+
+	sectionType   = 1
+	sectionImport = 2
+	sectionMemory = 5
+	sectionExport = 7
+
+	externFunc   = 0x00
+	externTable  = 0x01
+	externMemory = 0x02
+	externGlobal = 0x03
+
+	funcTypeForm = 0x60
+
+	valTypeI32 = 0x7F
+	valTypeI64 = 0x7E
+	valTypeF32 = 0x7D
+	valTypeF64 = 0x7C
+
+	limitsHasMax = 0x01
+	limitsShared = 0x02
+	limits64     = 0x04
+)
+
+var wasmMagic = []byte{0x00, 'a', 's', 'm'}
+
+var errMalformedModule = errors.New("malformed module")
+
+// ValidateProgram checks that [programBytes] can be called by this runtime,
+// so that invalid programs can be rejected when they are deployed rather than
+// when they are called (which is much more expensive). A program is rejected
+// if it:
+//   - uses wasm features that are not enabled by the config of the runtime
+//   - imports anything other than the host functions of the runtime (which
+//     rejects non-deterministic imports, like WASI clocks or randomness)
+//   - declares a memory larger than [Config.MaxMemoryPages]
+//   - doesn't export the [AllocName] function and the [MemoryName] memory
+//
+// The program is validated without being compiled.
+func (r *WasmRuntime) ValidateProgram(programBytes []byte) error {
+	if err := wasmtime.ModuleValidate(r.engine, programBytes); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProgram, err)
+	}
+	module, err := parseModule(programBytes)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidProgram, err)
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, imp := range module.imports {
+		hostModule, ok := r.hostImports.Modules[imp.module]
+		if !ok {
+			return fmt.Errorf("%w: %s.%s", ErrUnsupportedImport, imp.module, imp.name)
+		}
+		hostFunction, ok := hostModule.HostFunctions[imp.name]
+		if !ok || imp.kind != externFunc {
+			return fmt.Errorf("%w: %s.%s", ErrUnsupportedImport, imp.module, imp.name)
+		}
+		if int(imp.index) >= len(module.types) || !module.types[imp.index].matches(hostFunction.Function.wasmType()) {
+			return fmt.Errorf("%w: %s.%s has the wrong signature", ErrUnsupportedImport, imp.module, imp.name)
+		}
+	}
+
+	for _, memory := range module.memories {
+		if memory.min > uint64(r.cfg.MaxMemoryPages) || (memory.hasMax && memory.max > uint64(r.cfg.MaxMemoryPages)) {
+			return fmt.Errorf("%w: %d pages allowed", ErrMemoryTooLarge, r.cfg.MaxMemoryPages)
+		}
+	}
+
+	if !slices.Contains(module.exports, wasmExport{name: AllocName, kind: externFunc}) {
+		return fmt.Errorf("%w: %s function", ErrMissingExport, AllocName)
+	}
+	if !slices.Contains(module.exports, wasmExport{name: MemoryName, kind: externMemory}) {
+		return fmt.Errorf("%w: %s memory", ErrMissingExport, MemoryName)
+	}
+	return nil
+}
+
+// wasmModule holds the parts of a wasm module that are validated by
+// [WasmRuntime.ValidateProgram].
+type wasmModule struct {
+	types    []funcType
+	imports  []wasmImport
+	memories []memoryLimits
+	exports  []wasmExport
+}
+
+type funcType struct {
+	params  []byte
+	results []byte
+}
+
+func (f funcType) matches(ft *wasmtime.FuncType) bool {
+	return slices.Equal(f.params, valTypes(ft.Params())) && slices.Equal(f.results, valTypes(ft.Results()))
+}
+
+func valTypes(types []*wasmtime.ValType) []byte {
+	encoded := make([]byte, len(types))
+	for i, t := range types {
+		switch t.Kind() {
+		case wasmtime.KindI32:
+			encoded[i] = valTypeI32
+		case wasmtime.KindI64:
+			encoded[i] = valTypeI64
+		case wasmtime.KindF32:
+			encoded[i] = valTypeF32
+		case wasmtime.KindF64:
+			encoded[i] = valTypeF64
+		}
+	}
+	return encoded
+}
+
+type wasmImport struct {
+	module string
+	name   string
+	kind   byte
+	// index is the type index of imported functions
+	index uint32
+}
+
+type memoryLimits struct {
+	min    uint64
+	max    uint64
+	hasMax bool
+}
+
+type wasmExport struct {
+	name string
+	kind byte
+}
+
+// parseModule parses the type, import, memory and export sections of the
+// binary wasm module [b]. The other sections are skipped.
+func parseModule(b []byte) (*wasmModule, error) {
+	if len(b) < 8 || !bytes.Equal(b[:4], wasmMagic) || b[4] != wasmVersion || !bytes.Equal(b[5:8], []byte{0, 0, 0}) {
+		return nil, fmt.Errorf("%w: invalid header", errMalformedModule)
+	}
+	module := &wasmModule{}
+	r := &wasmReader{b: b, off: 8}
+	for r.off < len(r.b) {
+		id := r.readByte()
+		size := r.readU32()
+		section := r.readBytes(int(size))
+		if r.err != nil {
+			return nil, r.err
+		}
+		sr := &wasmReader{b: section}
+		switch id {
+		case sectionType:
+			module.types = make([]funcType, sr.readCount())
+			for i := range module.types {
+				if sr.readByte() != funcTypeForm {
+					return nil, fmt.Errorf("%w: invalid type", errMalformedModule)
+				}
+				module.types[i].params = sr.readBytes(int(sr.readU32()))
+				module.types[i].results = sr.readBytes(int(sr.readU32()))
+			}
+		case sectionImport:
+			module.imports = make([]wasmImport, sr.readCount())
+			for i := range module.imports {
+				imp := &module.imports[i]
+				imp.module = sr.readName()
+				imp.name = sr.readName()
+				imp.kind = sr.readByte()
+				switch imp.kind {
+				case externFunc:
+					imp.index = sr.readU32()
+				case externTable:
+					sr.readByte()
+					sr.readLimits()
+				case externMemory:
+					module.memories = append(module.memories, sr.readLimits())
+				case externGlobal:
+					sr.readBytes(2)
+				default:
+					return nil, fmt.Errorf("%w: invalid import", errMalformedModule)
+				}
+			}
+		case sectionMemory:
+			count := sr.readCount()
+			for i := 0; i < count && sr.err == nil; i++ {
+				module.memories = append(module.memories, sr.readLimits())
+			}
+		case sectionExport:
+			module.exports = make([]wasmExport, sr.readCount())
+			for i := range module.exports {
+				module.exports[i].name = sr.readName()
+				module.exports[i].kind = sr.readByte()
+				sr.readU32()
+			}
+		}
+		if sr.err != nil {
+			return nil, sr.err
+		}
+	}
+	return module, nil
+}
+
+type wasmReader struct {
+	b   []byte
+	off int
+	err error
+}
+
+func (r *wasmReader) readByte() byte {
+	b := r.readBytes(1)
+	if len(b) == 0 {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *wasmReader) readBytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.b)-r.off {
+		r.err = fmt.Errorf("%w: unexpected end", errMalformedModule)
+		return nil
+	}
+	b := r.b[r.off : r.off+n]
+	r.off += n
+	return b
+}
+
+// readU64 reads an unsigned LEB128 integer.
+func (r *wasmReader) readU64() uint64 {
+	var v uint64
+	for shift := 0; shift < 64; shift += 7 {
+		b := r.readByte()
+		if r.err != nil {
+			return 0
+		}
+		v |= uint64(b&0x7F) << shift
+		if b&0x80 == 0 {
+			return v
+		}
+	}
+	r.err = fmt.Errorf("%w: integer too large", errMalformedModule)
+	return 0
+}
+
+func (r *wasmReader) readU32() uint32 {
+	v := r.readU64()
+	if v > uint64(^uint32(0)) {
+		r.err = fmt.Errorf("%w: integer too large", errMalformedModule)
+		return 0
+	}
+	return uint32(v)
+}
+
+// readCount reads the number of entries of a vector, which can't be larger
+// than the number of bytes left (every entry takes at least one byte).
+func (r *wasmReader) readCount() int {
+	count := r.readU32()
+	if r.err == nil && int(count) > len(r.b)-r.off {
+		r.err = fmt.Errorf("%w: unexpected end", errMalformedModule)
+	}
+	if r.err != nil {
+		return 0
+	}
+	return int(count)
+}
+
+func (r *wasmReader) readName() string {
+	return string(r.readBytes(int(r.readU32())))
+}
+
+func (r *wasmReader) readLimits() memoryLimits {
+	flags := r.readByte()
+	if flags&^(limitsHasMax|limitsShared|limits64) != 0 {
+		r.err = fmt.Errorf("%w: invalid limits", errMalformedModule)
+	}
+	limits := memoryLimits{min: r.readU64()}
+	if flags&limitsHasMax != 0 {
+		limits.hasMax = true
+		limits.max = r.readU64()
+	}
+	return limits
+}
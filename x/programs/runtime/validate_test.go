@@ -0,0 +1,175 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/bytecodealliance/wasmtime-go/v14"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/x/programs/test"
+)
+
+// validatedProgram is a valid program (with the default config) where %s is
+// replaced with additional imports or functions.
+const validatedProgram = `
+(module
+  (import "state" "put" (func $put (param i32 i32)))
+  %s
+  (memory (export "memory") 1)
+  (func (export "alloc") (param i32) (result i32)
+    (i32.const 0)))
+`
+
+func TestValidateProgram(t *testing.T) {
+	tests := []struct {
+		name    string
+		program string
+		config  func() *Config
+		err     error
+	}{
+		{
+			name:    "valid",
+			program: fmt.Sprintf(validatedProgram, ""),
+		},
+		{
+			name:    "missing alloc",
+			program: `(module (memory (export "memory") 1))`,
+			err:     ErrMissingExport,
+		},
+		{
+			name:    "missing memory",
+			program: `(module (memory 1) (func (export "alloc") (param i32) (result i32) (i32.const 0)))`,
+			err:     ErrMissingExport,
+		},
+		{
+			name:    "non-deterministic import",
+			program: fmt.Sprintf(validatedProgram, `(import "wasi_snapshot_preview1" "random_get" (func (param i32 i32) (result i32)))`),
+			err:     ErrUnsupportedImport,
+		},
+		{
+			name:    "unknown host function",
+			program: fmt.Sprintf(validatedProgram, `(import "state" "delete" (func (param i32 i32)))`),
+			err:     ErrUnsupportedImport,
+		},
+		{
+			name:    "wrong host function signature",
+			program: fmt.Sprintf(validatedProgram, `(import "state" "get" (func (param i32 i32)))`),
+			err:     ErrUnsupportedImport,
+		},
+		{
+			name:    "imported global",
+			program: fmt.Sprintf(validatedProgram, `(import "state" "put" (global i32))`),
+			err:     ErrUnsupportedImport,
+		},
+		{
+			name:    "memory too large",
+			program: `(module (memory (export "memory") 513) (func (export "alloc") (param i32) (result i32) (i32.const 0)))`,
+			err:     ErrMemoryTooLarge,
+		},
+		{
+			name:    "maximum memory too large",
+			program: `(module (memory (export "memory") 1 513) (func (export "alloc") (param i32) (result i32) (i32.const 0)))`,
+			err:     ErrMemoryTooLarge,
+		},
+		{
+			name:    "shared memory",
+			program: `(module (memory (export "memory") 1 1 shared) (func (export "alloc") (param i32) (result i32) (i32.const 0)))`,
+			err:     ErrInvalidProgram,
+		},
+		{
+			name:    "disabled feature",
+			program: fmt.Sprintf(validatedProgram, `(func (result v128) (v128.const i64x2 0 0))`),
+			config: func() *Config {
+				cfg, err := NewConfigBuilder().Build()
+				require.NoError(t, err)
+				return cfg
+			},
+			err: ErrInvalidProgram,
+		},
+		{
+			name:    "enabled feature",
+			program: fmt.Sprintf(validatedProgram, `(func (result v128) (v128.const i64x2 0 0))`),
+			config: func() *Config {
+				cfg, err := NewConfigBuilder().WithSIMD(true).Build()
+				require.NoError(t, err)
+				return cfg
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			cfg := NewConfig()
+			if tt.config != nil {
+				cfg = tt.config()
+			}
+			r := NewRuntime(cfg, logging.NoLog{})
+			program, err := wasmtime.Wat2Wasm(tt.program)
+			require.NoError(err)
+			require.ErrorIs(r.ValidateProgram(program), tt.err)
+		})
+	}
+
+	// Malformed modules are rejected
+	r := NewRuntime(NewConfig(), logging.NoLog{})
+	require.ErrorIs(t, r.ValidateProgram([]byte{0, 'a', 's', 'm'}), ErrInvalidProgram)
+	_, err := parseModule([]byte{0, 'a', 's', 'm', 1, 0, 0, 0, sectionType, 100})
+	require.ErrorIs(t, err, errMalformedModule)
+}
+
+func TestMemoryLimit(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	// grow traps if the memory can't grow by the number of pages following
+	// the program context
+	program, err := wasmtime.Wat2Wasm(`
+(module
+  (memory (export "memory") 1)
+  (global $next (mut i32) (i32.const 1024))
+  (func (export "alloc") (param $size i32) (result i32)
+    (local $ptr i32)
+    (local.set $ptr (global.get $next))
+    (global.set $next (i32.add (global.get $next) (local.get $size)))
+    (local.get $ptr))
+  (func (export "grow") (param $ctx i32)
+    (if (i32.eq (memory.grow (i32.load8_u (i32.add (local.get $ctx) (i32.const 114)))) (i32.const -1))
+      (then unreachable))))
+`)
+	require.NoError(err)
+	programID := ids.GenerateTestID()
+	account := codec.CreateAddress(0, programID)
+	state := &watStateManager{
+		StateManager: &test.StateManager{
+			AccountMap: map[codec.Address]ids.ID{account: programID},
+			Mu:         test.NewTestDB(),
+		},
+		programs: map[ids.ID][]byte{programID: program},
+	}
+	cfg := NewConfig()
+	cfg.MaxMemoryPages = 2
+	r := NewRuntime(cfg, logging.NoLog{})
+	require.NoError(r.ValidateProgram(program))
+
+	grow := func(pages byte) error {
+		_, err := r.CallProgram(ctx, &CallInfo{
+			State:        state,
+			Program:      account,
+			FunctionName: "grow",
+			Params:       []byte{pages},
+			Fuel:         1_000_000,
+		})
+		return err
+	}
+	require.NoError(grow(1))
+	require.Error(grow(2))
+}
@@ -0,0 +1,23 @@
+use borsh::BorshSerialize;
+
+#[derive(BorshSerialize)]
+struct EventArgs<'a> {
+    topic: &'a str,
+    data: &'a [u8],
+}
+
+/// Emits an event with the given `topic` and `data`. Unlike [`log`](crate::log),
+/// events are available in release builds and are returned with the result of
+/// the call (they are discarded if the call fails).
+/// # Panics
+/// Panics if the args cannot be serialized
+pub fn emit_event(topic: &str, data: &[u8]) {
+    #[link(wasm_import_module = "events")]
+    extern "C" {
+        #[link_name = "emit"]
+        fn ffi(ptr: *const u8, len: usize);
+    }
+    let args = borsh::to_vec(&EventArgs { topic, data }).expect("failed to serialize args");
+
+    unsafe { ffi(args.as_ptr(), args.len()) };
+}
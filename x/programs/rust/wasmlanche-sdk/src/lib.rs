@@ -6,6 +6,7 @@ pub mod build;
 pub mod state;
 
 mod context;
+mod events;
 mod logging;
 mod memory;
 mod program;
@@ -13,9 +14,10 @@ mod types;
 
 pub use self::{
     context::{Context, ExternalCallContext},
+    events::emit_event,
     logging::{log, register_panic},
     memory::HostPtr,
-    program::{send, DeferDeserialize, ExternalCallError, Program},
+    program::{send, set_admin, DeferDeserialize, ExternalCallError, Program},
     types::{Address, Gas, Id, ID_LEN},
 };
 pub use sdk_macros::{public, state_keys};
@@ -47,6 +47,12 @@ pub enum ExternalCallError {
     OutOfFuel = 2,
     #[error("insufficient funds")]
     InsufficientFunds = 3,
+    #[error("the maximum call depth was exceeded")]
+    CallDepthExceeded = 4,
+    #[error("the program can't be called while it is being called")]
+    Reentrancy = 5,
+    #[error("the caller is not authorized")]
+    Unauthorized = 6,
 }
 
 /// Transfer currency from the calling program to the passed address
@@ -164,6 +170,47 @@ impl Program {
 
         borsh::from_slice(&bytes).expect("failed to deserialize the account")
     }
+
+    /// Upgrades the account of this program to `program_id`, calling the
+    /// `migrate` function of the new program (if any) with `args`. The calling
+    /// program must be this program or its admin.
+    /// # Errors
+    /// Returns a [`ExternalCallError`] if the upgrade (or the migration) fails.
+    /// # Panics
+    /// Panics if there was an issue deserializing the result
+    pub fn upgrade(
+        &self,
+        program_id: Id,
+        args: &[u8],
+        max_units: Gas,
+    ) -> Result<(), ExternalCallError> {
+        #[link(wasm_import_module = "program")]
+        extern "C" {
+            #[link_name = "upgrade"]
+            fn upgrade(ptr: *const u8, len: usize) -> HostPtr;
+        }
+        let args = borsh::to_vec(&(self, program_id, args, max_units))
+            .expect("failed to serialize args");
+
+        let bytes = unsafe { upgrade(args.as_ptr(), args.len()) };
+
+        borsh::from_slice(&bytes).expect("failed to deserialize the result")
+    }
+}
+
+/// Sets (or removes) the admin that can upgrade the account of the calling
+/// program.
+/// # Panics
+/// Panics if the args cannot be serialized
+pub fn set_admin(admin: Option<Address>) {
+    #[link(wasm_import_module = "program")]
+    extern "C" {
+        #[link_name = "set_admin"]
+        fn set_admin(ptr: *const u8, len: usize);
+    }
+    let args = borsh::to_vec(&admin).expect("failed to serialize args");
+
+    unsafe { set_admin(args.as_ptr(), args.len()) };
 }
 
 #[derive(BorshSerialize)]
@@ -37,17 +37,21 @@ type StateManager struct {
 	ProgramsMap map[ids.ID]string
 	AccountMap  map[codec.Address]ids.ID
 	Balances    map[codec.Address]uint64
+	Admins      map[codec.Address]codec.Address
 	Mu          state.Mutable
+
+	// undo reverts each change made so far
+	undo []func(context.Context)
 }
 
-func (t StateManager) GetAccountProgram(_ context.Context, account codec.Address) (ids.ID, error) {
+func (t *StateManager) GetAccountProgram(_ context.Context, account codec.Address) (ids.ID, error) {
 	if programID, ok := t.AccountMap[account]; ok {
 		return programID, nil
 	}
 	return ids.Empty, nil
 }
 
-func (t StateManager) GetProgramBytes(_ context.Context, programID ids.ID) ([]byte, error) {
+func (t *StateManager) GetProgramBytes(_ context.Context, programID ids.ID) ([]byte, error) {
 	programName, ok := t.ProgramsMap[programID]
 	if !ok {
 		return nil, errors.New("couldn't find program")
@@ -62,25 +66,48 @@ func (t StateManager) GetProgramBytes(_ context.Context, programID ids.ID) ([]by
 	return os.ReadFile(filepath.Join(dir, "/wasm32-unknown-unknown/debug/"+programName+".wasm"))
 }
 
-func (t StateManager) NewAccountWithProgram(_ context.Context, programID ids.ID, _ []byte) (codec.Address, error) {
+func (t *StateManager) NewAccountWithProgram(ctx context.Context, programID ids.ID, _ []byte) (codec.Address, error) {
 	account := codec.CreateAddress(0, programID)
-	t.AccountMap[account] = programID
-	return account, nil
+	return account, t.SetAccountProgram(ctx, account, programID)
 }
 
-func (t StateManager) SetAccountProgram(_ context.Context, account codec.Address, programID ids.ID) error {
+func (t *StateManager) SetAccountProgram(_ context.Context, account codec.Address, programID ids.ID) error {
+	prev, ok := t.AccountMap[account]
+	t.undo = append(t.undo, func(context.Context) {
+		if ok {
+			t.AccountMap[account] = prev
+		} else {
+			delete(t.AccountMap, account)
+		}
+	})
 	t.AccountMap[account] = programID
 	return nil
 }
 
-func (t StateManager) GetBalance(_ context.Context, address codec.Address) (uint64, error) {
+func (t *StateManager) GetAccountAdmin(_ context.Context, account codec.Address) (codec.Address, error) {
+	return t.Admins[account], nil
+}
+
+func (t *StateManager) SetAccountAdmin(_ context.Context, account codec.Address, admin codec.Address) error {
+	if t.Admins == nil {
+		t.Admins = map[codec.Address]codec.Address{}
+	}
+	prev := t.Admins[account]
+	t.undo = append(t.undo, func(context.Context) {
+		t.Admins[account] = prev
+	})
+	t.Admins[account] = admin
+	return nil
+}
+
+func (t *StateManager) GetBalance(_ context.Context, address codec.Address) (uint64, error) {
 	if balance, ok := t.Balances[address]; ok {
 		return balance, nil
 	}
 	return 0, nil
 }
 
-func (t StateManager) TransferBalance(ctx context.Context, from codec.Address, to codec.Address, amount uint64) error {
+func (t *StateManager) TransferBalance(ctx context.Context, from codec.Address, to codec.Address, amount uint64) error {
 	balance, err := t.GetBalance(ctx, from)
 	if err != nil {
 		return err
@@ -88,13 +115,29 @@ func (t StateManager) TransferBalance(ctx context.Context, from codec.Address, t
 	if balance < amount {
 		return errors.New("insufficient balance")
 	}
+	t.undo = append(t.undo, func(context.Context) {
+		t.Balances[to] -= amount
+		t.Balances[from] += amount
+	})
 	t.Balances[from] -= amount
 	t.Balances[to] += amount
 	return nil
 }
 
-func (t StateManager) GetProgramState(address codec.Address) state.Mutable {
-	return &prefixedState{address: address, inner: t.Mu}
+func (t *StateManager) GetProgramState(address codec.Address) state.Mutable {
+	return &prefixedState{address: address, inner: t.Mu, manager: t}
+}
+
+func (t *StateManager) OpIndex() int {
+	return len(t.undo)
+}
+
+func (t *StateManager) Rollback(ctx context.Context, restorePoint int) error {
+	for i := len(t.undo) - 1; i >= restorePoint; i-- {
+		t.undo[i](ctx)
+	}
+	t.undo = t.undo[:restorePoint]
+	return nil
 }
 
 var _ state.Mutable = (*prefixedState)(nil)
@@ -102,6 +145,7 @@ var _ state.Mutable = (*prefixedState)(nil)
 type prefixedState struct {
 	address codec.Address
 	inner   state.Mutable
+	manager *StateManager
 }
 
 func (p *prefixedState) GetValue(ctx context.Context, key []byte) (value []byte, err error) {
@@ -109,11 +153,27 @@ func (p *prefixedState) GetValue(ctx context.Context, key []byte) (value []byte,
 }
 
 func (p *prefixedState) Insert(ctx context.Context, key []byte, value []byte) error {
-	return p.inner.Insert(ctx, prependAccountToKey(p.address, key), value)
+	k := prependAccountToKey(p.address, key)
+	p.record(ctx, k)
+	return p.inner.Insert(ctx, k, value)
 }
 
 func (p *prefixedState) Remove(ctx context.Context, key []byte) error {
-	return p.inner.Remove(ctx, prependAccountToKey(p.address, key))
+	k := prependAccountToKey(p.address, key)
+	p.record(ctx, k)
+	return p.inner.Remove(ctx, k)
+}
+
+// record allows the change to [key] to be reverted
+func (p *prefixedState) record(ctx context.Context, key []byte) {
+	prev, err := p.inner.GetValue(ctx, key)
+	p.manager.undo = append(p.manager.undo, func(ctx context.Context) {
+		if err == nil {
+			_ = p.inner.Insert(ctx, key, prev)
+		} else {
+			_ = p.inner.Remove(ctx, key)
+		}
+	})
 }
 
 // prependAccountToKey makes the key relative to the account
@@ -0,0 +1,144 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var _ chain.Action = (*Claim)(nil)
+
+type Claim struct {
+	// [NodeID] is the validator the actor delegated to. All accrued rewards and
+	// any stake that finished unbonding are sent to the actor.
+	NodeID ids.NodeID `json:"nodeID"`
+
+	s *Staking
+}
+
+func (s *Staking) NewClaim(nodeID ids.NodeID) *Claim {
+	return &Claim{NodeID: nodeID, s: s}
+}
+
+func (c *Claim) GetTypeID() uint8 {
+	return c.s.c.FirstActionID + claimID
+}
+
+func (c *Claim) StateKeys(actor codec.Address, _ ids.ID) state.Keys {
+	return state.Keys{
+		string(c.s.DelegationKey(c.NodeID, actor)): state.Read | state.Write,
+		string(c.s.RewardsKey()):                   state.Read,
+		string(c.s.balances.BalanceKey(actor)):     state.All,
+	}
+}
+
+func (c *Claim) StateKeysMaxChunks() []uint16 {
+	return []uint16{DelegationChunks, RewardsChunks, c.s.balances.BalanceChunks()}
+}
+
+func (c *Claim) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) ([][]byte, error) {
+	delegation, err := c.s.GetDelegation(ctx, mu, c.NodeID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if delegation == nil {
+		return nil, ErrDelegationMissing
+	}
+	_, index, err := c.s.GetRewards(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	if err := settle(delegation, index); err != nil {
+		return nil, err
+	}
+	result := &ClaimResult{Rewards: delegation.Rewards}
+	if delegation.Unbonding > 0 && timestamp >= delegation.UnbondingEnd {
+		result.Unbonded = delegation.Unbonding
+	}
+	amount, err := smath.Add64(result.Rewards, result.Unbonded)
+	if err != nil {
+		return nil, err
+	}
+	if amount == 0 {
+		return nil, ErrNothingToClaim
+	}
+	delegation.Rewards = 0
+	delegation.Unbonding -= result.Unbonded
+	if delegation.Amount == 0 && delegation.Unbonding == 0 {
+		err = c.s.deleteDelegation(ctx, mu, c.NodeID, actor)
+	} else {
+		err = c.s.setDelegation(ctx, mu, c.NodeID, actor, delegation)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := c.s.balances.AddBalance(ctx, mu, actor, amount); err != nil {
+		return nil, err
+	}
+	output, err := result.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{output}, nil
+}
+
+func (*Claim) ComputeUnits(chain.Rules) uint64 {
+	return ClaimComputeUnits
+}
+
+func (*Claim) Size() int {
+	return ids.NodeIDLen
+}
+
+func (c *Claim) Marshal(p *codec.Packer) {
+	p.PackFixedBytes(c.NodeID.Bytes())
+}
+
+func (s *Staking) unmarshalClaim(p *codec.Packer) (chain.Action, error) {
+	claim := Claim{s: s}
+	unpackNodeID(p, &claim.NodeID)
+	return &claim, p.Err()
+}
+
+func (c *Claim) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, c.GetTypeID())
+}
+
+// ClaimResult is a custom successful response output that provides
+// information about a successful [Claim].
+type ClaimResult struct {
+	Rewards  uint64 `json:"rewards"`
+	Unbonded uint64 `json:"unbonded"`
+}
+
+func UnmarshalClaimResult(b []byte) (*ClaimResult, error) {
+	p := codec.NewReader(b, consts.Uint64Len*2)
+	var result ClaimResult
+	result.Rewards = p.UnpackUint64(false)
+	result.Unbonded = p.UnpackUint64(false)
+	return &result, p.Err()
+}
+
+func (c *ClaimResult) Marshal() ([]byte, error) {
+	p := codec.NewWriter(consts.Uint64Len*2, consts.Uint64Len*2)
+	p.PackUint64(c.Rewards)
+	p.PackUint64(c.Unbonded)
+	return p.Bytes(), p.Err()
+}
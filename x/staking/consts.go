@@ -0,0 +1,22 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+// Action IDs are offset by [Config.FirstActionID].
+const (
+	registerValidatorID   uint8 = 0
+	delegateID            uint8 = 1
+	undelegateID          uint8 = 2
+	claimID               uint8 = 3
+	deregisterValidatorID uint8 = 4
+)
+
+const (
+	// TODO: tune this
+	RegisterValidatorComputeUnits   = 10
+	DelegateComputeUnits            = 5
+	UndelegateComputeUnits          = 5
+	ClaimComputeUnits               = 5
+	DeregisterValidatorComputeUnits = 5
+)
@@ -0,0 +1,131 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var _ chain.Action = (*Delegate)(nil)
+
+type Delegate struct {
+	// [NodeID] is the validator to delegate to.
+	NodeID ids.NodeID `json:"nodeID"`
+
+	// [Amount] is the amount of the native asset to delegate.
+	Amount uint64 `json:"amount"`
+
+	s *Staking
+}
+
+func (s *Staking) NewDelegate(nodeID ids.NodeID, amount uint64) *Delegate {
+	return &Delegate{NodeID: nodeID, Amount: amount, s: s}
+}
+
+func (d *Delegate) GetTypeID() uint8 {
+	return d.s.c.FirstActionID + delegateID
+}
+
+func (d *Delegate) StateKeys(actor codec.Address, _ ids.ID) state.Keys {
+	return state.Keys{
+		string(d.s.ValidatorKey(d.NodeID)):         state.Read | state.Write,
+		string(d.s.DelegationKey(d.NodeID, actor)): state.All,
+		string(d.s.RewardsKey()):                   state.All,
+		string(d.s.balances.BalanceKey(actor)):     state.Read | state.Write,
+	}
+}
+
+func (d *Delegate) StateKeysMaxChunks() []uint16 {
+	return []uint16{ValidatorChunks, DelegationChunks, RewardsChunks, d.s.balances.BalanceChunks()}
+}
+
+func (d *Delegate) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) ([][]byte, error) {
+	if d.Amount == 0 {
+		// This should be guarded via [Unmarshal] but we check anyways.
+		return nil, ErrValueZero
+	}
+	validator, err := d.s.GetValidator(ctx, mu, d.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	if validator == nil {
+		return nil, ErrValidatorMissing
+	}
+	totalStake, index, err := d.s.GetRewards(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	delegation, err := d.s.GetDelegation(ctx, mu, d.NodeID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if delegation == nil {
+		delegation = &Delegation{rewardIndex: index}
+	}
+	if err := settle(delegation, index); err != nil {
+		return nil, err
+	}
+	if delegation.Amount, err = smath.Add64(delegation.Amount, d.Amount); err != nil {
+		return nil, err
+	}
+	if delegation.Amount < d.s.c.MinDelegatorStake {
+		return nil, ErrStakeTooLow
+	}
+	if validator.Stake, err = smath.Add64(validator.Stake, d.Amount); err != nil {
+		return nil, err
+	}
+	if totalStake, err = smath.Add64(totalStake, d.Amount); err != nil {
+		return nil, err
+	}
+	if err := d.s.balances.SubBalance(ctx, mu, actor, d.Amount); err != nil {
+		return nil, err
+	}
+	if err := d.s.setValidator(ctx, mu, d.NodeID, validator); err != nil {
+		return nil, err
+	}
+	if err := d.s.setDelegation(ctx, mu, d.NodeID, actor, delegation); err != nil {
+		return nil, err
+	}
+	return nil, d.s.setRewards(ctx, mu, totalStake, index)
+}
+
+func (*Delegate) ComputeUnits(chain.Rules) uint64 {
+	return DelegateComputeUnits
+}
+
+func (*Delegate) Size() int {
+	return ids.NodeIDLen + consts.Uint64Len
+}
+
+func (d *Delegate) Marshal(p *codec.Packer) {
+	p.PackFixedBytes(d.NodeID.Bytes())
+	p.PackUint64(d.Amount)
+}
+
+func (s *Staking) unmarshalDelegate(p *codec.Packer) (chain.Action, error) {
+	delegate := Delegate{s: s}
+	unpackNodeID(p, &delegate.NodeID)
+	delegate.Amount = p.UnpackUint64(true)
+	return &delegate, p.Err()
+}
+
+func (d *Delegate) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, d.GetTypeID())
+}
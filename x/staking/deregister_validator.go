@@ -0,0 +1,120 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var _ chain.Action = (*DeregisterValidator)(nil)
+
+type DeregisterValidator struct {
+	// [NodeID] is the validator to remove. Only its owner can remove it, once
+	// all other delegators have undelegated. The whole stake of the owner
+	// starts unbonding and can be claimed with [Claim].
+	NodeID ids.NodeID `json:"nodeID"`
+
+	s *Staking
+}
+
+func (s *Staking) NewDeregisterValidator(nodeID ids.NodeID) *DeregisterValidator {
+	return &DeregisterValidator{NodeID: nodeID, s: s}
+}
+
+func (d *DeregisterValidator) GetTypeID() uint8 {
+	return d.s.c.FirstActionID + deregisterValidatorID
+}
+
+func (d *DeregisterValidator) StateKeys(actor codec.Address, _ ids.ID) state.Keys {
+	return state.Keys{
+		string(d.s.ValidatorKey(d.NodeID)):         state.Read | state.Write,
+		string(d.s.DelegationKey(d.NodeID, actor)): state.Read | state.Write,
+		string(d.s.RewardsKey()):                   state.Read | state.Write,
+	}
+}
+
+func (*DeregisterValidator) StateKeysMaxChunks() []uint16 {
+	return []uint16{ValidatorChunks, DelegationChunks, RewardsChunks}
+}
+
+func (d *DeregisterValidator) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) ([][]byte, error) {
+	validator, err := d.s.GetValidator(ctx, mu, d.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	if validator == nil {
+		return nil, ErrValidatorMissing
+	}
+	if actor != validator.Owner {
+		return nil, ErrNotOwner
+	}
+	delegation, err := d.s.GetDelegation(ctx, mu, d.NodeID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if delegation == nil {
+		return nil, ErrDelegationMissing
+	}
+	if validator.Stake != delegation.Amount {
+		// Other delegators must undelegate first, otherwise their stake would
+		// be left with a validator that no longer exists.
+		return nil, ErrDelegationsRemaining
+	}
+	totalStake, index, err := d.s.GetRewards(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	if err := settle(delegation, index); err != nil {
+		return nil, err
+	}
+	if delegation.Unbonding, err = smath.Add64(delegation.Unbonding, delegation.Amount); err != nil {
+		return nil, err
+	}
+	delegation.UnbondingEnd = timestamp + d.s.c.UnbondingPeriod
+	delegation.Amount = 0
+	if err := d.s.deleteValidator(ctx, mu, d.NodeID); err != nil {
+		return nil, err
+	}
+	if err := d.s.setDelegation(ctx, mu, d.NodeID, actor, delegation); err != nil {
+		return nil, err
+	}
+	return nil, d.s.setRewards(ctx, mu, totalStake-validator.Stake, index)
+}
+
+func (*DeregisterValidator) ComputeUnits(chain.Rules) uint64 {
+	return DeregisterValidatorComputeUnits
+}
+
+func (*DeregisterValidator) Size() int {
+	return ids.NodeIDLen
+}
+
+func (d *DeregisterValidator) Marshal(p *codec.Packer) {
+	p.PackFixedBytes(d.NodeID.Bytes())
+}
+
+func (s *Staking) unmarshalDeregisterValidator(p *codec.Packer) (chain.Action, error) {
+	deregister := DeregisterValidator{s: s}
+	unpackNodeID(p, &deregister.NodeID)
+	return &deregister, p.Err()
+}
+
+func (d *DeregisterValidator) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, d.GetTypeID())
+}
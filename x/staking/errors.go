@@ -0,0 +1,21 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import "errors"
+
+var (
+	ErrValueZero            = errors.New("value is zero")
+	ErrValidatorExists      = errors.New("validator already exists")
+	ErrValidatorMissing     = errors.New("validator is missing")
+	ErrDelegationMissing    = errors.New("delegation is missing")
+	ErrStakeTooLow          = errors.New("stake is too low")
+	ErrInsufficientStake    = errors.New("insufficient stake")
+	ErrNothingToClaim       = errors.New("nothing to claim")
+	ErrOverflow             = errors.New("overflow")
+	ErrNotOwner             = errors.New("actor is not the owner")
+	ErrDelegationsRemaining = errors.New("validator has remaining delegations")
+	ErrInvalidCertificate   = errors.New("invalid staking certificate")
+	ErrInvalidSignature     = errors.New("invalid staking signature")
+)
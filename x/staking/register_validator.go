@@ -0,0 +1,183 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+
+	astaking "github.com/ava-labs/avalanchego/staking"
+	smath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// maxSignatureLen is the size of a signature by the largest RSA staking key
+// (4096 bits) allowed by [astaking.ParseCertificate].
+const maxSignatureLen = 512
+
+// registrationPrefix separates the messages signed to register a validator
+// from anything else signed by staking keys.
+var registrationPrefix = []byte("hypersdk/staking/register")
+
+var _ chain.Action = (*RegisterValidator)(nil)
+
+type RegisterValidator struct {
+	// [NodeID] is the validator to register. The actor becomes the owner of
+	// the validator.
+	NodeID ids.NodeID `json:"nodeID"`
+
+	// [Stake] is the amount of the native asset delegated to the validator by
+	// its owner.
+	Stake uint64 `json:"stake"`
+
+	// [Certificate] is the staking certificate of [NodeID] and [Signature] is
+	// the signature of [RegistrationMessage] by its key, which proves that the
+	// actor controls [NodeID].
+	Certificate []byte `json:"certificate"`
+	Signature   []byte `json:"signature"`
+
+	s *Staking
+}
+
+func (s *Staking) NewRegisterValidator(nodeID ids.NodeID, stake uint64, certificate []byte, signature []byte) *RegisterValidator {
+	return &RegisterValidator{NodeID: nodeID, Stake: stake, Certificate: certificate, Signature: signature, s: s}
+}
+
+// RegistrationMessage returns the message that the staking key of [nodeID]
+// must sign for [owner] to register it on the chain [chainID].
+func RegistrationMessage(chainID ids.ID, nodeID ids.NodeID, owner codec.Address) []byte {
+	msg := make([]byte, 0, len(registrationPrefix)+ids.IDLen+ids.NodeIDLen+codec.AddressLen)
+	msg = append(msg, registrationPrefix...)
+	msg = append(msg, chainID[:]...)
+	msg = append(msg, nodeID[:]...)
+	return append(msg, owner[:]...)
+}
+
+// SignRegistration signs the [RegistrationMessage] of [nodeID] and [owner]
+// with [signer] (the staking key of [nodeID], i.e. the private key of a
+// [tls.Certificate] loaded with [astaking.LoadTLSCertFromFiles]).
+func SignRegistration(signer crypto.Signer, chainID ids.ID, nodeID ids.NodeID, owner codec.Address) ([]byte, error) {
+	hash := sha256.Sum256(RegistrationMessage(chainID, nodeID, owner))
+	return signer.Sign(rand.Reader, hash[:], crypto.SHA256)
+}
+
+func (r *RegisterValidator) GetTypeID() uint8 {
+	return r.s.c.FirstActionID + registerValidatorID
+}
+
+func (r *RegisterValidator) StateKeys(actor codec.Address, _ ids.ID) state.Keys {
+	return state.Keys{
+		string(r.s.ValidatorKey(r.NodeID)):         state.Allocate | state.Write,
+		string(r.s.DelegationKey(r.NodeID, actor)): state.All,
+		string(r.s.RewardsKey()):                   state.All,
+		string(r.s.balances.BalanceKey(actor)):     state.Read | state.Write,
+	}
+}
+
+func (r *RegisterValidator) StateKeysMaxChunks() []uint16 {
+	return []uint16{ValidatorChunks, DelegationChunks, RewardsChunks, r.s.balances.BalanceChunks()}
+}
+
+func (r *RegisterValidator) Execute(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	_ int64,
+	actor codec.Address,
+	_ ids.ID,
+) ([][]byte, error) {
+	if r.Stake < r.s.c.MinValidatorStake {
+		return nil, ErrStakeTooLow
+	}
+	cert, err := astaking.ParseCertificate(r.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCertificate, err)
+	}
+	if ids.NodeIDFromCert(cert) != r.NodeID {
+		return nil, ErrInvalidCertificate
+	}
+	if err := astaking.CheckSignature(cert, RegistrationMessage(rules.ChainID(), r.NodeID, actor), r.Signature); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	validator, err := r.s.GetValidator(ctx, mu, r.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	if validator != nil {
+		return nil, ErrValidatorExists
+	}
+	totalStake, index, err := r.s.GetRewards(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	newTotalStake, err := smath.Add64(totalStake, r.Stake)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.s.balances.SubBalance(ctx, mu, actor, r.Stake); err != nil {
+		return nil, err
+	}
+	if err := r.s.setValidator(ctx, mu, r.NodeID, &Validator{Owner: actor, Stake: r.Stake}); err != nil {
+		return nil, err
+	}
+	// The actor may still have stake unbonding from a previous registration of
+	// [NodeID], which must be kept.
+	delegation, err := r.s.GetDelegation(ctx, mu, r.NodeID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if delegation == nil {
+		delegation = &Delegation{rewardIndex: index}
+	}
+	if err := settle(delegation, index); err != nil {
+		return nil, err
+	}
+	delegation.Amount = r.Stake
+	if err := r.s.setDelegation(ctx, mu, r.NodeID, actor, delegation); err != nil {
+		return nil, err
+	}
+	return nil, r.s.setRewards(ctx, mu, newTotalStake, index)
+}
+
+func (*RegisterValidator) ComputeUnits(chain.Rules) uint64 {
+	return RegisterValidatorComputeUnits
+}
+
+func (r *RegisterValidator) Size() int {
+	return ids.NodeIDLen + consts.Uint64Len + codec.BytesLen(r.Certificate) + codec.BytesLen(r.Signature)
+}
+
+func (r *RegisterValidator) Marshal(p *codec.Packer) {
+	p.PackFixedBytes(r.NodeID.Bytes())
+	p.PackUint64(r.Stake)
+	p.PackBytes(r.Certificate)
+	p.PackBytes(r.Signature)
+}
+
+func (s *Staking) unmarshalRegisterValidator(p *codec.Packer) (chain.Action, error) {
+	register := RegisterValidator{s: s}
+	unpackNodeID(p, &register.NodeID)
+	register.Stake = p.UnpackUint64(true)
+	p.UnpackBytes(astaking.MaxCertificateLen, true, &register.Certificate)
+	p.UnpackBytes(maxSignatureLen, true, &register.Signature)
+	return &register, p.Err()
+}
+
+func (r *RegisterValidator) ValidRange(rules chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(rules, r.GetTypeID())
+}
+
+func unpackNodeID(p *codec.Packer, dest *ids.NodeID) {
+	b := dest[:]
+	p.UnpackFixedBytes(ids.NodeIDLen, &b)
+}
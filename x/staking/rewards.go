@@ -0,0 +1,83 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+// rewardPrecision scales the reward index so that small rewards distributed
+// over a large amount of stake are not lost to rounding.
+var rewardPrecision = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+// settle adds the rewards accrued by [d] since it was last modified and moves
+// its reward index to [index].
+func settle(d *Delegation, index *big.Int) error {
+	accrued := new(big.Int).Sub(index, d.rewardIndex)
+	accrued.Mul(accrued, new(big.Int).SetUint64(d.Amount))
+	accrued.Quo(accrued, rewardPrecision)
+	if !accrued.IsUint64() {
+		return ErrOverflow
+	}
+	rewards, err := smath.Add64(d.Rewards, accrued.Uint64())
+	if err != nil {
+		return err
+	}
+	d.Rewards = rewards
+	d.rewardIndex = index
+	return nil
+}
+
+// distribute adds [RewardShare] of [fee] to the reward index. If nothing is
+// staked, the fee is not distributed.
+func (s *Staking) distribute(ctx context.Context, mu state.Mutable, fee uint64) error {
+	totalStake, index, err := s.GetRewards(ctx, mu)
+	if err != nil {
+		return err
+	}
+	if totalStake == 0 {
+		return nil
+	}
+	reward := new(big.Int).Mul(new(big.Int).SetUint64(fee), new(big.Int).SetUint64(s.c.RewardShare))
+	reward.Quo(reward, big.NewInt(FeeDenominator))
+	if reward.Sign() == 0 {
+		return nil
+	}
+	reward.Mul(reward, rewardPrecision)
+	reward.Quo(reward, new(big.Int).SetUint64(totalStake))
+	return s.setRewards(ctx, mu, totalStake, index.Add(index, reward))
+}
+
+var _ chain.BlockFeeHandler = (*stateManager)(nil)
+
+type stateManager struct {
+	chain.StateManager
+
+	s *Staking
+}
+
+// StateManager wraps the [chain.StateManager] of a VM so that [RewardShare] of
+// the fees paid by each block is distributed to stakers (fees are burned
+// otherwise).
+//
+// Fees are distributed once per block (see [chain.BlockFeeHandler]), so fee
+// payments don't access [RewardsKey] and don't prevent transactions from being
+// executed in parallel.
+func (s *Staking) StateManager(inner chain.StateManager) chain.StateManager {
+	return &stateManager{inner, s}
+}
+
+func (m *stateManager) BlockStateKeys() state.Keys {
+	return state.Keys{string(m.s.RewardsKey()): state.All}
+}
+
+func (m *stateManager) BlockFees(ctx context.Context, mu state.Mutable, fees uint64) error {
+	return m.s.distribute(ctx, mu, fees)
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// FeeDenominator is the denominator of [Config.RewardShare].
+const FeeDenominator = 10_000
+
+// BalanceHandler moves the native asset of a VM in and out of stake.
+type BalanceHandler interface {
+	// BalanceKey returns the state key that stores the native balance of
+	// [addr] (suffixed with its max chunks).
+	BalanceKey(addr codec.Address) []byte
+
+	// BalanceChunks is the max chunks of any key returned by [BalanceKey].
+	BalanceChunks() uint16
+
+	AddBalance(ctx context.Context, mu state.Mutable, addr codec.Address, amount uint64) error
+	SubBalance(ctx context.Context, mu state.Mutable, addr codec.Address, amount uint64) error
+}
+
+type Config struct {
+	// Prefix is the first byte of all keys written by the module. It must not
+	// be used by any other keys of the VM.
+	Prefix byte
+
+	// FirstActionID is the type ID of [RegisterValidator]. [Delegate],
+	// [Undelegate], [Claim], and [DeregisterValidator] use the following IDs.
+	FirstActionID uint8
+
+	MinValidatorStake uint64
+	MinDelegatorStake uint64
+
+	// UnbondingPeriod is the time (in ms) that undelegated stake must wait
+	// before it can be claimed.
+	UnbondingPeriod int64
+
+	// RewardShare is the portion of each fee (in basis points) that is
+	// distributed to stakers.
+	RewardShare uint64
+}
+
+// Staking provides the actions and storage of a native staking module that
+// can be registered by any hypersdk VM.
+type Staking struct {
+	c        Config
+	balances BalanceHandler
+}
+
+func New(c Config, balances BalanceHandler) *Staking {
+	return &Staking{c, balances}
+}
+
+// Register adds all staking actions to [registry].
+func (s *Staking) Register(registry *codec.TypeParser[chain.Action]) error {
+	errs := &wrappers.Errs{}
+	errs.Add(
+		registry.Register(s.c.FirstActionID+registerValidatorID, s.unmarshalRegisterValidator),
+		registry.Register(s.c.FirstActionID+delegateID, s.unmarshalDelegate),
+		registry.Register(s.c.FirstActionID+undelegateID, s.unmarshalUndelegate),
+		registry.Register(s.c.FirstActionID+claimID, s.unmarshalClaim),
+		registry.Register(s.c.FirstActionID+deregisterValidatorID, s.unmarshalDeregisterValidator),
+	)
+	return errs.Err
+}
@@ -0,0 +1,407 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"context"
+	"crypto"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+
+	astaking "github.com/ava-labs/avalanchego/staking"
+)
+
+var _ state.Mutable = (*testDB)(nil)
+
+type testDB struct {
+	storage map[string][]byte
+}
+
+func newTestDB() *testDB {
+	return &testDB{storage: make(map[string][]byte)}
+}
+
+func (db *testDB) GetValue(_ context.Context, key []byte) ([]byte, error) {
+	val, ok := db.storage[string(key)]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return val, nil
+}
+
+func (db *testDB) Insert(_ context.Context, key []byte, value []byte) error {
+	db.storage[string(key)] = value
+	return nil
+}
+
+func (db *testDB) Remove(_ context.Context, key []byte) error {
+	delete(db.storage, string(key))
+	return nil
+}
+
+var (
+	_ BalanceHandler   = (*testBalances)(nil)
+	_ chain.FeeHandler = (*testBalances)(nil)
+)
+
+// testBalances stores native balances under the 0x0 prefix.
+type testBalances struct{}
+
+func (*testBalances) BalanceKey(addr codec.Address) []byte {
+	k := make([]byte, 1+codec.AddressLen+consts.Uint16Len)
+	copy(k[1:], addr[:])
+	binary.BigEndian.PutUint16(k[1+codec.AddressLen:], 1)
+	return k
+}
+
+func (*testBalances) BalanceChunks() uint16 {
+	return 1
+}
+
+func (b *testBalances) getBalance(ctx context.Context, im state.Immutable, addr codec.Address) (uint64, error) {
+	v, err := im.GetValue(ctx, b.BalanceKey(addr))
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+func (b *testBalances) AddBalance(ctx context.Context, mu state.Mutable, addr codec.Address, amount uint64) error {
+	bal, err := b.getBalance(ctx, mu, addr)
+	if err != nil {
+		return err
+	}
+	return mu.Insert(ctx, b.BalanceKey(addr), binary.BigEndian.AppendUint64(nil, bal+amount))
+}
+
+func (b *testBalances) SubBalance(ctx context.Context, mu state.Mutable, addr codec.Address, amount uint64) error {
+	bal, err := b.getBalance(ctx, mu, addr)
+	if err != nil {
+		return err
+	}
+	if bal < amount {
+		return ErrInsufficientStake
+	}
+	return mu.Insert(ctx, b.BalanceKey(addr), binary.BigEndian.AppendUint64(nil, bal-amount))
+}
+
+func (b *testBalances) SponsorStateKeys(addr codec.Address) state.Keys {
+	return state.Keys{string(b.BalanceKey(addr)): state.Read | state.Write}
+}
+
+func (*testBalances) CanDeduct(context.Context, codec.Address, state.Immutable, uint64) error {
+	return nil
+}
+
+func (b *testBalances) Deduct(ctx context.Context, addr codec.Address, mu state.Mutable, amount uint64) error {
+	return b.SubBalance(ctx, mu, addr, amount)
+}
+
+var _ chain.StateManager = (*testStateManager)(nil)
+
+type testStateManager struct {
+	*testBalances
+}
+
+func (*testStateManager) HeightKey() []byte {
+	return []byte{0x2}
+}
+
+func (*testStateManager) TimestampKey() []byte {
+	return []byte{0x3}
+}
+
+func (*testStateManager) FeeKey() []byte {
+	return []byte{0x4}
+}
+
+func newTestStaking() (*Staking, *testBalances) {
+	balances := &testBalances{}
+	return New(Config{
+		Prefix:            0x1,
+		FirstActionID:     10,
+		MinValidatorStake: 100,
+		MinDelegatorStake: 10,
+		UnbondingPeriod:   1_000,
+		RewardShare:       5_000, // 50%
+	}, balances), balances
+}
+
+var testChainID = ids.GenerateTestID()
+
+type testRules struct {
+	chain.Rules
+}
+
+func (*testRules) ChainID() ids.ID {
+	return testChainID
+}
+
+func execute(ctx context.Context, mu state.Mutable, action chain.Action, timestamp int64, actor codec.Address) ([][]byte, error) {
+	return action.Execute(ctx, &testRules{}, mu, timestamp, actor, ids.Empty)
+}
+
+type testValidator struct {
+	nodeID      ids.NodeID
+	certificate []byte
+	signer      crypto.Signer
+}
+
+func newTestValidator(t *testing.T) *testValidator {
+	require := require.New(t)
+	tlsCert, err := astaking.NewTLSCert()
+	require.NoError(err)
+	cert, err := astaking.ParseCertificate(tlsCert.Leaf.Raw)
+	require.NoError(err)
+	signer, ok := tlsCert.PrivateKey.(crypto.Signer)
+	require.True(ok)
+	return &testValidator{
+		nodeID:      ids.NodeIDFromCert(cert),
+		certificate: tlsCert.Leaf.Raw,
+		signer:      signer,
+	}
+}
+
+func (v *testValidator) register(t *testing.T, s *Staking, stake uint64, owner codec.Address) *RegisterValidator {
+	signature, err := SignRegistration(v.signer, testChainID, v.nodeID, owner)
+	require.NoError(t, err)
+	return s.NewRegisterValidator(v.nodeID, stake, v.certificate, signature)
+}
+
+func TestStakingRewards(t *testing.T) {
+	var (
+		require = require.New(t)
+		ctx     = context.TODO()
+		db      = newTestDB()
+		owner   = codec.CreateAddress(0, ids.GenerateTestID())
+		alice   = codec.CreateAddress(0, ids.GenerateTestID())
+		node    = newTestValidator(t)
+		nodeID  = node.nodeID
+	)
+	s, balances := newTestStaking()
+	require.NoError(balances.AddBalance(ctx, db, owner, 1_000))
+	require.NoError(balances.AddBalance(ctx, db, alice, 2_000))
+
+	// Fees are distributed once per block (so fee payments don't conflict) and
+	// are not distributed if nothing is staked
+	fees := s.StateManager(&testStateManager{balances}).(chain.BlockFeeHandler)
+	require.NotContains(balances.SponsorStateKeys(alice), string(s.RewardsKey()))
+	require.Contains(fees.BlockStateKeys(), string(s.RewardsKey()))
+	require.NoError(balances.Deduct(ctx, alice, db, 100))
+	require.NoError(fees.BlockFees(ctx, db, 100))
+	totalStake, index, err := s.GetRewards(ctx, db)
+	require.NoError(err)
+	require.Zero(totalStake)
+	require.Zero(index.Sign())
+
+	// Register validator
+	_, err = execute(ctx, db, node.register(t, s, 99, owner), 0, owner)
+	require.ErrorIs(err, ErrStakeTooLow)
+
+	// Only the owner the staking key signed for can register the validator
+	_, err = execute(ctx, db, node.register(t, s, 100, alice), 0, owner)
+	require.ErrorIs(err, ErrInvalidSignature)
+	other := newTestValidator(t)
+	register := other.register(t, s, 100, owner)
+	register.NodeID = nodeID
+	_, err = execute(ctx, db, register, 0, owner)
+	require.ErrorIs(err, ErrInvalidCertificate)
+	register = node.register(t, s, 100, owner)
+	register.Certificate = register.Certificate[1:]
+	_, err = execute(ctx, db, register, 0, owner)
+	require.ErrorIs(err, ErrInvalidCertificate)
+
+	_, err = execute(ctx, db, node.register(t, s, 100, owner), 0, owner)
+	require.NoError(err)
+	_, err = execute(ctx, db, node.register(t, s, 100, alice), 0, alice)
+	require.ErrorIs(err, ErrValidatorExists)
+
+	// Delegate
+	_, err = execute(ctx, db, s.NewDelegate(ids.GenerateTestNodeID(), 300), 0, alice)
+	require.ErrorIs(err, ErrValidatorMissing)
+	_, err = execute(ctx, db, s.NewDelegate(nodeID, 300), 0, alice)
+	require.NoError(err)
+	validator, err := s.GetValidator(ctx, db, nodeID)
+	require.NoError(err)
+	require.Equal(owner, validator.Owner)
+	require.Equal(uint64(400), validator.Stake)
+	balance, err := balances.getBalance(ctx, db, alice)
+	require.NoError(err)
+	require.Equal(uint64(1_600), balance)
+
+	// Distribute fees (half of 800 split 1:3)
+	require.NoError(balances.Deduct(ctx, alice, db, 800))
+	require.NoError(fees.BlockFees(ctx, db, 800))
+	delegation, err := s.GetDelegation(ctx, db, nodeID, alice)
+	require.NoError(err)
+	_, index, err = s.GetRewards(ctx, db)
+	require.NoError(err)
+	require.NoError(settle(delegation, index))
+	require.Equal(uint64(300), delegation.Rewards)
+
+	output, err := execute(ctx, db, s.NewClaim(nodeID), 0, owner)
+	require.NoError(err)
+	result, err := UnmarshalClaimResult(output[0])
+	require.NoError(err)
+	require.Equal(uint64(100), result.Rewards)
+	require.Zero(result.Unbonded)
+	_, err = execute(ctx, db, s.NewClaim(nodeID), 0, owner)
+	require.ErrorIs(err, ErrNothingToClaim)
+
+	// Undelegate
+	_, err = execute(ctx, db, s.NewUndelegate(nodeID, 1), 0, owner)
+	require.ErrorIs(err, ErrStakeTooLow)
+	_, err = execute(ctx, db, s.NewUndelegate(nodeID, 295), 0, alice)
+	require.ErrorIs(err, ErrStakeTooLow)
+	_, err = execute(ctx, db, s.NewUndelegate(nodeID, 300), 10, alice)
+	require.NoError(err)
+	totalStake, _, err = s.GetRewards(ctx, db)
+	require.NoError(err)
+	require.Equal(uint64(100), totalStake)
+
+	// Rewards are no longer accrued by undelegated stake
+	require.NoError(balances.Deduct(ctx, alice, db, 200))
+	require.NoError(fees.BlockFees(ctx, db, 200))
+
+	// Claim rewards before unbonding finishes
+	output, err = execute(ctx, db, s.NewClaim(nodeID), 500, alice)
+	require.NoError(err)
+	result, err = UnmarshalClaimResult(output[0])
+	require.NoError(err)
+	require.Equal(uint64(300), result.Rewards)
+	require.Zero(result.Unbonded)
+
+	// Claim stake after unbonding finishes
+	output, err = execute(ctx, db, s.NewClaim(nodeID), 1_010, alice)
+	require.NoError(err)
+	result, err = UnmarshalClaimResult(output[0])
+	require.NoError(err)
+	require.Zero(result.Rewards)
+	require.Equal(uint64(300), result.Unbonded)
+	delegation, err = s.GetDelegation(ctx, db, nodeID, alice)
+	require.NoError(err)
+	require.Nil(delegation)
+	balance, err = balances.getBalance(ctx, db, alice)
+	require.NoError(err)
+	require.Equal(uint64(1_600-800-200+300+300), balance)
+
+	output, err = execute(ctx, db, s.NewClaim(nodeID), 1_010, owner)
+	require.NoError(err)
+	result, err = UnmarshalClaimResult(output[0])
+	require.NoError(err)
+	require.Equal(uint64(100), result.Rewards)
+}
+
+func TestStakingDeregister(t *testing.T) {
+	var (
+		require = require.New(t)
+		ctx     = context.TODO()
+		db      = newTestDB()
+		owner   = codec.CreateAddress(0, ids.GenerateTestID())
+		alice   = codec.CreateAddress(0, ids.GenerateTestID())
+		node    = newTestValidator(t)
+		nodeID  = node.nodeID
+	)
+	s, balances := newTestStaking()
+	require.NoError(balances.AddBalance(ctx, db, owner, 1_000))
+	require.NoError(balances.AddBalance(ctx, db, alice, 1_000))
+
+	_, err := execute(ctx, db, s.NewDeregisterValidator(nodeID), 0, owner)
+	require.ErrorIs(err, ErrValidatorMissing)
+	_, err = execute(ctx, db, node.register(t, s, 500, owner), 0, owner)
+	require.NoError(err)
+	_, err = execute(ctx, db, s.NewDelegate(nodeID, 100), 0, alice)
+	require.NoError(err)
+
+	// Only the owner can deregister, once other delegations are undelegated
+	_, err = execute(ctx, db, s.NewDeregisterValidator(nodeID), 0, alice)
+	require.ErrorIs(err, ErrNotOwner)
+	_, err = execute(ctx, db, s.NewDeregisterValidator(nodeID), 0, owner)
+	require.ErrorIs(err, ErrDelegationsRemaining)
+	_, err = execute(ctx, db, s.NewUndelegate(nodeID, 100), 0, alice)
+	require.NoError(err)
+	_, err = execute(ctx, db, s.NewDeregisterValidator(nodeID), 10, owner)
+	require.NoError(err)
+	validator, err := s.GetValidator(ctx, db, nodeID)
+	require.NoError(err)
+	require.Nil(validator)
+	totalStake, _, err := s.GetRewards(ctx, db)
+	require.NoError(err)
+	require.Zero(totalStake)
+	_, err = execute(ctx, db, s.NewDelegate(nodeID, 100), 10, alice)
+	require.ErrorIs(err, ErrValidatorMissing)
+
+	// The whole stake of the owner can be claimed after unbonding
+	_, err = execute(ctx, db, s.NewClaim(nodeID), 500, owner)
+	require.ErrorIs(err, ErrNothingToClaim)
+	output, err := execute(ctx, db, s.NewClaim(nodeID), 1_010, owner)
+	require.NoError(err)
+	result, err := UnmarshalClaimResult(output[0])
+	require.NoError(err)
+	require.Equal(uint64(500), result.Unbonded)
+	delegation, err := s.GetDelegation(ctx, db, nodeID, owner)
+	require.NoError(err)
+	require.Nil(delegation)
+	balance, err := balances.getBalance(ctx, db, owner)
+	require.NoError(err)
+	require.Equal(uint64(1_000), balance)
+	output, err = execute(ctx, db, s.NewClaim(nodeID), 1_010, alice)
+	require.NoError(err)
+	result, err = UnmarshalClaimResult(output[0])
+	require.NoError(err)
+	require.Equal(uint64(100), result.Unbonded)
+
+	// Registering again keeps stake that is still unbonding
+	_, err = execute(ctx, db, s.NewDeregisterValidator(nodeID), 0, owner)
+	require.ErrorIs(err, ErrValidatorMissing)
+	_, err = execute(ctx, db, node.register(t, s, 400, owner), 2_000, owner)
+	require.NoError(err)
+	_, err = execute(ctx, db, s.NewDeregisterValidator(nodeID), 2_000, owner)
+	require.NoError(err)
+	_, err = execute(ctx, db, node.register(t, s, 100, owner), 2_500, owner)
+	require.NoError(err)
+	delegation, err = s.GetDelegation(ctx, db, nodeID, owner)
+	require.NoError(err)
+	require.Equal(uint64(100), delegation.Amount)
+	require.Equal(uint64(400), delegation.Unbonding)
+}
+
+func TestStakingRegister(t *testing.T) {
+	require := require.New(t)
+	s, _ := newTestStaking()
+	registry := codec.NewTypeParser[chain.Action]()
+	require.NoError(s.Register(registry))
+
+	node := newTestValidator(t)
+	nodeID := node.nodeID
+	for _, action := range []chain.Action{
+		node.register(t, s, 100, codec.EmptyAddress),
+		s.NewDelegate(nodeID, 10),
+		s.NewUndelegate(nodeID, 10),
+		s.NewClaim(nodeID),
+		s.NewDeregisterValidator(nodeID),
+	} {
+		p := codec.NewWriter(action.Size(), action.Size())
+		action.Marshal(p)
+		require.NoError(p.Err())
+		unmarshal, ok := registry.LookupIndex(action.GetTypeID())
+		require.True(ok)
+		parsed, err := unmarshal(codec.NewReader(p.Bytes(), action.Size()))
+		require.NoError(err)
+		require.Equal(action, parsed)
+		require.Len(parsed.StateKeysMaxChunks(), len(parsed.StateKeys(codec.EmptyAddress, ids.Empty)))
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+// State
+// [Config.Prefix] +
+// 0x0/ (validators)
+//   -> [nodeID] => owner|stake
+// 0x1/ (delegations)
+//   -> [nodeID|delegator] => amount|rewardIndex|rewards|unbonding|unbondingEnd
+// 0x2/ (rewards) => totalStake|rewardIndex
+
+const (
+	validatorPrefix  = 0x0
+	delegationPrefix = 0x1
+	rewardsPrefix    = 0x2
+
+	// rewardIndexLen is the size of a serialized reward index (a uint256)
+	rewardIndexLen = 32
+)
+
+const (
+	ValidatorChunks  uint16 = 1
+	DelegationChunks uint16 = 2
+	RewardsChunks    uint16 = 1
+)
+
+type Validator struct {
+	Owner codec.Address `json:"owner"`
+
+	// Stake is the sum of all delegations to the validator (including the
+	// delegation of [Owner]).
+	Stake uint64 `json:"stake"`
+}
+
+type Delegation struct {
+	Amount uint64 `json:"amount"`
+
+	// Rewards are the rewards accrued by the delegation that have not been
+	// claimed yet (as of the last time it was modified).
+	Rewards uint64 `json:"rewards"`
+
+	// Unbonding is the stake that was undelegated and can be claimed after
+	// [UnbondingEnd].
+	Unbonding    uint64 `json:"unbonding"`
+	UnbondingEnd int64  `json:"unbondingEnd"`
+
+	rewardIndex *big.Int
+}
+
+// [Prefix] + [validatorPrefix] + [nodeID]
+func (s *Staking) ValidatorKey(nodeID ids.NodeID) (k []byte) {
+	k = make([]byte, 2+ids.NodeIDLen+consts.Uint16Len)
+	k[0] = s.c.Prefix
+	k[1] = validatorPrefix
+	copy(k[2:], nodeID[:])
+	binary.BigEndian.PutUint16(k[2+ids.NodeIDLen:], ValidatorChunks)
+	return
+}
+
+// [Prefix] + [delegationPrefix] + [nodeID] + [delegator]
+func (s *Staking) DelegationKey(nodeID ids.NodeID, delegator codec.Address) (k []byte) {
+	k = make([]byte, 2+ids.NodeIDLen+codec.AddressLen+consts.Uint16Len)
+	k[0] = s.c.Prefix
+	k[1] = delegationPrefix
+	copy(k[2:], nodeID[:])
+	copy(k[2+ids.NodeIDLen:], delegator[:])
+	binary.BigEndian.PutUint16(k[2+ids.NodeIDLen+codec.AddressLen:], DelegationChunks)
+	return
+}
+
+// [Prefix] + [rewardsPrefix]
+func (s *Staking) RewardsKey() (k []byte) {
+	k = make([]byte, 2+consts.Uint16Len)
+	k[0] = s.c.Prefix
+	k[1] = rewardsPrefix
+	binary.BigEndian.PutUint16(k[2:], RewardsChunks)
+	return
+}
+
+func (s *Staking) GetValidator(ctx context.Context, im state.Immutable, nodeID ids.NodeID) (*Validator, error) {
+	v, err := im.GetValue(ctx, s.ValidatorKey(nodeID))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var validator Validator
+	copy(validator.Owner[:], v[:codec.AddressLen])
+	validator.Stake = binary.BigEndian.Uint64(v[codec.AddressLen:])
+	return &validator, nil
+}
+
+func (s *Staking) setValidator(ctx context.Context, mu state.Mutable, nodeID ids.NodeID, validator *Validator) error {
+	v := make([]byte, codec.AddressLen+consts.Uint64Len)
+	copy(v, validator.Owner[:])
+	binary.BigEndian.PutUint64(v[codec.AddressLen:], validator.Stake)
+	return mu.Insert(ctx, s.ValidatorKey(nodeID), v)
+}
+
+func (s *Staking) deleteValidator(ctx context.Context, mu state.Mutable, nodeID ids.NodeID) error {
+	return mu.Remove(ctx, s.ValidatorKey(nodeID))
+}
+
+func (s *Staking) GetDelegation(
+	ctx context.Context,
+	im state.Immutable,
+	nodeID ids.NodeID,
+	delegator codec.Address,
+) (*Delegation, error) {
+	v, err := im.GetValue(ctx, s.DelegationKey(nodeID, delegator))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var delegation Delegation
+	delegation.Amount = binary.BigEndian.Uint64(v)
+	delegation.rewardIndex = new(big.Int).SetBytes(v[consts.Uint64Len : consts.Uint64Len+rewardIndexLen])
+	delegation.Rewards = binary.BigEndian.Uint64(v[consts.Uint64Len+rewardIndexLen:])
+	delegation.Unbonding = binary.BigEndian.Uint64(v[consts.Uint64Len*2+rewardIndexLen:])
+	delegation.UnbondingEnd = int64(binary.BigEndian.Uint64(v[consts.Uint64Len*3+rewardIndexLen:]))
+	return &delegation, nil
+}
+
+func (s *Staking) setDelegation(
+	ctx context.Context,
+	mu state.Mutable,
+	nodeID ids.NodeID,
+	delegator codec.Address,
+	delegation *Delegation,
+) error {
+	v := make([]byte, consts.Uint64Len*4+rewardIndexLen)
+	binary.BigEndian.PutUint64(v, delegation.Amount)
+	delegation.rewardIndex.FillBytes(v[consts.Uint64Len : consts.Uint64Len+rewardIndexLen])
+	binary.BigEndian.PutUint64(v[consts.Uint64Len+rewardIndexLen:], delegation.Rewards)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len*2+rewardIndexLen:], delegation.Unbonding)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len*3+rewardIndexLen:], uint64(delegation.UnbondingEnd))
+	return mu.Insert(ctx, s.DelegationKey(nodeID, delegator), v)
+}
+
+func (s *Staking) deleteDelegation(ctx context.Context, mu state.Mutable, nodeID ids.NodeID, delegator codec.Address) error {
+	return mu.Remove(ctx, s.DelegationKey(nodeID, delegator))
+}
+
+// GetRewards returns the total stake of all validators and the rewards
+// distributed per unit of stake (scaled by [rewardPrecision]).
+func (s *Staking) GetRewards(ctx context.Context, im state.Immutable) (uint64, *big.Int, error) {
+	v, err := im.GetValue(ctx, s.RewardsKey())
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, new(big.Int), nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	totalStake := binary.BigEndian.Uint64(v)
+	index := new(big.Int).SetBytes(v[consts.Uint64Len:])
+	return totalStake, index, nil
+}
+
+func (s *Staking) setRewards(ctx context.Context, mu state.Mutable, totalStake uint64, index *big.Int) error {
+	if index.BitLen() > rewardIndexLen*8 {
+		return ErrOverflow
+	}
+	v := make([]byte, consts.Uint64Len+rewardIndexLen)
+	binary.BigEndian.PutUint64(v, totalStake)
+	index.FillBytes(v[consts.Uint64Len:])
+	return mu.Insert(ctx, s.RewardsKey(), v)
+}
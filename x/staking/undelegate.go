@@ -0,0 +1,134 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+var _ chain.Action = (*Undelegate)(nil)
+
+type Undelegate struct {
+	// [NodeID] is the validator to undelegate from.
+	NodeID ids.NodeID `json:"nodeID"`
+
+	// [Amount] is the amount of stake to undelegate. It can be claimed after
+	// [UnbondingPeriod] (undelegating again resets the period for all stake
+	// that is still unbonding).
+	Amount uint64 `json:"amount"`
+
+	s *Staking
+}
+
+func (s *Staking) NewUndelegate(nodeID ids.NodeID, amount uint64) *Undelegate {
+	return &Undelegate{NodeID: nodeID, Amount: amount, s: s}
+}
+
+func (u *Undelegate) GetTypeID() uint8 {
+	return u.s.c.FirstActionID + undelegateID
+}
+
+func (u *Undelegate) StateKeys(actor codec.Address, _ ids.ID) state.Keys {
+	return state.Keys{
+		string(u.s.ValidatorKey(u.NodeID)):         state.Read | state.Write,
+		string(u.s.DelegationKey(u.NodeID, actor)): state.Read | state.Write,
+		string(u.s.RewardsKey()):                   state.Read | state.Write,
+	}
+}
+
+func (*Undelegate) StateKeysMaxChunks() []uint16 {
+	return []uint16{ValidatorChunks, DelegationChunks, RewardsChunks}
+}
+
+func (u *Undelegate) Execute(
+	ctx context.Context,
+	_ chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) ([][]byte, error) {
+	if u.Amount == 0 {
+		// This should be guarded via [Unmarshal] but we check anyways.
+		return nil, ErrValueZero
+	}
+	validator, err := u.s.GetValidator(ctx, mu, u.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	if validator == nil {
+		return nil, ErrValidatorMissing
+	}
+	delegation, err := u.s.GetDelegation(ctx, mu, u.NodeID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if delegation == nil {
+		return nil, ErrDelegationMissing
+	}
+	if u.Amount > delegation.Amount {
+		return nil, ErrInsufficientStake
+	}
+	remaining := delegation.Amount - u.Amount
+	if actor == validator.Owner && remaining < u.s.c.MinValidatorStake {
+		// The owner of a validator must always keep the minimum stake.
+		return nil, ErrStakeTooLow
+	}
+	if actor != validator.Owner && remaining > 0 && remaining < u.s.c.MinDelegatorStake {
+		return nil, ErrStakeTooLow
+	}
+	totalStake, index, err := u.s.GetRewards(ctx, mu)
+	if err != nil {
+		return nil, err
+	}
+	if err := settle(delegation, index); err != nil {
+		return nil, err
+	}
+	delegation.Amount = remaining
+	if delegation.Unbonding, err = smath.Add64(delegation.Unbonding, u.Amount); err != nil {
+		return nil, err
+	}
+	delegation.UnbondingEnd = timestamp + u.s.c.UnbondingPeriod
+	validator.Stake -= u.Amount
+	if err := u.s.setValidator(ctx, mu, u.NodeID, validator); err != nil {
+		return nil, err
+	}
+	if err := u.s.setDelegation(ctx, mu, u.NodeID, actor, delegation); err != nil {
+		return nil, err
+	}
+	return nil, u.s.setRewards(ctx, mu, totalStake-u.Amount, index)
+}
+
+func (*Undelegate) ComputeUnits(chain.Rules) uint64 {
+	return UndelegateComputeUnits
+}
+
+func (*Undelegate) Size() int {
+	return ids.NodeIDLen + consts.Uint64Len
+}
+
+func (u *Undelegate) Marshal(p *codec.Packer) {
+	p.PackFixedBytes(u.NodeID.Bytes())
+	p.PackUint64(u.Amount)
+}
+
+func (s *Staking) unmarshalUndelegate(p *codec.Packer) (chain.Action, error) {
+	undelegate := Undelegate{s: s}
+	unpackNodeID(p, &undelegate.NodeID)
+	undelegate.Amount = p.UnpackUint64(true)
+	return &undelegate, p.Err()
+}
+
+func (u *Undelegate) ValidRange(r chain.Rules) (int64, int64) {
+	return chain.ActionValidRange(r, u.GetTypeID())
+}